@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var auditCmdName = "audit"
+
+// AuditCmd command
+var AuditCmd = cli.Command{
+	Name:  "audit",
+	Usage: "audit COMMAND",
+	Subcommands: []cli.Command{
+		auditList,
+	},
+}
+
+var auditList = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List the changes of infrastructure of current tenant recorded in the audit log",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "since",
+			Value: "24h",
+			Usage: "Lists the records since a duration before now (ie 24h, 30m) or a date (RFC3339, ie 2020-06-01T00:00:00Z); empty for all",
+		},
+		cli.StringFlag{
+			Name:  "resource",
+			Usage: "Lists only the records concerning a kind of resource (ie host) or a resource (ie host:foo)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", auditCmdName, c.Command.Name, c.Args())
+		list, err := client.New().Audit.List(c.String("since"), c.String("resource"), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "list of audit records", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(list.GetRecords())
+	},
+}
@@ -17,9 +17,15 @@
 package commands
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
+	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/client"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
 	"github.com/CS-SI/SafeScale/lib/utils"
@@ -40,6 +46,9 @@ var BucketCmd = cli.Command{
 		bucketInspect,
 		bucketMount,
 		bucketUnmount,
+		bucketSync,
+		bucketLifecycle,
+		bucketURL,
 	},
 }
 
@@ -70,6 +79,17 @@ var bucketCreate = cli.Command{
 	Aliases:   []string{"new"},
 	Usage:     "Creates a bucket",
 	ArgsUsage: "<Bucket_name>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "encrypt",
+			Usage: "Encrypts client-side the objects of the bucket, with a key derived from 'CryptKey' of the tenant section 'objectstorage' if --key is not used",
+		},
+		cli.StringFlag{
+			Name:   "key",
+			Usage:  "Passphrase building the key encrypting the objects of the bucket (implies --encrypt)",
+			EnvVar: "SAFESCALE_BUCKET_KEY",
+		},
+	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", bucketCmdName, c.Command.Name, c.Args())
 		if c.NArg() != 1 {
@@ -77,7 +97,9 @@ var bucketCreate = cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Bucket_name>."))
 		}
 
-		err := client.New().Bucket.Create(c.Args().Get(0), temporal.GetExecutionTimeout())
+		passphrase := c.String("key")
+		encrypted := c.Bool("encrypt") || passphrase != ""
+		err := client.New().Bucket.Create(c.Args().Get(0), encrypted, passphrase, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(
 				clitools.ExitOnRPC(
@@ -240,3 +262,248 @@ var bucketUnmount = cli.Command{
 		return clitools.SuccessResponse(nil)
 	},
 }
+
+var bucketSync = cli.Command{
+	Name:      "sync",
+	Usage:     "Replicates the objects of a bucket in another one, possibly of another tenant",
+	ArgsUsage: "<[Source_tenant:]Source_bucket> <[Destination_tenant:]Destination_bucket>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "delete",
+			Usage: "Deletes from destination the objects previously synchronized and since deleted from source",
+		},
+		cli.IntFlag{
+			Name:  "concurrency",
+			Value: abstract.DefaultBucketSyncConcurrency,
+			Usage: "Number of objects copied in parallel",
+		},
+		cli.BoolFlag{
+			Name:  "watch",
+			Usage: "Synchronizes continuously, polling the source bucket every --interval",
+		},
+		cli.DurationFlag{
+			Name:  "interval",
+			Value: time.Minute,
+			Usage: "Delay between two synchronizations with --watch",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", bucketCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Source_bucket> and/or <Destination_bucket>."))
+		}
+		interval := c.Duration("interval")
+		if c.Bool("watch") && interval <= 0 {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("Invalid value of --interval: must be positive."))
+		}
+
+		for {
+			report, err := client.New().Bucket.Sync(
+				c.Args().Get(0), c.Args().Get(1), c.Bool("delete"), c.Int("concurrency"), temporal.GetLongOperationTimeout(),
+			)
+			if err != nil {
+				return clitools.FailureResponse(
+					clitools.ExitOnRPC(
+						utils.Capitalize(
+							client.DecorateError(
+								err, "synchronization of buckets", true,
+							).Error(),
+						),
+					),
+				)
+			}
+			if !c.Bool("watch") {
+				return clitools.SuccessResponse(report)
+			}
+			_ = clitools.SuccessResponse(report)
+			time.Sleep(interval)
+		}
+	},
+}
+
+var bucketLifecycle = cli.Command{
+	Name:  "lifecycle",
+	Usage: "manage lifecycle rules of a bucket, enforced periodically by safescaled",
+	Subcommands: []cli.Command{
+		bucketLifecycleSet,
+		bucketLifecycleGet,
+		bucketLifecycleClear,
+	},
+}
+
+var bucketLifecycleSet = cli.Command{
+	Name:      "set",
+	Usage:     "Replaces the lifecycle rules of a bucket",
+	ArgsUsage: "<Bucket_name>",
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "rule",
+			Usage: "Rule in the form '[prefix=<prefix>,][expire-after=<days>,][keep-last=<count>]'; can be used several times",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", bucketCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Bucket_name>."))
+		}
+		if len(c.StringSlice("rule")) == 0 {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("Missing mandatory option --rule."))
+		}
+
+		var rules []*pb.BucketLifecycleRule
+		for _, spec := range c.StringSlice("rule") {
+			rule, err := parseBucketLifecycleRule(spec)
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnInvalidOption(err.Error()))
+			}
+			rules = append(rules, rule)
+		}
+		err := client.New().Bucket.SetLifecycle(c.Args().Get(0), rules, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "setting of lifecycle of bucket", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+// parseBucketLifecycleRule parses a rule in the form "[prefix=<prefix>,][expire-after=<days>,][keep-last=<count>]"
+func parseBucketLifecycleRule(spec string) (*pb.BucketLifecycleRule, error) {
+	rule := &pb.BucketLifecycleRule{}
+	for _, item := range strings.Split(spec, ",") {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid item '%s' in rule '%s': must be in the form <key>=<value>", item, spec)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch key {
+		case "prefix":
+			rule.Prefix = value
+		case "expire-after", "keep-last":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid value '%s' of '%s' in rule '%s': must be a positive integer", value, key, spec)
+			}
+			if key == "expire-after" {
+				rule.ExpireAfterDays = int32(n)
+			} else {
+				rule.KeepLast = int32(n)
+			}
+		default:
+			return nil, fmt.Errorf("unknown key '%s' in rule '%s'", key, spec)
+		}
+	}
+	if rule.ExpireAfterDays == 0 && rule.KeepLast == 0 {
+		return nil, fmt.Errorf("rule '%s' must contain expire-after and/or keep-last", spec)
+	}
+	return rule, nil
+}
+
+var bucketLifecycleGet = cli.Command{
+	Name:      "get",
+	Aliases:   []string{"inspect"},
+	Usage:     "Shows the lifecycle rules of a bucket",
+	ArgsUsage: "<Bucket_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", bucketCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Bucket_name>."))
+		}
+
+		resp, err := client.New().Bucket.GetLifecycle(c.Args().Get(0), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "inspection of lifecycle of bucket", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
+var bucketLifecycleClear = cli.Command{
+	Name:      "clear",
+	Usage:     "Removes the lifecycle rules of a bucket",
+	ArgsUsage: "<Bucket_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", bucketCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Bucket_name>."))
+		}
+
+		err := client.New().Bucket.ClearLifecycle(c.Args().Get(0), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "removal of lifecycle of bucket", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var bucketURL = cli.Command{
+	Name:      "url",
+	Aliases:   []string{"presign"},
+	Usage:     "Builds a time-limited URL to download or upload an object without credentials",
+	ArgsUsage: "<Bucket_name> <Object_name>",
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "ttl",
+			Value: time.Hour,
+			Usage: "Validity of the URL (at most 168h)",
+		},
+		cli.BoolFlag{
+			Name:  "upload",
+			Usage: "If used, the URL allows to upload the object (HTTP PUT) instead of downloading it",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", bucketCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Bucket_name> and/or <Object_name>."))
+		}
+		ttl := c.Duration("ttl")
+		if ttl < time.Second {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("Invalid value of --ttl: must be at least 1s."))
+		}
+
+		resp, err := client.New().Bucket.PresignObject(
+			c.Args().Get(0), c.Args().Get(1), c.Bool("upload"), ttl, temporal.GetExecutionTimeout(),
+		)
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "presigning of object", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
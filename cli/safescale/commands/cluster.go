@@ -27,24 +27,17 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/client"
-	"github.com/CS-SI/SafeScale/lib/server/cluster"
-	"github.com/CS-SI/SafeScale/lib/server/cluster/api"
-	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
-	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
-	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/complexity"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
-	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
-	"github.com/CS-SI/SafeScale/lib/server/install"
 	"github.com/CS-SI/SafeScale/lib/utils"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
-	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
-	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
@@ -52,7 +45,7 @@ import (
 var (
 	clusterName string
 	// clusterServiceName *string
-	clusterInstance api.Cluster
+	clusterInstance *pb.Cluster
 )
 
 var clusterCommandName = "cluster"
@@ -77,6 +70,9 @@ var ClusterCommand = cli.Command{
 		clusterStopCommand,
 		clusterExpandCommand,
 		clusterShrinkCommand,
+		clusterAutoscaleCommand,
+		clusterCheckHealthCommand,
+		clusterAutoRepairCommand,
 		clusterDcosCommand,
 		clusterKubectlCommand,
 		clusterHelmCommand,
@@ -102,9 +98,9 @@ func extractClusterArgument(c *cli.Context) error {
 		}
 
 		var err error
-		clusterInstance, err = cluster.Load(concurrency.RootTask(), clusterName)
+		clusterInstance, err = client.New().Cluster.Inspect(clusterName, temporal.GetExecutionTimeout())
 		if err != nil {
-			if _, ok := err.(fail.ErrNotFound); ok {
+			if status.Code(err) == codes.NotFound {
 				if !c.Command.HasName("create") {
 					return clitools.ExitOnErrorWithMessage(
 						exitcode.NotFound, fmt.Sprintf("Cluster '%s' not found.\n", clusterName),
@@ -134,21 +130,18 @@ var clusterListCommand = cli.Command{
 
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", clusterCommandName, c.Command.Name, c.Args())
-		list, err := cluster.List()
+		list, err := client.New().Cluster.List(temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(fmt.Sprintf("failed to get cluster list: %v", err)))
 		}
 
 		var formatted []interface{}
-		for _, value := range list {
-			c := value.(api.Cluster)
-			converted, err := convertToMap(c)
+		for _, value := range list.GetClusters() {
+			converted, err := convertToMap(value)
 			if err != nil {
 				return clitools.FailureResponse(
 					clitools.ExitOnErrorWithMessage(
-						exitcode.Run, fmt.Sprintf(
-							"failed to extract data about cluster '%s'", c.GetIdentity(concurrency.RootTask()).Name,
-						),
+						exitcode.Run, fmt.Sprintf("failed to extract data about cluster '%s'", value.GetName()),
 					),
 				)
 			}
@@ -215,138 +208,80 @@ func outputClusterConfig() (map[string]interface{}, error) {
 
 // convertToMap converts clusterInstance to its equivalent in map[string]interface{},
 // with fields converted to string and used as keys
-func convertToMap(c api.Cluster) (map[string]interface{}, error) {
-	identity := c.GetIdentity(concurrency.RootTask())
+func convertToMap(c *pb.Cluster) (map[string]interface{}, error) {
+	if c == nil {
+		return nil, fail.InvalidParameterError("c", "cannot be nil")
+	}
 
 	result := map[string]interface{}{
-		"name":             identity.Name,
-		"flavor":           identity.Flavor,
-		"flavor_label":     identity.Flavor.String(),
-		"complexity":       identity.Complexity,
-		"complexity_label": identity.Complexity.String(),
-		"admin_login":      "cladm",
-		"admin_password":   identity.AdminPassword,
-		"keypair":          identity.Keypair,
+		"name":             c.GetName(),
+		"flavor":           flavor.Enum(c.GetFlavor()),
+		"flavor_label":     c.GetFlavorLabel(),
+		"complexity":       complexity.Enum(c.GetComplexity()),
+		"complexity_label": c.GetComplexityLabel(),
+		"admin_login":      c.GetAdminLogin(),
+		"admin_password":   c.GetAdminPassword(),
+		"tenant":           c.GetTenant(),
 	}
 
-	properties := c.GetProperties(concurrency.RootTask())
-	err := properties.LockForRead(property.CompositeV1).ThenUse(
-		func(clonable data.Clonable) error {
-			result["tenant"] = clonable.(*clusterpropsv1.Composite).Tenants[0]
-			return nil
-		},
-	)
-	if err != nil {
-		return nil, err
+	netCfg := c.GetNetwork()
+	result["network_id"] = netCfg.GetNetworkId()
+	result["cidr"] = netCfg.GetCidr()
+	result["default_route_ip"] = netCfg.GetDefaultRouteIp()
+	result["gateway_ip"] = netCfg.GetDefaultRouteIp() // legacy ...
+	result["primary_gateway_ip"] = netCfg.GetGatewayIp()
+	result["endpoint_ip"] = netCfg.GetEndpointIp()
+	result["primary_public_ip"] = netCfg.GetEndpointIp()
+	if netCfg.GetSecondaryGatewayIp() != "" {
+		result["secondary_gateway_ip"] = netCfg.GetSecondaryGatewayIp()
+		result["secondary_public_ip"] = netCfg.GetSecondaryPublicIp()
+		result["public_ip"] = netCfg.GetEndpointIp() // legacy ...
 	}
 
-	netCfg, err := c.GetNetworkConfig(concurrency.RootTask())
-	if err != nil {
-		return nil, err
-	}
-	result["network_id"] = netCfg.NetworkID
-	result["cidr"] = netCfg.CIDR
-	result["default_route_ip"] = netCfg.DefaultRouteIP
-	result["gateway_ip"] = netCfg.DefaultRouteIP // legacy ...
-	result["primary_gateway_ip"] = netCfg.GatewayIP
-	result["endpoint_ip"] = netCfg.EndpointIP
-	result["primary_public_ip"] = netCfg.EndpointIP
-	if netCfg.SecondaryGatewayIP != "" {
-		result["secondary_gateway_ip"] = netCfg.SecondaryGatewayIP
-		result["secondary_public_ip"] = netCfg.SecondaryPublicIP
-		result["public_ip"] = netCfg.EndpointIP // legacy ...
-	}
-	if !properties.Lookup(property.DefaultsV2) {
-		err = properties.LockForRead(property.DefaultsV1).ThenUse(
-			func(clonable data.Clonable) error {
-				defaultsV1 := clonable.(*clusterpropsv1.Defaults)
-				result["defaults"] = map[string]interface{}{
-					"image":  defaultsV1.Image,
-					"master": defaultsV1.MasterSizing,
-					"node":   defaultsV1.NodeSizing,
-				}
-				return nil
-			},
-		)
-	} else {
-		err = properties.LockForRead(property.DefaultsV2).ThenUse(
-			func(clonable data.Clonable) error {
-				defaultsV2 := clonable.(*clusterpropsv2.Defaults)
-				result["defaults"] = map[string]interface{}{
-					"image":   defaultsV2.Image,
-					"gateway": defaultsV2.GatewaySizing,
-					"master":  defaultsV2.MasterSizing,
-					"node":    defaultsV2.NodeSizing,
-				}
-				return nil
-			},
-		)
+	defaults := c.GetDefaults()
+	result["defaults"] = map[string]interface{}{
+		"image":   defaults.GetImage(),
+		"gateway": defaults.GetGatewaySizing(),
+		"master":  defaults.GetMasterSizing(),
+		"node":    defaults.GetNodeSizing(),
 	}
-	if err != nil {
-		return nil, err
+
+	result["nodes"] = map[string]interface{}{
+		"masters": c.GetMasters(),
+		"nodes":   c.GetNodes(),
 	}
 
-	err = properties.LockForRead(property.NodesV1).ThenUse(
-		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes)
-			result["nodes"] = map[string]interface{}{
-				"masters": nodesV1.Masters,
-				"nodes":   nodesV1.PrivateNodes,
-			}
-			return nil
-		},
-	)
-	if err != nil {
-		return nil, err
+	disabled := map[string]struct{}{}
+	for _, v := range c.GetDisabledFeatures() {
+		disabled[v] = struct{}{}
 	}
-	err = properties.LockForRead(property.FeaturesV1).ThenUse(
-		func(clonable data.Clonable) error {
-			result["features"] = clonable.(*clusterpropsv1.Features)
-			return nil
-		},
-	)
-	if err != nil {
-		return nil, err
+	result["features"] = map[string]interface{}{
+		"installed": c.GetInstalledFeatures(),
+		"disabled":  disabled,
 	}
 
-	err = properties.LockForRead(property.StateV1).ThenUse(
-		func(clonable data.Clonable) error {
-			state := clonable.(*clusterpropsv1.State).State
-			result["last_state"] = state
-			result["last_state_label"] = state.String()
-			return nil
-		},
-	)
-	if err != nil {
-		return nil, err
-	}
-	result["admin_login"] = "cladm"
+	result["last_state"] = c.GetState()
+	result["last_state_label"] = c.GetStateLabel()
 
 	// Add information not directly in cluster GetConfig()
 	// FUTURE: replace use of !Disabled["remotedesktop"] with use of Installed["remotedesktop"] (not yet implemented)
-	if _, ok := result["features"].(*clusterpropsv1.Features).Disabled["remotedesktop"]; !ok {
+	if _, ok := disabled["remotedesktop"]; !ok {
 		remoteDesktops := map[string][]string{}
-
-		clientHost := client.New().Host
-		for _, id := range c.ListMasterIDs(concurrency.RootTask()) {
-			host, err := clientHost.Inspect(id, temporal.GetExecutionTimeout())
-			if err != nil {
-				return nil, err
-			}
+		for _, master := range c.GetMasters() {
 			urlFmt := "https://%s/_platform/remotedesktop/%s/"
-			urls := []string{fmt.Sprintf(urlFmt, netCfg.EndpointIP, host.Name)}
-			if netCfg.SecondaryPublicIP != "" {
+			urls := []string{fmt.Sprintf(urlFmt, netCfg.GetEndpointIp(), master.GetName())}
+			if netCfg.GetSecondaryPublicIp() != "" {
 				// VPL: no public VIP IP yet, so don't repeat primary gateway public IP
 				// urls = append(urls, fmt.Sprintf(+urlFmt, netCfg.PrimaryPublicIP, host.Name))
-				urls = append(urls, fmt.Sprintf(urlFmt, netCfg.SecondaryPublicIP, host.Name))
+				urls = append(urls, fmt.Sprintf(urlFmt, netCfg.GetSecondaryPublicIp(), master.GetName()))
 			}
-			remoteDesktops[host.Name] = urls
+			remoteDesktops[master.GetName()] = urls
 		}
 		result["remote_desktop"] = remoteDesktops
 	} else {
 		result["remote_desktop"] = fmt.Sprintf(
 			"Remote Desktop not installed. To install it, execute 'safescale platform add-feature %s remotedesktop'.",
-			clusterName,
+			c.GetName(),
 		)
 	}
 
@@ -451,6 +386,10 @@ var clusterCreateCommand = cli.Command{
 			Name:  "node-sizing",
 			Usage: `Describe node sizing in format "<component><operator><value>[,...]" (cf. --sizing for details)`,
 		},
+		cli.BoolFlag{
+			Name:  "estimate",
+			Usage: "Displays the hourly and monthly cost of the gateways, masters and nodes instead of creating the cluster",
+		},
 		cli.UintFlag{
 			Name:  "cpu",
 			Usage: "DEPRECATED! use --sizing and friends instead! Defines the number of cpu of masters and nodes in the cluster",
@@ -494,9 +433,9 @@ var clusterCreateCommand = cli.Command{
 		domain := c.String("domain")
 
 		disable := c.StringSlice("disable")
-		disableFeatures := map[string]struct{}{}
+		var disableFeatures []string
 		for _, v := range disable {
-			disableFeatures[strings.ToLower(v)] = struct{}{}
+			disableFeatures = append(disableFeatures, strings.ToLower(v))
 		}
 
 		los := c.String("os")
@@ -560,29 +499,29 @@ var clusterCreateCommand = cli.Command{
 				mastersDef = gatewaysDef         // ... nor for masters
 			}
 		}
-		clusterInstance, err := cluster.Create(
-			concurrency.RootTask(),
-			control.Request{
-				Name:                    clusterName,
-				Complexity:              clusterComplexity,
-				CIDR:                    cidr,
-				Domain:                  domain,
-				Flavor:                  clusterFlavor,
-				KeepOnFailure:           keep,
-				Force:                   force,
-				GatewaysDef:             gatewaysDef,
-				MastersDef:              mastersDef,
-				NodesDef:                nodesDef,
-				DisabledDefaultFeatures: disableFeatures,
-			},
-		)
-		if err != nil {
-			if clusterInstance != nil {
-				cluDel := clusterInstance.Delete(concurrency.RootTask())
-				if cluDel != nil {
-					logrus.Warnf("Error deleting cluster instance: %s", cluDel)
-				}
+		def := &pb.ClusterDefinition{
+			Name:             clusterName,
+			Complexity:       int32(clusterComplexity),
+			Cidr:             cidr,
+			Domain:           domain,
+			Flavor:           int32(clusterFlavor),
+			KeepOnFailure:    keep,
+			Force:            force,
+			Gateways:         gatewaysDef,
+			Masters:          mastersDef,
+			Nodes:            nodesDef,
+			DisabledFeatures: disableFeatures,
+		}
+		if c.Bool("estimate") {
+			estimate, err := client.New().Cluster.Estimate(def, temporal.GetExecutionTimeout())
+			if err != nil {
+				msg := fmt.Sprintf("failed to estimate cluster: %s", err.Error())
+				return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
 			}
+			return clitools.SuccessResponse(estimate)
+		}
+		clusterInstance, err := client.New().Cluster.Create(def, temporal.GetExecutionTimeout())
+		if err != nil {
 			msg := fmt.Sprintf("failed to create cluster: %s", err.Error())
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
 		}
@@ -643,15 +582,7 @@ var clusterDeleteCommand = cli.Command{
 		if !yes && !utils.UserConfirmed(fmt.Sprintf("Are you sure you want to delete Cluster '%s'", clusterName)) {
 			return clitools.SuccessResponse("Aborted")
 		}
-		if force {
-			err = clusterInstance.Wipe(concurrency.RootTask())
-			if err != nil {
-				return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
-			}
-			return clitools.SuccessResponse(nil)
-		}
-
-		err = clusterInstance.Delete(concurrency.RootTask())
+		err = client.New().Cluster.Delete(clusterName, force, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
 		}
@@ -679,7 +610,7 @@ var clusterStopCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		err = clusterInstance.Stop(concurrency.RootTask())
+		err = client.New().Cluster.Stop(clusterName, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
 		}
@@ -708,7 +639,7 @@ var clusterStartCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		err = clusterInstance.Start(concurrency.RootTask())
+		err = client.New().Cluster.Start(clusterName, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
 		}
@@ -734,7 +665,7 @@ var clusterStateCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		state, err := clusterInstance.GetState(concurrency.RootTask())
+		state, err := client.New().Cluster.State(clusterName, temporal.GetExecutionTimeout())
 		if err != nil {
 			msg := fmt.Sprintf("failed to get cluster state: %s", err.Error())
 			return clitools.FailureResponse(clitools.ExitOnRPC(msg))
@@ -742,8 +673,8 @@ var clusterStateCommand = cli.Command{
 		return clitools.SuccessResponse(
 			map[string]interface{}{
 				"Name":       clusterName,
-				"State":      state,
-				"StateLabel": state.String(),
+				"State":      state.GetState(),
+				"StateLabel": state.GetStateLabel(),
 			},
 		)
 	},
@@ -811,11 +742,11 @@ var clusterExpandCommand = cli.Command{
 	<operator> can be =,<,> (except for disk where valid operators are only = or >)
 	<value> can be an integer (for cpu and disk) or a float (for ram) or an including interval "[<lower value>-<upper value>]"`,
 		},
-        cli.BoolFlag{
-            Name:  "keep-on-failure, k",
-            Usage: "If used, the resources are not deleted on failure (default: not set)",
-        },
-    },
+		cli.BoolFlag{
+			Name:  "keep-on-failure, k",
+			Usage: "If used, the resources are not deleted on failure (default: not set)",
+		},
+	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", clusterCommandName, c.Command.Name, c.Args())
 		err := extractClusterArgument(c)
@@ -859,12 +790,19 @@ var clusterExpandCommand = cli.Command{
 				}
 			}
 		}
-        nodesDef.KeepOnFailure = c.Bool("keep-on-failure")
-        
-		hosts, err := clusterInstance.AddNodes(concurrency.RootTask(), count, nodesDef)
+		if nodesDef == nil {
+			nodesDef = &pb.HostDefinition{}
+		}
+		nodesDef.KeepOnFailure = c.Bool("keep-on-failure")
+
+		nodes, err := client.New().Cluster.Expand(clusterName, count, nodesDef, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
 		}
+		var hosts []string
+		for _, v := range nodes.GetNodes() {
+			hosts = append(hosts, v.GetId())
+		}
 		return clitools.SuccessResponse(hosts)
 	},
 }
@@ -913,10 +851,7 @@ var clusterShrinkCommand = cli.Command{
 		if count > 1 {
 			countS = "s"
 		}
-		present, err := clusterInstance.CountNodes(concurrency.RootTask())
-		if err != nil {
-			return clitools.FailureResponse(err)
-		}
+		present := uint(len(clusterInstance.GetNodes()))
 		if count > present {
 			msg := fmt.Sprintf("cannot delete %d node%s, the cluster contains only %d of them", count, countS, present)
 			return clitools.FailureResponse(clitools.ExitOnInvalidOption(msg))
@@ -930,21 +865,174 @@ var clusterShrinkCommand = cli.Command{
 		}
 
 		// fmt.Printf("Deleting %d node%s from Cluster '%s' (this may take a while)...\n", count, countS, clusterName)
-		var msgs []string
-		availableMaster, err := clusterInstance.FindAvailableMaster(concurrency.RootTask())
+		_, err = client.New().Cluster.Shrink(clusterName, int(count), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+// clusterAutoscaleCommand handles 'deploy cluster <clustername> autoscale'
+var clusterAutoscaleCommand = cli.Command{
+	Name:      "autoscale",
+	Usage:     "autoscale CLUSTERNAME",
+	ArgsUsage: "CLUSTERNAME",
+	Description: `
+Shows the autoscaling policy of the cluster if no option is given; otherwise enables (or disables with --disable)
+the autoscaling of the nodes of the cluster, the options not given keeping their current values.`,
+
+	Flags: []cli.Flag{
+		cli.UintFlag{
+			Name:  "min",
+			Usage: "Define the minimum number of nodes",
+		},
+		cli.UintFlag{
+			Name:  "max",
+			Usage: "Define the maximum number of nodes",
+		},
+		cli.Float64Flag{
+			Name:  "scale-up-threshold",
+			Usage: "Define the usage of CPU or RAM (in percent) above which a node is added; default: 80",
+		},
+		cli.Float64Flag{
+			Name:  "scale-down-threshold",
+			Usage: "Define the usage of CPU and RAM (in percent) below which a node is removed; default: 30",
+		},
+		cli.StringFlag{
+			Name:  "scale-up-cooldown",
+			Usage: "Define the delay after a scaling before a node can be added; default: 5m",
+		},
+		cli.StringFlag{
+			Name:  "scale-down-cooldown",
+			Usage: "Define the delay after a scaling before a node can be removed; default: 15m",
+		},
+		cli.BoolFlag{
+			Name:  "disable",
+			Usage: "Disable the autoscaling of the cluster",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", clusterCommandName, c.Command.Name, c.Args())
+		err := extractClusterArgument(c)
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		for i := uint(0); i < count; i++ {
-			err := clusterInstance.DeleteLastNode(concurrency.RootTask(), availableMaster)
-			if err != nil {
-				msgs = append(msgs, fmt.Sprintf("failed to delete node #%d: %s", i+1, err.Error()))
-			}
+
+		clientSession := client.New()
+		policy, err := clientSession.Cluster.InspectAutoscaling(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
 		}
-		if len(msgs) > 0 {
-			return clitools.FailureResponse(clitools.ExitOnRPC(strings.Join(msgs, "\n")))
+		if c.NumFlags() == 0 {
+			return clitools.SuccessResponse(policy)
 		}
-		return clitools.SuccessResponse(nil)
+
+		policy.Name = clusterName
+		policy.Enabled = !c.Bool("disable")
+		if c.IsSet("min") {
+			policy.MinNodes = int32(c.Uint("min"))
+		}
+		if c.IsSet("max") {
+			policy.MaxNodes = int32(c.Uint("max"))
+		}
+		if c.IsSet("scale-up-threshold") {
+			policy.ScaleUpThreshold = float32(c.Float64("scale-up-threshold"))
+		}
+		if c.IsSet("scale-down-threshold") {
+			policy.ScaleDownThreshold = float32(c.Float64("scale-down-threshold"))
+		}
+		if c.IsSet("scale-up-cooldown") {
+			policy.ScaleUpCooldown = c.String("scale-up-cooldown")
+		}
+		if c.IsSet("scale-down-cooldown") {
+			policy.ScaleDownCooldown = c.String("scale-down-cooldown")
+		}
+		if policy.Enabled && policy.MaxNodes == 0 {
+			msg := "missing mandatory option --max to enable the autoscaling"
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(msg))
+		}
+
+		policy, err = clientSession.Cluster.SetAutoscaling(policy, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(policy)
+	},
+}
+
+// clusterCheckHealthCommand handles 'deploy cluster <clustername> check-health'
+var clusterCheckHealthCommand = cli.Command{
+	Name:      "check-health",
+	Usage:     "check-health CLUSTERNAME",
+	ArgsUsage: "CLUSTERNAME",
+	Description: `
+Probes the gateways, masters and nodes of the cluster, and displays their state. Dead nodes are replaced if the
+auto-repair of the cluster is enabled.`,
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", clusterCommandName, c.Command.Name, c.Args())
+		err := extractClusterArgument(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		health, err := client.New().Cluster.CheckHealth(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(health)
+	},
+}
+
+// clusterAutoRepairCommand handles 'deploy cluster <clustername> auto-repair'
+var clusterAutoRepairCommand = cli.Command{
+	Name:      "auto-repair",
+	Usage:     "auto-repair CLUSTERNAME",
+	ArgsUsage: "CLUSTERNAME",
+	Description: `
+Shows the health policy of the cluster if no option is given; otherwise enables (or disables with --disable) the
+replacement of the nodes found dead (failed or unreachable) by the health probes of safescaled.`,
+
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "delay",
+			Usage: "Define the delay during which a node has to be found dead before being replaced; default: 10m",
+		},
+		cli.BoolFlag{
+			Name:  "disable",
+			Usage: "Disable the replacement of dead nodes",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", clusterCommandName, c.Command.Name, c.Args())
+		err := extractClusterArgument(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession := client.New()
+		policy, err := clientSession.Cluster.InspectHealthPolicy(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		if c.NumFlags() == 0 {
+			return clitools.SuccessResponse(policy)
+		}
+
+		policy.Name = clusterName
+		policy.AutoRepair = !c.Bool("disable")
+		if c.IsSet("delay") {
+			policy.RepairDelay = c.String("delay")
+		}
+
+		policy, err = clientSession.Cluster.SetHealthPolicy(policy, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(policy)
 	},
 }
 
@@ -969,10 +1057,9 @@ var clusterDcosCommand = cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		identity := clusterInstance.GetIdentity(concurrency.RootTask())
-		if identity.Flavor != flavor.DCOS {
+		if flavor.Enum(clusterInstance.GetFlavor()) != flavor.DCOS {
 			msg := fmt.Sprintf(
-				"Can't call dcos on this cluster, its flavor isn't DCOS (%s).\n", identity.Flavor.String(),
+				"Can't call dcos on this cluster, its flavor isn't DCOS (%s).\n", clusterInstance.GetFlavorLabel(),
 			)
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.NotApplicable, msg))
 		}
@@ -1263,14 +1350,12 @@ var clusterRunCommand = cli.Command{
 
 func executeCommand(command string, files *RemoteFilesHandler, outs outputs.Enum) error {
 	logrus.Debugf("command=[%s]", command)
-	master, err := clusterInstance.FindAvailableMaster(concurrency.RootTask())
+	availableMaster, err := client.New().Cluster.FindAvailableMaster(clusterName, temporal.GetExecutionTimeout())
 	if err != nil {
-		msg := fmt.Sprintf(
-			"No masters found available for the cluster '%s': %v",
-			clusterInstance.GetIdentity(concurrency.RootTask()).Name, err.Error(),
-		)
+		msg := fmt.Sprintf("No masters found available for the cluster '%s': %v", clusterName, err.Error())
 		return clitools.ExitOnErrorWithMessage(exitcode.RPC, msg)
 	}
+	master := availableMaster.GetId()
 
 	if files != nil && files.Count() > 0 {
 		if !Debug {
@@ -1319,11 +1404,11 @@ var clusterListFeaturesCommand = cli.Command{
 
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", clusterCommandName, c.Command.Name, c.Args())
-		features, err := install.ListFeatures("cluster")
+		features, err := client.New().Cluster.ListFeatures(temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
 		}
-		return clitools.SuccessResponse(features)
+		return clitools.SuccessResponse(features.GetFeatures())
 	},
 }
 
@@ -1356,42 +1441,14 @@ var clusterAddFeatureCommand = cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		feature, err := install.NewFeature(concurrency.RootTask(), featureName)
-		if err != nil {
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
-		}
-		if feature == nil {
-			msg := fmt.Sprintf("failed to find a feature named '%s'.\n", featureName)
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.NotFound, msg))
-		}
-
-		values := install.Variables{}
-		params := c.StringSlice("param")
-		for _, k := range params {
-			res := strings.Split(k, "=")
-			if len(res[0]) > 0 {
-				values[res[0]] = strings.Join(res[1:], "=")
-			}
-		}
-
-		settings := install.Settings{}
-		settings.SkipProxy = c.Bool("skip-proxy")
-
-		target, err := install.NewClusterTarget(concurrency.RootTask(), clusterInstance)
-		if err != nil {
-			return clitools.FailureResponse(err)
-		}
-		results, err := feature.Add(target, values, settings)
+		values := extractFeatureParameters(c.StringSlice("param"))
+		err = client.New().Cluster.AddFeature(
+			clusterName, featureName, values, c.Bool("skip-proxy"), temporal.GetExecutionTimeout(),
+		)
 		if err != nil {
-			msg := fmt.Sprintf(
-				"error installing feature '%s' on cluster '%s': %s\n", featureName, clusterName, err.Error(),
-			)
-			return clitools.FailureResponse(clitools.ExitOnRPC(msg))
-		}
-		if !results.Successful() {
 			msg := fmt.Sprintf("failed to install feature '%s' on cluster '%s'", featureName, clusterName)
 			if Debug || Verbose {
-				msg += fmt.Sprintf(":\n%s", results.AllErrorMessages())
+				msg += fmt.Sprintf(":\n%s", err.Error())
 			}
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
 		}
@@ -1399,6 +1456,18 @@ var clusterAddFeatureCommand = cli.Command{
 	},
 }
 
+// extractFeatureParameters converts the "--param" flags in the form <name>=<value> to a map
+func extractFeatureParameters(params []string) map[string]string {
+	values := map[string]string{}
+	for _, k := range params {
+		res := strings.Split(k, "=")
+		if len(res[0]) > 0 {
+			values[res[0]] = strings.Join(res[1:], "=")
+		}
+	}
+	return values
+}
+
 // clusterCheckFeatureCommand handles 'deploy cluster check-feature CLUSTERNAME FEATURENAME'
 var clusterCheckFeatureCommand = cli.Command{
 	Name:      "check-feature",
@@ -1421,46 +1490,21 @@ var clusterCheckFeatureCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		feature, err := install.NewFeature(concurrency.RootTask(), featureName)
+		values := extractFeatureParameters(c.StringSlice("param"))
+		err = client.New().Cluster.CheckFeature(clusterName, featureName, values, temporal.GetExecutionTimeout())
 		if err != nil {
-			_, _ = fmt.Fprintln(os.Stderr, err.Error())
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
-		}
-		if feature == nil {
-			msg := fmt.Sprintf("failed to find a feature named '%s'.\n", featureName)
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.NotFound, msg))
-		}
-
-		values := install.Variables{}
-		params := c.StringSlice("param")
-		for _, k := range params {
-			res := strings.Split(k, "=")
-			if len(res[0]) > 0 {
-				values[res[0]] = strings.Join(res[1:], "=")
+			if status.Code(err) == codes.NotFound {
+				msg := fmt.Sprintf("Feature '%s' not found on cluster '%s'", featureName, clusterName)
+				if Verbose || Debug {
+					msg += fmt.Sprintf(":\n%s", err.Error())
+				}
+				return clitools.FailureResponse(clitools.ExitOnNotFound(msg))
 			}
-		}
-
-		settings := install.Settings{}
-
-		target, err := install.NewClusterTarget(concurrency.RootTask(), clusterInstance)
-		if err != nil {
-			return clitools.FailureResponse(err)
-		}
-		results, err := feature.Check(target, values, settings)
-		if err != nil {
 			msg := fmt.Sprintf(
 				"error checking if feature '%s' is installed on '%s': %s\n", featureName, clusterName, err.Error(),
 			)
 			return clitools.FailureResponse(clitools.ExitOnRPC(msg))
 		}
-
-		if !results.Successful() {
-			msg := fmt.Sprintf("Feature '%s' not found on cluster '%s'", featureName, clusterName)
-			if Verbose || Debug {
-				msg += fmt.Sprintf(":\n%s", results.AllErrorMessages())
-			}
-			return clitools.FailureResponse(clitools.ExitOnNotFound(msg))
-		}
 		msg := fmt.Sprintf("Feature '%s' found on cluster '%s'", featureName, clusterName)
 		return clitools.SuccessResponse(msg)
 	},
@@ -1488,42 +1532,14 @@ var clusterDeleteFeatureCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		feature, err := install.NewFeature(concurrency.RootTask(), featureName)
-		if err != nil {
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
-		}
-		if feature == nil {
-			msg := fmt.Sprintf("failed to find a feature named '%s'.\n", featureName)
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.NotFound, msg))
-		}
-
-		values := install.Variables{}
-		params := c.StringSlice("param")
-		for _, k := range params {
-			res := strings.Split(k, "=")
-			if len(res[0]) > 0 {
-				values[res[0]] = strings.Join(res[1:], "=")
-			}
-		}
-
-		settings := install.Settings{}
 		// TODO: Reverse proxy rules are not yet purged when feature is removed, but current code
-		// will try to apply them... Quick fix: Setting SkipProxy to true prevent this
-		settings.SkipProxy = true
-
-		target, err := install.NewClusterTarget(concurrency.RootTask(), clusterInstance)
-		if err != nil {
-			return clitools.FailureResponse(err)
-		}
-		results, err := feature.Remove(target, values, settings)
+		// will try to apply them... Quick fix: the daemon sets SkipProxy to true to prevent this
+		values := extractFeatureParameters(c.StringSlice("param"))
+		err = client.New().Cluster.DeleteFeature(clusterName, featureName, values, temporal.GetExecutionTimeout())
 		if err != nil {
-			msg := fmt.Sprintf("error uninstalling feature '%s' on '%s': %s\n", featureName, clusterName, err.Error())
-			return clitools.FailureResponse(clitools.ExitOnRPC(msg))
-		}
-		if !results.Successful() {
 			msg := fmt.Sprintf("failed to delete feature '%s' from cluster '%s'", featureName, clusterName)
 			if Verbose || Debug {
-				msg += fmt.Sprintf(":\n%s\n", results.AllErrorMessages())
+				msg += fmt.Sprintf(":\n%s\n", err.Error())
 			}
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
 		}
@@ -1583,21 +1599,16 @@ var clusterNodeListCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		hostClt := client.New().Host
-		var formatted []map[string]interface{}
+		list, err := client.New().Cluster.ListNodes(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
 
-		list := clusterInstance.ListNodeIDs(concurrency.RootTask())
-		for _, i := range list {
-			host, err := hostClt.Inspect(i, temporal.GetExecutionTimeout())
-			if err != nil {
-				msg := fmt.Sprintf("failed to get data for node '%s': %s. Ignoring.", i, err.Error())
-				// fmt.Println(msg)
-				logrus.Warnln(msg)
-				continue
-			}
+		var formatted []map[string]interface{}
+		for _, node := range list.GetNodes() {
 			formatted = append(
 				formatted, map[string]interface{}{
-					"name": host.Name,
+					"name": node.GetName(),
 				},
 			)
 		}
@@ -1806,22 +1817,17 @@ var clusterMasterListCommand = cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		hostClt := client.New().Host
-		var formatted []map[string]interface{}
+		list, err := client.New().Cluster.ListMasters(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
 
-		list := clusterInstance.ListMasterIDs(concurrency.RootTask())
-		for _, i := range list {
-			host, err := hostClt.Inspect(i, temporal.GetExecutionTimeout())
-			if err != nil {
-				msg := fmt.Sprintf("failed to get data for master '%s': %s. Ignoring.", i, err.Error())
-				fmt.Println(msg)
-				logrus.Warnln(msg)
-				continue
-			}
+		var formatted []map[string]interface{}
+		for _, master := range list.GetNodes() {
 			formatted = append(
 				formatted, map[string]interface{}{
-					"name": host.Name,
-					"id":   host.Id,
+					"name": master.GetName(),
+					"id":   master.GetId(),
 				},
 			)
 		}
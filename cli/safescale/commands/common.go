@@ -23,6 +23,7 @@ import (
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
@@ -70,3 +71,12 @@ func extractHostArgument(c *cli.Context, hostnamePos int) error {
 
 	return nil
 }
+
+// extractLabels converts the "--label" flags in the form <key>=<value> to a map
+func extractLabels(c *cli.Context) (map[string]string, error) {
+	labels, err := abstract.ParseLabels(c.StringSlice("label"))
+	if err != nil {
+		return nil, clitools.FailureResponse(clitools.ExitOnInvalidOption(err.Error()))
+	}
+	return labels, nil
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var dataCmdName = "data"
+
+// DataCmd data command
+var DataCmd = cli.Command{
+	Name:  "data",
+	Usage: "data COMMAND",
+	Subcommands: []cli.Command{
+		dataPush,
+		dataGet,
+		dataList,
+		dataDelete,
+	},
+}
+
+var dataPush = cli.Command{
+	Name:      "push",
+	Usage:     "Stores a local file, encrypted and split over one or several buckets",
+	ArgsUsage: "<Local_path>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "Name of the stored file (default: base name of <Local_path>)",
+		},
+		cli.StringSliceFlag{
+			Name:  "bucket",
+			Usage: "Bucket receiving chunks of the file, as [<tenant>:]<bucket>; may be used several times (default: data bucket of the current tenant)",
+		},
+		cli.IntFlag{
+			Name:  "data-shards",
+			Usage: "Number of data shards per chunk (erasure coding)",
+		},
+		cli.IntFlag{
+			Name:  "parity-shards",
+			Usage: "Number of parity shards per chunk (erasure coding)",
+		},
+		cli.IntFlag{
+			Name:  "replicas",
+			Usage: "Number of copies of each chunk (replication, exclusive with erasure coding)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", dataCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Local_path>."))
+		}
+
+		localPath, err := filepath.Abs(c.Args().First())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
+		}
+		name := c.String("name")
+		if name == "" {
+			name = filepath.Base(localPath)
+		}
+		file := &pb.File{
+			LocalPath:    localPath,
+			Name:         name,
+			Buckets:      c.StringSlice("bucket"),
+			DataShards:   int32(c.Int("data-shards")),
+			ParityShards: int32(c.Int("parity-shards")),
+			Replicas:     int32(c.Int("replicas")),
+		}
+		err = client.New().Data.Push(file, temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "push of data", true,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var dataGet = cli.Command{
+	Name:      "get",
+	Usage:     "Restores a stored file in a local file",
+	ArgsUsage: "<File_name> [<Local_path>]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", dataCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 1 || c.NArg() > 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <File_name>."))
+		}
+
+		name := c.Args().First()
+		localPath := c.Args().Get(1)
+		if localPath == "" {
+			localPath = filepath.Base(name)
+		}
+		localPath, err := filepath.Abs(localPath)
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
+		}
+		err = client.New().Data.Get(localPath, name, temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "get of data", true,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var dataList = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List stored files",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", dataCmdName, c.Command.Name, c.Args())
+		resp, err := client.New().Data.List(temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "list of data", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
+var dataDelete = cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"remove", "rm"},
+	Usage:     "Delete a stored file",
+	ArgsUsage: "<File_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", dataCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <File_name>."))
+		}
+
+		err := client.New().Data.Delete(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "deletion of data", true,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
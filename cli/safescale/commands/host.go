@@ -28,6 +28,7 @@ import (
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
 	"github.com/CS-SI/SafeScale/lib/server/install"
 	"github.com/CS-SI/SafeScale/lib/utils"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
@@ -53,6 +54,7 @@ var HostCmd = cli.Command{
 		hostReboot,
 		hostStart,
 		hostStop,
+		hostCaptureImage,
 		hostCheckFeatureCommand,
 		hostAddFeatureCommand,
 		hostDeleteFeatureCommand,
@@ -114,6 +116,32 @@ var hostStop = cli.Command{
 	},
 }
 
+var hostCaptureImage = cli.Command{
+	Name:      "capture-image",
+	Usage:     "Create an image from the root disk of Host",
+	ArgsUsage: "<Host_name|Host_ID> <Image_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", hostCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_name> and/or <Image_name>."))
+		}
+		image, err := client.New().Host.CaptureImage(c.Args().Get(0), c.Args().Get(1), temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "capture of image of host", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(image)
+	},
+}
+
 var hostReboot = cli.Command{
 	Name:      "reboot",
 	Usage:     "reboot Host",
@@ -151,10 +179,18 @@ var hostList = cli.Command{
 			Name:  "all",
 			Usage: "List all hosts on tenant (not only those created by SafeScale)",
 		},
+		cli.StringSliceFlag{
+			Name:  "label, l",
+			Usage: "Lists only the hosts carrying this label, in the form <key>=<value> (can be used several times)",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", hostCmdName, c.Command.Name, c.Args())
-		hosts, err := client.New().Host.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		labels, err := extractLabels(c)
+		if err != nil {
+			return err
+		}
+		hosts, err := client.New().Host.List(c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(
 				clitools.ExitOnRPC(
@@ -270,6 +306,27 @@ var hostCreate = cli.Command{
 			Name:  "keep-on-failure, k",
 			Usage: "If set, the abstract are not deleted on failure (default: not set)",
 		},
+		cli.StringSliceFlag{
+			Name:  "label, l",
+			Usage: "Adds a label to the host, in the form <key>=<value> (can be used several times)",
+		},
+		cli.BoolFlag{
+			Name:  "estimate",
+			Usage: "Displays the hourly and monthly cost of the host instead of creating it",
+		},
+		cli.StringFlag{
+			Name:  "zone",
+			Usage: "Availability zone where to create the host (default: zone of the tenant)",
+		},
+		cli.StringFlag{
+			Name:  "server-group",
+			Usage: "Name of the group of hosts to which the placement policy applies",
+		},
+		cli.StringFlag{
+			Name:  "placement",
+			Value: "none",
+			Usage: "Placement of the host relatively to the other hosts of the server group; can be none, affinity or anti-affinity",
+		},
 		cli.StringFlag{
 			Name: "S, sizing",
 			Usage: `Describe sizing of host in format "<component><operator><value>[,...]" where:
@@ -337,6 +394,32 @@ var hostCreate = cli.Command{
 		if err != nil {
 			return err
 		}
+		def.Labels, err = extractLabels(c)
+		if err != nil {
+			return err
+		}
+		policy, err := placementpolicy.Parse(c.String("placement"))
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(err.Error()))
+		}
+		def.Zone = c.String("zone")
+		def.ServerGroup = c.String("server-group")
+		def.Placement = pb.PlacementPolicy(policy)
+		if c.Bool("estimate") {
+			estimate, err := client.New().Host.Estimate(def, temporal.GetExecutionTimeout())
+			if err != nil {
+				return clitools.FailureResponse(
+					clitools.ExitOnRPC(
+						utils.Capitalize(
+							client.DecorateError(
+								err, "estimation of host", false,
+							).Error(),
+						),
+					),
+				)
+			}
+			return clitools.SuccessResponse(estimate)
+		}
 		resp, err := client.New().Host.Create(def, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(
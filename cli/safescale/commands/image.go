@@ -34,6 +34,7 @@ var ImageCmd = cli.Command{
 	Usage: "image COMMAND",
 	Subcommands: []cli.Command{
 		imageList,
+		imageDelete,
 	},
 }
 
@@ -64,3 +65,30 @@ var imageList = cli.Command{
 		return clitools.SuccessResponse(images.GetImages())
 	},
 }
+
+var imageDelete = cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Delete an image",
+	ArgsUsage: "<Image_name|Image_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", imageCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Image_name|Image_ID>."))
+		}
+		err := client.New().Image.Delete(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "deletion of image", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
@@ -53,10 +53,18 @@ var networkList = cli.Command{
 			Name:  "all",
 			Usage: "List all Networks on tenant (not only those created by SafeScale)",
 		},
+		cli.StringSliceFlag{
+			Name:  "label, l",
+			Usage: "Lists only the Networks carrying this label, in the form <key>=<value> (can be used several times)",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
-		networks, err := client.New().Network.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		labels, err := extractLabels(c)
+		if err != nil {
+			return err
+		}
+		networks, err := client.New().Network.List(c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(
 				clitools.ExitOnRPC(
@@ -224,6 +232,10 @@ var networkCreate = cli.Command{
 			Name:  "keep-on-failure, k",
 			Usage: "If set, the abstract are not deleted on failure (default: not set)",
 		},
+		cli.StringSliceFlag{
+			Name:  "label, l",
+			Usage: "Adds a label to the network, in the form <key>=<value> (can be used several times)",
+		},
 		cli.StringFlag{
 			Name: "S, sizing",
 			Usage: `Describe sizing of network gateway in format "<component><operator><value>[,...]" where:
@@ -271,6 +283,10 @@ var networkCreate = cli.Command{
 		if err != nil {
 			return err
 		}
+		labels, err := extractLabels(c)
+		if err != nil {
+			return err
+		}
 		netdef := pb.NetworkDefinition{
 			Cidr:     c.String("cidr"),
 			Name:     c.Args().Get(0),
@@ -282,6 +298,7 @@ var networkCreate = cli.Command{
 				Sizing:  def.Sizing,
 			},
 			KeepOnFailure: c.Bool("keep-on-failure"),
+			Labels:        labels,
 		}
 		network, err := client.New().Network.Create(&netdef, temporal.GetExecutionTimeout())
 		if err != nil {
@@ -0,0 +1,356 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var securityGroupCmdName = "security-group"
+
+// SecurityGroupCmd command
+var SecurityGroupCmd = cli.Command{
+	Name:    "security-group",
+	Aliases: []string{"sg"},
+	Usage:   "security-group COMMAND",
+	Subcommands: []cli.Command{
+		securityGroupBind,
+		securityGroupCreate,
+		securityGroupDelete,
+		securityGroupInspect,
+		securityGroupList,
+		securityGroupRule,
+		securityGroupUnbind,
+	},
+}
+
+var securityGroupList = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List available security groups",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		list, err := client.New().SecurityGroup.List(temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "list of security groups", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(list.GetSecurityGroups())
+	},
+}
+
+var securityGroupCreate = cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new"},
+	Usage:     "create a security group",
+	ArgsUsage: "<security_group_name>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "description",
+			Value: "",
+			Usage: "Describes the usage of the security group",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <security_group_name>."))
+		}
+
+		def := &pb.SecurityGroupDefinition{
+			Name:        c.Args().First(),
+			Description: c.String("description"),
+		}
+		sg, err := client.New().SecurityGroup.Create(def, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "creation of security group", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(sg)
+	},
+}
+
+var securityGroupInspect = cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "inspect a security group",
+	ArgsUsage: "<security_group_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <security_group_name>."))
+		}
+
+		sg, err := client.New().SecurityGroup.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "inspection of security group", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(sg)
+	},
+}
+
+var securityGroupDelete = cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "delete security group(s)",
+	ArgsUsage: "<security_group_name> [<security_group_name>...]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <security_group_name>."))
+		}
+
+		var list []string
+		list = append(list, c.Args().First())
+		list = append(list, c.Args().Tail()...)
+		for _, ref := range list {
+			err := client.New().SecurityGroup.Delete(ref, temporal.GetExecutionTimeout())
+			if err != nil {
+				return clitools.FailureResponse(
+					clitools.ExitOnRPC(
+						utils.Capitalize(
+							client.DecorateError(
+								err, "deletion of security group", false,
+							).Error(),
+						),
+					),
+				)
+			}
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var securityGroupRule = cli.Command{
+	Name:  "rule",
+	Usage: "manage rules of a security group",
+	Subcommands: []cli.Command{
+		securityGroupRuleAdd,
+		securityGroupRuleDelete,
+	},
+}
+
+var securityGroupRuleAdd = cli.Command{
+	Name:      "add",
+	Aliases:   []string{"new"},
+	Usage:     "add a rule to a security group",
+	ArgsUsage: "<security_group_name>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "direction",
+			Value: "ingress",
+			Usage: "Direction of the traffic concerned by the rule (ingress or egress)",
+		},
+		cli.StringFlag{
+			Name:  "protocol",
+			Value: "tcp",
+			Usage: "Protocol concerned by the rule (tcp, udp, icmp); empty means any",
+		},
+		cli.IntFlag{
+			Name:  "port-from",
+			Value: 0,
+			Usage: "First port of the range",
+		},
+		cli.IntFlag{
+			Name:  "port-to",
+			Value: 0,
+			Usage: "Last port of the range (defaults to port-from)",
+		},
+		cli.StringFlag{
+			Name:  "cidr",
+			Value: "0.0.0.0/0",
+			Usage: "Remote network concerned by the rule",
+		},
+		cli.BoolFlag{
+			Name:  "ipv6",
+			Usage: "If used, the rule concerns IPv6 traffic",
+		},
+		cli.StringFlag{
+			Name:  "description",
+			Value: "",
+			Usage: "Describes the rule",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <security_group_name>."))
+		}
+
+		rule := &pb.SecurityGroupRule{
+			Description: c.String("description"),
+			Direction:   c.String("direction"),
+			EtherType:   4,
+			Protocol:    c.String("protocol"),
+			PortFrom:    int32(c.Int("port-from")),
+			PortTo:      int32(c.Int("port-to")),
+			Cidr:        c.String("cidr"),
+		}
+		if c.Bool("ipv6") {
+			rule.EtherType = 6
+			if !c.IsSet("cidr") {
+				rule.Cidr = "::/0"
+			}
+		}
+		sg, err := client.New().SecurityGroup.AddRule(c.Args().First(), rule, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "addition of rule to security group", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(sg)
+	},
+}
+
+var securityGroupRuleDelete = cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "delete a rule from a security group",
+	ArgsUsage: "<security_group_name> <rule_id>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <security_group_name> and/or <rule_id>."))
+		}
+
+		sg, err := client.New().SecurityGroup.DeleteRule(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "deletion of rule from security group", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(sg)
+	},
+}
+
+var securityGroupBind = cli.Command{
+	Name:      "bind",
+	Usage:     "apply a security group to a host or a network",
+	ArgsUsage: "host|network <security_group_name> <host_or_network_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		return securityGroupBindAction(c, true)
+	},
+}
+
+var securityGroupUnbind = cli.Command{
+	Name:      "unbind",
+	Usage:     "remove a security group from a host or a network",
+	ArgsUsage: "host|network <security_group_name> <host_or_network_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		return securityGroupBindAction(c, false)
+	},
+}
+
+// securityGroupBindAction binds (if bind is true) or unbinds (if bind is false) a security group to/from a host or a network
+func securityGroupBindAction(c *cli.Context, bind bool) error {
+	if c.NArg() != 3 {
+		_ = cli.ShowSubcommandHelp(c)
+		return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument host|network, <security_group_name> and/or <host_or_network_name>."))
+	}
+
+	sgRef, targetRef := c.Args().Get(1), c.Args().Get(2)
+	sgClient := client.New().SecurityGroup
+	var (
+		err  error
+		what string
+	)
+	switch c.Args().Get(0) {
+	case "host":
+		what = "host"
+		if bind {
+			err = sgClient.BindToHost(sgRef, targetRef, temporal.GetExecutionTimeout())
+		} else {
+			err = sgClient.UnbindFromHost(sgRef, targetRef, temporal.GetExecutionTimeout())
+		}
+	case "network", "net":
+		what = "network"
+		if bind {
+			err = sgClient.BindToNetwork(sgRef, targetRef, temporal.GetExecutionTimeout())
+		} else {
+			err = sgClient.UnbindFromNetwork(sgRef, targetRef, temporal.GetExecutionTimeout())
+		}
+	default:
+		_ = cli.ShowSubcommandHelp(c)
+		return clitools.FailureResponse(clitools.ExitOnInvalidArgument(fmt.Sprintf("Invalid target type '%s'; must be 'host' or 'network'.", c.Args().Get(0))))
+	}
+	if err != nil {
+		action := "binding"
+		if !bind {
+			action = "unbinding"
+		}
+		return clitools.FailureResponse(
+			clitools.ExitOnRPC(
+				utils.Capitalize(
+					client.DecorateError(
+						err, fmt.Sprintf("%s of security group to %s", action, what), false,
+					).Error(),
+				),
+			),
+		)
+	}
+	return clitools.SuccessResponse(nil)
+}
@@ -17,6 +17,8 @@
 package commands
 
 import (
+	"path/filepath"
+
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
@@ -36,6 +38,9 @@ var TenantCmd = cli.Command{
 		tenantList,
 		tenantGet,
 		tenantSet,
+		tenantQuotas,
+		tenantMetadata,
+		tenantFsck,
 		// tenantStorageList,
 		// tenantStorageGet,
 		// tenantStorageSet,
@@ -111,6 +116,221 @@ var tenantSet = cli.Command{
 	},
 }
 
+var tenantMetadata = cli.Command{
+	Name:  "metadata",
+	Usage: "manage metadata of current tenant",
+	Subcommands: []cli.Command{
+		tenantMetadataMigrate,
+		tenantMetadataExport,
+		tenantMetadataImport,
+		tenantMetadataBackup,
+		tenantMetadataRekey,
+	},
+}
+
+var tenantMetadataMigrate = cli.Command{
+	Name:  "migrate",
+	Usage: "Upgrades the properties of all the metadata of current tenant to their latest version",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Reports the migrations to apply without saving them",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		report, err := client.New().Tenant.MigrateMetadata(c.Bool("dry-run"), temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "migrate tenant metadata", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(report)
+	},
+}
+
+var tenantMetadataExport = cli.Command{
+	Name:  "export",
+	Usage: "Writes an archive of the decrypted metadata of current tenant; the archive has to be kept safe",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "Path of the archive to write (tar.gz)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		if c.String("out") == "" {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("Missing mandatory option --out."))
+		}
+		path, err := filepath.Abs(c.String("out"))
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(err.Error()))
+		}
+		report, err := client.New().Tenant.ExportMetadata(path, temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "export tenant metadata", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(report)
+	},
+}
+
+var tenantMetadataImport = cli.Command{
+	Name:  "import",
+	Usage: "Writes in the metadata of current tenant the content of an archive produced by 'export', encrypted with the key of the tenant",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "in",
+			Usage: "Path of the archive to read (tar.gz)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		if c.String("in") == "" {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("Missing mandatory option --in."))
+		}
+		path, err := filepath.Abs(c.String("in"))
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(err.Error()))
+		}
+		report, err := client.New().Tenant.ImportMetadata(path, temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "import tenant metadata", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(report)
+	},
+}
+
+var tenantMetadataBackup = cli.Command{
+	Name:  "backup",
+	Usage: "Writes an archive of the metadata of current tenant in a bucket",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "bucket",
+			Usage: "Bucket receiving the archive; cannot be the metadata bucket",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		if c.String("bucket") == "" {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("Missing mandatory option --bucket."))
+		}
+		report, err := client.New().Tenant.BackupMetadata(c.String("bucket"), temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "backup tenant metadata", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(report)
+	},
+}
+
+var tenantMetadataRekey = cli.Command{
+	Name:  "rekey",
+	Usage: "Re-encrypts the metadata of current tenant with a new key; can be run again to resume an interrupted rotation",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "new-key",
+			Usage: "Passphrase of the new key; becomes the value of 'CryptKey' in section 'metadata' of the tenant",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		if c.String("new-key") == "" {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("Missing mandatory option --new-key."))
+		}
+		report, err := client.New().Tenant.RekeyMetadata(c.String("new-key"), temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "rekey tenant metadata", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(report)
+	},
+}
+
+var tenantFsck = cli.Command{
+	Name:  "fsck",
+	Usage: "Checks the metadata of current tenant against the resources of the provider",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "repair",
+			Usage: "Fixes the metadata; resources of the provider without metadata are only reported",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		report, err := client.New().Tenant.Fsck(c.Bool("repair"), temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "check tenant metadata", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(report)
+	},
+}
+
+var tenantQuotas = cli.Command{
+	Name:  "quotas",
+	Usage: "Show quotas and resource usage of current tenant",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		quotas, err := client.New().Tenant.Quotas(temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "get tenant quotas", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(quotas)
+	},
+}
+
 // var tenantStorageList = cli.Command{
 // 	Name:    "storage-list",
 // 	Aliases: []string{"storage-ls"},
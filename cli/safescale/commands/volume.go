@@ -44,6 +44,7 @@ var VolumeCmd = cli.Command{
 		volumeCreate,
 		volumeAttach,
 		volumeDetach,
+		volumeSnapshot,
 	},
 }
 
@@ -56,10 +57,18 @@ var volumeList = cli.Command{
 			Name:  "all",
 			Usage: "List all Volumes on tenant (not only those created by SafeScale)",
 		},
+		cli.StringSliceFlag{
+			Name:  "label, l",
+			Usage: "Lists only the Volumes carrying this label, in the form <key>=<value> (can be used several times)",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
-		volumes, err := client.New().Volume.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		labels, err := extractLabels(c)
+		if err != nil {
+			return err
+		}
+		volumes, err := client.New().Volume.List(c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(
 				clitools.ExitOnRPC(
@@ -151,6 +160,10 @@ var volumeCreate = cli.Command{
 			Value: "HDD",
 			Usage: fmt.Sprintf("Allowed values: %s", getAllowedSpeeds()),
 		},
+		cli.StringSliceFlag{
+			Name:  "label, l",
+			Usage: "Adds a label to the volume, in the form <key>=<value> (can be used several times)",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
@@ -174,10 +187,15 @@ var volumeCreate = cli.Command{
 				),
 			)
 		}
+		labels, err := extractLabels(c)
+		if err != nil {
+			return err
+		}
 		def := pb.VolumeDefinition{
-			Name:  c.Args().First(),
-			Size:  volSize,
-			Speed: pb.VolumeSpeed(volSpeed),
+			Name:   c.Args().First(),
+			Size:   volSize,
+			Speed:  pb.VolumeSpeed(volSpeed),
+			Labels: labels,
 		}
 
 		volume, err := client.New().Volume.Create(&def, temporal.GetExecutionTimeout())
@@ -272,6 +290,156 @@ var volumeDetach = cli.Command{
 	},
 }
 
+var volumeSnapshot = cli.Command{
+	Name:  "snapshot",
+	Usage: "manage snapshots of volumes",
+	Subcommands: []cli.Command{
+		volumeSnapshotCreate,
+		volumeSnapshotList,
+		volumeSnapshotDelete,
+		volumeSnapshotRestore,
+	},
+}
+
+var volumeSnapshotCreate = cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new"},
+	Usage:     "Create a snapshot of a volume",
+	ArgsUsage: "<Volume_name|Volume_ID> <Snapshot_name>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "description",
+			Usage: "Describes the snapshot",
+		},
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "If used, allows to snapshot a volume attached to an host",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name|Volume_ID> and/or <Snapshot_name>."))
+		}
+
+		def := pb.VolumeSnapshotDefinition{
+			Volume:      &pb.Reference{Name: c.Args().Get(0)},
+			Name:        c.Args().Get(1),
+			Description: c.String("description"),
+			Force:       c.Bool("force"),
+		}
+		snapshot, err := client.New().Volume.CreateSnapshot(&def, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "creation of volume snapshot", true,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(snapshot)
+	},
+}
+
+var volumeSnapshotList = cli.Command{
+	Name:      "list",
+	Aliases:   []string{"ls"},
+	Usage:     "List snapshots of volumes",
+	ArgsUsage: "[<Volume_name|Volume_ID>]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
+		snapshots, err := client.New().Volume.ListSnapshots(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "list of volume snapshots", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(snapshots.Snapshots)
+	},
+}
+
+var volumeSnapshotDelete = cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Delete a volume snapshot",
+	ArgsUsage: "<Snapshot_name|Snapshot_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Snapshot_name|Snapshot_ID>."))
+		}
+
+		err := client.New().Volume.DeleteSnapshot(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "deletion of volume snapshot", false,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var volumeSnapshotRestore = cli.Command{
+	Name:      "restore",
+	Usage:     "Create a new volume from a volume snapshot",
+	ArgsUsage: "<Snapshot_name|Snapshot_ID> <Volume_name>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "speed",
+			Value: "HDD",
+			Usage: fmt.Sprintf("Allowed values: %s", getAllowedSpeeds()),
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Snapshot_name|Snapshot_ID> and/or <Volume_name>."))
+		}
+
+		speed := c.String("speed")
+		volSpeed, ok := pb.VolumeSpeed_value[speed]
+		if !ok {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid speed '%s'", speed)))
+		}
+		req := pb.VolumeSnapshotRestoreRequest{
+			Snapshot: &pb.Reference{Name: c.Args().Get(0)},
+			Name:     c.Args().Get(1),
+			Speed:    pb.VolumeSpeed(volSpeed),
+		}
+		volume, err := client.New().Volume.RestoreSnapshot(&req, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(
+				clitools.ExitOnRPC(
+					utils.Capitalize(
+						client.DecorateError(
+							err, "restoration of volume snapshot", true,
+						).Error(),
+					),
+				),
+			)
+		}
+		return clitools.SuccessResponse(toDisplaybleVolume(volume))
+	},
+}
+
 type volumeInfoDisplayable struct {
 	ID        string
 	Name      string
@@ -281,13 +449,15 @@ type volumeInfoDisplayable struct {
 	MountPath string
 	Format    string
 	Device    string
+	Labels    map[string]string `json:",omitempty"`
 }
 
 type volumeDisplayable struct {
-	ID    string
-	Name  string
-	Speed string
-	Size  int32
+	ID     string
+	Name   string
+	Speed  string
+	Size   int32
+	Labels map[string]string `json:",omitempty"`
 }
 
 func toDisplaybleVolumeInfo(volumeInfo *pb.VolumeInfo) *volumeInfoDisplayable {
@@ -300,6 +470,7 @@ func toDisplaybleVolumeInfo(volumeInfo *pb.VolumeInfo) *volumeInfoDisplayable {
 		volumeInfo.GetMountPath(),
 		volumeInfo.GetFormat(),
 		volumeInfo.GetDevice(),
+		volumeInfo.GetLabels(),
 	}
 }
 
@@ -309,6 +480,7 @@ func toDisplaybleVolume(volumeInfo *pb.Volume) *volumeDisplayable {
 		volumeInfo.GetName(),
 		pb.VolumeSpeed_name[int32(volumeInfo.GetSpeed())],
 		volumeInfo.GetSize(),
+		volumeInfo.GetLabels(),
 	}
 }
 
@@ -157,6 +157,9 @@ func main() {
 		return nil
 	}
 
+	app.Commands = append(app.Commands, commands.AuditCmd)
+	sort.Sort(cli.CommandsByName(commands.AuditCmd.Subcommands))
+
 	app.Commands = append(app.Commands, commands.NetworkCmd)
 	sort.Sort(cli.CommandsByName(commands.NetworkCmd.Subcommands))
 
@@ -175,6 +178,9 @@ func main() {
 	app.Commands = append(app.Commands, commands.BucketCmd)
 	sort.Sort(cli.CommandsByName(commands.BucketCmd.Subcommands))
 
+	app.Commands = append(app.Commands, commands.DataCmd)
+	sort.Sort(cli.CommandsByName(commands.DataCmd.Subcommands))
+
 	app.Commands = append(app.Commands, commands.ShareCmd)
 	sort.Sort(cli.CommandsByName(commands.ShareCmd.Subcommands))
 
@@ -187,6 +193,9 @@ func main() {
 	app.Commands = append(app.Commands, commands.ClusterCommand)
 	sort.Sort(cli.CommandsByName(commands.ClusterCommand.Subcommands))
 
+	app.Commands = append(app.Commands, commands.SecurityGroupCmd)
+	sort.Sort(cli.CommandsByName(commands.SecurityGroupCmd.Subcommands))
+
 	sort.Sort(cli.CommandsByName(app.Commands))
 
 	// err := app.Run(os.Args)
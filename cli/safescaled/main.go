@@ -26,6 +26,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -33,7 +34,9 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/listeners"
 	"github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
@@ -80,6 +83,52 @@ func work(version string) {
 		suffix = suffixCandidate
 	}
 
+	// DEV VAR
+	lifecycleInterval := abstract.DefaultBucketLifecycleInterval
+	if intervalCandidate := os.Getenv("SAFESCALE_LIFECYCLE_INTERVAL"); intervalCandidate != "" {
+		interval, err := time.ParseDuration(intervalCandidate)
+		if err == nil && interval > 0 {
+			lifecycleInterval = interval
+		}
+	}
+
+	// DEV VAR
+	backupBucket := os.Getenv("SAFESCALE_METADATA_BACKUP_BUCKET")
+	backupInterval := abstract.DefaultMetadataBackupInterval
+	if intervalCandidate := os.Getenv("SAFESCALE_METADATA_BACKUP_INTERVAL"); intervalCandidate != "" {
+		interval, err := time.ParseDuration(intervalCandidate)
+		if err == nil && interval > 0 {
+			backupInterval = interval
+		}
+	}
+
+	// DEV VAR
+	auditRetention := abstract.DefaultAuditRetention
+	if retentionCandidate := os.Getenv("SAFESCALE_AUDIT_RETENTION"); retentionCandidate != "" {
+		retention, err := time.ParseDuration(retentionCandidate)
+		if err == nil && retention > 0 {
+			auditRetention = retention
+		}
+	}
+
+	// DEV VAR
+	autoscaleInterval := control.DefaultAutoscaleInterval
+	if intervalCandidate := os.Getenv("SAFESCALE_AUTOSCALE_INTERVAL"); intervalCandidate != "" {
+		interval, err := time.ParseDuration(intervalCandidate)
+		if err == nil && interval > 0 {
+			autoscaleInterval = interval
+		}
+	}
+
+	// DEV VAR
+	healthCheckInterval := control.DefaultHealthCheckInterval
+	if intervalCandidate := os.Getenv("SAFESCALE_HEALTH_CHECK_INTERVAL"); intervalCandidate != "" {
+		interval, err := time.ParseDuration(intervalCandidate)
+		if err == nil && interval > 0 {
+			healthCheckInterval = interval
+		}
+	}
+
 	envVars := os.Environ()
 	for _, envVar := range envVars {
 		if strings.HasPrefix(envVar, "SAFESCALE") {
@@ -96,18 +145,34 @@ func work(version string) {
 	s := grpc.NewServer()
 
 	logrus.Infoln("Registering services")
+	pb.RegisterAuditServiceServer(s, &listeners.AuditListener{})
 	pb.RegisterBucketServiceServer(s, &listeners.BucketListener{})
-	// pb.RegisterDataServiceServer(s, &listeners.DataListener{})
+	pb.RegisterClusterServiceServer(s, &listeners.ClusterListener{})
+	pb.RegisterDataServiceServer(s, &listeners.DataListener{})
 	pb.RegisterHostServiceServer(s, &listeners.HostListener{})
 	pb.RegisterImageServiceServer(s, &listeners.ImageListener{})
 	pb.RegisterJobServiceServer(s, &listeners.JobManagerListener{})
 	pb.RegisterNetworkServiceServer(s, &listeners.NetworkListener{})
+	pb.RegisterSecurityGroupServiceServer(s, &listeners.SecurityGroupListener{})
 	pb.RegisterShareServiceServer(s, &listeners.ShareListener{})
 	pb.RegisterSshServiceServer(s, &listeners.SSHListener{})
 	pb.RegisterTemplateServiceServer(s, &listeners.TemplateListener{})
 	pb.RegisterTenantServiceServer(s, &listeners.TenantListener{})
 	pb.RegisterVolumeServiceServer(s, &listeners.VolumeListener{})
 
+	logrus.Infof("Enforcing lifecycle rules of buckets every %s", lifecycleInterval)
+	go listeners.EnforceBucketLifecycles(lifecycleInterval)
+	if backupBucket != "" {
+		logrus.Infof("Backing up metadata in bucket '%s' every %s", backupBucket, backupInterval)
+		go listeners.BackupMetadataPeriodically(backupBucket, backupInterval)
+	}
+	logrus.Infof("Removing records of audit log older than %s", auditRetention)
+	go listeners.PurgeAuditLogPeriodically(auditRetention)
+	logrus.Infof("Applying autoscaling policies of clusters every %s", autoscaleInterval)
+	go listeners.AutoscaleClustersPeriodically(autoscaleInterval)
+	logrus.Infof("Checking health of clusters every %s", healthCheckInterval)
+	go listeners.CheckClustersHealthPeriodically(healthCheckInterval)
+
 	// logrus.Println("Initializing service factory")
 	// commands.InitServiceFactory()
 
@@ -407,7 +407,8 @@ func analyzeTenant(group *sync.WaitGroup, theTenant string) (err error) {
 			hostHandler := handlers.NewHostHandler(serviceProvider)
 
 			host, err := hostHandler.Create(
-				context.Background(), hostName, network.Name, "Ubuntu 18.04", true, template.Name, false, "", false,
+				context.Background(), hostName, network.Name, "Ubuntu 18.04", true, template.Name, false, "", false, nil,
+				abstract.HostPlacement{},
 			)
 			if err != nil {
 				logrus.Warnf("template [%s] host '%s': error creation: %v\n", template.Name, hostName, err.Error())
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// audit is the part of the safescale client handling the audit log
+type audit struct {
+	session *Session
+}
+
+// List returns the records of the audit log since 'since' (a duration or a date), concerning 'resource' if not empty
+func (a *audit) List(since, resource string, timeout time.Duration) (*pb.AuditRecordList, error) {
+	a.session.Connect()
+	defer a.session.Disconnect()
+	service := pb.NewAuditServiceClient(a.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.List(ctxTo, &pb.AuditListRequest{Since: since, Resource: resource})
+}
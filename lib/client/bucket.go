@@ -61,7 +61,7 @@ func (c *bucket) List(timeout time.Duration) (_ *pb.BucketList, err error) {
 }
 
 // Create ...
-func (c *bucket) Create(name string, timeout time.Duration) error {
+func (c *bucket) Create(name string, encrypted bool, passphrase string, timeout time.Duration) error {
 	c.session.Connect()
 	defer c.session.Disconnect()
 
@@ -81,7 +81,7 @@ func (c *bucket) Create(name string, timeout time.Duration) error {
 		ctxTo = ctx
 	}
 
-	_, err = service.Create(ctxTo, &pb.Bucket{Name: name})
+	_, err = service.Create(ctxTo, &pb.Bucket{Name: name, Encrypted: encrypted, Passphrase: passphrase})
 
 	return err
 }
@@ -298,3 +298,134 @@ func (c *bucket) Unmount(bucketName, hostName string, timeout time.Duration) err
 
 	return err
 }
+
+// Sync replicates the objects of bucket 'source' in bucket 'destination', both in the form [<tenant>:]<bucket>
+func (c *bucket) Sync(source, destination string, delete bool, concurrency int, timeout time.Duration) (*pb.BucketSyncReport, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewBucketServiceClient(c.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.Sync(
+		ctxTo, &pb.BucketSyncRequest{
+			Source:      source,
+			Destination: destination,
+			Delete:      delete,
+			Concurrency: int32(concurrency),
+		},
+	)
+}
+
+// SetLifecycle replaces the lifecycle rules of a bucket
+func (c *bucket) SetLifecycle(bucketName string, rules []*pb.BucketLifecycleRule, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewBucketServiceClient(c.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	_, err = service.SetLifecycle(ctxTo, &pb.BucketLifecycle{Bucket: bucketName, Rules: rules})
+	return err
+}
+
+// GetLifecycle returns the lifecycle rules of a bucket
+func (c *bucket) GetLifecycle(bucketName string, timeout time.Duration) (*pb.BucketLifecycle, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewBucketServiceClient(c.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.GetLifecycle(ctxTo, &pb.Bucket{Name: bucketName})
+}
+
+// ClearLifecycle removes the lifecycle rules of a bucket
+func (c *bucket) ClearLifecycle(bucketName string, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewBucketServiceClient(c.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	_, err = service.ClearLifecycle(ctxTo, &pb.Bucket{Name: bucketName})
+	return err
+}
+
+// PresignObject returns an URL giving access to an object during ttl, to download it or, if upload is true, to upload it
+func (c *bucket) PresignObject(bucketName, objectName string, upload bool, ttl, timeout time.Duration) (*pb.BucketObjectURL, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewBucketServiceClient(c.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.PresignObject(
+		ctxTo, &pb.BucketObjectURLRequest{
+			Bucket: bucketName,
+			Object: objectName,
+			Upload: upload,
+			Ttl:    int64(ttl.Seconds()),
+		},
+	)
+}
@@ -36,17 +36,20 @@ import (
 
 // Session units the different abstract proposed by safescaled as safescale client
 type Session struct {
-	Bucket *bucket
-	// Data       *data
-	Host       *host
-	Image      *image
-	JobManager *jobManager
-	Network    *network
-	Share      *share
-	SSH        *ssh
-	Template   *template
-	Tenant     *tenant
-	Volume     *volume
+	Audit         *audit
+	Bucket        *bucket
+	Cluster       *cluster
+	Data          *data
+	Host          *host
+	Image         *image
+	JobManager    *jobManager
+	Network       *network
+	SecurityGroup *securityGroup
+	Share         *share
+	SSH           *ssh
+	Template      *template
+	Tenant        *tenant
+	Volume        *volume
 
 	safescaledHost string
 	safescaledPort int
@@ -80,12 +83,15 @@ func New() Client {
 		safescaledPort: safescaledPort,
 	}
 
+	s.Audit = &audit{session: s}
 	s.Bucket = &bucket{session: s}
-	// s.Data = &data{session: s}
+	s.Cluster = &cluster{session: s}
+	s.Data = &data{session: s}
 	s.Host = &host{session: s}
 	s.Image = &image{session: s}
 	s.Network = &network{session: s}
 	s.JobManager = &jobManager{session: s}
+	s.SecurityGroup = &securityGroup{session: s}
 	s.Share = &share{session: s}
 	s.SSH = &ssh{session: s}
 	s.Template = &template{session: s}
@@ -0,0 +1,327 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"time"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// cluster is the safescale client part handling clusters
+type cluster struct {
+	// session is not used currently
+	session *Session
+}
+
+// Create creates a new cluster
+func (c *cluster) Create(def *pb.ClusterDefinition, timeout time.Duration) (*pb.Cluster, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Create(ctx, def)
+}
+
+// Estimate returns the cost of the cluster that would be created with the definition
+func (c *cluster) Estimate(def *pb.ClusterDefinition, timeout time.Duration) (*pb.CostEstimate, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Estimate(ctx, def)
+}
+
+// List lists the clusters
+func (c *cluster) List(timeout time.Duration) (*pb.ClusterList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.List(ctx, &googleprotobuf.Empty{})
+}
+
+// Inspect returns the description of a cluster
+func (c *cluster) Inspect(name string, timeout time.Duration) (*pb.Cluster, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Inspect(ctx, &pb.Reference{Name: name})
+}
+
+// State returns the current state of a cluster
+func (c *cluster) State(name string, timeout time.Duration) (*pb.ClusterState, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.State(ctx, &pb.Reference{Name: name})
+}
+
+// Start starts a cluster
+func (c *cluster) Start(name string, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Start(ctx, &pb.Reference{Name: name})
+	return err
+}
+
+// Stop stops a cluster
+func (c *cluster) Stop(name string, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Stop(ctx, &pb.Reference{Name: name})
+	return err
+}
+
+// Delete deletes a cluster; if force is set, errors are ignored during deletion
+func (c *cluster) Delete(name string, force bool, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Delete(ctx, &pb.ClusterDeleteRequest{Name: name, Force: force})
+	return err
+}
+
+// Expand adds count nodes to a cluster
+func (c *cluster) Expand(name string, count int, def *pb.HostDefinition, timeout time.Duration) (*pb.ClusterNodeList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Expand(ctx, &pb.ClusterResizeRequest{Name: name, Count: int32(count), NodeDefinition: def})
+}
+
+// Shrink removes the count last added nodes of a cluster
+func (c *cluster) Shrink(name string, count int, timeout time.Duration) (*pb.ClusterNodeList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Shrink(ctx, &pb.ClusterResizeRequest{Name: name, Count: int32(count)})
+}
+
+// InspectAutoscaling returns the autoscaling policy of a cluster
+func (c *cluster) InspectAutoscaling(name string, timeout time.Duration) (*pb.ClusterAutoscaling, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.InspectAutoscaling(ctx, &pb.Reference{Name: name})
+}
+
+// SetAutoscaling replaces the autoscaling policy of a cluster
+func (c *cluster) SetAutoscaling(def *pb.ClusterAutoscaling, timeout time.Duration) (*pb.ClusterAutoscaling, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.SetAutoscaling(ctx, def)
+}
+
+// CheckHealth probes the hosts of a cluster and returns them with their state
+func (c *cluster) CheckHealth(name string, timeout time.Duration) (*pb.ClusterHealth, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.CheckHealth(ctx, &pb.Reference{Name: name})
+}
+
+// InspectHealthPolicy returns the health policy of a cluster
+func (c *cluster) InspectHealthPolicy(name string, timeout time.Duration) (*pb.ClusterHealthPolicy, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.InspectHealthPolicy(ctx, &pb.Reference{Name: name})
+}
+
+// SetHealthPolicy replaces the health policy of a cluster
+func (c *cluster) SetHealthPolicy(def *pb.ClusterHealthPolicy, timeout time.Duration) (*pb.ClusterHealthPolicy, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.SetHealthPolicy(ctx, def)
+}
+
+// ListMasters lists the masters of a cluster
+func (c *cluster) ListMasters(name string, timeout time.Duration) (*pb.ClusterNodeList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.ListMasters(ctx, &pb.Reference{Name: name})
+}
+
+// ListNodes lists the nodes of a cluster
+func (c *cluster) ListNodes(name string, timeout time.Duration) (*pb.ClusterNodeList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.ListNodes(ctx, &pb.Reference{Name: name})
+}
+
+// FindAvailableMaster returns a master of the cluster ready to execute orders
+func (c *cluster) FindAvailableMaster(name string, timeout time.Duration) (*pb.ClusterNode, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.FindAvailableMaster(ctx, &pb.Reference{Name: name})
+}
+
+// ListFeatures lists the features suitable for clusters
+func (c *cluster) ListFeatures(timeout time.Duration) (*pb.ClusterFeatureList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.ListFeatures(ctx, &googleprotobuf.Empty{})
+}
+
+// AddFeature installs a feature on a cluster
+func (c *cluster) AddFeature(clusterName, featureName string, params map[string]string, skipProxy bool, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ClusterFeatureRequest{Cluster: clusterName, Feature: featureName, Params: params, SkipProxy: skipProxy}
+	_, err = service.AddFeature(ctx, req)
+	return err
+}
+
+// CheckFeature checks if a feature is installed on a cluster
+func (c *cluster) CheckFeature(clusterName, featureName string, params map[string]string, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ClusterFeatureRequest{Cluster: clusterName, Feature: featureName, Params: params}
+	_, err = service.CheckFeature(ctx, req)
+	return err
+}
+
+// DeleteFeature removes a feature from a cluster
+func (c *cluster) DeleteFeature(clusterName, featureName string, params map[string]string, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ClusterFeatureRequest{Cluster: clusterName, Feature: featureName, Params: params, SkipProxy: true}
+	_, err = service.DeleteFeature(ctx, req)
+	return err
+}
@@ -17,21 +17,23 @@
 package client
 
 import (
+	"context"
 	"time"
 
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/utils"
-	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 )
 
-// bucket is the part of the safescale client handling buckets
+// data is the part of the safescale client handling data files stored in buckets
 type data struct {
 	// session is not used currently.
 	session *Session
 }
 
-// Push ...
-func (c *data) Push(localFilePath string, fileName string, timeout time.Duration) error {
+// Push stores a local file in the buckets described by the request
+func (c *data) Push(file *pb.File, timeout time.Duration) error {
 	c.session.Connect()
 	defer c.session.Disconnect()
 	service := pb.NewDataServiceClient(c.session.connection)
@@ -40,11 +42,21 @@ func (c *data) Push(localFilePath string, fileName string, timeout time.Duration
 		return err
 	}
 
-	_, err = service.Push(ctx, &pb.File{LocalPath: localFilePath, Name: fileName})
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	_, err = service.Push(ctxTo, file)
 	return err
 }
 
-// Get ...
+// Get restores the stored file 'fileName' in 'localFilePath'
 func (c *data) Get(localFilePath string, fileName string, timeout time.Duration) error {
 	c.session.Connect()
 	defer c.session.Disconnect()
@@ -54,11 +66,21 @@ func (c *data) Get(localFilePath string, fileName string, timeout time.Duration)
 		return err
 	}
 
-	_, err = service.Get(ctx, &pb.File{LocalPath: localFilePath, Name: fileName})
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	_, err = service.Get(ctxTo, &pb.File{LocalPath: localFilePath, Name: fileName})
 	return err
 }
 
-// List ...
+// List returns the stored files
 func (c *data) List(timeout time.Duration) (*pb.FileList, error) {
 	c.session.Connect()
 	defer c.session.Disconnect()
@@ -68,11 +90,20 @@ func (c *data) List(timeout time.Duration) (*pb.FileList, error) {
 		return nil, err
 	}
 
-	return service.List(ctx, &googleprotobuf.Empty{})
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
 
+	return service.List(ctxTo, &googleprotobuf.Empty{})
 }
 
-// Delete ...
+// Delete removes the stored file 'fileName'
 func (c *data) Delete(fileName string, timeout time.Duration) error {
 	c.session.Connect()
 	defer c.session.Disconnect()
@@ -82,6 +113,16 @@ func (c *data) Delete(fileName string, timeout time.Duration) error {
 		return err
 	}
 
-	_, err = service.Delete(ctx, &pb.File{Name: fileName})
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	_, err = service.Delete(ctxTo, &pb.File{Name: fileName})
 	return err
 }
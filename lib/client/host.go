@@ -37,7 +37,7 @@ type host struct {
 }
 
 // List ...
-func (h *host) List(all bool, timeout time.Duration) (*pb.HostList, error) {
+func (h *host) List(all bool, labels map[string]string, timeout time.Duration) (*pb.HostList, error) {
 	h.session.Connect()
 	defer h.session.Disconnect()
 	service := pb.NewHostServiceClient(h.session.connection)
@@ -46,7 +46,7 @@ func (h *host) List(all bool, timeout time.Duration) (*pb.HostList, error) {
 		return nil, err
 	}
 
-	return service.List(ctx, &pb.HostListRequest{All: all})
+	return service.List(ctx, &pb.HostListRequest{All: all, Labels: labels})
 }
 
 // Inspect ...
@@ -117,6 +117,19 @@ func (h *host) Stop(name string, timeout time.Duration) error {
 	return err
 }
 
+// CaptureImage creates an image named imageName from the root disk of the host
+func (h *host) CaptureImage(name string, imageName string, timeout time.Duration) (*pb.Image, error) {
+	h.session.Connect()
+	defer h.session.Disconnect()
+	service := pb.NewHostServiceClient(h.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.CaptureImage(ctx, &pb.HostImageRequest{Host: &pb.Reference{Name: name}, Name: imageName})
+}
+
 // Create ...
 func (h *host) Create(def *pb.HostDefinition, timeout time.Duration) (*pb.Host, error) {
 	if def == nil {
@@ -134,6 +147,23 @@ func (h *host) Create(def *pb.HostDefinition, timeout time.Duration) (*pb.Host,
 	return service.Create(ctx, def)
 }
 
+// Estimate returns the cost of the host that would be created with the definition
+func (h *host) Estimate(def *pb.HostDefinition, timeout time.Duration) (*pb.CostEstimate, error) {
+	if def == nil {
+		return nil, fail.InvalidParameterError("def", "cannot be nil")
+	}
+
+	h.session.Connect()
+	defer h.session.Disconnect()
+	service := pb.NewHostServiceClient(h.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Estimate(ctx, def)
+}
+
 // Delete deletes several hosts at the same time in goroutines
 func (h *host) Delete(names []string, timeout time.Duration) error {
 	h.session.Connect()
@@ -41,3 +41,17 @@ func (img *image) List(all bool, timeout time.Duration) (*pb.ImageList, error) {
 
 	return service.List(ctx, &pb.ImageListRequest{All: all})
 }
+
+// Delete deletes an image
+func (img *image) Delete(ref string, timeout time.Duration) error {
+	img.session.Connect()
+	defer img.session.Disconnect()
+	service := pb.NewImageServiceClient(img.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Delete(ctx, &pb.Reference{Name: ref})
+	return err
+}
@@ -34,7 +34,7 @@ type network struct {
 }
 
 // List ...
-func (n *network) List(all bool, timeout time.Duration) (*pb.NetworkList, error) {
+func (n *network) List(all bool, labels map[string]string, timeout time.Duration) (*pb.NetworkList, error) {
 	n.session.Connect()
 	defer n.session.Disconnect()
 	service := pb.NewNetworkServiceClient(n.session.connection)
@@ -45,7 +45,8 @@ func (n *network) List(all bool, timeout time.Duration) (*pb.NetworkList, error)
 
 	return service.List(
 		ctx, &pb.NetworkListRequest{
-			All: all,
+			All:    all,
+			Labels: labels,
 		},
 	)
 }
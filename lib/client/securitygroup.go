@@ -0,0 +1,171 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"time"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// securityGroup is the safescale client part handling security groups
+type securityGroup struct {
+	// session is not used currently
+	session *Session
+}
+
+// List lists the security groups
+func (sg *securityGroup) List(timeout time.Duration) (*pb.SecurityGroupList, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.List(ctx, &googleprotobuf.Empty{})
+}
+
+// Create creates a new security group
+func (sg *securityGroup) Create(def *pb.SecurityGroupDefinition, timeout time.Duration) (*pb.SecurityGroup, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Create(ctx, def)
+}
+
+// Inspect returns the description of a security group
+func (sg *securityGroup) Inspect(ref string, timeout time.Duration) (*pb.SecurityGroup, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Inspect(ctx, &pb.Reference{Name: ref})
+}
+
+// Delete deletes a security group
+func (sg *securityGroup) Delete(ref string, timeout time.Duration) error {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Delete(ctx, &pb.Reference{Name: ref})
+	return err
+}
+
+// AddRule adds a rule to a security group
+func (sg *securityGroup) AddRule(ref string, rule *pb.SecurityGroupRule, timeout time.Duration) (*pb.SecurityGroup, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.AddRule(ctx, &pb.SecurityGroupRuleRequest{Group: &pb.Reference{Name: ref}, Rule: rule})
+}
+
+// DeleteRule deletes a rule from a security group
+func (sg *securityGroup) DeleteRule(ref string, ruleID string, timeout time.Duration) (*pb.SecurityGroup, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.DeleteRule(ctx, &pb.SecurityGroupRuleDeleteRequest{Group: &pb.Reference{Name: ref}, RuleId: ruleID})
+}
+
+// BindToHost applies a security group to a host
+func (sg *securityGroup) BindToHost(ref string, hostRef string, timeout time.Duration) error {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.SecurityGroupBindRequest{Group: &pb.Reference{Name: ref}, Resource: &pb.Reference{Name: hostRef}}
+	_, err = service.BindToHost(ctx, req)
+	return err
+}
+
+// UnbindFromHost removes a security group from a host
+func (sg *securityGroup) UnbindFromHost(ref string, hostRef string, timeout time.Duration) error {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.SecurityGroupBindRequest{Group: &pb.Reference{Name: ref}, Resource: &pb.Reference{Name: hostRef}}
+	_, err = service.UnbindFromHost(ctx, req)
+	return err
+}
+
+// BindToNetwork applies a security group to a network
+func (sg *securityGroup) BindToNetwork(ref string, networkRef string, timeout time.Duration) error {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.SecurityGroupBindRequest{Group: &pb.Reference{Name: ref}, Resource: &pb.Reference{Name: networkRef}}
+	_, err = service.BindToNetwork(ctx, req)
+	return err
+}
+
+// UnbindFromNetwork removes a security group from a network
+func (sg *securityGroup) UnbindFromNetwork(ref string, networkRef string, timeout time.Duration) error {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.SecurityGroupBindRequest{Group: &pb.Reference{Name: ref}, Resource: &pb.Reference{Name: networkRef}}
+	_, err = service.UnbindFromNetwork(ctx, req)
+	return err
+}
@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"time"
 
 	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
@@ -57,6 +58,19 @@ func (t *tenant) Get(timeout time.Duration) (*pb.TenantName, error) {
 	return service.Get(ctx, &googleprotobuf.Empty{})
 }
 
+// Quotas ...
+func (t *tenant) Quotas(timeout time.Duration) (*pb.TenantQuotas, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Quotas(ctx, &googleprotobuf.Empty{})
+}
+
 // Set ...
 func (t *tenant) Set(name string, timeout time.Duration) error {
 	t.session.Connect()
@@ -70,3 +84,141 @@ func (t *tenant) Set(name string, timeout time.Duration) error {
 	_, err = service.Set(ctx, &pb.TenantName{Name: name})
 	return err
 }
+
+// MigrateMetadata upgrades the properties of the metadata of the current tenant; with dryRun, nothing is saved
+func (t *tenant) MigrateMetadata(dryRun bool, timeout time.Duration) (*pb.MetadataMigrationReport, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.MigrateMetadata(ctxTo, &pb.MetadataMigrationRequest{DryRun: dryRun})
+}
+
+// ExportMetadata writes an archive of the metadata of the current tenant in a file local to the daemon
+func (t *tenant) ExportMetadata(path string, timeout time.Duration) (*pb.MetadataArchiveReport, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.ExportMetadata(ctxTo, &pb.MetadataArchiveRequest{Path: path})
+}
+
+// ImportMetadata writes in the metadata of the current tenant the content of an archive local to the daemon
+func (t *tenant) ImportMetadata(path string, timeout time.Duration) (*pb.MetadataArchiveReport, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.ImportMetadata(ctxTo, &pb.MetadataArchiveRequest{Path: path})
+}
+
+// BackupMetadata writes an archive of the metadata of the current tenant in a bucket
+func (t *tenant) BackupMetadata(bucketName string, timeout time.Duration) (*pb.MetadataArchiveReport, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.BackupMetadata(ctxTo, &pb.MetadataArchiveRequest{Bucket: bucketName})
+}
+
+// RekeyMetadata re-encrypts the metadata of the current tenant with the key derived from newKey
+func (t *tenant) RekeyMetadata(newKey string, timeout time.Duration) (*pb.MetadataRekeyReport, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.RekeyMetadata(ctxTo, &pb.MetadataRekeyRequest{NewKey: newKey})
+}
+
+// Fsck cross-checks the metadata of the current tenant against the resources of its provider; with repair, the metadata are fixed
+func (t *tenant) Fsck(repair bool, timeout time.Duration) (*pb.FsckReport, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	return service.Fsck(ctxTo, &pb.FsckRequest{Repair: repair})
+}
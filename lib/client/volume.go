@@ -33,7 +33,7 @@ type volume struct {
 }
 
 // List ...
-func (v *volume) List(all bool, timeout time.Duration) (*pb.VolumeList, error) {
+func (v *volume) List(all bool, labels map[string]string, timeout time.Duration) (*pb.VolumeList, error) {
 	v.session.Connect()
 	defer v.session.Disconnect()
 	service := pb.NewVolumeServiceClient(v.session.connection)
@@ -42,7 +42,7 @@ func (v *volume) List(all bool, timeout time.Duration) (*pb.VolumeList, error) {
 		return nil, err
 	}
 
-	return service.List(ctx, &pb.VolumeListRequest{All: all})
+	return service.List(ctx, &pb.VolumeListRequest{All: all, Labels: labels})
 
 }
 
@@ -147,3 +147,56 @@ func (v *volume) Detach(volumeName string, hostName string, timeout time.Duratio
 	)
 	return err
 }
+
+// CreateSnapshot creates a snapshot of a volume
+func (v *volume) CreateSnapshot(def *pb.VolumeSnapshotDefinition, timeout time.Duration) (*pb.VolumeSnapshot, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.CreateSnapshot(ctx, def)
+}
+
+// ListSnapshots lists the volume snapshots, restricted to the ones of volumeName if not empty
+func (v *volume) ListSnapshots(volumeName string, timeout time.Duration) (*pb.VolumeSnapshotList, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.ListSnapshots(ctx, &pb.Reference{Name: volumeName})
+}
+
+// DeleteSnapshot deletes a volume snapshot
+func (v *volume) DeleteSnapshot(name string, timeout time.Duration) error {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.DeleteSnapshot(ctx, &pb.Reference{Name: name})
+	return err
+}
+
+// RestoreSnapshot creates a new volume from a volume snapshot
+func (v *volume) RestoreSnapshot(req *pb.VolumeSnapshotRestoreRequest, timeout time.Duration) (*pb.Volume, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.RestoreSnapshot(ctx, req)
+}
@@ -171,5 +171,8 @@ func (x *HostDefinition) Clone() *HostDefinition {
 		Sizing:        x.Sizing.Clone(),
 		Domain:        x.Domain,
 		KeepOnFailure: x.KeepOnFailure,
+		Zone:          x.Zone,
+		ServerGroup:   x.ServerGroup,
+		Placement:     x.Placement,
 	}
 }
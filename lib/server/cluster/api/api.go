@@ -50,12 +50,12 @@ type Cluster interface {
 	AddNode(concurrency.Task, *pb.HostDefinition) (string, error)
 	// AddNodes adds several nodes
 	AddNodes(concurrency.Task, int, *pb.HostDefinition) ([]string, error)
-	// DeleteLastNode deletes a node
-	DeleteLastNode(concurrency.Task, string) error
+	// DeleteLastNode deletes the last node added, and returns it
+	DeleteLastNode(concurrency.Task, string) (*propsv2.Node, error)
 	// DeleteSpecificNode deletes a node identified by its ID
 	DeleteSpecificNode(concurrency.Task, string, string) error
 	// ListMasters lists the masters (if there is such masters in the flavor...)
-	ListMasters(concurrency.Task) []*propsv1.Node
+	ListMasters(concurrency.Task) []*propsv2.Node
 	// ListMasterNames lists the names of masters (if there is such masters in the flavor...)
 	ListMasterNames(concurrency.Task) []string
 	// ListMasterIDs lists the IDs of masters (if there is such masters in the flavor...)
@@ -65,7 +65,7 @@ type Cluster interface {
 	// FindAvailableMaster returns ID of the first master available to execute order
 	FindAvailableMaster(concurrency.Task) (string, error)
 	// ListNodes lists Nodes in the cluster
-	ListNodes(concurrency.Task) []*propsv1.Node
+	ListNodes(concurrency.Task) []*propsv2.Node
 	// ListNodeNames lists IDs of the nodes in the cluster
 	ListNodeNames(concurrency.Task) []string
 	// ListNodeIDs lists IDs of the nodes in the cluster
@@ -81,6 +81,20 @@ type Cluster interface {
 	// CountNodes counts the nodes of the cluster
 	CountNodes(concurrency.Task) (uint, error)
 
+	// GetAutoscaling returns the autoscaling policy of the cluster
+	GetAutoscaling(concurrency.Task) (*propsv1.Autoscaling, error)
+	// SetAutoscaling replaces the autoscaling policy of the cluster
+	SetAutoscaling(concurrency.Task, *propsv1.Autoscaling) error
+	// Autoscale adds or removes a node according to the autoscaling policy and the load of the nodes
+	Autoscale(concurrency.Task) (int, error)
+
+	// GetHealth returns the health policy of the cluster
+	GetHealth(concurrency.Task) (*propsv1.Health, error)
+	// SetHealth replaces the health policy of the cluster
+	SetHealth(concurrency.Task, *propsv1.Health) error
+	// CheckHealth probes the hosts of the cluster, records their state and replaces the dead nodes if allowed
+	CheckHealth(concurrency.Task) (*propsv2.Nodes, error)
+
 	// Delete allows to destroy infrastructure of cluster
 	Delete(concurrency.Task) error
 
@@ -0,0 +1,471 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/client"
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/clusterstate"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// DefaultAutoscaleInterval is the default delay between 2 evaluations of the autoscaling policies of the clusters
+	DefaultAutoscaleInterval = time.Minute
+	// DefaultScaleUpThreshold is the default usage of CPU or RAM (in percent) above which a node is added
+	DefaultScaleUpThreshold = 80.0
+	// DefaultScaleDownThreshold is the default usage of CPU and RAM (in percent) below which a node is removed
+	DefaultScaleDownThreshold = 30.0
+	// DefaultScaleUpCooldown is the default delay after a scaling before a node can be added
+	DefaultScaleUpCooldown = 5 * time.Minute
+	// DefaultScaleDownCooldown is the default delay after a scaling before a node can be removed
+	DefaultScaleDownCooldown = 15 * time.Minute
+
+	// nodeLoadCommand prints the load average of the last minute relative to the count of CPU, and the part of the
+	// RAM used, in percent
+	nodeLoadCommand = "awk -v n=$(nproc) '{printf \"%.1f \", $1*100/n}' /proc/loadavg; " +
+		"free | awk '/^Mem:/{printf \"%.1f\\n\", ($2-$7)*100/$2}'"
+)
+
+// scalingLocks serializes per cluster the changes of the nodes done by safescaled (autoscaling, repair, expand and
+// shrink), each of them working with its own Controller
+var scalingLocks = struct {
+	sync.Mutex
+	byCluster map[string]*sync.Mutex
+}{byCluster: map[string]*sync.Mutex{}}
+
+// LockScaling waits until no other change of the nodes of the cluster 'name' is running in safescaled, and returns
+// the function releasing the lock
+func LockScaling(name string) func() {
+	scalingLocks.Lock()
+	lock, ok := scalingLocks.byCluster[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		scalingLocks.byCluster[name] = lock
+	}
+	scalingLocks.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// reload replaces the properties of the Controller by the ones in metadata, possibly changed by another Controller
+func (c *Controller) reload(task concurrency.Task) error {
+	err := c.metadata.Reload(task)
+	if err != nil {
+		return err
+	}
+	if !c.metadata.Written() {
+		return nil
+	}
+	mc, err := c.metadata.Get()
+	if err != nil {
+		return err
+	}
+	c.replace(task, mc)
+	return nil
+}
+
+// Load describes the usage of the nodes of a cluster, used to decide to add or remove nodes
+type Load struct {
+	// PendingWorkloads is the count of workloads waiting for resources to run (for example pending pods of K8S)
+	PendingWorkloads int
+	// CPU is the average usage of CPU of the nodes, in percent (for K8S, the CPU requested by pods)
+	CPU float64
+	// RAM is the average usage of RAM of the nodes, in percent (for K8S, the RAM requested by pods)
+	RAM float64
+}
+
+// GetAutoscaling returns the autoscaling policy of the cluster
+func (c *Controller) GetAutoscaling(task concurrency.Task) (policy *clusterpropsv1.Autoscaling, err error) {
+	if c == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if task == nil {
+		return nil, fail.InvalidParameterError("task", "cannot be nil")
+	}
+
+	c.RLock(task)
+	defer c.RUnlock(task)
+
+	err = c.Properties.LockForRead(property.AutoscalingV1).ThenUse(
+		func(clonable data.Clonable) error {
+			policy = clonable.Clone().(*clusterpropsv1.Autoscaling)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// SetAutoscaling replaces the autoscaling policy of the cluster; zero thresholds and cooldowns are replaced by their
+// default values, and the dates of the last scalings are kept
+func (c *Controller) SetAutoscaling(task concurrency.Task, policy *clusterpropsv1.Autoscaling) (err error) {
+	if c == nil {
+		return fail.InvalidInstanceError()
+	}
+	if task == nil {
+		return fail.InvalidParameterError("task", "cannot be nil")
+	}
+	if policy == nil {
+		return fail.InvalidParameterError("policy", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(
+		task, fmt.Sprintf("(%v, %d, %d)", policy.Enabled, policy.MinNodes, policy.MaxNodes), true,
+	).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	newPolicy := policy.Clone().(*clusterpropsv1.Autoscaling)
+	if newPolicy.ScaleUpThreshold == 0 {
+		newPolicy.ScaleUpThreshold = DefaultScaleUpThreshold
+	}
+	if newPolicy.ScaleDownThreshold == 0 {
+		newPolicy.ScaleDownThreshold = DefaultScaleDownThreshold
+	}
+	if newPolicy.ScaleUpCooldown == 0 {
+		newPolicy.ScaleUpCooldown = DefaultScaleUpCooldown
+	}
+	if newPolicy.ScaleDownCooldown == 0 {
+		newPolicy.ScaleDownCooldown = DefaultScaleDownCooldown
+	}
+	err = validateAutoscaling(newPolicy)
+	if err != nil {
+		return err
+	}
+
+	return c.UpdateMetadata(
+		task, func() error {
+			return c.Properties.LockForWrite(property.AutoscalingV1).ThenUse(
+				func(clonable data.Clonable) error {
+					autoscalingV1 := clonable.(*clusterpropsv1.Autoscaling)
+					newPolicy.LastScaleUp = autoscalingV1.LastScaleUp
+					newPolicy.LastScaleDown = autoscalingV1.LastScaleDown
+					newPolicy.LastScalingFailed = autoscalingV1.LastScalingFailed
+					autoscalingV1.Replace(newPolicy)
+					return nil
+				},
+			)
+		},
+	)
+}
+
+// validateAutoscaling checks the consistency of an autoscaling policy
+func validateAutoscaling(policy *clusterpropsv1.Autoscaling) error {
+	if policy.MinNodes < 0 {
+		return fail.InvalidParameterError("MinNodes", "cannot be negative")
+	}
+	if policy.Enabled && policy.MaxNodes <= 0 {
+		return fail.InvalidParameterError("MaxNodes", "must be an int > 0")
+	}
+	if policy.MaxNodes > 0 && policy.MaxNodes < policy.MinNodes {
+		return fail.InvalidParameterError("MaxNodes", "cannot be lower than MinNodes")
+	}
+	if policy.ScaleUpThreshold <= 0 || policy.ScaleUpThreshold > 100 {
+		return fail.InvalidParameterError("ScaleUpThreshold", "must be a percentage")
+	}
+	if policy.ScaleDownThreshold < 0 || policy.ScaleDownThreshold >= policy.ScaleUpThreshold {
+		return fail.InvalidParameterError("ScaleDownThreshold", "must be a percentage lower than ScaleUpThreshold")
+	}
+	if policy.ScaleUpCooldown < 0 || policy.ScaleDownCooldown < 0 {
+		return fail.InvalidParameterError("cooldown", "cannot be negative")
+	}
+	return nil
+}
+
+// Autoscale adds or removes a node of the cluster according to its autoscaling policy and the load of its nodes;
+// returns the change of the count of nodes (+1, -1 or 0)
+// A node is drained before being removed.
+func (c *Controller) Autoscale(task concurrency.Task) (delta int, err error) {
+	if c == nil {
+		return 0, fail.InvalidInstanceError()
+	}
+	if task == nil {
+		return 0, fail.InvalidParameterError("task", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(task, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	// the nodes cannot be changed by a repair, an expand or a shrink between the decision and the end of the scaling
+	defer LockScaling(c.Name)()
+	err = c.reload(task)
+	if err != nil {
+		return 0, err
+	}
+
+	policy, err := c.GetAutoscaling(task)
+	if err != nil {
+		return 0, err
+	}
+	if !policy.Enabled {
+		return 0, nil
+	}
+	state, err := c.GetState(task)
+	if err != nil {
+		return 0, err
+	}
+	if state != clusterstate.Nominal {
+		log.Debugf("[cluster %s] autoscaling skipped, cluster is in state '%s'", c.Name, state.String())
+		return 0, nil
+	}
+
+	nodes := c.ListNodes(task)
+	var load *Load
+	if len(nodes) > 0 {
+		load, err = c.foreman.getLoad(task)
+		if err != nil {
+			// the bounds of the policy are still enforced
+			log.Warnf("[cluster %s] failed to get the load of the nodes: %v", c.Name, err)
+			load = nil
+		}
+	}
+
+	now := time.Now()
+	delta, reason := decideScaling(policy, load, len(nodes), now)
+	switch delta {
+	case 1:
+		log.Infof("[cluster %s] autoscaling: %s, adding a node", c.Name, reason)
+		_, err = c.AddNodes(task, 1, nil)
+	case -1:
+		log.Infof("[cluster %s] autoscaling: %s, removing a node", c.Name, reason)
+		err = c.removeNodeForAutoscaling(task, nodes[len(nodes)-1])
+	default:
+		return 0, nil
+	}
+
+	// the cooldown applies also after a failure, to not retry continuously
+	derr := c.UpdateMetadata(
+		task, func() error {
+			return c.Properties.LockForWrite(property.AutoscalingV1).ThenUse(
+				func(clonable data.Clonable) error {
+					autoscalingV1 := clonable.(*clusterpropsv1.Autoscaling)
+					if delta > 0 {
+						autoscalingV1.LastScaleUp = now
+					} else {
+						autoscalingV1.LastScaleDown = now
+					}
+					autoscalingV1.LastScalingFailed = err != nil
+					return nil
+				},
+			)
+		},
+	)
+	if err != nil {
+		return 0, fail.AddConsequence(err, derr)
+	}
+	if derr != nil {
+		return delta, derr
+	}
+	return delta, nil
+}
+
+// decideScaling returns the change of the count of nodes required by the policy for the load (nil if unknown)
+// and the reason of the change
+func decideScaling(policy *clusterpropsv1.Autoscaling, load *Load, nodeCount int, now time.Time) (int, string) {
+	lastScaling := policy.LastScaleUp
+	if policy.LastScaleDown.After(lastScaling) {
+		lastScaling = policy.LastScaleDown
+	}
+	sinceLastScaling := now.Sub(lastScaling)
+
+	// the bounds are enforced during the cooldown, except after a failure to not create or delete hosts continuously
+	if nodeCount < policy.MinNodes {
+		if policy.LastScalingFailed && sinceLastScaling < policy.ScaleUpCooldown {
+			return 0, ""
+		}
+		return 1, fmt.Sprintf("%d nodes, below the minimum of %d", nodeCount, policy.MinNodes)
+	}
+	if policy.MaxNodes > 0 && nodeCount > policy.MaxNodes {
+		if policy.LastScalingFailed && sinceLastScaling < policy.ScaleDownCooldown {
+			return 0, ""
+		}
+		return -1, fmt.Sprintf("%d nodes, above the maximum of %d", nodeCount, policy.MaxNodes)
+	}
+	if load == nil {
+		return 0, ""
+	}
+
+	if nodeCount < policy.MaxNodes && sinceLastScaling >= policy.ScaleUpCooldown {
+		if load.PendingWorkloads > 0 {
+			return 1, fmt.Sprintf("%d pending workloads", load.PendingWorkloads)
+		}
+		if load.CPU >= policy.ScaleUpThreshold || load.RAM >= policy.ScaleUpThreshold {
+			return 1, fmt.Sprintf("usage of CPU %.1f%%, of RAM %.1f%%", load.CPU, load.RAM)
+		}
+	}
+
+	if nodeCount > policy.MinNodes && nodeCount > 1 && sinceLastScaling >= policy.ScaleDownCooldown &&
+		load.PendingWorkloads == 0 && load.CPU < policy.ScaleDownThreshold && load.RAM < policy.ScaleDownThreshold {
+		// does not remove a node if the remaining ones would have to scale up
+		ratio := float64(nodeCount) / float64(nodeCount-1)
+		if load.CPU*ratio < policy.ScaleUpThreshold && load.RAM*ratio < policy.ScaleUpThreshold {
+			return -1, fmt.Sprintf("usage of CPU %.1f%%, of RAM %.1f%%", load.CPU, load.RAM)
+		}
+	}
+	return 0, ""
+}
+
+// removeNodeForAutoscaling drains then deletes a node; if the node cannot be drained, it is put back in service
+func (c *Controller) removeNodeForAutoscaling(task concurrency.Task, node *clusterpropsv2.Node) (err error) {
+	selectedMaster, err := c.FindAvailableMaster(task)
+	if err != nil {
+		return err
+	}
+	pbHost, err := client.New().Host.Inspect(node.ID, temporal.GetExecutionTimeout())
+	if err != nil {
+		return err
+	}
+
+	err = c.foreman.drainNode(task, pbHost, selectedMaster)
+	if err != nil {
+		derr := c.foreman.undrainNode(task, pbHost, selectedMaster)
+		if derr != nil {
+			log.Errorf("[cluster %s] failed to put node '%s' back in service: %v", c.Name, node.Name, derr)
+		}
+		return fail.AddConsequence(fail.Wrap(err, fmt.Sprintf("failed to drain node '%s'", node.Name)), derr)
+	}
+
+	return c.DeleteSpecificNode(task, node.ID, selectedMaster)
+}
+
+// getLoad returns the load of the nodes of the cluster, using the "maker" GetLoad if defined, or the usage of
+// CPU and RAM of the nodes collected over SSH
+func (b *foreman) getLoad(task concurrency.Task) (*Load, error) {
+	if b.makers.GetLoad != nil {
+		return b.makers.GetLoad(task, b)
+	}
+
+	nodeIDs := b.cluster.ListNodeIDs(task)
+	if len(nodeIDs) == 0 {
+		return &Load{}, nil
+	}
+	clientSSH := client.New().SSH
+	load := &Load{}
+	for _, id := range nodeIDs {
+		retcode, stdout, stderr, err := clientSSH.Run(
+			id, nodeLoadCommand, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if retcode != 0 {
+			return nil, fmt.Errorf("failed to get load of node '%s': %s", id, stderr)
+		}
+		cpu, ram, err := parseNodeLoad(stdout)
+		if err != nil {
+			return nil, fail.Wrap(err, fmt.Sprintf("failed to get load of node '%s'", id))
+		}
+		load.CPU += cpu
+		load.RAM += ram
+	}
+	load.CPU /= float64(len(nodeIDs))
+	load.RAM /= float64(len(nodeIDs))
+	return load, nil
+}
+
+// parseNodeLoad reads the usage of CPU and RAM printed by nodeLoadCommand
+func parseNodeLoad(out string) (cpu float64, ram float64, err error) {
+	_, err = fmt.Sscanf(strings.TrimSpace(out), "%f %f", &cpu, &ram)
+	if err != nil {
+		return 0, 0, fail.SyntaxError(fmt.Sprintf("unexpected load '%s': %v", strings.TrimSpace(out), err))
+	}
+	return cpu, ram, nil
+}
+
+// drainNode moves the workloads off a node, using the "maker" DrainNode if defined, or Docker Swarm (always
+// installed on clusters other than K8S)
+func (b *foreman) drainNode(task concurrency.Task, pbHost *pb.Host, selectedMaster string) error {
+	if b.makers.DrainNode != nil {
+		return b.makers.DrainNode(task, b, pbHost, selectedMaster)
+	}
+	if b.cluster.GetIdentity(task).Flavor == flavor.K8S {
+		return nil
+	}
+	return b.setSwarmNodeAvailability(pbHost, selectedMaster, "drain")
+}
+
+// undrainNode puts a drained node back in service
+func (b *foreman) undrainNode(task concurrency.Task, pbHost *pb.Host, selectedMaster string) error {
+	if b.makers.UndrainNode != nil {
+		return b.makers.UndrainNode(task, b, pbHost, selectedMaster)
+	}
+	if b.cluster.GetIdentity(task).Flavor == flavor.K8S {
+		return nil
+	}
+	return b.setSwarmNodeAvailability(pbHost, selectedMaster, "active")
+}
+
+// setSwarmNodeAvailability changes the availability of a node in Docker Swarm; with 'drain', waits for the tasks
+// running on the node to be moved
+func (b *foreman) setSwarmNodeAvailability(pbHost *pb.Host, selectedMaster string, availability string) error {
+	clientSSH := client.New().SSH
+	cmd := fmt.Sprintf("docker node update --availability %s %s", availability, pbHost.Name)
+	retcode, _, stderr, err := clientSSH.Run(
+		selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+	)
+	if err != nil {
+		return err
+	}
+	if retcode != 0 {
+		return fmt.Errorf("failed to set availability of node '%s' to '%s': %s", pbHost.Name, availability, stderr)
+	}
+	if availability != "drain" {
+		return nil
+	}
+
+	cmd = fmt.Sprintf("docker node ps %s --filter desired-state=running --quiet | wc -l", pbHost.Name)
+	return retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			retcode, stdout, stderr, err := clientSSH.Run(
+				selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+			)
+			if err != nil {
+				return err
+			}
+			if retcode != 0 {
+				return fmt.Errorf("failed to list tasks of node '%s': %s", pbHost.Name, stderr)
+			}
+			if count := strings.TrimSpace(stdout); count != "0" {
+				return fmt.Errorf("%s tasks still running on node '%s'", count, pbHost.Name)
+			}
+			return nil
+		},
+		temporal.GetHostTimeout(),
+	)
+}
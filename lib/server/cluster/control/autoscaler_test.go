@@ -0,0 +1,113 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+)
+
+func newTestAutoscaling() *clusterpropsv1.Autoscaling {
+	return &clusterpropsv1.Autoscaling{
+		Enabled:            true,
+		MinNodes:           1,
+		MaxNodes:           4,
+		ScaleUpThreshold:   DefaultScaleUpThreshold,
+		ScaleDownThreshold: DefaultScaleDownThreshold,
+		ScaleUpCooldown:    DefaultScaleUpCooldown,
+		ScaleDownCooldown:  DefaultScaleDownCooldown,
+	}
+}
+
+func TestDecideScaling(t *testing.T) {
+	now := time.Now()
+	policy := newTestAutoscaling()
+
+	delta, _ := decideScaling(policy, nil, 0, now)
+	assert.Equal(t, 1, delta)
+	delta, _ = decideScaling(policy, nil, 5, now)
+	assert.Equal(t, -1, delta)
+	delta, _ = decideScaling(policy, nil, 2, now)
+	assert.Equal(t, 0, delta)
+
+	delta, _ = decideScaling(policy, &Load{PendingWorkloads: 2}, 2, now)
+	assert.Equal(t, 1, delta)
+	delta, _ = decideScaling(policy, &Load{CPU: 90, RAM: 10}, 2, now)
+	assert.Equal(t, 1, delta)
+	delta, _ = decideScaling(policy, &Load{CPU: 90, RAM: 10}, 4, now)
+	assert.Equal(t, 0, delta)
+
+	delta, _ = decideScaling(policy, &Load{CPU: 10, RAM: 10}, 3, now)
+	assert.Equal(t, -1, delta)
+	delta, _ = decideScaling(policy, &Load{CPU: 10, RAM: 10}, 1, now)
+	assert.Equal(t, 0, delta)
+	// with 2 nodes at 29%, the remaining one would be at 58%, so stays below the scale up threshold
+	delta, _ = decideScaling(policy, &Load{CPU: 29, RAM: 10}, 2, now)
+	assert.Equal(t, -1, delta)
+	policy.ScaleUpThreshold = 50
+	delta, _ = decideScaling(policy, &Load{CPU: 29, RAM: 10}, 2, now)
+	assert.Equal(t, 0, delta)
+}
+
+func TestDecideScaling_Cooldown(t *testing.T) {
+	now := time.Now()
+	policy := newTestAutoscaling()
+
+	policy.LastScaleUp = now.Add(-time.Minute)
+	delta, _ := decideScaling(policy, &Load{CPU: 90}, 2, now)
+	assert.Equal(t, 0, delta)
+	delta, _ = decideScaling(policy, &Load{CPU: 90}, 2, now.Add(DefaultScaleUpCooldown))
+	assert.Equal(t, 1, delta)
+
+	// the cooldown after a scale up delays also the scale down
+	delta, _ = decideScaling(policy, &Load{CPU: 10, RAM: 10}, 3, now.Add(DefaultScaleUpCooldown))
+	assert.Equal(t, 0, delta)
+	delta, _ = decideScaling(policy, &Load{CPU: 10, RAM: 10}, 3, now.Add(DefaultScaleDownCooldown))
+	assert.Equal(t, -1, delta)
+
+	// the bounds are enforced during the cooldown
+	delta, _ = decideScaling(policy, &Load{}, 0, now)
+	assert.Equal(t, 1, delta)
+
+	// except after a failure
+	policy.LastScalingFailed = true
+	delta, _ = decideScaling(policy, nil, 0, now)
+	assert.Equal(t, 0, delta)
+	delta, _ = decideScaling(policy, nil, 0, now.Add(DefaultScaleUpCooldown))
+	assert.Equal(t, 1, delta)
+	policy.LastScaleDown = now
+	delta, _ = decideScaling(policy, nil, 5, now.Add(DefaultScaleUpCooldown))
+	assert.Equal(t, 0, delta)
+	delta, _ = decideScaling(policy, nil, 5, now.Add(DefaultScaleDownCooldown))
+	assert.Equal(t, -1, delta)
+}
+
+func TestValidateAutoscaling(t *testing.T) {
+	policy := newTestAutoscaling()
+	assert.Nil(t, validateAutoscaling(policy))
+
+	policy.MaxNodes = 0
+	assert.NotNil(t, validateAutoscaling(policy))
+	policy.Enabled = false
+	assert.Nil(t, validateAutoscaling(policy))
+
+	policy = newTestAutoscaling()
+	policy.MinNodes = 5
+	assert.NotNil(t, validateAutoscaling(policy))
+
+	policy = newTestAutoscaling()
+	policy.ScaleDownThreshold = policy.ScaleUpThreshold
+	assert.NotNil(t, validateAutoscaling(policy))
+}
+
+func TestParseNodeLoad(t *testing.T) {
+	cpu, ram, err := parseNodeLoad("42.5 63.1\n")
+	assert.Nil(t, err)
+	assert.Equal(t, 42.5, cpu)
+	assert.Equal(t, 63.1, ram)
+
+	_, _, err = parseNodeLoad("")
+	assert.NotNil(t, err)
+}
@@ -30,6 +30,7 @@ import (
 	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
 	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/clusterstate"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodestate"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/identity"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
@@ -121,6 +122,7 @@ func (c *Controller) Create(task concurrency.Task, req Request, f Foreman) (err
 		fmt.Sprintf("Ending creation of infrastructure of cluster '%s'", req.Name),
 	)()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(task.GetContext(), c.service, "cluster.create", "cluster:"+req.Name, nil)(&err)
 
 	c.Lock(task)
 
@@ -141,11 +143,39 @@ func (c *Controller) Create(task concurrency.Task, req Request, f Foreman) (err
 		return err
 	}
 
+	c.Identity.Name = req.Name
+	c.Identity.Flavor = req.Flavor
+	c.Identity.Complexity = req.Complexity
 	c.foreman = f.(*foreman)
 	c.Unlock(task)
 	return c.foreman.construct(task, req)
 }
 
+// Estimate returns the cost of the infrastructure of the Cluster described by req, without creating anything
+func (c *Controller) Estimate(task concurrency.Task, req Request, f Foreman) (_ *abstract.CostEstimate, err error) {
+	if c == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if task == nil {
+		return nil, fail.InvalidParameterError("task", "cannot be nil")
+	}
+	if f == nil {
+		return nil, fail.InvalidParameterError("f", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(task, fmt.Sprintf("('%s')", req.Name), true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	c.Lock(task)
+	c.Identity.Name = req.Name
+	c.Identity.Flavor = req.Flavor
+	c.Identity.Complexity = req.Complexity
+	c.foreman = f.(*foreman)
+	c.Unlock(task)
+	return c.foreman.estimate(task, req)
+}
+
 // GetService returns the service from the provider
 func (c *Controller) GetService(task concurrency.Task) iaas.Service {
 	var err error
@@ -260,9 +290,9 @@ func (c *Controller) CountNodes(task concurrency.Task) (_ uint, err error) {
 	var count uint
 
 	c.RLock(task)
-	err = c.GetProperties(task).LockForRead(property.NodesV1).ThenUse(
+	err = c.GetProperties(task).LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			count = uint(len(clonable.(*clusterpropsv1.Nodes).PrivateNodes))
+			count = uint(len(clonable.(*clusterpropsv2.Nodes).PrivateNodes))
 			return nil
 		},
 	)
@@ -274,8 +304,8 @@ func (c *Controller) CountNodes(task concurrency.Task) (_ uint, err error) {
 }
 
 // ListMasters lists the names of the master nodes in the Cluster
-func (c *Controller) ListMasters(task concurrency.Task) []*clusterpropsv1.Node {
-	var list []*clusterpropsv1.Node
+func (c *Controller) ListMasters(task concurrency.Task) []*clusterpropsv2.Node {
+	var list []*clusterpropsv2.Node
 	if task == nil {
 		return list
 	}
@@ -283,9 +313,9 @@ func (c *Controller) ListMasters(task concurrency.Task) []*clusterpropsv1.Node {
 	c.RLock(task)
 	defer c.RUnlock(task)
 
-	err := c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err := c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			list = clonable.(*clusterpropsv1.Nodes).Masters
+			list = clonable.(*clusterpropsv2.Nodes).Masters
 			return nil
 		},
 	)
@@ -305,10 +335,10 @@ func (c *Controller) ListMasterNames(task concurrency.Task) []string {
 	c.RLock(task)
 	defer c.RUnlock(task)
 
-	err := c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err := c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes).Masters
-			for _, v := range nodesV1 {
+			nodesV2 := clonable.(*clusterpropsv2.Nodes).Masters
+			for _, v := range nodesV2 {
 				list = append(list, v.Name)
 			}
 			return nil
@@ -330,10 +360,10 @@ func (c *Controller) ListMasterIDs(task concurrency.Task) []string {
 	c.RLock(task)
 	defer c.RUnlock(task)
 
-	err := c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err := c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes).Masters
-			for _, v := range nodesV1 {
+			nodesV2 := clonable.(*clusterpropsv2.Nodes).Masters
+			for _, v := range nodesV2 {
 				list = append(list, v.ID)
 			}
 			return nil
@@ -355,10 +385,10 @@ func (c *Controller) ListMasterIPs(task concurrency.Task) []string {
 	c.RLock(task)
 	defer c.RUnlock(task)
 
-	err := c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err := c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes).Masters
-			for _, v := range nodesV1 {
+			nodesV2 := clonable.(*clusterpropsv2.Nodes).Masters
+			for _, v := range nodesV2 {
 				list = append(list, v.PrivateIP)
 			}
 			return nil
@@ -371,17 +401,17 @@ func (c *Controller) ListMasterIPs(task concurrency.Task) []string {
 }
 
 // ListNodes lists the nodes in the Cluster
-func (c *Controller) ListNodes(task concurrency.Task) []*clusterpropsv1.Node {
-	var list []*clusterpropsv1.Node
+func (c *Controller) ListNodes(task concurrency.Task) []*clusterpropsv2.Node {
+	var list []*clusterpropsv2.Node
 	if task == nil {
 		return list
 	}
 	c.RLock(task)
 	defer c.RUnlock(task)
 
-	err := c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err := c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			list = clonable.(*clusterpropsv1.Nodes).PrivateNodes
+			list = clonable.(*clusterpropsv2.Nodes).PrivateNodes
 			return nil
 		},
 	)
@@ -401,10 +431,10 @@ func (c *Controller) ListNodeNames(task concurrency.Task) []string {
 	c.RLock(task)
 	defer c.RUnlock(task)
 
-	err := c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err := c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes).PrivateNodes
-			for _, v := range nodesV1 {
+			nodesV2 := clonable.(*clusterpropsv2.Nodes).PrivateNodes
+			for _, v := range nodesV2 {
 				list = append(list, v.Name)
 			}
 			return nil
@@ -426,10 +456,10 @@ func (c *Controller) ListNodeIDs(task concurrency.Task) []string {
 	c.RLock(task)
 	defer c.RUnlock(task)
 
-	err := c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err := c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes).PrivateNodes
-			for _, v := range nodesV1 {
+			nodesV2 := clonable.(*clusterpropsv2.Nodes).PrivateNodes
+			for _, v := range nodesV2 {
 				list = append(list, v.ID)
 			}
 			return nil
@@ -450,10 +480,10 @@ func (c *Controller) ListNodeIPs(task concurrency.Task) []string {
 	c.RLock(task)
 	defer c.RUnlock(task)
 
-	err := c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err := c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes).PrivateNodes
-			for _, v := range nodesV1 {
+			nodesV2 := clonable.(*clusterpropsv2.Nodes).PrivateNodes
+			for _, v := range nodesV2 {
 				list = append(list, v.PrivateIP)
 			}
 			return nil
@@ -485,12 +515,12 @@ func (c *Controller) GetNode(task concurrency.Task, hostID string) (host *pb.Hos
 	defer c.RUnlock(task)
 
 	found := false
-	err = c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err = c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes)
-			// found, _ := findNodeByID(nodesV1.PublicNodes, hostID)
+			nodesV2 := clonable.(*clusterpropsv2.Nodes)
+			// found, _ := findNodeByID(nodesV2.PublicNodes, hostID)
 			// if !found {
-			found, _ = findNodeByID(nodesV1.PrivateNodes, hostID)
+			found, _ = findNodeByID(nodesV2.PrivateNodes, hostID)
 			// }
 			return nil
 		},
@@ -514,9 +544,9 @@ func (c *Controller) SearchNode(task concurrency.Task, hostID string) bool {
 	defer c.RUnlock(task)
 
 	found := false
-	_ = c.Properties.LockForRead(property.NodesV1).ThenUse(
+	_ = c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			found, _ = findNodeByID(clonable.(*clusterpropsv1.Nodes).PrivateNodes, hostID)
+			found, _ = findNodeByID(clonable.(*clusterpropsv2.Nodes).PrivateNodes, hostID)
 			return nil
 		},
 	)
@@ -625,7 +655,14 @@ func (c *Controller) UpdateMetadata(task concurrency.Task, updatefn func() error
 	c.Lock(task)
 	defer c.Unlock(task)
 
-	c.metadata.Acquire()
+	// The lock on metadata is named after the cluster, even if the metadata have not been written yet
+	if c.metadata.name == "" {
+		c.metadata.name = c.Identity.Name
+	}
+	err = c.metadata.Acquire()
+	if err != nil {
+		return err
+	}
 	defer c.metadata.Release()
 
 	err = c.metadata.Reload(task)
@@ -667,13 +704,16 @@ func (c *Controller) DeleteMetadata(task concurrency.Task) (err error) {
 	c.Lock(task)
 	defer c.Unlock(task)
 
-	c.metadata.Acquire()
+	err = c.metadata.Acquire()
+	if err != nil {
+		return err
+	}
 	defer c.metadata.Release()
 
 	return c.metadata.Delete()
 }
 
-func findNodeByID(list []*clusterpropsv1.Node, ID string) (bool, int) {
+func findNodeByID(list []*clusterpropsv2.Node, ID string) (bool, int) {
 	var idx int
 	found := false
 	for i, v := range list {
@@ -686,7 +726,7 @@ func findNodeByID(list []*clusterpropsv1.Node, ID string) (bool, int) {
 	return found, idx
 }
 
-func findNodeByName(list []*clusterpropsv1.Node, name string) (bool, int) {
+func findNodeByName(list []*clusterpropsv2.Node, name string) (bool, int) {
 	var idx int
 	found := false
 	for i, v := range list {
@@ -699,7 +739,7 @@ func findNodeByName(list []*clusterpropsv1.Node, name string) (bool, int) {
 	return found, idx
 }
 
-func deleteNodeFromListByID(list []*clusterpropsv1.Node, ID string) (*clusterpropsv1.Node, []*clusterpropsv1.Node, error) {
+func deleteNodeFromListByID(list []*clusterpropsv2.Node, ID string) (*clusterpropsv2.Node, []*clusterpropsv2.Node, error) {
 	length := len(list)
 	found, idx := findNodeByID(list, ID)
 	if !found {
@@ -714,7 +754,7 @@ func deleteNodeFromListByID(list []*clusterpropsv1.Node, ID string) (*clusterpro
 	return node, list, nil
 }
 
-func deleteNodeFromListByName(list []*clusterpropsv1.Node, name string) (*clusterpropsv1.Node, error) {
+func deleteNodeFromListByName(list []*clusterpropsv2.Node, name string) (*clusterpropsv2.Node, error) {
 	length := len(list)
 	found, idx := findNodeByName(list, name)
 	if !found {
@@ -763,30 +803,8 @@ func (c *Controller) getImageAndNodeDescriptionUsedInClusterFromMetadata(task co
 
 	var hostImage string
 	nodeDef := &pb.HostDefinition{}
+	// A property.DefaultsV1 is migrated to property.DefaultsV2 when metadata are read (cf. init())
 	properties := c.GetProperties(task)
-	if !properties.Lookup(property.DefaultsV2) {
-		// If property.DefaultsV2 is not found but there is a property.DefaultsV1, converts it to DefaultsV2
-		err := properties.LockForRead(property.DefaultsV1).ThenUse(
-			func(clonable data.Clonable) error {
-				defaultsV1 := clonable.(*clusterpropsv1.Defaults)
-				return c.UpdateMetadata(
-					task, func() error {
-						return properties.LockForWrite(property.DefaultsV2).ThenUse(
-							func(clonable data.Clonable) error {
-								defaultsV2 := clonable.(*clusterpropsv2.Defaults)
-								convertDefaultsV1ToDefaultsV2(defaultsV1, defaultsV2)
-								return nil
-							},
-						)
-					},
-				)
-			},
-		)
-		if err != nil {
-			return "", nodeDef, err
-		}
-	}
-
 	err = properties.LockForRead(property.DefaultsV2).ThenUse(
 		func(clonable data.Clonable) error {
 			defaultsV2 := clonable.(*clusterpropsv2.Defaults)
@@ -818,6 +836,9 @@ func (c *Controller) AddNodes(task concurrency.Task, count int, req *pb.HostDefi
 	tracer := debug.NewTracer(task, fmt.Sprintf("(%d)", count), true)
 	defer tracer.GoingIn().OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		task.GetContext(), c.service, "cluster.expand", "cluster:"+c.Name, map[string]string{"count": fmt.Sprint(count)},
+	)(&err)
 
 	// retrieve cluster characteristics
 	hostImage, nodeDef, err := c.getImageAndNodeDescriptionUsedInClusterFromMetadata(task)
@@ -841,6 +862,12 @@ func (c *Controller) AddNodes(task concurrency.Task, count int, req *pb.HostDefi
 	}
 	nodeDef.Network = netCfg.NetworkID
 
+	// Refuses early if the tenant has not enough resources left for the nodes
+	err = c.GetService(task).CheckQuotas(hostDefinitionUsage(count, nodeDef, false))
+	if err != nil {
+		return nil, err
+	}
+
 	timeout := temporal.GetExecutionTimeout() + time.Duration(count)*time.Minute
 
 	creationFailed := false
@@ -946,6 +973,75 @@ func (c *Controller) AddNodes(task concurrency.Task, count int, req *pb.HostDefi
 	return hosts, nil
 }
 
+func init() {
+	metadata.RegisterPropertiesFolder(clusterFolderName, "clusters")
+	metadata.RegisterArchivedFolder(clusterFolderName)
+	serialize.PropertyMigrationRegistry.Register(
+		"clusters", serialize.PropertyMigration{
+			From: property.DefaultsV1,
+			To:   property.DefaultsV2,
+			Convert: func(from, to data.Clonable) error {
+				convertDefaultsV1ToDefaultsV2(from.(*clusterpropsv1.Defaults), to.(*clusterpropsv2.Defaults))
+				return nil
+			},
+		},
+	)
+	serialize.PropertyMigrationRegistry.Register(
+		"clusters", serialize.PropertyMigration{
+			From: property.NetworkV1,
+			To:   property.NetworkV2,
+			Convert: func(from, to data.Clonable) error {
+				convertNetworkV1ToNetworkV2(from.(*clusterpropsv1.Network), to.(*clusterpropsv2.Network))
+				return nil
+			},
+		},
+	)
+	serialize.PropertyMigrationRegistry.Register(
+		"clusters", serialize.PropertyMigration{
+			From: property.NodesV1,
+			To:   property.NodesV2,
+			Convert: func(from, to data.Clonable) error {
+				convertNodesV1ToNodesV2(from.(*clusterpropsv1.Nodes), to.(*clusterpropsv2.Nodes))
+				return nil
+			},
+		},
+	)
+}
+
+func convertNodesV1ToNodesV2(nodesV1 *clusterpropsv1.Nodes, nodesV2 *clusterpropsv2.Nodes) {
+	convert := func(in []*clusterpropsv1.Node) []*clusterpropsv2.Node {
+		out := make([]*clusterpropsv2.Node, 0, len(in))
+		for _, v := range in {
+			out = append(
+				out, &clusterpropsv2.Node{
+					ID:        v.ID,
+					Name:      v.Name,
+					PublicIP:  v.PublicIP,
+					PrivateIP: v.PrivateIP,
+					State:     nodestate.Unknown,
+				},
+			)
+		}
+		return out
+	}
+	nodesV2.Masters = convert(nodesV1.Masters)
+	nodesV2.PublicNodes = convert(nodesV1.PublicNodes)
+	nodesV2.PrivateNodes = convert(nodesV1.PrivateNodes)
+	nodesV2.MasterLastIndex = nodesV1.MasterLastIndex
+	nodesV2.PrivateLastIndex = nodesV1.PrivateLastIndex
+	nodesV2.PublicLastIndex = nodesV1.PublicLastIndex
+}
+
+func convertNetworkV1ToNetworkV2(networkV1 *clusterpropsv1.Network, networkV2 *clusterpropsv2.Network) {
+	networkV2.NetworkID = networkV1.NetworkID
+	networkV2.CIDR = networkV1.CIDR
+	networkV2.GatewayID = networkV1.GatewayID
+	networkV2.GatewayIP = networkV1.GatewayIP
+	networkV2.DefaultRouteIP = networkV1.GatewayIP
+	networkV2.PrimaryPublicIP = networkV1.PublicIP
+	networkV2.EndpointIP = networkV1.PublicIP
+}
+
 func convertDefaultsV1ToDefaultsV2(defaultsV1 *clusterpropsv1.Defaults, defaultsV2 *clusterpropsv2.Defaults) {
 	defaultsV2.Image = defaultsV1.Image
 	defaultsV2.MasterSizing = abstract.SizingRequirements{
@@ -1019,6 +1115,22 @@ func (c *Controller) ForceGetState(task concurrency.Task) (state clusterstate.En
 	if err != nil {
 		return clusterstate.Unknown, err
 	}
+	if state == clusterstate.Nominal {
+		// a gateway or a master found unhealthy by the last health probes degrades the cluster
+		c.RLock(task)
+		err = c.Properties.LockForRead(property.NodesV2).ThenUse(
+			func(clonable data.Clonable) error {
+				if isDegraded(clonable.(*clusterpropsv2.Nodes)) {
+					state = clusterstate.Degraded
+				}
+				return nil
+			},
+		)
+		c.RUnlock(task)
+		if err != nil {
+			return clusterstate.Unknown, err
+		}
+	}
 
 	err = c.UpdateMetadata(
 		task, func() error {
@@ -1077,16 +1189,16 @@ func (c *Controller) deleteMaster(task concurrency.Task, hostID string) (err err
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
 
 	// Removes master from cluster metadata
-	var master *clusterpropsv1.Node
+	var master *clusterpropsv2.Node
 	err = c.UpdateMetadata(
 		task, func() error {
-			return c.Properties.LockForWrite(property.NodesV1).ThenUse(
+			return c.Properties.LockForWrite(property.NodesV2).ThenUse(
 				func(clonable data.Clonable) error {
-					nodesV1 := clonable.(*clusterpropsv1.Nodes)
+					nodesV2 := clonable.(*clusterpropsv2.Nodes)
 
 					var innerErr error
-					var newMasters []*clusterpropsv1.Node
-					master, newMasters, innerErr = deleteNodeFromListByID(nodesV1.Masters, hostID)
+					var newMasters []*clusterpropsv2.Node
+					master, newMasters, innerErr = deleteNodeFromListByID(nodesV2.Masters, hostID)
 					if innerErr != nil {
 						switch innerErr.(type) {
 						case fail.ErrNotFound:
@@ -1095,7 +1207,7 @@ func (c *Controller) deleteMaster(task concurrency.Task, hostID string) (err err
 							return innerErr
 						}
 					}
-					nodesV1.Masters = newMasters
+					nodesV2.Masters = newMasters
 					return nil
 				},
 			)
@@ -1110,10 +1222,10 @@ func (c *Controller) deleteMaster(task concurrency.Task, hostID string) (err err
 		if err != nil {
 			derr := c.UpdateMetadata(
 				task, func() error {
-					return c.Properties.LockForWrite(property.NodesV1).ThenUse(
+					return c.Properties.LockForWrite(property.NodesV2).ThenUse(
 						func(clonable data.Clonable) error {
-							nodesV1 := clonable.(*clusterpropsv1.Nodes)
-							nodesV1.Masters = append(nodesV1.Masters, master)
+							nodesV2 := clonable.(*clusterpropsv2.Nodes)
+							nodesV2.Masters = append(nodesV2.Masters, master)
 							return nil
 						},
 					)
@@ -1135,33 +1247,37 @@ func (c *Controller) deleteMaster(task concurrency.Task, hostID string) (err err
 	return nil
 }
 
-// DeleteLastNode deletes the last Agent node added
-func (c *Controller) DeleteLastNode(task concurrency.Task, selectedMaster string) (err error) {
+// DeleteLastNode deletes the last Agent node added, and returns it
+func (c *Controller) DeleteLastNode(task concurrency.Task, selectedMaster string) (_ *clusterpropsv2.Node, err error) {
 	if c == nil {
-		return fail.InvalidInstanceError()
+		return nil, fail.InvalidInstanceError()
 	}
 	if task == nil {
-		return fail.InvalidParameterError("task", "cannot be nil")
+		return nil, fail.InvalidParameterError("task", "cannot be nil")
 	}
 
 	tracer := debug.NewTracer(task, fmt.Sprintf("('%s')", selectedMaster), true).GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(task.GetContext(), c.service, "cluster.shrink", "cluster:"+c.Name, nil)(&err)
 
-	var node *clusterpropsv1.Node
+	var node *clusterpropsv2.Node
 
 	// Get last node id from metadata
 	c.RLock(task)
-	err = c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err = c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes)
-			node = nodesV1.PrivateNodes[len(nodesV1.PrivateNodes)-1]
+			nodesV2 := clonable.(*clusterpropsv2.Nodes)
+			if len(nodesV2.PrivateNodes) == 0 {
+				return fail.NotFoundError("the cluster has no node to delete")
+			}
+			node = nodesV2.PrivateNodes[len(nodesV2.PrivateNodes)-1]
 			return nil
 		},
 	)
 	c.RUnlock(task)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if selectedMaster == "" {
@@ -1169,11 +1285,15 @@ func (c *Controller) DeleteLastNode(task concurrency.Task, selectedMaster string
 		if err != nil {
 			errDelNode := c.deleteNode(task, node, "")
 			err = fail.AddConsequence(err, errDelNode)
-			return err
+			return nil, err
 		}
 	}
 
-	return c.deleteNode(task, node, selectedMaster)
+	err = c.deleteNode(task, node, selectedMaster)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
 }
 
 // DeleteSpecificNode deletes the node specified by its ID
@@ -1222,23 +1342,26 @@ func (c *Controller) DeleteSpecificNode(task concurrency.Task, hostID string, se
 	tracer := debug.NewTracer(task, fmt.Sprintf("(%s)", hostID), true).GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		task.GetContext(), c.service, "cluster.node.delete", "cluster:"+c.Name, map[string]string{"node": hostID},
+	)(&err)
 
 	var (
-		node *clusterpropsv1.Node
+		node *clusterpropsv2.Node
 	)
 
 	c.RLock(task)
-	err = c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err = c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes)
+			nodesV2 := clonable.(*clusterpropsv2.Nodes)
 			var (
 				idx   int
 				found bool
 			)
-			if found, idx = findNodeByID(nodesV1.PrivateNodes, hostID); !found {
+			if found, idx = findNodeByID(nodesV2.PrivateNodes, hostID); !found {
 				return fail.NotFoundError(fmt.Sprintf("failed to find node '%s'", hostID))
 			}
-			node = nodesV1.PrivateNodes[idx]
+			node = nodesV2.PrivateNodes[idx]
 			return nil
 		},
 	)
@@ -1269,7 +1392,7 @@ func (c *Controller) DeleteSpecificNode(task concurrency.Task, hostID string, se
 }
 
 // deleteNode deletes the node specified by its ID
-func (c *Controller) deleteNode(task concurrency.Task, node *clusterpropsv1.Node, selectedMaster string) (err error) {
+func (c *Controller) deleteNode(task concurrency.Task, node *clusterpropsv2.Node, selectedMaster string) (err error) {
 	if c == nil {
 		return fail.InvalidInstanceError()
 	}
@@ -1326,16 +1449,16 @@ func (c *Controller) deleteNode(task concurrency.Task, node *clusterpropsv1.Node
 	// Removes node from cluster metadata (done before really deleting node to prevent operations on the node in parallel)
 	err = c.UpdateMetadata(
 		task, func() error {
-			return c.Properties.LockForWrite(property.NodesV1).ThenUse(
+			return c.Properties.LockForWrite(property.NodesV2).ThenUse(
 				func(clonable data.Clonable) error {
-					nodesV1 := clonable.(*clusterpropsv1.Nodes)
+					nodesV2 := clonable.(*clusterpropsv2.Nodes)
 					var innerErr error
-					var newMasters []*clusterpropsv1.Node
-					node, newMasters, innerErr = deleteNodeFromListByID(nodesV1.PrivateNodes, node.ID)
+					var newMasters []*clusterpropsv2.Node
+					node, newMasters, innerErr = deleteNodeFromListByID(nodesV2.PrivateNodes, node.ID)
 					if innerErr != nil {
 						return innerErr
 					}
-					nodesV1.PrivateNodes = newMasters
+					nodesV2.PrivateNodes = newMasters
 					return nil
 				},
 			)
@@ -1350,10 +1473,10 @@ func (c *Controller) deleteNode(task concurrency.Task, node *clusterpropsv1.Node
 		if err != nil && hostExistsInNodeMetadata != nil && *hostExistsInNodeMetadata == true {
 			derr := c.UpdateMetadata(
 				task, func() error {
-					return c.Properties.LockForWrite(property.NodesV1).ThenUse(
+					return c.Properties.LockForWrite(property.NodesV2).ThenUse(
 						func(clonable data.Clonable) error {
-							nodesV1 := clonable.(*clusterpropsv1.Nodes)
-							nodesV1.PrivateNodes = append(nodesV1.PrivateNodes, node)
+							nodesV2 := clonable.(*clusterpropsv2.Nodes)
+							nodesV2.PrivateNodes = append(nodesV2.PrivateNodes, node)
 							return nil
 						},
 					)
@@ -1410,6 +1533,7 @@ func (c *Controller) Delete(task concurrency.Task) (err error) {
 	tracer := debug.NewTracer(task, "", true).GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(task.GetContext(), c.service, "cluster.delete", "cluster:"+c.Name, nil)(&err)
 
 	return c.foreman.destruct(task)
 }
@@ -1442,6 +1566,7 @@ func (c *Controller) Stop(task concurrency.Task) (err error) {
 	tracer := debug.NewTracer(task, "", true).GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(task.GetContext(), c.service, "cluster.stop", "cluster:"+c.Name, nil)(&err)
 
 	state, _ := c.ForceGetState(task)
 	switch state {
@@ -1471,16 +1596,16 @@ func (c *Controller) Stop(task concurrency.Task) (err error) {
 	// Stops the abstract of the cluster
 
 	var (
-		nodes                         []*clusterpropsv1.Node
-		masters                       []*clusterpropsv1.Node
+		nodes                         []*clusterpropsv2.Node
+		masters                       []*clusterpropsv2.Node
 		gatewayID, secondaryGatewayID string
 	)
 	c.RLock(task)
-	err = c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err = c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes)
-			masters = nodesV1.Masters
-			nodes = nodesV1.PrivateNodes
+			nodesV2 := clonable.(*clusterpropsv2.Nodes)
+			masters = nodesV2.Masters
+			nodes = nodesV2.PrivateNodes
 			return nil
 		},
 	)
@@ -1588,6 +1713,7 @@ func (c *Controller) Start(task concurrency.Task) (err error) {
 	tracer := debug.NewTracer(task, "", true).GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(task.GetContext(), c.service, "cluster.start", "cluster:"+c.Name, nil)(&err)
 
 	state, err := c.ForceGetState(task)
 	if err != nil {
@@ -1619,16 +1745,16 @@ func (c *Controller) Start(task concurrency.Task) (err error) {
 
 	// Starts the abstract of the cluster
 	var (
-		nodes                         []*clusterpropsv1.Node
-		masters                       []*clusterpropsv1.Node
+		nodes                         []*clusterpropsv2.Node
+		masters                       []*clusterpropsv2.Node
 		gatewayID, secondaryGatewayID string
 	)
 	c.RLock(task)
-	err = c.Properties.LockForRead(property.NodesV1).ThenUse(
+	err = c.Properties.LockForRead(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes)
-			masters = nodesV1.Masters
-			nodes = nodesV1.PrivateNodes
+			nodesV2 := clonable.(*clusterpropsv2.Nodes)
+			masters = nodesV2.Masters
+			nodes = nodesV2.PrivateNodes
 			return nil
 		},
 	)
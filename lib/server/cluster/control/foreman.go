@@ -24,6 +24,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	txttmpl "text/template"
@@ -44,6 +45,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/clusterstate"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/complexity"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodestate"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodetype"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
@@ -106,6 +108,11 @@ type Makers struct {
 	LeaveMasterFromCluster      func(task concurrency.Task, f Foreman, pbHost *pb.Host) error
 	LeaveNodeFromCluster        func(task concurrency.Task, f Foreman, pbHost *pb.Host, selectedMaster string) error
 	GetState                    func(task concurrency.Task, f Foreman) (clusterstate.Enum, error)
+	GetLoad                     func(task concurrency.Task, f Foreman) (*Load, error)                                // load of the nodes, used by autoscaling
+	DrainNode                   func(task concurrency.Task, f Foreman, pbHost *pb.Host, selectedMaster string) error // moves the workloads off a node before its removal
+	UndrainNode                 func(task concurrency.Task, f Foreman, pbHost *pb.Host, selectedMaster string) error // cancels DrainNode
+	// GetNodeState returns the state of a node for the cluster and its reason, used by health probes
+	GetNodeState func(task concurrency.Task, f Foreman, pbHost *pb.Host, selectedMaster string) (nodestate.Enum, string, error)
 }
 
 //go:generate mockgen -destination=../mocks/mock_foreman.go -package=mocks github.com/CS-SI/SafeScale/lib/server/cluster/control Foreman
@@ -224,100 +231,9 @@ func (b *foreman) construct(task concurrency.Task, req Request) (err error) {
 		return err
 	}
 
-	// Determine default image
-	var imageID string
-	if req.NodesDef != nil {
-		imageID = req.NodesDef.ImageId
-	}
-	if imageID == "" && b.makers.DefaultImage != nil {
-		imageID = b.makers.DefaultImage(task, b)
-	}
-	if imageID == "" {
-		imageID = "Ubuntu 18.04" // FIXME: Remove hardcoded default
-	}
-
-	// Determine Gateway sizing
-	var gatewaysDefault *pb.HostDefinition
-	if b.makers.DefaultGatewaySizing != nil {
-		gatewaysDefault = complementHostDefinition(nil, b.makers.DefaultGatewaySizing(task, b))
-	} else {
-		gatewaysDefault = &pb.HostDefinition{
-			Sizing: &pb.HostSizing{
-				MinCpuCount: 2,
-				MaxCpuCount: 4,
-				MinRamSize:  7.0,
-				MaxRamSize:  16.0,
-				MinDiskSize: 50,
-				GpuCount:    -1,
-			},
-		}
-	}
-	gatewaysDefault.ImageId = imageID
-
-	gatewaysDef := complementHostDefinition(
-		req.GatewaysDef, gatewaysDefault,
-	) // FIXME: OPP Issue warning if not enough size
-
-	if lower, err := gatewaysDef.LowerThan(gatewaysDefault); err == nil && lower {
-		if !req.Force {
-			return fail.Errorf(
-				fmt.Sprintf(
-					"requested gateway sizing %s less than recommended %s", spew.Sdump(gatewaysDef),
-					spew.Sdump(gatewaysDefault),
-				), nil,
-			)
-		}
-	}
-
-	// Determine master sizing
-	var mastersDefault *pb.HostDefinition
-	if b.makers.DefaultMasterSizing != nil {
-		mastersDefault = complementHostDefinition(nil, b.makers.DefaultMasterSizing(task, b))
-	} else {
-		mastersDefault = &pb.HostDefinition{
-			Sizing: &pb.HostSizing{
-				MinCpuCount: 4,
-				MaxCpuCount: 8,
-				MinRamSize:  15.0,
-				MaxRamSize:  32.0,
-				MinDiskSize: 100,
-				GpuCount:    -1,
-			},
-		}
-	}
-	// Note: no way yet to define master sizing from cli...
-	mastersDefault.ImageId = imageID
-	mastersDef := complementHostDefinition(req.MastersDef, mastersDefault) // FIXME: OPP Issue warning if not enough size
-
-	if lower, err := mastersDef.LowerThan(mastersDefault); err == nil && lower {
-		if !req.Force {
-			return fail.Errorf("requested master sizing less than recommended", nil)
-		}
-	}
-
-	// Determine node sizing
-	var nodesDefault *pb.HostDefinition
-	if b.makers.DefaultNodeSizing != nil {
-		nodesDefault = complementHostDefinition(nil, b.makers.DefaultNodeSizing(task, b))
-	} else {
-		nodesDefault = &pb.HostDefinition{
-			Sizing: &pb.HostSizing{
-				MinCpuCount: 4,
-				MaxCpuCount: 8,
-				MinRamSize:  15.0,
-				MaxRamSize:  32.0,
-				MinDiskSize: 100,
-				GpuCount:    -1,
-			},
-		}
-	}
-	nodesDefault.ImageId = imageID
-	nodesDef := complementHostDefinition(req.NodesDef, nodesDefault) // FIXME: OPP Issue warning if not enough size
-
-	if lower, err := nodesDef.LowerThan(nodesDefault); err == nil && lower {
-		if !req.Force {
-			return fail.Errorf("requested node sizing less than recommended", nil)
-		}
+	gatewaysDef, mastersDef, nodesDef, err := b.determineSizings(task, req)
+	if err != nil {
+		return err
 	}
 
 	// Initialize service to use
@@ -332,13 +248,21 @@ func (b *foreman) construct(task concurrency.Task, req Request) (err error) {
 	}
 
 	// Determine if Gateway Failover must be set
-	caps := svc.GetCapabilities()
-	gwFailoverDisabled := req.Complexity == complexity.Small || !caps.PrivateVirtualIP
-	for k := range req.DisabledDefaultFeatures {
-		if k == "gateway-failover" {
-			gwFailoverDisabled = true
-			break
-		}
+	gwFailoverDisabled := isGatewayFailoverDisabled(svc, req)
+
+	// Refuses early if the tenant has not enough resources left for the cluster
+	gatewayCount := 2
+	if gwFailoverDisabled {
+		gatewayCount = 1
+	}
+	masterCount, nodeCount, _ := b.determineRequiredNodes(task)
+	needed := hostDefinitionUsage(gatewayCount, gatewaysDef, true)
+	needed.Networks = 1
+	needed.Add(hostDefinitionUsage(masterCount, mastersDef, false))
+	needed.Add(hostDefinitionUsage(nodeCount, nodesDef, false))
+	err = svc.CheckQuotas(needed)
+	if err != nil {
+		return err
 	}
 
 	// Creates network
@@ -465,7 +389,7 @@ func (b *foreman) construct(task concurrency.Task, req Request) (err error) {
 					if merr != nil {
 						return merr
 					}
-					defaultsV2.Image = imageID
+					defaultsV2.Image = nodesDef.ImageId
 					return nil
 				},
 			)
@@ -1211,6 +1135,19 @@ func complementHostDefinition(req *pb.HostDefinition, def *pb.HostDefinition) *p
 	return finalDef
 }
 
+// hostDefinitionUsage returns the resources needed to create count hosts using the minimal sizing of def
+func hostDefinitionUsage(count int, def *pb.HostDefinition, public bool) abstract.Usage {
+	usage := abstract.Usage{
+		Cores:     count * int(def.GetSizing().GetMinCpuCount()),
+		RAMSize:   float32(count) * def.GetSizing().GetMinRamSize(),
+		Instances: count,
+	}
+	if public {
+		usage.PublicIPs = count
+	}
+	return usage
+}
+
 // GetState returns "actively" (if active state is proposed by maker) the current state of the cluster
 func (b *foreman) getState(task concurrency.Task) (clusterstate.Enum, error) {
 	if b.makers.GetState != nil {
@@ -1356,6 +1293,171 @@ func (b *foreman) configureCluster(task concurrency.Task, params concurrency.Tas
 
 }
 
+// estimate returns the cost of the gateways, masters and nodes of the cluster described by req
+func (b *foreman) estimate(task concurrency.Task, req Request) (_ *abstract.CostEstimate, err error) {
+	tracer := debug.NewTracer(task, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	gatewaysDef, mastersDef, nodesDef, err := b.determineSizings(task, req)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := b.cluster.GetService(task)
+	gatewayCount := 2
+	if isGatewayFailoverDisabled(svc, req) {
+		gatewayCount = 1
+	}
+	masterCount, nodeCount, _ := b.determineRequiredNodes(task)
+
+	groups := []struct {
+		role  string
+		count int
+		def   *pb.HostDefinition
+	}{
+		{"gateway", gatewayCount, gatewaysDef},
+		{"master", masterCount, mastersDef},
+		{"node", nodeCount, nodesDef},
+	}
+	estimate := &abstract.CostEstimate{}
+	for _, g := range groups {
+		if g.count == 0 {
+			continue
+		}
+		sizing, err := srvutils.FromPBHostSizing(g.def.Sizing)
+		if err != nil {
+			return nil, err
+		}
+		templates, err := svc.SelectTemplatesBySize(sizing, req.Force)
+		if err != nil {
+			return nil, err
+		}
+		if len(templates) == 0 {
+			return nil, fail.NotFoundError(
+				fmt.Sprintf("failed to find template corresponding to requested sizing of %ss", g.role),
+			)
+		}
+		price, err := svc.GetPricePerHour(*templates[0])
+		if err != nil {
+			return nil, err
+		}
+		estimate.Add(g.role, g.count, templates[0], price)
+	}
+	return estimate, nil
+}
+
+// determineSizings returns the definitions of gateways, masters and nodes, built from the request completed
+// with the defaults of the flavor
+func (b *foreman) determineSizings(task concurrency.Task, req Request) (gatewaysDef, mastersDef, nodesDef *pb.HostDefinition, err error) {
+	// Determine default image
+	var imageID string
+	if req.NodesDef != nil {
+		imageID = req.NodesDef.ImageId
+	}
+	if imageID == "" && b.makers.DefaultImage != nil {
+		imageID = b.makers.DefaultImage(task, b)
+	}
+	if imageID == "" {
+		imageID = "Ubuntu 18.04" // FIXME: Remove hardcoded default
+	}
+
+	// Determine Gateway sizing
+	var gatewaysDefault *pb.HostDefinition
+	if b.makers.DefaultGatewaySizing != nil {
+		gatewaysDefault = complementHostDefinition(nil, b.makers.DefaultGatewaySizing(task, b))
+	} else {
+		gatewaysDefault = &pb.HostDefinition{
+			Sizing: &pb.HostSizing{
+				MinCpuCount: 2,
+				MaxCpuCount: 4,
+				MinRamSize:  7.0,
+				MaxRamSize:  16.0,
+				MinDiskSize: 50,
+				GpuCount:    -1,
+			},
+		}
+	}
+	gatewaysDefault.ImageId = imageID
+
+	gatewaysDef = complementHostDefinition(
+		req.GatewaysDef, gatewaysDefault,
+	) // FIXME: OPP Issue warning if not enough size
+
+	if lower, err := gatewaysDef.LowerThan(gatewaysDefault); err == nil && lower {
+		if !req.Force {
+			return nil, nil, nil, fail.Errorf(
+				fmt.Sprintf(
+					"requested gateway sizing %s less than recommended %s", spew.Sdump(gatewaysDef),
+					spew.Sdump(gatewaysDefault),
+				), nil,
+			)
+		}
+	}
+
+	// Determine master sizing
+	var mastersDefault *pb.HostDefinition
+	if b.makers.DefaultMasterSizing != nil {
+		mastersDefault = complementHostDefinition(nil, b.makers.DefaultMasterSizing(task, b))
+	} else {
+		mastersDefault = &pb.HostDefinition{
+			Sizing: &pb.HostSizing{
+				MinCpuCount: 4,
+				MaxCpuCount: 8,
+				MinRamSize:  15.0,
+				MaxRamSize:  32.0,
+				MinDiskSize: 100,
+				GpuCount:    -1,
+			},
+		}
+	}
+	// Note: no way yet to define master sizing from cli...
+	mastersDefault.ImageId = imageID
+	mastersDef = complementHostDefinition(req.MastersDef, mastersDefault) // FIXME: OPP Issue warning if not enough size
+
+	if lower, err := mastersDef.LowerThan(mastersDefault); err == nil && lower {
+		if !req.Force {
+			return nil, nil, nil, fail.Errorf("requested master sizing less than recommended", nil)
+		}
+	}
+
+	// Determine node sizing
+	var nodesDefault *pb.HostDefinition
+	if b.makers.DefaultNodeSizing != nil {
+		nodesDefault = complementHostDefinition(nil, b.makers.DefaultNodeSizing(task, b))
+	} else {
+		nodesDefault = &pb.HostDefinition{
+			Sizing: &pb.HostSizing{
+				MinCpuCount: 4,
+				MaxCpuCount: 8,
+				MinRamSize:  15.0,
+				MaxRamSize:  32.0,
+				MinDiskSize: 100,
+				GpuCount:    -1,
+			},
+		}
+	}
+	nodesDefault.ImageId = imageID
+	nodesDef = complementHostDefinition(req.NodesDef, nodesDefault) // FIXME: OPP Issue warning if not enough size
+
+	if lower, err := nodesDef.LowerThan(nodesDefault); err == nil && lower {
+		if !req.Force {
+			return nil, nil, nil, fail.Errorf("requested node sizing less than recommended", nil)
+		}
+	}
+
+	return gatewaysDef, mastersDef, nodesDef, nil
+}
+
+// isGatewayFailoverDisabled tells if the cluster described by req uses a single gateway
+func isGatewayFailoverDisabled(svc iaas.Service, req Request) bool {
+	if req.Complexity == complexity.Small || !svc.GetCapabilities().PrivateVirtualIP {
+		return true
+	}
+	_, ok := req.DisabledDefaultFeatures["gateway-failover"]
+	return ok
+}
+
 func (b *foreman) determineRequiredNodes(task concurrency.Task) (int, int, int) {
 	if b.makers.MinimumRequiredServers != nil {
 		return b.makers.MinimumRequiredServers(task, b)
@@ -1679,7 +1781,7 @@ func (b *foreman) joinNodesFromList(task concurrency.Task, hosts []string) error
 			return fmt.Errorf("failed to add label to docker Swarm worker '%s': %s", pbHost.Name, stderr)
 		}
 
-		if b.makers.JoinMasterToCluster != nil {
+		if b.makers.JoinNodeToCluster != nil {
 			err = b.makers.JoinNodeToCluster(task, b, pbHost)
 			if err != nil {
 				return err
@@ -2131,9 +2233,26 @@ func (b *foreman) taskCreateMasters(t concurrency.Task, params concurrency.TaskP
 
 	logrus.Debugf("[cluster %s] creating %d master%s...\n", clusterName, count, utils.Plural(count))
 
+	// Spreads masters over availability zones and hypervisors, unless the placement is explicitly set
+	var zones []string
+	complx := b.cluster.GetIdentity(t).Complexity
+	if count > 1 && (complx == complexity.Normal || complx == complexity.Large) {
+		if def.Zone == "" {
+			zones = b.listAvailabilityZones()
+		}
+		if def.ServerGroup == "" && def.Placement == pb.PlacementPolicy_NONE {
+			def.ServerGroup = clusterName + "-masters"
+			def.Placement = pb.PlacementPolicy_ANTIAFFINITY
+		}
+	}
+
 	var subtasks []concurrency.Task
 	timeout := timeoutCtxHost + time.Duration(count)*time.Minute
 	for i := 0; i < count; i++ {
+		masterDef := def.Clone()
+		if len(zones) > 0 {
+			masterDef.Zone = zones[i%len(zones)]
+		}
 		subtask, err := t.New()
 		if err != nil {
 			return nil, err
@@ -2141,7 +2260,7 @@ func (b *foreman) taskCreateMasters(t concurrency.Task, params concurrency.TaskP
 		subtask, err = subtask.Start(
 			b.taskCreateMaster, data.Map{
 				"index":     i + 1,
-				"masterDef": def,
+				"masterDef": masterDef,
 				"timeout":   timeout,
 				"nokeep":    nokeep,
 			},
@@ -2167,6 +2286,26 @@ func (b *foreman) taskCreateMasters(t concurrency.Task, params concurrency.TaskP
 	return nil, nil
 }
 
+// listAvailabilityZones returns the sorted list of the available zones, or nil if there is only one
+func (b *foreman) listAvailabilityZones() []string {
+	azList, err := b.cluster.service.ListAvailabilityZones()
+	if err != nil {
+		logrus.Warnf("[cluster %s] failed to list availability zones, masters will not be spread: %v", b.cluster.Name, err)
+		return nil
+	}
+	var zones []string
+	for zone, available := range azList {
+		if available {
+			zones = append(zones, zone)
+		}
+	}
+	if len(zones) < 2 {
+		return nil
+	}
+	sort.Strings(zones)
+	return zones
+}
+
 // taskCreateMaster creates one master
 // This function is intended to be call as a goroutine
 func (b *foreman) taskCreateMaster(t concurrency.Task, params concurrency.TaskParameters) (result concurrency.TaskResult, err error) {
@@ -2214,18 +2353,19 @@ func (b *foreman) taskCreateMaster(t concurrency.Task, params concurrency.TaskPa
 		// Updates cluster metadata to keep track of created host, before testing if an error occurred during the creation
 		mErr := b.cluster.UpdateMetadata(
 			t, func() error {
-				// Locks for write the NodesV1 extension...
-				return b.cluster.GetProperties(t).LockForWrite(property.NodesV1).ThenUse(
+				// Locks for write the NodesV2 extension...
+				return b.cluster.GetProperties(t).LockForWrite(property.NodesV2).ThenUse(
 					func(clonable data.Clonable) error {
-						nodesV1 := clonable.(*clusterpropsv1.Nodes)
+						nodesV2 := clonable.(*clusterpropsv2.Nodes)
 						// Update swarmCluster definition in Object Storage
-						node := &clusterpropsv1.Node{
+						node := &clusterpropsv2.Node{
 							ID:        pbHost.Id,
 							Name:      pbHost.Name,
 							PrivateIP: pbHost.PrivateIp,
 							PublicIP:  pbHost.PublicIp,
+							State:     nodestate.Unknown,
 						}
-						nodesV1.Masters = append(nodesV1.Masters, node)
+						nodesV2.Masters = append(nodesV2.Masters, node)
 						return nil
 					},
 				)
@@ -2520,7 +2660,7 @@ func (b *foreman) taskCreateNode(t concurrency.Task, params concurrency.TaskPara
 	}
 
 	clientHost := client.New().Host
-	var node *clusterpropsv1.Node
+	var node *clusterpropsv2.Node
 	pbHost, err := clientHost.Create(hostDef, timeout)
 	if pbHost != nil {
 		defer func() {
@@ -2533,18 +2673,19 @@ func (b *foreman) taskCreateNode(t concurrency.Task, params concurrency.TaskPara
 		}()
 		mErr := b.cluster.UpdateMetadata(
 			t, func() error {
-				// Locks for write the NodesV1 extension...
-				return b.cluster.GetProperties(t).LockForWrite(property.NodesV1).ThenUse(
+				// Locks for write the NodesV2 extension...
+				return b.cluster.GetProperties(t).LockForWrite(property.NodesV2).ThenUse(
 					func(clonable data.Clonable) error {
-						nodesV1 := clonable.(*clusterpropsv1.Nodes)
+						nodesV2 := clonable.(*clusterpropsv2.Nodes)
 						// Registers the new Agent in the swarmCluster struct
-						node = &clusterpropsv1.Node{
+						node = &clusterpropsv2.Node{
 							ID:        pbHost.Id,
 							Name:      pbHost.Name,
 							PrivateIP: pbHost.PrivateIp,
 							PublicIP:  pbHost.PublicIp,
+							State:     nodestate.Unknown,
 						}
-						nodesV1.PrivateNodes = append(nodesV1.PrivateNodes, node)
+						nodesV2.PrivateNodes = append(nodesV2.PrivateNodes, node)
 						return nil
 					},
 				)
@@ -2579,6 +2720,14 @@ func (b *foreman) taskCreateNode(t concurrency.Task, params concurrency.TaskPara
 		return nil, err
 	}
 
+	if b.makers.CreateNode != nil {
+		err = b.makers.CreateNode(t, b, index, pbHost)
+		if err != nil {
+			logrus.Debugf("[%s] failure running flavor-specific creation of node", hostLabel)
+			return nil, err
+		}
+	}
+
 	logrus.Debugf("[%s] host resource creation successful.", hostLabel)
 	return pbHost.Id, nil
 }
@@ -2956,16 +3105,16 @@ func (b *foreman) buildHostname(task concurrency.Task, core string, nodeType nod
 
 	// Locks for write the manager extension...
 	b.cluster.Lock(task)
-	outerErr := b.cluster.GetProperties(task).LockForWrite(property.NodesV1).ThenUse(
+	outerErr := b.cluster.GetProperties(task).LockForWrite(property.NodesV2).ThenUse(
 		func(clonable data.Clonable) error {
-			nodesV1 := clonable.(*clusterpropsv1.Nodes)
+			nodesV2 := clonable.(*clusterpropsv2.Nodes)
 			switch nodeType {
 			case nodetype.Node:
-				nodesV1.PrivateLastIndex++
-				index = nodesV1.PrivateLastIndex
+				nodesV2.PrivateLastIndex++
+				index = nodesV2.PrivateLastIndex
 			case nodetype.Master:
-				nodesV1.MasterLastIndex++
-				index = nodesV1.MasterLastIndex
+				nodesV2.MasterLastIndex++
+				index = nodesV2.MasterLastIndex
 			}
 			return nil
 		},
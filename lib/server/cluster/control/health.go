@@ -0,0 +1,511 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/client"
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/clusterstate"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodestate"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// DefaultHealthCheckInterval is the default delay between 2 health probes of the clusters
+	DefaultHealthCheckInterval = 5 * time.Minute
+	// DefaultRepairDelay is the default delay during which a node has to be found dead before being replaced
+	DefaultRepairDelay = 10 * time.Minute
+)
+
+// nodeProbe is the result of the health probe of a host of the cluster
+type nodeProbe struct {
+	id     string
+	name   string
+	state  nodestate.Enum
+	reason string
+}
+
+// GetHealth returns the health policy of the cluster
+func (c *Controller) GetHealth(task concurrency.Task) (policy *clusterpropsv1.Health, err error) {
+	if c == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if task == nil {
+		return nil, fail.InvalidParameterError("task", "cannot be nil")
+	}
+
+	c.RLock(task)
+	defer c.RUnlock(task)
+
+	err = c.Properties.LockForRead(property.HealthV1).ThenUse(
+		func(clonable data.Clonable) error {
+			policy = clonable.Clone().(*clusterpropsv1.Health)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if policy.RepairDelay == 0 {
+		policy.RepairDelay = DefaultRepairDelay
+	}
+	return policy, nil
+}
+
+// SetHealth replaces the health policy of the cluster; a zero repair delay is replaced by its default value, and
+// the dates of the last check and repair are kept
+func (c *Controller) SetHealth(task concurrency.Task, policy *clusterpropsv1.Health) (err error) {
+	if c == nil {
+		return fail.InvalidInstanceError()
+	}
+	if task == nil {
+		return fail.InvalidParameterError("task", "cannot be nil")
+	}
+	if policy == nil {
+		return fail.InvalidParameterError("policy", "cannot be nil")
+	}
+	if policy.RepairDelay < 0 {
+		return fail.InvalidParameterError("policy.RepairDelay", "cannot be negative")
+	}
+
+	tracer := debug.NewTracer(task, fmt.Sprintf("(%v, %s)", policy.AutoRepair, policy.RepairDelay), true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	newPolicy := policy.Clone().(*clusterpropsv1.Health)
+	if newPolicy.RepairDelay == 0 {
+		newPolicy.RepairDelay = DefaultRepairDelay
+	}
+
+	return c.UpdateMetadata(
+		task, func() error {
+			return c.Properties.LockForWrite(property.HealthV1).ThenUse(
+				func(clonable data.Clonable) error {
+					healthV1 := clonable.(*clusterpropsv1.Health)
+					newPolicy.LastCheck = healthV1.LastCheck
+					newPolicy.LastRepair = healthV1.LastRepair
+					healthV1.Replace(newPolicy)
+					return nil
+				},
+			)
+		},
+	)
+}
+
+// CheckHealth probes the gateways, masters and nodes of the cluster, combining the state of the host for the provider,
+// its reachability by SSH and the state of the node for the cluster; records the results in the metadata of the
+// cluster, replaces the dead nodes if the health policy allows it, and returns the nodes with their state
+func (c *Controller) CheckHealth(task concurrency.Task) (nodes *clusterpropsv2.Nodes, err error) {
+	if c == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if task == nil {
+		return nil, fail.InvalidParameterError("task", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(task, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	policy, err := c.GetHealth(task)
+	if err != nil {
+		return nil, err
+	}
+	netCfg, err := c.GetNetworkConfig(task)
+	if err != nil {
+		return nil, err
+	}
+	gatewayIDs := []string{netCfg.GatewayID}
+	if netCfg.SecondaryGatewayID != "" {
+		gatewayIDs = append(gatewayIDs, netCfg.SecondaryGatewayID)
+	}
+
+	// without available master, the state of the nodes for the cluster cannot be asked
+	selectedMaster, err := c.FindAvailableMaster(task)
+	if err != nil {
+		log.Warnf("[cluster %s] no master available to check the state of the nodes: %v", c.Name, err)
+		selectedMaster = ""
+	}
+
+	var (
+		subtasks []concurrency.Task
+		errs     []string
+	)
+	probe := func(hostID string, checkCluster bool) {
+		subtask, err := task.New()
+		if err == nil {
+			subtask, err = subtask.Start(
+				c.taskProbeNode, data.Map{
+					"hostID":         hostID,
+					"selectedMaster": selectedMaster,
+					"checkCluster":   checkCluster,
+				},
+			)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to start probe of host '%s': %v", hostID, err))
+			return
+		}
+		subtasks = append(subtasks, subtask)
+	}
+	for _, id := range gatewayIDs {
+		probe(id, false)
+	}
+	for _, id := range c.ListMasterIDs(task) {
+		probe(id, true)
+	}
+	for _, id := range c.ListNodeIDs(task) {
+		probe(id, true)
+	}
+	probes := map[string]nodeProbe{}
+	for _, s := range subtasks {
+		result, err := s.Wait()
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		p := result.(nodeProbe)
+		probes[p.id] = p
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	now := time.Now()
+	err = c.UpdateMetadata(
+		task, func() error {
+			innerErr := c.Properties.LockForWrite(property.NodesV2).ThenUse(
+				func(clonable data.Clonable) error {
+					nodesV2 := clonable.(*clusterpropsv2.Nodes)
+					gateways := make([]*clusterpropsv2.Node, 0, len(gatewayIDs))
+					for _, id := range gatewayIDs {
+						gateway := &clusterpropsv2.Node{ID: id, State: nodestate.Unknown}
+						for _, v := range nodesV2.Gateways {
+							if v.ID == id {
+								gateway = v
+								break
+							}
+						}
+						gateways = append(gateways, gateway)
+					}
+					nodesV2.Gateways = gateways
+					for _, list := range [][]*clusterpropsv2.Node{nodesV2.Gateways, nodesV2.Masters, nodesV2.PrivateNodes} {
+						for _, node := range list {
+							if p, ok := probes[node.ID]; ok {
+								recordNodeProbe(node, p, now)
+							}
+						}
+					}
+					nodes = nodesV2.Clone().(*clusterpropsv2.Nodes)
+					return nil
+				},
+			)
+			if innerErr != nil {
+				return innerErr
+			}
+			return c.Properties.LockForWrite(property.HealthV1).ThenUse(
+				func(clonable data.Clonable) error {
+					clonable.(*clusterpropsv1.Health).LastCheck = now
+					return nil
+				},
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !policy.AutoRepair {
+		return nodes, nil
+	}
+
+	// the nodes cannot be changed by the autoscaling, an expand or a shrink during the repair; the nodes are read again
+	// once locked, another Controller may have changed them since the probes
+	defer LockScaling(c.Name)()
+	err = c.reload(task)
+	if err != nil {
+		return nodes, err
+	}
+	state, err := c.GetState(task)
+	if err != nil {
+		return nodes, err
+	}
+	if state != clusterstate.Nominal && state != clusterstate.Degraded {
+		log.Debugf("[cluster %s] repair skipped, cluster is in state '%s'", c.Name, state.String())
+		return nodes, nil
+	}
+	var repaired bool
+	for _, node := range c.ListNodes(task) {
+		if !isNodeToRepair(policy, node, now) {
+			continue
+		}
+		log.Infof(
+			"[cluster %s] node '%s' is %s since %s (%s), replacing it", c.Name, node.Name, node.State.String(),
+			node.StateSince.Format(time.RFC3339), node.StateReason,
+		)
+		rerr := c.replaceNode(task, node, selectedMaster)
+		if rerr != nil {
+			errs = append(errs, fmt.Sprintf("failed to replace node '%s': %v", node.Name, rerr))
+			continue
+		}
+		repaired = true
+	}
+	if repaired {
+		err = c.UpdateMetadata(
+			task, func() error {
+				innerErr := c.Properties.LockForWrite(property.HealthV1).ThenUse(
+					func(clonable data.Clonable) error {
+						clonable.(*clusterpropsv1.Health).LastRepair = now
+						return nil
+					},
+				)
+				if innerErr != nil {
+					return innerErr
+				}
+				return c.Properties.LockForRead(property.NodesV2).ThenUse(
+					func(clonable data.Clonable) error {
+						nodes = clonable.Clone().(*clusterpropsv2.Nodes)
+						return nil
+					},
+				)
+			},
+		)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return nodes, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nodes, nil
+}
+
+// recordNodeProbe updates the state of a node with the result of its probe
+func recordNodeProbe(node *clusterpropsv2.Node, p nodeProbe, now time.Time) {
+	if node.Name == "" {
+		node.Name = p.name
+	}
+	if node.State != p.state || node.StateSince.IsZero() {
+		node.StateSince = now
+	}
+	node.State = p.state
+	node.StateReason = p.reason
+	node.StateDate = now
+}
+
+// isNodeToRepair tells if a node has been dead long enough to be replaced according to the health policy
+func isNodeToRepair(policy *clusterpropsv1.Health, node *clusterpropsv2.Node, now time.Time) bool {
+	if !policy.AutoRepair {
+		return false
+	}
+	if node.State != nodestate.Failed && node.State != nodestate.Unreachable {
+		return false
+	}
+	delay := policy.RepairDelay
+	if delay == 0 {
+		delay = DefaultRepairDelay
+	}
+	return !node.StateSince.IsZero() && now.Sub(node.StateSince) >= delay
+}
+
+// isDegraded tells if a gateway or a master of the cluster is found unhealthy by the last health probes
+func isDegraded(nodes *clusterpropsv2.Nodes) bool {
+	for _, list := range [][]*clusterpropsv2.Node{nodes.Gateways, nodes.Masters} {
+		for _, node := range list {
+			switch node.State {
+			case nodestate.NotReady, nodestate.Unreachable, nodestate.Failed:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// taskProbeNode probes the health of a host of the cluster
+// This function is intended to be call as a goroutine
+func (c *Controller) taskProbeNode(t concurrency.Task, params concurrency.TaskParameters) (result concurrency.TaskResult, err error) {
+	p := params.(data.Map)
+	hostID := p["hostID"].(string)
+	selectedMaster := p["selectedMaster"].(string)
+	checkCluster := p["checkCluster"].(bool)
+
+	tracer := debug.NewTracer(t, fmt.Sprintf("(%s)", hostID), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	out := nodeProbe{id: hostID}
+
+	hostState, err := c.service.GetHostState(hostID)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			out.state, out.reason = nodestate.Failed, "host not found"
+			return out, nil
+		}
+		out.state, out.reason = nodestate.Unknown, fmt.Sprintf("failed to get state of host: %v", err)
+		return out, nil
+	}
+	pbHost, err := client.New().Host.Inspect(hostID, temporal.GetExecutionTimeout())
+	if err != nil {
+		// a host that cannot be inspected must not prevent the recording of the state of the others
+		out.state, out.reason = inspectErrorState(err)
+		return out, nil
+	}
+	out.name = pbHost.Name
+
+	switch hostState {
+	case hoststate.STARTED:
+	case hoststate.STOPPED, hoststate.STOPPING:
+		out.state, out.reason = nodestate.Stopped, "host is "+strings.ToLower(hostState.String())
+		return out, nil
+	case hoststate.STARTING:
+		out.state, out.reason = nodestate.NotReady, "host is starting"
+		return out, nil
+	case hoststate.ERROR, hoststate.TERMINATED:
+		out.state, out.reason = nodestate.Failed, "host is "+strings.ToLower(hostState.String())
+		return out, nil
+	default:
+		out.state, out.reason = nodestate.Unknown, "host is in unknown state"
+		return out, nil
+	}
+
+	retcode, _, _, err := client.New().SSH.Run(
+		hostID, "true", outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+	)
+	if err != nil || retcode != 0 {
+		out.state, out.reason = nodestate.Unreachable, "host cannot be reached by SSH"
+		return out, nil
+	}
+
+	out.state = nodestate.Started
+	if !checkCluster || selectedMaster == "" {
+		return out, nil
+	}
+	out.state, out.reason, err = c.foreman.getNodeState(t, pbHost, selectedMaster)
+	if err != nil {
+		out.state, out.reason = nodestate.Unknown, fmt.Sprintf("failed to get state of node in cluster: %v", err)
+	}
+	return out, nil
+}
+
+// inspectErrorState returns the state of a node whose host cannot be inspected, and its reason
+func inspectErrorState(err error) (nodestate.Enum, string) {
+	if _, ok := err.(fail.ErrNotFound); ok {
+		return nodestate.Failed, fmt.Sprintf("host not found: %v", err)
+	}
+	return nodestate.Unknown, fmt.Sprintf("failed to inspect host: %v", err)
+}
+
+// replaceNode removes a dead node from the cluster and creates a new one, through the same path than AddNodes
+// (Makers CreateNode, ConfigureNode then JoinNodeToCluster)
+func (c *Controller) replaceNode(task concurrency.Task, node *clusterpropsv2.Node, selectedMaster string) (err error) {
+	tracer := debug.NewTracer(task, fmt.Sprintf("(%s)", node.Name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		task.GetContext(), c.service, "cluster.node.replace", "cluster:"+c.Name, map[string]string{"node": node.Name},
+	)(&err)
+
+	// a dead node cannot leave the cluster by itself, the cluster has to forget it
+	if selectedMaster != "" && node.Name != "" {
+		ferr := c.foreman.forgetNode(task, node.Name, selectedMaster)
+		if ferr != nil {
+			log.Warnf("[cluster %s] failed to remove node '%s' from the cluster: %v", c.Name, node.Name, ferr)
+		}
+	}
+	err = c.deleteNode(task, node, "")
+	if err != nil {
+		return err
+	}
+	_, err = c.AddNodes(task, 1, nil)
+	return err
+}
+
+// getNodeState returns the state of a node for the cluster, using the "maker" GetNodeState if defined, or Docker
+// Swarm (always installed on clusters other than K8S)
+func (b *foreman) getNodeState(task concurrency.Task, pbHost *pb.Host, selectedMaster string) (nodestate.Enum, string, error) {
+	if b.makers.GetNodeState != nil {
+		return b.makers.GetNodeState(task, b, pbHost, selectedMaster)
+	}
+	if b.cluster.GetIdentity(task).Flavor == flavor.K8S {
+		return nodestate.Started, "", nil
+	}
+
+	cmd := fmt.Sprintf("docker node inspect --format '{{.Status.State}} {{.Spec.Availability}}' %s", pbHost.Name)
+	retcode, stdout, stderr, err := client.New().SSH.Run(
+		selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+	)
+	if err != nil {
+		return nodestate.Unknown, "", err
+	}
+	if retcode != 0 {
+		return nodestate.NotReady, fmt.Sprintf("not a member of Docker Swarm: %s", strings.TrimSpace(stderr)), nil
+	}
+	state, reason := parseSwarmNodeState(stdout)
+	return state, reason, nil
+}
+
+// parseSwarmNodeState converts the status and the availability of a Docker Swarm node to a node state
+func parseSwarmNodeState(out string) (nodestate.Enum, string) {
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return nodestate.Unknown, fmt.Sprintf("unexpected state of Docker Swarm node '%s'", strings.TrimSpace(out))
+	}
+	if fields[0] != "ready" {
+		return nodestate.NotReady, "Docker Swarm node is " + fields[0]
+	}
+	if fields[1] != "active" {
+		return nodestate.Disabled, "Docker Swarm node availability is " + fields[1]
+	}
+	return nodestate.Started, ""
+}
+
+// forgetNode removes a node from the cluster without running anything on the node, which may be dead
+func (b *foreman) forgetNode(task concurrency.Task, name string, selectedMaster string) error {
+	var cmd string
+	if b.cluster.GetIdentity(task).Flavor == flavor.K8S {
+		cmd = fmt.Sprintf("sudo -u cladm -i kubectl delete node %s --ignore-not-found", name)
+	} else {
+		cmd = fmt.Sprintf("docker node rm --force %s || true", name)
+	}
+	retcode, _, stderr, err := client.New().SSH.Run(
+		selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+	)
+	if err != nil {
+		return err
+	}
+	if retcode != 0 {
+		return fmt.Errorf("failed to remove node '%s' from cluster: %s", name, stderr)
+	}
+	return nil
+}
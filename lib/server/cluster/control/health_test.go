@@ -0,0 +1,93 @@
+package control
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodestate"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func TestRecordNodeProbe(t *testing.T) {
+	start := time.Now()
+	node := &clusterpropsv2.Node{ID: "id", State: nodestate.Unknown}
+
+	recordNodeProbe(node, nodeProbe{id: "id", name: "node-1", state: nodestate.Started}, start)
+	assert.Equal(t, "node-1", node.Name)
+	assert.Equal(t, nodestate.Started, node.State)
+	assert.Equal(t, start, node.StateSince)
+
+	later := start.Add(time.Minute)
+	recordNodeProbe(node, nodeProbe{id: "id", state: nodestate.Started}, later)
+	assert.Equal(t, start, node.StateSince)
+	assert.Equal(t, later, node.StateDate)
+
+	recordNodeProbe(node, nodeProbe{id: "id", state: nodestate.Unreachable, reason: "no SSH"}, later)
+	assert.Equal(t, later, node.StateSince)
+	assert.Equal(t, "no SSH", node.StateReason)
+}
+
+func TestIsNodeToRepair(t *testing.T) {
+	now := time.Now()
+	policy := &clusterpropsv1.Health{AutoRepair: true, RepairDelay: 10 * time.Minute}
+	node := &clusterpropsv2.Node{State: nodestate.Failed, StateSince: now.Add(-15 * time.Minute)}
+
+	assert.True(t, isNodeToRepair(policy, node, now))
+
+	node.StateSince = now.Add(-5 * time.Minute)
+	assert.False(t, isNodeToRepair(policy, node, now))
+
+	node.StateSince = now.Add(-15 * time.Minute)
+	node.State = nodestate.Unreachable
+	assert.True(t, isNodeToRepair(policy, node, now))
+	node.State = nodestate.NotReady
+	assert.False(t, isNodeToRepair(policy, node, now))
+	node.State = nodestate.Stopped
+	assert.False(t, isNodeToRepair(policy, node, now))
+
+	node.State = nodestate.Failed
+	policy.AutoRepair = false
+	assert.False(t, isNodeToRepair(policy, node, now))
+}
+
+func TestIsDegraded(t *testing.T) {
+	nodes := &clusterpropsv2.Nodes{
+		Gateways:     []*clusterpropsv2.Node{{State: nodestate.Started}},
+		Masters:      []*clusterpropsv2.Node{{State: nodestate.Unknown}},
+		PrivateNodes: []*clusterpropsv2.Node{{State: nodestate.Failed}},
+	}
+	assert.False(t, isDegraded(nodes))
+
+	nodes.Masters[0].State = nodestate.NotReady
+	assert.True(t, isDegraded(nodes))
+
+	nodes.Masters[0].State = nodestate.Started
+	nodes.Gateways[0].State = nodestate.Unreachable
+	assert.True(t, isDegraded(nodes))
+}
+
+func TestParseSwarmNodeState(t *testing.T) {
+	state, _ := parseSwarmNodeState("ready active\n")
+	assert.Equal(t, nodestate.Started, state)
+	state, _ = parseSwarmNodeState("ready drain\n")
+	assert.Equal(t, nodestate.Disabled, state)
+	state, _ = parseSwarmNodeState("down active\n")
+	assert.Equal(t, nodestate.NotReady, state)
+	state, _ = parseSwarmNodeState("")
+	assert.Equal(t, nodestate.Unknown, state)
+}
+
+func TestInspectErrorState(t *testing.T) {
+	state, reason := inspectErrorState(fail.NotFoundError("host 'node-1' not found"))
+	assert.Equal(t, nodestate.Failed, state)
+	assert.Contains(t, reason, "host 'node-1' not found")
+
+	state, reason = inspectErrorState(fmt.Errorf("connection refused"))
+	assert.Equal(t, nodestate.Unknown, state)
+	assert.Contains(t, reason, "connection refused")
+}
@@ -254,10 +254,20 @@ func (m *Metadata) Browse(callback func(*Controller) error) error {
 }
 
 // Acquire waits until the write lock is available, then locks the metadata
-func (m *Metadata) Acquire() {
+// The lock is shared with the other processes using the same metadata; fails with fail.ErrNotAvailable on contention.
+func (m *Metadata) Acquire() error {
 	// m.lock.Lock()
 	// defer m.lock.Unlock()
-	m.item.Acquire()
+	if m == nil {
+		return fail.InvalidInstanceError()
+	}
+	if m.item == nil {
+		return fail.InvalidInstanceContentError("m.item", "cannot be nil")
+	}
+	if m.name == "" {
+		return fail.InvalidInstanceContentError("m.name", "cannot be empty string")
+	}
+	return m.item.Acquire(m.name)
 }
 
 // Release unlocks the metadata
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// Autoscaling contains the policy used by safescaled to add or remove nodes of the cluster according to their load
+// !!! FROZEN !!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with updated/additional fields
+type Autoscaling struct {
+	// Enabled tells if safescaled scales the cluster
+	Enabled bool `json:"enabled"`
+	// MinNodes is the minimum count of nodes of the cluster
+	MinNodes int `json:"min_nodes"`
+	// MaxNodes is the maximum count of nodes of the cluster
+	MaxNodes int `json:"max_nodes"`
+	// ScaleUpThreshold is the usage of CPU or RAM of the nodes (in percent) above which a node is added
+	ScaleUpThreshold float64 `json:"scale_up_threshold"`
+	// ScaleDownThreshold is the usage of CPU and RAM of the nodes (in percent) below which a node is removed
+	ScaleDownThreshold float64 `json:"scale_down_threshold"`
+	// ScaleUpCooldown is the delay after a scaling before a node can be added
+	ScaleUpCooldown time.Duration `json:"scale_up_cooldown"`
+	// ScaleDownCooldown is the delay after a scaling before a node can be removed
+	ScaleDownCooldown time.Duration `json:"scale_down_cooldown"`
+	// LastScaleUp is the date of the last addition of a node by safescaled
+	LastScaleUp time.Time `json:"last_scale_up,omitempty"`
+	// LastScaleDown is the date of the last removal of a node by safescaled
+	LastScaleDown time.Time `json:"last_scale_down,omitempty"`
+	// LastScalingFailed tells if the last addition or removal of a node by safescaled failed
+	LastScalingFailed bool `json:"last_scaling_failed,omitempty"`
+}
+
+func newAutoscaling() *Autoscaling {
+	return &Autoscaling{}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (a *Autoscaling) Content() data.Clonable {
+	return a
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (a *Autoscaling) Clone() data.Clonable {
+	return newAutoscaling().Replace(a)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (a *Autoscaling) Replace(p data.Clonable) data.Clonable {
+	*a = *p.(*Autoscaling)
+	return a
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("clusters", property.AutoscalingV1, newAutoscaling())
+}
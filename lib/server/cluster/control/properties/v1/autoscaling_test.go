@@ -0,0 +1,31 @@
+package propertiesv1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoscaling_Clone(t *testing.T) {
+	ct := newAutoscaling()
+	ct.Enabled = true
+	ct.MinNodes = 1
+	ct.MaxNodes = 5
+	ct.ScaleUpCooldown = 5 * time.Minute
+
+	clonedCt, ok := ct.Clone().(*Autoscaling)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.MaxNodes = 10
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
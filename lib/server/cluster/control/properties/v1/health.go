@@ -0,0 +1,67 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// Health contains the policy used by safescaled to repair the cluster after its health probes
+// !!! FROZEN !!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with updated/additional fields
+type Health struct {
+	// AutoRepair tells if safescaled replaces the dead nodes
+	AutoRepair bool `json:"auto_repair"`
+	// RepairDelay is the delay during which a node has to be found dead before being replaced
+	RepairDelay time.Duration `json:"repair_delay"`
+	// LastCheck is the date of the last health probes of the cluster
+	LastCheck time.Time `json:"last_check,omitempty"`
+	// LastRepair is the date of the last replacement of a node by safescaled
+	LastRepair time.Time `json:"last_repair,omitempty"`
+}
+
+func newHealth() *Health {
+	return &Health{}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (h *Health) Content() data.Clonable {
+	return h
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (h *Health) Clone() data.Clonable {
+	return newHealth().Replace(h)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (h *Health) Replace(p data.Clonable) data.Clonable {
+	*h = *p.(*Health)
+	return h
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("clusters", property.HealthV1, newHealth())
+}
@@ -0,0 +1,29 @@
+package propertiesv1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth_Clone(t *testing.T) {
+	ct := newHealth()
+	ct.AutoRepair = true
+	ct.RepairDelay = 10 * time.Minute
+
+	clonedCt, ok := ct.Clone().(*Health)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.AutoRepair = false
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
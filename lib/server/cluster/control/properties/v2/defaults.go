@@ -24,7 +24,7 @@ import (
 )
 
 // Defaults stores default information about cluster
-// FIXME: make sure there is code to migrate propertiesv1.Network to propertiesv2.Network when needed
+// propertiesv1.Defaults is upgraded to Defaults by the migration registered in package control
 // !!! FROZEN !!!
 // Note: if tagged as FROZEN, must not be changed ever.
 //       Create a new version instead with updated/additional fields
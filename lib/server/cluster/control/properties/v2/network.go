@@ -23,7 +23,7 @@ import (
 )
 
 // Network replace propertiesv1.Network
-// FIXME: make sure there is code to migrate propertiesv1.Network to propertiesv2.Network when needed
+// propertiesv1.Network is upgraded to Network by the migration registered in package control
 // !!! FROZEN !!!
 // Note: if tagged as FROZEN, must not be changed ever.
 //       Create a new version instead with updated/additional fields
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv2
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodestate"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// Node replaces propertiesv1.Node, adding the health of the node
+// !!! FROZEN !!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with updated/additional fields
+type Node struct {
+	ID          string         `json:"id"`                     // ID of the node
+	Name        string         `json:"name"`                   // Name of the node
+	PublicIP    string         `json:"public_ip"`              // public ip of the node
+	PrivateIP   string         `json:"private_ip"`             // private ip of the node
+	State       nodestate.Enum `json:"state"`                  // state of the node found by the last health probe
+	StateReason string         `json:"state_reason,omitempty"` // reason of the state, when not Started
+	StateDate   time.Time      `json:"state_date,omitempty"`   // date of the last health probe
+	StateSince  time.Time      `json:"state_since,omitempty"`  // date of the first health probe finding the current state
+}
+
+// Nodes replaces propertiesv1.Nodes
+// propertiesv1.Nodes is upgraded to Nodes by the migration registered in package control
+// !!! FROZEN !!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with updated/additional fields
+type Nodes struct {
+	Gateways         []*Node `json:"gateways,omitempty"`      // Gateways contains the gateways of the cluster, filled by the health probes
+	Masters          []*Node `json:"masters"`                 // Masters contains the ID of the masters
+	PublicNodes      []*Node `json:"public_nodes,omitempty"`  // PublicNodes is a slice of IDs of the public cluster nodes
+	PrivateNodes     []*Node `json:"private_nodes,omitempty"` // PrivateNodes is a slice of IDs of the private cluster nodes
+	MasterLastIndex  int     `json:"master_last_index"`       // MasterLastIndex
+	PrivateLastIndex int     `json:"private_last_index"`      // PrivateLastIndex
+	PublicLastIndex  int     `json:"public_last_index"`       // PublicLastIndex
+}
+
+func newNodes() *Nodes {
+	return &Nodes{
+		Gateways:     []*Node{},
+		Masters:      []*Node{},
+		PublicNodes:  []*Node{},
+		PrivateNodes: []*Node{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (n *Nodes) Content() data.Clonable {
+	return n
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (n *Nodes) Clone() data.Clonable {
+	return newNodes().Replace(n)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (n *Nodes) Replace(p data.Clonable) data.Clonable {
+	src := p.(*Nodes)
+	*n = *src
+	n.Gateways = cloneNodeList(src.Gateways)
+	n.Masters = cloneNodeList(src.Masters)
+	n.PublicNodes = cloneNodeList(src.PublicNodes)
+	n.PrivateNodes = cloneNodeList(src.PrivateNodes)
+	return n
+}
+
+// cloneNodeList returns a deep copy of a list of nodes
+func cloneNodeList(src []*Node) []*Node {
+	out := make([]*Node, len(src))
+	for k, v := range src {
+		newV := *v
+		out[k] = &newV
+	}
+	return out
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("clusters", property.NodesV2, newNodes())
+}
@@ -0,0 +1,37 @@
+package propertiesv2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodestate"
+)
+
+func TestNodes_Clone(t *testing.T) {
+	node := &Node{
+		ID:        "",
+		Name:      "Something",
+		PublicIP:  "",
+		PrivateIP: "",
+		State:     nodestate.Started,
+	}
+
+	ct := newNodes()
+	ct.PrivateNodes = append(ct.PrivateNodes, node)
+
+	clonedCt, ok := ct.Clone().(*Nodes)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.PrivateNodes[0].State = nodestate.Failed
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
@@ -28,4 +28,12 @@ const (
 	Disabled
 	// Stopped the node is stopped
 	Stopped
+	// NotReady the node is reachable, but the cluster doesn't consider it ready to take load
+	NotReady
+	// Unreachable the node is started for the provider, but cannot be reached by SSH
+	Unreachable
+	// Failed the node is in error or doesn't exist anymore for the provider
+	Failed
+	// Unknown the state of the node has not been probed yet
+	Unknown
 )
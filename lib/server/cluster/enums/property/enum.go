@@ -40,4 +40,10 @@ const (
 	NetworkV2 = "10"
 	// ControlPlaneV1 contains optional additional info about Control Plane of the cluster
 	ControlPlaneV1 = "11"
+	// AutoscalingV1 contains optional additional info about the autoscaling policy of the nodes of the cluster
+	AutoscalingV1 = "12"
+	// NodesV2 contains optional additional info describing Nodes inside the cluster, with their health
+	NodesV2 = "13"
+	// HealthV1 contains optional additional info about the health probes of the cluster and the repair policy
+	HealthV1 = "14"
 )
@@ -23,7 +23,6 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
-	"github.com/CS-SI/SafeScale/lib/client"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/api"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
@@ -32,20 +31,15 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/cluster/flavors/k8s"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/flavors/swarm"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
-	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
-// Load ...
-func Load(task concurrency.Task, name string) (api.Cluster, error) {
-	tenant, err := client.New().Tenant.Get(temporal.GetExecutionTimeout())
-	if err != nil {
-		return nil, err
-	}
-	svc, err := iaas.UseService(tenant.Name)
-	if err != nil {
-		return nil, err
+// Load loads the metadata of the cluster named 'name' using the service 'svc'
+func Load(task concurrency.Task, svc iaas.Service, name string) (api.Cluster, error) {
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
 	}
 
 	m, err := control.NewMetadata(svc)
@@ -89,12 +83,16 @@ func setForeman(task concurrency.Task, controller *control.Controller) error {
 }
 
 // Create creates a cluster following the parameters of the request
-func Create(task concurrency.Task, req control.Request) (_ api.Cluster, err error) {
+// req.Tenant must contain the name of the tenant corresponding to 'svc'
+func Create(task concurrency.Task, svc iaas.Service, req control.Request) (_ api.Cluster, err error) {
 	tracer := debug.NewTracer(task, "", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
 
 	// Validates parameters
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
 	if req.Name == "" {
 		return nil, fail.InvalidParameterError("req.Name", "cannot be empty!")
 	}
@@ -102,22 +100,16 @@ func Create(task concurrency.Task, req control.Request) (_ api.Cluster, err erro
 		return nil, fail.InvalidParameterError("req.CIDR", "cannot be empty!")
 	}
 
-	log.Infof("Creating infrastructure for cluster '%s'", req.Name)
-
-	tenant, err := client.New().Tenant.Get(temporal.GetExecutionTimeout())
-	if err != nil {
-		return nil, err
-	}
-	svc, err := iaas.UseService(tenant.Name)
-	if err != nil {
-		return nil, err
+	if req.Tenant == "" {
+		return nil, fail.InvalidParameterError("req.Tenant", "cannot be empty!")
 	}
 
+	log.Infof("Creating infrastructure for cluster '%s'", req.Name)
+
 	controller, err := control.NewController(svc)
 	if err != nil {
 		return nil, err
 	}
-	req.Tenant = tenant.Name
 	switch req.Flavor {
 	case flavor.BOH:
 		err = controller.Create(task, req, control.NewForeman(controller, boh.Makers))
@@ -156,9 +148,37 @@ func Create(task concurrency.Task, req control.Request) (_ api.Cluster, err erro
 	return controller, nil
 }
 
+// Estimate returns the cost of the infrastructure of the cluster described by req, without creating anything
+func Estimate(task concurrency.Task, svc iaas.Service, req control.Request) (_ *abstract.CostEstimate, err error) {
+	tracer := debug.NewTracer(task, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+
+	controller, err := control.NewController(svc)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Flavor {
+	case flavor.BOH:
+		return controller.Estimate(task, req, control.NewForeman(controller, boh.Makers))
+	case flavor.DCOS:
+		return controller.Estimate(task, req, control.NewForeman(controller, dcos.Makers))
+	case flavor.K8S:
+		return controller.Estimate(task, req, control.NewForeman(controller, k8s.Makers))
+	case flavor.SWARM:
+		return controller.Estimate(task, req, control.NewForeman(controller, swarm.Makers))
+	default:
+		return nil, fail.NotImplementedError(fmt.Sprintf("cluster Flavor '%s' not yet implemented", req.Flavor.String()))
+	}
+}
+
 // Delete deletes the infrastructure of the cluster named 'name'
-func Delete(task concurrency.Task, name string) error {
-	instance, err := Load(task, name)
+func Delete(task concurrency.Task, svc iaas.Service, name string) error {
+	instance, err := Load(task, svc, name)
 	if err != nil {
 		return fmt.Errorf("failed to find a cluster named '%s': %s", name, err.Error())
 	}
@@ -168,14 +188,9 @@ func Delete(task concurrency.Task, name string) error {
 }
 
 // List lists the clusters already created
-func List() (clusterList []api.Cluster, err error) {
-	tenant, err := client.New().Tenant.Get(temporal.GetExecutionTimeout())
-	if err != nil {
-		return nil, err
-	}
-	svc, err := iaas.UseService(tenant.Name)
-	if err != nil {
-		return nil, err
+func List(svc iaas.Service) (clusterList []api.Cluster, err error) {
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
 	}
 
 	m, err := control.NewMetadata(svc)
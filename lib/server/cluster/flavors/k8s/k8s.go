@@ -31,6 +31,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
 	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/complexity"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodestate"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodetype"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
 	"github.com/CS-SI/SafeScale/lib/server/install"
@@ -39,6 +40,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 	"github.com/CS-SI/SafeScale/lib/utils/template"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
 //go:generate rice embed-go
@@ -60,6 +62,10 @@ var (
 		ConfigureCluster:            configureCluster,
 		UnconfigureCluster:          unconfigureCluster,
 		LeaveNodeFromCluster:        leaveNodeFromCluster,
+		GetLoad:                     getLoad,
+		DrainNode:                   drainNode,
+		UndrainNode:                 undrainNode,
+		GetNodeState:                getNodeState,
 	}
 )
 
@@ -440,3 +446,143 @@ func leaveNodeFromCluster(task concurrency.Task, b control.Foreman, pbHost *pb.H
 
 	return nil
 }
+
+// getLoad returns the count of pods which cannot be scheduled for lack of resources, and the part of the CPU and
+// RAM of the nodes (excluding masters) requested by pods
+func getLoad(task concurrency.Task, foreman control.Foreman) (*control.Load, error) {
+	selectedMaster, err := foreman.Cluster().FindAvailableMaster(task)
+	if err != nil {
+		return nil, err
+	}
+	clientSSH := client.New().SSH
+
+	cmd := "sudo -u cladm -i kubectl get pods --all-namespaces --field-selector=status.phase=Pending -o " +
+		"jsonpath='{range .items[*]}{.status.conditions[?(@.reason==\"Unschedulable\")].reason}{\"\\n\"}{end}' " +
+		"| grep -c Unschedulable || true"
+	retcode, retout, _, err := clientSSH.Run(
+		selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if retcode != 0 {
+		return nil, fmt.Errorf("error listing pending pods: errorcode %d", retcode)
+	}
+	pending, err := strconv.Atoi(strings.TrimSpace(retout))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected count of pending pods '%s'", strings.TrimSpace(retout))
+	}
+
+	cmd = "sudo -u cladm -i kubectl describe nodes --selector='!node-role.kubernetes.io/master'"
+	retcode, retout, _, err = clientSSH.Run(
+		selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if retcode != 0 {
+		return nil, fmt.Errorf("error describing k8s nodes: errorcode %d", retcode)
+	}
+	cpu, ram := parseAllocatedResources(retout)
+	return &control.Load{PendingWorkloads: pending, CPU: cpu, RAM: ram}, nil
+}
+
+// parseAllocatedResources returns the average percentages of CPU and memory requested on the nodes, read in the
+// sections 'Allocated resources' of the output of 'kubectl describe nodes'
+func parseAllocatedResources(out string) (cpu float64, ram float64) {
+	var (
+		count     int
+		inSection bool
+	)
+	percent := func(field string) float64 {
+		value, err := strconv.ParseFloat(strings.Trim(field, "(%)"), 64)
+		if err != nil {
+			return 0
+		}
+		return value
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "Allocated resources:") {
+			inSection = true
+			count++
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			inSection = false
+			continue
+		}
+		fields := strings.Fields(line)
+		if !inSection || len(fields) < 3 {
+			continue
+		}
+		switch fields[0] {
+		case "cpu":
+			cpu += percent(fields[2])
+		case "memory":
+			ram += percent(fields[2])
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return cpu / float64(count), ram / float64(count)
+}
+
+// drainNode evicts the pods of a node and marks it unschedulable
+func drainNode(task concurrency.Task, b control.Foreman, pbHost *pb.Host, selectedMaster string) error {
+	cmd := fmt.Sprintf(
+		"sudo -u cladm -i kubectl drain %s --delete-local-data --force --ignore-daemonsets --timeout=%ds", pbHost.Name,
+		int(temporal.GetHostTimeout().Seconds()),
+	)
+	timeout := temporal.GetHostTimeout() + client.DefaultExecutionTimeout
+	retcode, _, stderr, err := client.New().SSH.Run(
+		selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, timeout,
+	)
+	if err != nil {
+		return err
+	}
+	if retcode != 0 {
+		return fmt.Errorf("error draining k8s node %s: %s", pbHost.Name, stderr)
+	}
+	return nil
+}
+
+// undrainNode marks a drained node schedulable again
+func undrainNode(task concurrency.Task, b control.Foreman, pbHost *pb.Host, selectedMaster string) error {
+	cmd := fmt.Sprintf("sudo -u cladm -i kubectl uncordon %s", pbHost.Name)
+	retcode, _, stderr, err := client.New().SSH.Run(
+		selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+	)
+	if err != nil {
+		return err
+	}
+	if retcode != 0 {
+		return fmt.Errorf("error uncordoning k8s node %s: %s", pbHost.Name, stderr)
+	}
+	return nil
+}
+
+// getNodeState returns the state of a node for Kubernetes, from the condition Ready reported by its kubelet
+func getNodeState(task concurrency.Task, b control.Foreman, pbHost *pb.Host, selectedMaster string) (nodestate.Enum, string, error) {
+	cmd := fmt.Sprintf(
+		"sudo -u cladm -i kubectl get node %s -o jsonpath='{.status.conditions[?(@.type==\"Ready\")].status} {.spec.unschedulable}'",
+		pbHost.Name,
+	)
+	retcode, stdout, stderr, err := client.New().SSH.Run(
+		selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+	)
+	if err != nil {
+		return nodestate.Unknown, "", err
+	}
+	if retcode != 0 {
+		return nodestate.NotReady, fmt.Sprintf("not a node of Kubernetes: %s", strings.TrimSpace(stderr)), nil
+	}
+	fields := strings.Fields(stdout)
+	if len(fields) == 0 || fields[0] != "True" {
+		return nodestate.NotReady, "kubelet is not ready", nil
+	}
+	if len(fields) > 1 && fields[1] == "true" {
+		return nodestate.Disabled, "node is cordoned", nil
+	}
+	return nodestate.Started, "", nil
+}
@@ -23,6 +23,7 @@ package ohpc
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	txttmpl "text/template"
 
@@ -30,10 +31,13 @@ import (
 	rice "github.com/GeertJohan/go.rice"
 
 	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/client"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/complexity"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodestate"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodetype"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/flavors/ohpc/enums/errorcode"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/template"
 )
@@ -74,6 +78,7 @@ var (
 		GetTemplateBox:              getTemplateBox,
 		GetGlobalSystemRequirements: getGlobalSystemRequirements,
 		GetNodeInstallationScript:   getNodeInstallationScript,
+		GetNodeState:                getNodeState,
 		// ConfigureCluster:            configureCluster,
 	}
 )
@@ -240,3 +245,27 @@ func getGlobalSystemRequirements(task concurrency.Task, foreman control.Foreman)
 	}
 	return anon.(string), nil
 }
+
+// getNodeState returns the state of a node for Slurm; without Slurm on the master, the node is considered started
+func getNodeState(task concurrency.Task, b control.Foreman, pbHost *pb.Host, selectedMaster string) (nodestate.Enum, string, error) {
+	cmd := fmt.Sprintf("command -v sinfo >/dev/null || exit 0; sinfo -h -N -n %s -o %%T | head -n 1", pbHost.Name)
+	retcode, stdout, stderr, err := client.New().SSH.Run(
+		selectedMaster, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, client.DefaultExecutionTimeout,
+	)
+	if err != nil {
+		return nodestate.Unknown, "", err
+	}
+	if retcode != 0 {
+		return nodestate.Unknown, "", fmt.Errorf("failed to get Slurm state of node '%s': %s", pbHost.Name, stderr)
+	}
+	// suffixes flag the node (not responding, powered off, ...)
+	state := strings.TrimRight(strings.TrimSpace(stdout), "*~#!%$@^-")
+	switch state {
+	case "", "idle", "allocated", "mixed", "completing":
+		return nodestate.Started, "", nil
+	case "draining", "drained":
+		return nodestate.Disabled, "Slurm node is " + state, nil
+	default:
+		return nodestate.NotReady, "Slurm node is " + state, nil
+	}
+}
@@ -25,25 +25,40 @@ import (
 	"github.com/sirupsen/logrus"
 
 	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/client"
 	"github.com/CS-SI/SafeScale/lib/server/cluster"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/complexity"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
 // Run runs the deployment
 func Run() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
+	tenant, err := client.New().Tenant.Get(temporal.GetExecutionTimeout())
+	if err != nil {
+		fmt.Printf("failed to get current tenant: %s\n", err.Error())
+		return
+	}
+	svc, err := iaas.UseService(tenant.Name)
+	if err != nil {
+		fmt.Printf("failed to use tenant '%s': %s\n", tenant.Name, err.Error())
+		return
+	}
+
 	clusterName := "test-cluster"
-	instance, err := cluster.Load(concurrency.RootTask(), clusterName)
+	instance, err := cluster.Load(concurrency.RootTask(), svc, clusterName)
 
 	if _, ok := err.(fail.ErrNotFound); ok {
 		logrus.Warnf("Cluster '%s' not found, creating it (this will take a while)\n", clusterName)
 		cinstance, cerr := cluster.Create(
-			concurrency.RootTask(), control.Request{
+			concurrency.RootTask(), svc, control.Request{
 				Name:       clusterName,
+				Tenant:     tenant.Name,
 				Complexity: complexity.Small,
 				// Complexity: complexity.Normal,
 				// Complexity: complexity.Large,
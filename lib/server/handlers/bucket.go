@@ -19,7 +19,9 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/graymeta/stow"
 
@@ -28,6 +30,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
 
@@ -36,12 +39,18 @@ import (
 // BucketAPI defines API to manipulate buckets
 type BucketAPI interface {
 	List(context.Context) ([]string, error)
-	Create(context.Context, string) error
+	Create(context.Context, string, abstract.BucketEncryption) error
 	Delete(context.Context, string) error
 	Destroy(context.Context, string) error
 	Inspect(context.Context, string) (*abstract.Bucket, error)
 	Mount(context.Context, string, string, string) error
 	Unmount(context.Context, string, string) error
+	Sync(context.Context, string, string, abstract.BucketSyncOptions) (*abstract.BucketSyncReport, error)
+	SetLifecycle(context.Context, string, []abstract.BucketLifecycleRule) error
+	GetLifecycle(context.Context, string) (*abstract.BucketLifecycle, error)
+	ClearLifecycle(context.Context, string) error
+	EnforceLifecycles(context.Context) error
+	Presign(context.Context, string, string, bool, time.Duration) (string, error)
 }
 
 // BucketHandler bucket service
@@ -68,8 +77,8 @@ func (handler *BucketHandler) List(ctx context.Context) (rv []string, err error)
 	return rv, err
 }
 
-// Create a bucket
-func (handler *BucketHandler) Create(ctx context.Context, name string) (err error) {
+// Create a bucket, whose objects are encrypted client-side if requested
+func (handler *BucketHandler) Create(ctx context.Context, name string, encryption abstract.BucketEncryption) (err error) {
 	if handler == nil {
 		return fail.InvalidInstanceError()
 	}
@@ -80,6 +89,9 @@ func (handler *BucketHandler) Create(ctx context.Context, name string) (err erro
 	tracer := debug.NewTracer(nil, "('"+name+"')", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "bucket.create", "bucket:"+name, map[string]string{"encrypted": fmt.Sprint(encryption.Enabled)},
+	)(&err)
 
 	bucket, err := handler.service.GetBucket(name)
 	if err != nil {
@@ -94,6 +106,15 @@ func (handler *BucketHandler) Create(ctx context.Context, name string) (err erro
 	if err != nil {
 		return err
 	}
+	if encryption.Enabled {
+		_, err = handler.service.EnableBucketEncryption(name, encryption.Passphrase)
+		if err != nil {
+			if derr := handler.service.DeleteBucket(name); derr != nil {
+				err = fail.AddConsequence(err, derr)
+			}
+			return err
+		}
+	}
 	return nil
 }
 
@@ -112,7 +133,11 @@ func (handler *BucketHandler) Destroy(ctx context.Context, name string) (err err
 	if err != nil {
 		return err
 	}
-	return nil
+	err = handler.forgetLifecycle(name)
+	if err != nil {
+		return err
+	}
+	return handler.service.ForgetBucketKey(name)
 }
 
 // Delete a bucket
@@ -120,12 +145,17 @@ func (handler *BucketHandler) Delete(ctx context.Context, name string) (err erro
 	tracer := debug.NewTracer(nil, "('"+name+"')", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "bucket.delete", "bucket:"+name, nil)(&err)
 
 	err = handler.service.DeleteBucket(name)
 	if err != nil {
 		return err
 	}
-	return nil
+	err = handler.forgetLifecycle(name)
+	if err != nil {
+		return err
+	}
+	return handler.service.ForgetBucketKey(name)
 }
 
 // Inspect a bucket
@@ -151,6 +181,14 @@ func (handler *BucketHandler) Inspect(ctx context.Context, name string) (mb *abs
 	mb = &abstract.Bucket{
 		Name: bucketName,
 	}
+	key, err := handler.service.GetBucketKey(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		mb.Encrypted = true
+		mb.KeyID = objectstorage.BuildKeyID(key)
+	}
 	return mb, nil
 }
 
@@ -161,6 +199,9 @@ func (handler *BucketHandler) Mount(ctx context.Context, bucketName, hostName, p
 	).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "bucket.mount", "bucket:"+bucketName, map[string]string{"host": hostName, "path": path},
+	)(&err)
 
 	// Check bucket existence
 	_, err = handler.service.GetBucket(bucketName)
@@ -168,6 +209,15 @@ func (handler *BucketHandler) Mount(ctx context.Context, bucketName, hostName, p
 		return err
 	}
 
+	// The filesystem would show the encrypted content of the objects
+	key, err := handler.service.GetBucketKey(bucketName)
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		return fail.NotAvailableError(fmt.Sprintf("bucket '%s' is encrypted client-side and cannot be mounted", bucketName))
+	}
+
 	// Get Host ID
 	hostHandler := NewHostHandler(handler.service)
 	host, err := hostHandler.Inspect(ctx, hostName)
@@ -228,6 +278,9 @@ func (handler *BucketHandler) Unmount(ctx context.Context, bucketName, hostName
 	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", bucketName, hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "bucket.unmount", "bucket:"+bucketName, map[string]string{"host": hostName},
+	)(&err)
 
 	// Check bucket existence
 	_, err = handler.Inspect(ctx, bucketName)
@@ -257,3 +310,34 @@ func (handler *BucketHandler) Unmount(ctx context.Context, bucketName, hostName
 	rerr := exec(ctx, "umount_object_storage.sh", data, host.ID, handler.service)
 	return rerr
 }
+
+// Presign returns an URL giving access to an object of the bucket during ttl, to download it or, if upload is true,
+// to upload it
+func (handler *BucketHandler) Presign(
+	ctx context.Context, bucketName, objectName string, upload bool, ttl time.Duration,
+) (url string, err error) {
+
+	if handler == nil {
+		return "", fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', %v, %s)", bucketName, objectName, upload, ttl), true,
+	).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	method := http.MethodGet
+	if upload {
+		method = http.MethodPut
+	} else {
+		_, err = handler.service.GetObject(bucketName, objectName)
+		if err != nil {
+			if err == stow.ErrNotFound { // FIXME: Remove stow dependency
+				return "", abstract.ResourceNotFoundError("object", bucketName+"/"+objectName)
+			}
+			return "", err
+		}
+	}
+	return handler.service.PresignObject(bucketName, objectName, method, ttl)
+}
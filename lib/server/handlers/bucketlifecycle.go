@@ -0,0 +1,242 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/graymeta/stow"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// SetLifecycle replaces the lifecycle rules of a bucket
+func (handler *BucketHandler) SetLifecycle(ctx context.Context, name string, rules []abstract.BucketLifecycleRule) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if len(rules) == 0 {
+		return fail.InvalidParameterError("rules", "cannot be empty slice")
+	}
+
+	tracer := debug.NewTracer(nil, "('"+name+"')", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	for _, r := range rules {
+		err = r.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	_, err = handler.service.GetBucket(name)
+	if err != nil {
+		if err == stow.ErrNotFound { // FIXME: Remove stow dependency
+			return abstract.ResourceNotFoundError("bucket", name)
+		}
+		return err
+	}
+
+	bl := abstract.NewBucketLifecycle(name)
+	bl.Rules = rules
+	_, err = metadata.SaveBucketLifecycle(handler.service, bl)
+	return err
+}
+
+// GetLifecycle returns the lifecycle rules of a bucket
+func (handler *BucketHandler) GetLifecycle(ctx context.Context, name string) (bl *abstract.BucketLifecycle, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "('"+name+"')", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mbl, err := metadata.LoadBucketLifecycle(handler.service, name)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("lifecycle rules of bucket", name)
+		}
+		return nil, err
+	}
+	return mbl.Get()
+}
+
+// ClearLifecycle removes the lifecycle rules of a bucket
+func (handler *BucketHandler) ClearLifecycle(ctx context.Context, name string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "('"+name+"')", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return handler.forgetLifecycle(name)
+}
+
+// forgetLifecycle deletes the lifecycle rules of a bucket, if any
+func (handler *BucketHandler) forgetLifecycle(name string) error {
+	mbl, err := metadata.LoadBucketLifecycle(handler.service, name)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	return mbl.Delete()
+}
+
+// EnforceLifecycles deletes the objects of the buckets designated by their lifecycle rules
+func (handler *BucketHandler) EnforceLifecycles(ctx context.Context) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mbl, err := metadata.NewBucketLifecycle(handler.service)
+	if err != nil {
+		return err
+	}
+	var lifecycles []*abstract.BucketLifecycle
+	err = mbl.Browse(
+		func(bl *abstract.BucketLifecycle) error {
+			lifecycles = append(lifecycles, bl)
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	var errors []error
+	for _, bl := range lifecycles {
+		if ctx.Err() != nil {
+			return fail.AbortedError("enforcement of bucket lifecycles aborted", ctx.Err())
+		}
+		deleted, err := handler.enforceLifecycle(bl, time.Now())
+		if err != nil {
+			if err == stow.ErrNotFound { // FIXME: Remove stow dependency
+				logrus.Infof("bucket '%s' does not exist anymore, forgetting its lifecycle rules", bl.Bucket)
+				err = handler.forgetLifecycle(bl.Bucket)
+			}
+			if err != nil {
+				errors = append(errors, fail.Wrap(err, fmt.Sprintf("failed to enforce lifecycle rules of bucket '%s'", bl.Bucket)))
+			}
+			continue
+		}
+		if deleted > 0 {
+			logrus.Infof("lifecycle rules of bucket '%s' deleted %d object(s)", bl.Bucket, deleted)
+		}
+		bl.LastEnforcement = time.Now()
+		_, err = metadata.SaveBucketLifecycle(handler.service, bl)
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if len(errors) > 0 {
+		return fail.ErrListError(errors)
+	}
+	return nil
+}
+
+// lifecycleObject is an object of a bucket considered by a lifecycle rule
+type lifecycleObject struct {
+	name       string
+	lastUpdate time.Time
+}
+
+// enforceLifecycle deletes the objects of a bucket designated by its lifecycle rules at date 'now',
+// and returns the number of objects deleted
+func (handler *BucketHandler) enforceLifecycle(bl *abstract.BucketLifecycle, now time.Time) (int, error) {
+	deleted := 0
+	for _, rule := range bl.Rules {
+		var objects []lifecycleObject
+		err := handler.service.BrowseBucket(
+			bl.Bucket, objectstorage.RootPath, rule.Prefix, func(o objectstorage.Object) error {
+				name, err := o.GetName()
+				if err != nil {
+					return err
+				}
+				lastUpdate, err := o.GetLastUpdate()
+				if err != nil {
+					return err
+				}
+				objects = append(objects, lifecycleObject{name: name, lastUpdate: lastUpdate})
+				return nil
+			},
+		)
+		if err != nil {
+			return deleted, err
+		}
+		// Most recent first
+		sort.Slice(
+			objects, func(i, j int) bool {
+				if objects[i].lastUpdate.Equal(objects[j].lastUpdate) {
+					return objects[i].name > objects[j].name
+				}
+				return objects[i].lastUpdate.After(objects[j].lastUpdate)
+			},
+		)
+
+		var expired []string
+		kept := 0
+		for _, o := range objects {
+			if rule.ExpireAfterDays > 0 && now.Sub(o.lastUpdate) > time.Duration(rule.ExpireAfterDays)*24*time.Hour {
+				expired = append(expired, o.name)
+				continue
+			}
+			if rule.KeepLast > 0 && kept >= rule.KeepLast {
+				expired = append(expired, o.name)
+				continue
+			}
+			kept++
+		}
+		for _, name := range expired {
+			err = handler.service.DeleteObject(bl.Bucket, name)
+			if err != nil {
+				if err == stow.ErrNotFound { // FIXME: Remove stow dependency
+					continue
+				}
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
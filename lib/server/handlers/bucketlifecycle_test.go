@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+)
+
+func TestBucketHandler_Lifecycle(t *testing.T) {
+	svc, err := iaas.NewServiceFromTenant(newTestTenant(t, "simulator"))
+	require.Nil(t, err)
+
+	_, err = svc.CreateBucket("lifecycle")
+	require.Nil(t, err)
+	for _, name := range []string{"logs/1", "logs/2", "logs/3", "other"} {
+		_, err = svc.WriteObject("lifecycle", name, strings.NewReader(name), int64(len(name)), nil)
+		require.Nil(t, err)
+	}
+
+	handler := &BucketHandler{service: svc}
+	err = handler.SetLifecycle(context.Background(), "lifecycle", []abstract.BucketLifecycleRule{{Prefix: "logs/"}})
+	assert.NotNil(t, err)
+	rules := []abstract.BucketLifecycleRule{{Prefix: "logs/", KeepLast: 1}}
+	require.Nil(t, handler.SetLifecycle(context.Background(), "lifecycle", rules))
+	bl, err := handler.GetLifecycle(context.Background(), "lifecycle")
+	require.Nil(t, err)
+	assert.Equal(t, rules, bl.Rules)
+
+	require.Nil(t, handler.EnforceLifecycles(context.Background()))
+	objects, err := svc.ListObjects("lifecycle", objectstorage.RootPath, objectstorage.NoPrefix)
+	require.Nil(t, err)
+	assert.Len(t, objects, 2)
+	assert.Contains(t, objects, "other")
+
+	// Expiration is relative to the date of last update
+	bl = abstract.NewBucketLifecycle("lifecycle")
+	bl.Rules = []abstract.BucketLifecycleRule{{ExpireAfterDays: 30}}
+	deleted, err := handler.enforceLifecycle(bl, time.Now().Add(29*24*time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 0, deleted)
+	deleted, err = handler.enforceLifecycle(bl, time.Now().Add(31*24*time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 2, deleted)
+
+	require.Nil(t, handler.ClearLifecycle(context.Background(), "lifecycle"))
+	_, err = handler.GetLifecycle(context.Background(), "lifecycle")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// syncedObject describes an object of the source bucket of a synchronization
+type syncedObject struct {
+	etag string
+	size int64
+}
+
+// Sync replicates the objects of bucket 'source' in bucket 'destination', both in the form [<tenant>:]<bucket>
+// Objects are copied when their ETag differs from the one replicated before; the state of the synchronization
+// is kept in metadata of the current tenant, so an interrupted synchronization resumes where it stopped.
+func (handler *BucketHandler) Sync(
+	ctx context.Context, source, destination string, options abstract.BucketSyncOptions,
+) (report *abstract.BucketSyncReport, err error) {
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", source, destination), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	src, err := abstract.ParseDataBucket(source)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := abstract.ParseDataBucket(destination)
+	if err != nil {
+		return nil, err
+	}
+	if src == dst {
+		return nil, fail.InvalidRequestError("source and destination of synchronization are the same bucket")
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = abstract.DefaultBucketSyncConcurrency
+	}
+
+	srcSvc, err := handler.tenantService(src.Tenant)
+	if err != nil {
+		return nil, err
+	}
+	dstSvc, err := handler.tenantService(dst.Tenant)
+	if err != nil {
+		return nil, err
+	}
+	found, err := srcSvc.FindBucket(src.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, abstract.ResourceNotFoundError("bucket", src.String())
+	}
+	found, err = dstSvc.FindBucket(dst.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		logrus.Debugf("creating bucket '%s' to synchronize '%s'", dst.String(), src.String())
+		_, err = dstSvc.CreateBucket(dst.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	state := abstract.NewBucketSyncState(src, dst)
+	mbs, err := metadata.LoadBucketSync(handler.service, state.GetName())
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); !ok {
+			return nil, err
+		}
+	} else {
+		state, err = mbs.Get()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	srcObjects := map[string]syncedObject{}
+	err = srcSvc.BrowseBucket(
+		src.Name, objectstorage.RootPath, objectstorage.NoPrefix, func(o objectstorage.Object) error {
+			name, err := o.GetName()
+			if err != nil {
+				return err
+			}
+			etag, _ := o.GetETag()
+			size, err := o.GetSize()
+			if err != nil {
+				size = -1
+			}
+			srcObjects[name] = syncedObject{etag: etag, size: size}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to browse bucket '%s'", src.String()))
+	}
+	dstObjects := map[string]string{}
+	err = dstSvc.BrowseBucket(
+		dst.Name, objectstorage.RootPath, objectstorage.NoPrefix, func(o objectstorage.Object) error {
+			name, err := o.GetName()
+			if err != nil {
+				return err
+			}
+			dstObjects[name], _ = o.GetETag()
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to browse bucket '%s'", dst.String()))
+	}
+
+	report = &abstract.BucketSyncReport{Source: src.String(), Destination: dst.String()}
+
+	// Keeps what has been done, even if the synchronization fails, to resume later
+	defer func() {
+		if err == nil {
+			state.LastSync = time.Now()
+		}
+		if _, serr := metadata.SaveBucketSync(handler.service, state); serr != nil {
+			err = fail.AddConsequence(err, serr)
+			if err == nil {
+				err = serr
+			}
+		}
+	}()
+
+	var toCopy []string
+	for name, o := range srcObjects {
+		if etag, ok := dstObjects[name]; ok && o.etag != "" && (state.Objects[name] == o.etag || etag == o.etag) {
+			state.Objects[name] = o.etag
+			report.Skipped++
+			continue
+		}
+		toCopy = append(toCopy, name)
+	}
+	sort.Strings(toCopy)
+
+	if len(toCopy) > 0 {
+		tg, err := concurrency.NewTaskGroupWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			mu        sync.Mutex
+			semaphore = make(chan struct{}, options.Concurrency)
+		)
+		copyAction := func(t concurrency.Task, p concurrency.TaskParameters) (concurrency.TaskResult, error) {
+			name := p.(string)
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			if t.Aborted() || ctx.Err() != nil {
+				return nil, fail.AbortedError("bucket synchronization aborted", nil)
+			}
+
+			n, err := copyBucketObject(srcSvc, src.Name, dstSvc, dst.Name, name, srcObjects[name].size)
+			if err != nil {
+				return nil, fail.Wrap(err, fmt.Sprintf("failed to copy object '%s'", name))
+			}
+			mu.Lock()
+			state.Objects[name] = srcObjects[name].etag
+			report.Copied++
+			report.Bytes += n
+			mu.Unlock()
+			return nil, nil
+		}
+		for _, name := range toCopy {
+			_, err = tg.Start(copyAction, name)
+			if err != nil {
+				break
+			}
+		}
+		_, werr := tg.WaitGroup()
+		if err == nil {
+			err = werr
+		}
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if options.Delete {
+		var replicated []string
+		for name := range state.Objects {
+			if _, ok := srcObjects[name]; !ok {
+				replicated = append(replicated, name)
+			}
+		}
+		sort.Strings(replicated)
+		for _, name := range replicated {
+			if _, ok := dstObjects[name]; ok {
+				err = dstSvc.DeleteObject(dst.Name, name)
+				if err != nil {
+					return report, fail.Wrap(err, fmt.Sprintf("failed to delete object '%s' of bucket '%s'", name, dst.String()))
+				}
+				report.Deleted++
+			}
+			delete(state.Objects, name)
+		}
+	}
+
+	return report, nil
+}
+
+// tenantService returns the service of the tenant, the current one if tenant is empty
+func (handler *BucketHandler) tenantService(tenant string) (iaas.Service, error) {
+	if tenant == "" {
+		return handler.service, nil
+	}
+	svc, err := iaas.UseService(tenant)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to use tenant '%s'", tenant))
+	}
+	return svc, nil
+}
+
+// copyBucketObject copies an object between buckets and returns the number of bytes copied
+// WriteMultiPartObject would store the content in several objects named after the parts, which is not a replica;
+// the content is streamed from source to destination instead, unless its size is unknown before reading it
+// (the size of the decrypted content of an encrypted bucket differs from the size of the object)
+func copyBucketObject(srcSvc iaas.Service, srcBucket string, dstSvc iaas.Service, dstBucket, name string, size int64) (int64, error) {
+	key, err := srcSvc.GetBucketKey(srcBucket)
+	if err != nil {
+		return 0, err
+	}
+	if key != nil || size < 0 {
+		var buffer bytes.Buffer
+		err = srcSvc.ReadObject(srcBucket, name, &buffer, 0, 0)
+		if err != nil {
+			return 0, err
+		}
+		n := int64(buffer.Len())
+		_, err = dstSvc.WriteObject(dstBucket, name, &buffer, n, nil)
+		return n, err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(srcSvc.ReadObject(srcBucket, name, writer, 0, 0))
+	}()
+	_, err = dstSvc.WriteObject(dstBucket, name, reader, size, nil)
+	// Unblocks the reading if the writing stopped early
+	_ = reader.CloseWithError(io.ErrClosedPipe)
+	if err != nil {
+		return 0, err
+	}
+	return size, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+)
+
+func TestBucketHandler_Sync(t *testing.T) {
+	svc, err := iaas.NewServiceFromTenant(newTestTenant(t, "simulator"))
+	require.Nil(t, err)
+
+	_, err = svc.CreateBucket("sync-source")
+	require.Nil(t, err)
+	for _, name := range []string{"a", "b", "dir/c"} {
+		_, err = svc.WriteObject("sync-source", name, strings.NewReader("content of "+name), int64(len("content of "+name)), nil)
+		require.Nil(t, err)
+	}
+
+	handler := &BucketHandler{service: svc}
+	report, err := handler.Sync(context.Background(), "sync-source", "sync-destination", abstract.BucketSyncOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, 3, report.Copied)
+	assert.Equal(t, 0, report.Skipped)
+
+	var buffer bytes.Buffer
+	require.Nil(t, svc.ReadObject("sync-destination", "dir/c", &buffer, 0, 0))
+	assert.Equal(t, "content of dir/c", buffer.String())
+
+	// Only the modified objects are copied again, and deletions are propagated on request
+	_, err = svc.WriteObject("sync-source", "a", strings.NewReader("new content"), int64(len("new content")), nil)
+	require.Nil(t, err)
+	require.Nil(t, svc.DeleteObject("sync-source", "b"))
+	report, err = handler.Sync(context.Background(), "sync-source", "sync-destination", abstract.BucketSyncOptions{Delete: true})
+	require.Nil(t, err)
+	assert.Equal(t, 1, report.Copied)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 1, report.Deleted)
+
+	_, err = svc.GetObject("sync-destination", "b")
+	assert.NotNil(t, err)
+
+	_, err = handler.Sync(context.Background(), "sync-source", "sync-source", abstract.BucketSyncOptions{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,793 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/cluster"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/api"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/clusterstate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/install"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+//go:generate mockgen -destination=../mocks/mock_clusterapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers ClusterAPI
+
+// ClusterAPI defines API to manipulate clusters
+type ClusterAPI interface {
+	Create(ctx context.Context, req control.Request) (api.Cluster, error)
+	Estimate(ctx context.Context, req control.Request) (*abstract.CostEstimate, error)
+	List(ctx context.Context) ([]api.Cluster, error)
+	Inspect(ctx context.Context, name string) (api.Cluster, error)
+	State(ctx context.Context, name string) (clusterstate.Enum, error)
+	Start(ctx context.Context, name string) error
+	Stop(ctx context.Context, name string) error
+	Delete(ctx context.Context, name string, force bool) error
+	Expand(ctx context.Context, name string, count int, nodeDef *pb.HostDefinition) ([]*clusterpropsv2.Node, error)
+	Shrink(ctx context.Context, name string, count int) ([]*clusterpropsv2.Node, error)
+	FindAvailableMaster(ctx context.Context, name string) (*clusterpropsv2.Node, error)
+	ListFeatures(ctx context.Context) ([]install.ClusterFeatureDescription, error)
+	AddFeature(ctx context.Context, name string, feature string, values install.Variables, settings install.Settings) error
+	CheckFeature(ctx context.Context, name string, feature string, values install.Variables, settings install.Settings) error
+	DeleteFeature(ctx context.Context, name string, feature string, values install.Variables, settings install.Settings) error
+	InspectAutoscaling(ctx context.Context, name string) (*clusterpropsv1.Autoscaling, error)
+	SetAutoscaling(ctx context.Context, name string, policy *clusterpropsv1.Autoscaling) (*clusterpropsv1.Autoscaling, error)
+	Autoscale(ctx context.Context) error
+	InspectHealth(ctx context.Context, name string) (*clusterpropsv1.Health, error)
+	SetHealth(ctx context.Context, name string, policy *clusterpropsv1.Health) (*clusterpropsv1.Health, error)
+	CheckHealth(ctx context.Context, name string) (*clusterpropsv2.Nodes, error)
+	CheckAllHealth(ctx context.Context) error
+}
+
+// ClusterHandler cluster service
+type ClusterHandler struct {
+	service iaas.Service
+}
+
+// NewClusterHandler creates a Cluster service
+func NewClusterHandler(svc iaas.Service) ClusterAPI {
+	return &ClusterHandler{service: svc}
+}
+
+// Create creates a new cluster following the request
+func (handler *ClusterHandler) Create(ctx context.Context, req control.Request) (instance api.Cluster, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nil, fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", req.Name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = cluster.Load(task, handler.service, req.Name)
+	if err == nil {
+		return nil, fail.DuplicateError(fmt.Sprintf("cluster '%s' already exists", req.Name))
+	}
+	if _, ok := err.(fail.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	instance, err = cluster.Create(task, handler.service, req)
+	if err != nil {
+		if instance != nil && !req.KeepOnFailure {
+			derr := instance.Delete(task)
+			if derr != nil {
+				err = fail.AddConsequence(err, derr)
+			}
+		}
+		return nil, err
+	}
+	if instance == nil {
+		return nil, fail.InconsistentError("cluster creation failed with nil result and nil error")
+	}
+	return instance, nil
+}
+
+// Estimate returns the cost of the infrastructure of the cluster described by the request
+func (handler *ClusterHandler) Estimate(ctx context.Context, req control.Request) (estimate *abstract.CostEstimate, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", req.Name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.Estimate(task, handler.service, req)
+}
+
+// List returns the clusters managed by SafeScale
+func (handler *ClusterHandler) List(ctx context.Context) (list []api.Cluster, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return cluster.List(handler.service)
+}
+
+// Inspect returns the cluster identified by 'name'
+func (handler *ClusterHandler) Inspect(ctx context.Context, name string) (instance api.Cluster, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler.load(task, name)
+}
+
+// State returns the current state of the cluster identified by 'name'
+func (handler *ClusterHandler) State(ctx context.Context, name string) (state clusterstate.Enum, err error) {
+	if handler == nil {
+		return clusterstate.Unknown, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return clusterstate.Unknown, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return clusterstate.Unknown, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return clusterstate.Unknown, err
+	}
+	return instance.GetState(task)
+}
+
+// Start starts the cluster identified by 'name'
+func (handler *ClusterHandler) Start(ctx context.Context, name string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return err
+	}
+	return instance.Start(task)
+}
+
+// Stop stops the cluster identified by 'name'
+func (handler *ClusterHandler) Stop(ctx context.Context, name string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return err
+	}
+	return instance.Stop(task)
+}
+
+// Delete deletes the cluster identified by 'name'; if 'force' is set, errors are ignored (cf. api.Cluster.Wipe())
+func (handler *ClusterHandler) Delete(ctx context.Context, name string, force bool) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', %v)", name, force), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return err
+	}
+	if force {
+		return instance.Wipe(task)
+	}
+	return instance.Delete(task)
+}
+
+// Expand adds 'count' nodes to the cluster identified by 'name'
+func (handler *ClusterHandler) Expand(ctx context.Context, name string, count int, nodeDef *pb.HostDefinition) (nodes []*clusterpropsv2.Node, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if count <= 0 {
+		return nil, fail.InvalidParameterError("count", "must be an int > 0")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', %d)", name, count), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	// waits for the end of an autoscaling or a repair of the cluster before loading it
+	defer control.LockScaling(name)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return nil, err
+	}
+	if nodeDef == nil {
+		nodeDef = &pb.HostDefinition{}
+	}
+	hostIDs, err := instance.AddNodes(task, count, nodeDef)
+	if err != nil {
+		return nil, err
+	}
+
+	added := make(map[string]struct{}, len(hostIDs))
+	for _, v := range hostIDs {
+		added[v] = struct{}{}
+	}
+	for _, v := range instance.ListNodes(task) {
+		if _, ok := added[v.ID]; ok {
+			nodes = append(nodes, v)
+		}
+	}
+	return nodes, nil
+}
+
+// Shrink removes the 'count' last added nodes of the cluster identified by 'name'
+func (handler *ClusterHandler) Shrink(ctx context.Context, name string, count int) (nodes []*clusterpropsv2.Node, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if count <= 0 {
+		return nil, fail.InvalidParameterError("count", "must be an int > 0")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', %d)", name, count), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	// waits for the end of an autoscaling or a repair of the cluster before loading it
+	defer control.LockScaling(name)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return nil, err
+	}
+
+	present := instance.ListNodes(task)
+	if count > len(present) {
+		return nil, fail.InvalidRequestError(
+			fmt.Sprintf(
+				"cannot delete %d node%s, the cluster contains only %d of them", count, utils.Plural(count),
+				len(present),
+			),
+		)
+	}
+
+	availableMaster, err := instance.FindAvailableMaster(task)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < count; i++ {
+		node, err := instance.DeleteLastNode(task, availableMaster)
+		if err != nil {
+			// a node failing to be deleted is put back last, so it would be the one deleted by the next attempt
+			return nodes, fmt.Errorf("failed to delete node #%d: %s", i+1, err.Error())
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// InspectAutoscaling returns the autoscaling policy of the cluster identified by 'name'
+func (handler *ClusterHandler) InspectAutoscaling(ctx context.Context, name string) (policy *clusterpropsv1.Autoscaling, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return nil, err
+	}
+	return instance.GetAutoscaling(task)
+}
+
+// SetAutoscaling replaces the autoscaling policy of the cluster identified by 'name', and returns the policy applied
+func (handler *ClusterHandler) SetAutoscaling(
+	ctx context.Context, name string, policy *clusterpropsv1.Autoscaling,
+) (_ *clusterpropsv1.Autoscaling, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if policy == nil {
+		return nil, fail.InvalidParameterError("policy", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "cluster.autoscaling.set", "cluster:"+name, map[string]string{
+			"enabled": fmt.Sprint(policy.Enabled), "min": fmt.Sprint(policy.MinNodes), "max": fmt.Sprint(policy.MaxNodes),
+		},
+	)(&err)
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return nil, err
+	}
+	err = instance.SetAutoscaling(task, policy)
+	if err != nil {
+		return nil, err
+	}
+	return instance.GetAutoscaling(task)
+}
+
+// Autoscale applies the autoscaling policies of all the clusters of the tenant; a failure on a cluster doesn't
+// prevent the scaling of the others
+func (handler *ClusterHandler) Autoscale(ctx context.Context) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	list, err := cluster.List(handler.service)
+	if err != nil {
+		return err
+	}
+	var msgs []string
+	for _, item := range list {
+		task, err := concurrency.NewTaskWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		name := item.GetIdentity(task).Name
+		policy, err := item.GetAutoscaling(task)
+		if err != nil || !policy.Enabled {
+			continue
+		}
+		instance, err := handler.load(task, name)
+		if err == nil {
+			_, err = instance.Autoscale(task)
+		}
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("failed to autoscale cluster '%s': %s", name, err.Error()))
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("%s", strings.Join(msgs, "\n"))
+	}
+	return nil
+}
+
+// InspectHealth returns the health policy of the cluster identified by 'name'
+func (handler *ClusterHandler) InspectHealth(ctx context.Context, name string) (policy *clusterpropsv1.Health, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return nil, err
+	}
+	return instance.GetHealth(task)
+}
+
+// SetHealth replaces the health policy of the cluster identified by 'name', and returns the policy applied
+func (handler *ClusterHandler) SetHealth(
+	ctx context.Context, name string, policy *clusterpropsv1.Health,
+) (_ *clusterpropsv1.Health, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if policy == nil {
+		return nil, fail.InvalidParameterError("policy", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "cluster.health.set", "cluster:"+name, map[string]string{
+			"auto_repair": fmt.Sprint(policy.AutoRepair), "repair_delay": policy.RepairDelay.String(),
+		},
+	)(&err)
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return nil, err
+	}
+	err = instance.SetHealth(task, policy)
+	if err != nil {
+		return nil, err
+	}
+	return instance.GetHealth(task)
+}
+
+// CheckHealth probes the hosts of the cluster identified by 'name', and returns its nodes with their state
+func (handler *ClusterHandler) CheckHealth(ctx context.Context, name string) (nodes *clusterpropsv2.Nodes, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return nil, err
+	}
+	return instance.CheckHealth(task)
+}
+
+// CheckAllHealth probes the hosts of all the clusters of the tenant; a failure on a cluster doesn't prevent the
+// probes of the others
+func (handler *ClusterHandler) CheckAllHealth(ctx context.Context) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	list, err := cluster.List(handler.service)
+	if err != nil {
+		return err
+	}
+	var msgs []string
+	for _, item := range list {
+		task, err := concurrency.NewTaskWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		name := item.GetIdentity(task).Name
+		instance, err := handler.load(task, name)
+		if err == nil {
+			var state clusterstate.Enum
+			state, err = instance.GetState(task)
+			if err == nil {
+				switch state {
+				case clusterstate.Creating, clusterstate.Initializing, clusterstate.Stopping, clusterstate.Stopped,
+					clusterstate.Starting, clusterstate.Removed:
+					// clusters being created, stopped or deleted are not probed
+					continue
+				}
+			}
+		}
+		if err == nil {
+			_, err = instance.CheckHealth(task)
+		}
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("failed to check health of cluster '%s': %s", name, err.Error()))
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("%s", strings.Join(msgs, "\n"))
+	}
+	return nil
+}
+
+// FindAvailableMaster returns a master of the cluster identified by 'name' ready to execute orders
+func (handler *ClusterHandler) FindAvailableMaster(ctx context.Context, name string) (node *clusterpropsv2.Node, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return nil, err
+	}
+	masterID, err := instance.FindAvailableMaster(task)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range instance.ListMasters(task) {
+		if v.ID == masterID {
+			return v, nil
+		}
+	}
+	return nil, fail.NotFoundError(fmt.Sprintf("failed to find master '%s' in cluster '%s'", masterID, name))
+}
+
+// ListFeatures lists the features suitable for clusters
+func (handler *ClusterHandler) ListFeatures(ctx context.Context) (list []install.ClusterFeatureDescription, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	features, err := install.ListFeatures("cluster")
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range features {
+		if desc, ok := v.(install.ClusterFeatureDescription); ok {
+			list = append(list, desc)
+		}
+	}
+	return list, nil
+}
+
+// AddFeature installs the feature 'feature' on the cluster identified by 'name'
+func (handler *ClusterHandler) AddFeature(ctx context.Context, name string, feature string, values install.Variables, settings install.Settings) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", name, feature), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "cluster.feature.add", "cluster:"+name, map[string]string{"feature": feature},
+	)(&err)
+
+	f, target, err := handler.prepareFeature(ctx, name, feature)
+	if err != nil {
+		return err
+	}
+
+	results, err := f.Add(target, values, settings)
+	if err != nil {
+		return fmt.Errorf("error installing feature '%s' on cluster '%s': %s", feature, name, err.Error())
+	}
+	if !results.Successful() {
+		return fmt.Errorf(
+			"failed to install feature '%s' on cluster '%s':\n%s", feature, name, results.AllErrorMessages(),
+		)
+	}
+	return nil
+}
+
+// CheckFeature checks if the feature 'feature' is installed on the cluster identified by 'name'
+// Returns fail.ErrNotFound if the feature is not installed
+func (handler *ClusterHandler) CheckFeature(ctx context.Context, name string, feature string, values install.Variables, settings install.Settings) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", name, feature), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	f, target, err := handler.prepareFeature(ctx, name, feature)
+	if err != nil {
+		return err
+	}
+
+	results, err := f.Check(target, values, settings)
+	if err != nil {
+		return fmt.Errorf("error checking if feature '%s' is installed on '%s': %s", feature, name, err.Error())
+	}
+	if !results.Successful() {
+		return fail.NotFoundError(
+			fmt.Sprintf(
+				"feature '%s' not found on cluster '%s':\n%s", feature, name, results.AllErrorMessages(),
+			),
+		)
+	}
+	return nil
+}
+
+// DeleteFeature removes the feature 'feature' from the cluster identified by 'name'
+func (handler *ClusterHandler) DeleteFeature(ctx context.Context, name string, feature string, values install.Variables, settings install.Settings) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", name, feature), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "cluster.feature.delete", "cluster:"+name, map[string]string{"feature": feature},
+	)(&err)
+
+	f, target, err := handler.prepareFeature(ctx, name, feature)
+	if err != nil {
+		return err
+	}
+
+	results, err := f.Remove(target, values, settings)
+	if err != nil {
+		return fmt.Errorf("error uninstalling feature '%s' on '%s': %s", feature, name, err.Error())
+	}
+	if !results.Successful() {
+		return fmt.Errorf(
+			"failed to delete feature '%s' from cluster '%s':\n%s", feature, name, results.AllErrorMessages(),
+		)
+	}
+	return nil
+}
+
+// load loads the cluster identified by 'name'
+func (handler *ClusterHandler) load(task concurrency.Task, name string) (api.Cluster, error) {
+	instance, err := cluster.Load(task, handler.service, name)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, fail.NotFoundError(fmt.Sprintf("cluster '%s' not found", name))
+		}
+		return nil, err
+	}
+	return instance, nil
+}
+
+// prepareFeature loads the cluster and the feature, and builds the install target
+func (handler *ClusterHandler) prepareFeature(ctx context.Context, name string, feature string) (*install.Feature, install.Target, error) {
+	if name == "" {
+		return nil, nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if feature == "" {
+		return nil, nil, fail.InvalidParameterError("feature", "cannot be empty string")
+	}
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	instance, err := handler.load(task, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := install.NewFeature(task, feature)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f == nil {
+		return nil, nil, fail.NotFoundError(fmt.Sprintf("failed to find a feature named '%s'", feature))
+	}
+	target, err := install.NewClusterTarget(task, instance)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, target, nil
+}
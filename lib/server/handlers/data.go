@@ -0,0 +1,520 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/klauspost/reedsolomon"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+//go:generate mockgen -destination=../mocks/mock_dataapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers DataAPI
+
+// DataAPI defines API to store files in buckets
+type DataAPI interface {
+	Push(ctx context.Context, localPath, name string, buckets []string, redundancy abstract.DataRedundancy) (*abstract.DataFile, error)
+	Get(ctx context.Context, localPath, name string) (*abstract.DataFile, error)
+	List(ctx context.Context) ([]*abstract.DataFile, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// DataHandler data service
+// Files are cut in chunks, each chunk is encrypted then spread over buckets with erasure coding or replication;
+// the manifest describing where the pieces are is kept in metadata
+type DataHandler struct {
+	service iaas.Service
+}
+
+// NewDataHandler creates a Data service
+func NewDataHandler(svc iaas.Service) DataAPI {
+	return &DataHandler{service: svc}
+}
+
+// dataStorages contains the object storages of the buckets of a file, in the order of DataFile.Buckets
+type dataStorages []objectstorage.Location
+
+// defaultDataBucket returns the bucket used when none is requested, derived from the name of the metadata bucket
+func (handler *DataHandler) defaultDataBucket() (abstract.DataBucket, error) {
+	return abstract.DataBucket{Name: handler.service.GetMetadataStore().GetName() + "-data"}, nil
+}
+
+// openStorages returns the object storages of the buckets, creating the buckets if needed and asked for
+func (handler *DataHandler) openStorages(buckets []abstract.DataBucket, create bool) (dataStorages, error) {
+	services := map[string]iaas.Service{"": handler.service}
+	storages := make(dataStorages, 0, len(buckets))
+	for _, b := range buckets {
+		svc, ok := services[b.Tenant]
+		if !ok {
+			var err error
+			svc, err = iaas.UseService(b.Tenant)
+			if err != nil {
+				return nil, fail.Wrap(err, fmt.Sprintf("failed to use tenant '%s'", b.Tenant))
+			}
+			services[b.Tenant] = svc
+		}
+		if create {
+			found, err := svc.FindBucket(b.Name)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				logrus.Debugf("creating bucket '%s' to store data", b.String())
+				_, err = svc.CreateBucket(b.Name)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		storages = append(storages, svc)
+	}
+	return storages, nil
+}
+
+// Push stores the local file 'localPath' under the name 'name'
+func (handler *DataHandler) Push(
+	ctx context.Context, localPath, name string, buckets []string, redundancy abstract.DataRedundancy,
+) (df *abstract.DataFile, err error) {
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if localPath == "" {
+		return nil, fail.InvalidParameterError("localPath", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if strings.Contains(name, "/") {
+		return nil, fail.InvalidParameterError("name", "cannot contain '/'")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s', %v)", localPath, name, buckets), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	_, err = metadata.LoadDataFile(handler.service, name)
+	if err == nil {
+		return nil, abstract.ResourceDuplicateError("data file", name)
+	}
+	if _, ok := err.(fail.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	var dataBuckets []abstract.DataBucket
+	for _, ref := range buckets {
+		b, err := abstract.ParseDataBucket(ref)
+		if err != nil {
+			return nil, err
+		}
+		dataBuckets = append(dataBuckets, b)
+	}
+	if len(dataBuckets) == 0 {
+		b, err := handler.defaultDataBucket()
+		if err != nil {
+			return nil, err
+		}
+		dataBuckets = append(dataBuckets, b)
+	}
+	if redundancy == (abstract.DataRedundancy{}) {
+		redundancy = abstract.DefaultDataRedundancy(len(dataBuckets))
+	}
+	err = redundancy.Validate(len(dataBuckets))
+	if err != nil {
+		return nil, err
+	}
+	if !redundancy.IsReplication() && redundancy.ShardCount() > len(dataBuckets) {
+		logrus.Warnf(
+			"%d shards per chunk spread over %d buckets: losing a bucket may lose more than one shard",
+			redundancy.ShardCount(), len(dataBuckets),
+		)
+	}
+
+	storages, err := handler.openStorages(dataBuckets, true)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to open '%s'", localPath))
+	}
+	defer func() {
+		if clerr := file.Close(); clerr != nil {
+			logrus.Warnf("failed to close '%s': %v", localPath, clerr)
+		}
+	}()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := crypt.NewEncryptionKey(nil)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	df = &abstract.DataFile{
+		ID:         id.String(),
+		Name:       name,
+		Size:       info.Size(),
+		Date:       time.Now(),
+		Key:        key[:],
+		ChunkSize:  abstract.DefaultDataChunkSize,
+		Redundancy: redundancy,
+		Buckets:    dataBuckets,
+	}
+
+	// Removes the pieces already stored on failure
+	defer func() {
+		if err != nil {
+			handler.deleteChunks(storages, df)
+		}
+	}()
+
+	buf := make([]byte, df.ChunkSize)
+	for index := 0; ; index++ {
+		select {
+		case <-ctx.Done():
+			return nil, fail.AbortedError("data push cancelled", ctx.Err())
+		default:
+		}
+
+		n, rerr := io.ReadFull(file, buf)
+		if n > 0 {
+			chunk, err := writeDataChunk(storages, df, index, buf[:n], key)
+			// Keeps track of the shards written even on failure, to be able to remove them
+			df.Chunks = append(df.Chunks, chunk)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rerr != nil {
+			return nil, fail.Wrap(rerr, fmt.Sprintf("failed to read '%s'", localPath))
+		}
+	}
+
+	_, err = metadata.SaveDataFile(handler.service, df)
+	if err != nil {
+		return nil, err
+	}
+	return df, nil
+}
+
+// writeDataChunk encrypts the chunk #index of the file, then stores its shards in the buckets
+func writeDataChunk(storages dataStorages, df *abstract.DataFile, index int, plain []byte, key *crypt.Key) (abstract.DataChunk, error) {
+	cipher, err := crypt.Encrypt(plain, key)
+	if err != nil {
+		return abstract.DataChunk{}, err
+	}
+	shards, err := splitDataChunk(df.Redundancy, cipher)
+	if err != nil {
+		return abstract.DataChunk{}, err
+	}
+
+	chunk := abstract.DataChunk{CipherSize: len(cipher)}
+	for i, shard := range shards {
+		// Shifts the buckets with the chunk index so the first shards are not always in the same bucket
+		bucketIndex := (index + i) % len(df.Buckets)
+		objectName := fmt.Sprintf("%s/%d.%d", df.ID, index, i)
+		_, err = storages[bucketIndex].WriteObject(
+			df.Buckets[bucketIndex].Name, objectName, bytes.NewReader(shard), int64(len(shard)), nil,
+		)
+		if err != nil {
+			return chunk, fail.Wrap(
+				err, fmt.Sprintf("failed to write shard '%s' in bucket '%s'", objectName, df.Buckets[bucketIndex].String()),
+			)
+		}
+		sum := sha256.Sum256(shard)
+		chunk.Shards = append(
+			chunk.Shards, abstract.DataShard{
+				Bucket:   bucketIndex,
+				Object:   objectName,
+				Checksum: hex.EncodeToString(sum[:]),
+			},
+		)
+	}
+	return chunk, nil
+}
+
+// splitDataChunk returns the shards to store for an encrypted chunk
+func splitDataChunk(redundancy abstract.DataRedundancy, cipher []byte) ([][]byte, error) {
+	if redundancy.IsReplication() {
+		shards := make([][]byte, redundancy.Replicas)
+		for i := range shards {
+			shards[i] = cipher
+		}
+		return shards, nil
+	}
+
+	enc, err := reedsolomon.New(redundancy.DataShards, redundancy.ParityShards)
+	if err != nil {
+		return nil, err
+	}
+	shards, err := enc.Split(cipher)
+	if err != nil {
+		return nil, err
+	}
+	err = enc.Encode(shards)
+	if err != nil {
+		return nil, err
+	}
+	return shards, nil
+}
+
+// Get restores the file 'name' into the local file 'localPath'
+func (handler *DataHandler) Get(ctx context.Context, localPath, name string) (df *abstract.DataFile, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if localPath == "" {
+		return nil, fail.InvalidParameterError("localPath", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", localPath, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mdf, err := metadata.LoadDataFile(handler.service, name)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("data file", name)
+		}
+		return nil, err
+	}
+	df, err = mdf.Get()
+	if err != nil {
+		return nil, err
+	}
+	key, err := df.GetKey()
+	if err != nil {
+		return nil, err
+	}
+	storages, err := handler.openStorages(df.Buckets, false)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to create '%s'", localPath))
+	}
+	defer func() {
+		if clerr := file.Close(); clerr != nil {
+			logrus.Warnf("failed to close '%s': %v", localPath, clerr)
+		}
+		if err != nil {
+			if rerr := os.Remove(localPath); rerr != nil {
+				logrus.Warnf("failed to remove incomplete file '%s': %v", localPath, rerr)
+			}
+		}
+	}()
+
+	for index, chunk := range df.Chunks {
+		select {
+		case <-ctx.Done():
+			return nil, fail.AbortedError("data get cancelled", ctx.Err())
+		default:
+		}
+
+		plain, err := readDataChunk(storages, df, index, chunk, key)
+		if err != nil {
+			return nil, err
+		}
+		_, err = file.Write(plain)
+		if err != nil {
+			return nil, fail.Wrap(err, fmt.Sprintf("failed to write '%s'", localPath))
+		}
+	}
+	return df, nil
+}
+
+// readDataChunk reads the shards of the chunk #index, rebuilds and decrypts it
+// Missing or corrupted shards are ignored, as long as enough shards are left to rebuild the chunk
+func readDataChunk(storages dataStorages, df *abstract.DataFile, index int, chunk abstract.DataChunk, key *crypt.Key) ([]byte, error) {
+	shards := make([][]byte, len(chunk.Shards))
+	available := 0
+	for i, s := range chunk.Shards {
+		if s.Bucket < 0 || s.Bucket >= len(df.Buckets) {
+			return nil, fail.InconsistentError(fmt.Sprintf("invalid bucket index in manifest of file '%s'", df.Name))
+		}
+		var buf bytes.Buffer
+		err := storages[s.Bucket].ReadObject(df.Buckets[s.Bucket].Name, s.Object, &buf, 0, 0)
+		if err != nil {
+			logrus.Warnf("failed to read shard '%s' in bucket '%s': %v", s.Object, df.Buckets[s.Bucket].String(), err)
+			continue
+		}
+		sum := sha256.Sum256(buf.Bytes())
+		if hex.EncodeToString(sum[:]) != s.Checksum {
+			logrus.Warnf("shard '%s' in bucket '%s' is corrupted, ignored", s.Object, df.Buckets[s.Bucket].String())
+			continue
+		}
+		shards[i] = buf.Bytes()
+		available++
+		if df.Redundancy.IsReplication() {
+			// One valid replica is enough
+			break
+		}
+	}
+
+	var cipher []byte
+	if df.Redundancy.IsReplication() {
+		for _, shard := range shards {
+			if shard != nil {
+				cipher = shard
+				break
+			}
+		}
+		if cipher == nil {
+			return nil, fail.NotFoundError(fmt.Sprintf("no valid replica left for chunk #%d of file '%s'", index, df.Name))
+		}
+	} else {
+		if available < df.Redundancy.DataShards {
+			return nil, fail.NotFoundError(
+				fmt.Sprintf(
+					"only %d valid shards left for chunk #%d of file '%s', %d needed", available, index, df.Name,
+					df.Redundancy.DataShards,
+				),
+			)
+		}
+		enc, err := reedsolomon.New(df.Redundancy.DataShards, df.Redundancy.ParityShards)
+		if err != nil {
+			return nil, err
+		}
+		if available < len(shards) {
+			err = enc.ReconstructData(shards)
+			if err != nil {
+				return nil, fail.Wrap(err, fmt.Sprintf("failed to rebuild chunk #%d of file '%s'", index, df.Name))
+			}
+		}
+		var buf bytes.Buffer
+		err = enc.Join(&buf, shards, chunk.CipherSize)
+		if err != nil {
+			return nil, err
+		}
+		cipher = buf.Bytes()
+	}
+
+	plain, err := crypt.Decrypt(cipher, key)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to decrypt chunk #%d of file '%s'", index, df.Name))
+	}
+	return plain, nil
+}
+
+// List returns the manifests of the stored files
+func (handler *DataHandler) List(ctx context.Context) (list []*abstract.DataFile, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mdf, err := metadata.NewDataFile(handler.service)
+	if err != nil {
+		return nil, err
+	}
+	err = mdf.Browse(
+		func(df *abstract.DataFile) error {
+			list = append(list, df)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Delete removes the file 'name' from the buckets, then its manifest
+func (handler *DataHandler) Delete(ctx context.Context, name string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mdf, err := metadata.LoadDataFile(handler.service, name)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return abstract.ResourceNotFoundError("data file", name)
+		}
+		return err
+	}
+	df, err := mdf.Get()
+	if err != nil {
+		return err
+	}
+	storages, err := handler.openStorages(df.Buckets, false)
+	if err != nil {
+		return err
+	}
+	handler.deleteChunks(storages, df)
+	return mdf.Delete()
+}
+
+// deleteChunks removes the shards of all the chunks of the file; failures are only logged, the shards
+// being unreachable without the manifest anyway
+func (handler *DataHandler) deleteChunks(storages dataStorages, df *abstract.DataFile) {
+	for _, chunk := range df.Chunks {
+		for _, s := range chunk.Shards {
+			err := storages[s.Bucket].DeleteObject(df.Buckets[s.Bucket].Name, s.Object)
+			if err != nil {
+				logrus.Warnf("failed to delete shard '%s' in bucket '%s': %v", s.Object, df.Buckets[s.Bucket].String(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+)
+
+func TestDataHandler_PushGetWithMissingShard(t *testing.T) {
+	svc, err := iaas.NewServiceFromTenant(newTestTenant(t, "simulator"))
+	require.Nil(t, err)
+
+	folder, err := ioutil.TempDir("", "safescale-data")
+	require.Nil(t, err)
+	content := make([]byte, 3*1024*1024+17)
+	_, _ = rand.Read(content)
+	source := filepath.Join(folder, "source.bin")
+	require.Nil(t, ioutil.WriteFile(source, content, 0600))
+
+	handler := NewDataHandler(svc)
+	buckets := []string{"data-1", "data-2", "data-3"}
+	df, err := handler.Push(context.Background(), source, "source.bin", buckets, abstract.DataRedundancy{})
+	require.Nil(t, err)
+	assert.Equal(t, int64(len(content)), df.Size)
+	assert.Equal(t, 2, df.Redundancy.DataShards)
+	assert.Equal(t, 1, df.Redundancy.ParityShards)
+	require.Len(t, df.Chunks, 1)
+	require.Len(t, df.Chunks[0].Shards, 3)
+
+	// Losing one shard out of three must not prevent the restoration
+	lost := df.Chunks[0].Shards[0]
+	require.Nil(t, svc.DeleteObject(buckets[lost.Bucket], lost.Object))
+
+	target := filepath.Join(folder, "target.bin")
+	_, err = handler.Get(context.Background(), target, "source.bin")
+	require.Nil(t, err)
+	restored, err := ioutil.ReadFile(target)
+	require.Nil(t, err)
+	assert.True(t, bytes.Equal(content, restored))
+
+	list, err := handler.List(context.Background())
+	require.Nil(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "source.bin", list[0].Name)
+
+	require.Nil(t, handler.Delete(context.Background(), "source.bin"))
+	list, err = handler.List(context.Background())
+	require.Nil(t, err)
+	assert.Empty(t, list)
+}
@@ -59,8 +59,8 @@ import (
 
 // HostAPI defines API to manipulate hosts
 type HostAPI interface {
-	Create(ctx context.Context, name string, net string, os string, public bool, sizingParam interface{}, force bool, domain string, keeponfailure bool) (*abstract.Host, error)
-	List(ctx context.Context, all bool) ([]*abstract.Host, error)
+	Create(ctx context.Context, name string, net string, os string, public bool, sizingParam interface{}, force bool, domain string, keeponfailure bool, labels map[string]string, placement abstract.HostPlacement) (*abstract.Host, error)
+	List(ctx context.Context, all bool, labels map[string]string) ([]*abstract.Host, error)
 	ForceInspect(ctx context.Context, ref string) (*abstract.Host, error)
 	Inspect(ctx context.Context, ref string) (*abstract.Host, error)
 	Delete(ctx context.Context, ref string) error
@@ -69,6 +69,8 @@ type HostAPI interface {
 	Resize(ctx context.Context, name string, cpu int, ram float32, disk int, gpuNumber int, freq float32) (*abstract.Host, error)
 	Start(ctx context.Context, ref string) error
 	Stop(ctx context.Context, ref string) error
+	CaptureImage(ctx context.Context, ref string, name string) (*abstract.Image, error)
+	Estimate(ctx context.Context, sizing abstract.SizingRequirements, force bool) (*abstract.CostEstimate, error)
 }
 
 // HostHandler host service
@@ -88,6 +90,7 @@ func (handler *HostHandler) Start(ctx context.Context, ref string) (err error) {
 	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "host.start", "host:"+ref, nil)(&err)
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -126,6 +129,7 @@ func (handler *HostHandler) Stop(ctx context.Context, ref string) (err error) {
 	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "host.stop", "host:"+ref, nil)(&err)
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -163,11 +167,96 @@ func (handler *HostHandler) Stop(ctx context.Context, ref string) (err error) {
 	return err
 }
 
+// Estimate returns the cost of the host that would be created with the sizing requirements
+func (handler *HostHandler) Estimate(ctx context.Context, sizing abstract.SizingRequirements, force bool) (estimate *abstract.CostEstimate, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("(<sizing>, %v)", force), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	templates, err := handler.service.SelectTemplatesBySize(sizing, force)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, fail.NotFoundError("failed to find template corresponding to requested sizing")
+	}
+	price, err := handler.service.GetPricePerHour(*templates[0])
+	if err != nil {
+		return nil, err
+	}
+
+	estimate = &abstract.CostEstimate{}
+	estimate.Add("host", 1, templates[0], price)
+	return estimate, nil
+}
+
+// CaptureImage creates an image named name from the root disk of the host referenced by ref
+func (handler *HostHandler) CaptureImage(ctx context.Context, ref, name string) (image *abstract.Image, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	images, err := handler.service.ListImages(true)
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range images {
+		if img.Name == name {
+			return nil, abstract.ResourceDuplicateError("image", name)
+		}
+	}
+
+	mh, err := metadata.LoadHost(handler.service, ref)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("host", ref)
+		}
+		return nil, err
+	}
+	host, err := mh.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	image, err = handler.service.CreateImageFromHost(host.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		logrus.Warnf("Image capture cancelled by user")
+		derr := handler.service.DeleteImage(image.ID)
+		if derr != nil {
+			logrus.Warnf("failed to delete image '%s': %v", name, derr)
+		}
+		return nil, fmt.Errorf("image capture cancelled by user")
+	default:
+	}
+
+	return image, nil
+}
+
 // Reboot reboots a host
 func (handler *HostHandler) Reboot(ctx context.Context, ref string) (err error) {
 	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "host.reboot", "host:"+ref, nil)(&err)
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -220,6 +309,12 @@ func (handler *HostHandler) Resize(ctx context.Context, ref string, cpu int, ram
 	).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "host.resize", "host:"+ref, map[string]string{
+			"cpu": fmt.Sprint(cpu), "ram": fmt.Sprint(ram), "disk": fmt.Sprint(disk), "gpu": fmt.Sprint(gpuNumber),
+			"freq": fmt.Sprint(freq),
+		},
+	)(&err)
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -302,7 +397,9 @@ func (handler *HostHandler) Resize(ctx context.Context, ref string, cpu int, ram
 // 	force bool,
 func (handler *HostHandler) Create(
 	ctx context.Context,
-	name string, net string, los string, public bool, sizingParam interface{}, force bool, domain string, keeponfailure bool) (newHost *abstract.Host, err error) {
+	name string, net string, los string, public bool, sizingParam interface{}, force bool, domain string, keeponfailure bool,
+	labels map[string]string, placement abstract.HostPlacement,
+) (newHost *abstract.Host, err error) {
 
 	if handler == nil {
 		return nil, fail.InvalidInstanceError()
@@ -313,6 +410,12 @@ func (handler *HostHandler) Create(
 	if name == "" {
 		return nil, fail.InvalidParameterError("name", "cannot be empty string")
 	}
+	if err = abstract.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+	if err = placement.Validate(); err != nil {
+		return nil, err
+	}
 
 	defer func() {
 		if newHost == nil && err == nil {
@@ -325,6 +428,10 @@ func (handler *HostHandler) Create(
 	).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "host.create", "host:"+name,
+		map[string]string{"network": net, "os": los, "public": fmt.Sprint(public), "domain": domain},
+	)(&err)
 
 	var (
 		sizing       *abstract.SizingRequirements
@@ -462,6 +569,16 @@ func (handler *HostHandler) Create(
 		}
 	}
 
+	// Refuses early if the tenant has not enough resources left
+	needed := abstract.Usage{Cores: template.Cores, RAMSize: template.RAMSize, Instances: 1}
+	if public {
+		needed.PublicIPs = 1
+	}
+	err = handler.service.CheckQuotas(needed)
+	if err != nil {
+		return nil, err
+	}
+
 	var img *abstract.Image
 	retryErr := retryOnCommunicationFailure(
 		func() error {
@@ -502,6 +619,8 @@ func (handler *HostHandler) Create(
 		DefaultRouteIP: defaultRouteIP,
 		DefaultGateway: primaryGateway,
 		KeyPair:        keypair,
+		Labels:         labels,
+		HostPlacement:  placement,
 	}
 
 	host = nil
@@ -659,6 +778,20 @@ func (handler *HostHandler) Create(
 		return nil, err
 	}
 
+	// Sets host extension LabelsV1
+	if len(labels) > 0 {
+		err = host.Properties.LockForWrite(hostproperty.LabelsV1).ThenUse(
+			func(clonable data.Clonable) error {
+				hostLabelsV1 := clonable.(*propsv1.HostLabels)
+				hostLabelsV1.Labels = labels
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Updates host property propsv1.HostNetwork
 	var (
 		defaultNetworkID string
@@ -714,6 +847,13 @@ func (handler *HostHandler) Create(
 		return nil, fail.InconsistentError("host is nil after mh.Write()")
 	}
 
+	// Hosts of a simulated provider are not reachable through SSH, there is nothing left to provision
+	if handler.service.GetCapabilities().SimulatedHosts {
+		logrus.Debugf("Host '%s' is simulated, skipping SSH provisioning", host.Name)
+		handler.linkHostToNetworks(host, networks)
+		return host, nil
+	}
+
 	// A host claimed ready by a Cloud provider is not necessarily ready
 	// to be used until ssh service is up and running. So we wait for it before
 	// claiming host is created
@@ -748,24 +888,7 @@ func (handler *HostHandler) Create(
 	}
 
 	// Updates host link with networks
-	for _, i := range networks {
-		merr := i.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(
-			func(clonable data.Clonable) error {
-				networkHostsV1 := clonable.(*propsv1.NetworkHosts)
-				networkHostsV1.ByName[host.Name] = host.ID
-				networkHostsV1.ByID[host.ID] = host.Name
-				return nil
-			},
-		)
-		if merr != nil {
-			logrus.Errorf(merr.Error())
-			continue
-		}
-		_, merr = metadata.SaveNetwork(handler.service, i)
-		if merr != nil {
-			logrus.Errorf(merr.Error())
-		}
-	}
+	handler.linkHostToNetworks(host, networks)
 
 	// Executes userdata phase2 script to finalize host installation
 	userDataPhase2, err := userData.Generate("phase2")
@@ -926,6 +1049,27 @@ func getPhaseWarningsAndErrors(ctx context.Context, sshHandler *SSHHandler, host
 	return warnings, errs
 }
 
+// linkHostToNetworks registers the host in the metadata of the networks it is connected to
+func (handler *HostHandler) linkHostToNetworks(host *abstract.Host, networks []*abstract.Network) {
+	for _, i := range networks {
+		merr := metadata.UpdateNetwork(
+			handler.service, i.ID, func(network *abstract.Network) error {
+				return network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(
+					func(clonable data.Clonable) error {
+						networkHostsV1 := clonable.(*propsv1.NetworkHosts)
+						networkHostsV1.ByName[host.Name] = host.ID
+						networkHostsV1.ByID[host.ID] = host.Name
+						return nil
+					},
+				)
+			},
+		)
+		if merr != nil {
+			logrus.Errorf(merr.Error())
+		}
+	}
+}
+
 func retrieveForensicsData(ctx context.Context, sshHandler *SSHHandler, host *abstract.Host) {
 	if sshHandler == nil || host == nil {
 		return
@@ -1030,12 +1174,16 @@ func (handler *HostHandler) getOrCreateDefaultNetwork() (network *abstract.Netwo
 }
 
 // List returns the host list
-func (handler *HostHandler) List(ctx context.Context, all bool) (hosts []*abstract.Host, err error) {
-	tracer := debug.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+// If labels is not empty, only the hosts carrying all of these labels are returned
+func (handler *HostHandler) List(ctx context.Context, all bool, labels map[string]string) (hosts []*abstract.Host, err error) {
+	tracer := debug.NewTracer(nil, fmt.Sprintf("(%v, %v)", all, labels), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
 
 	if all {
+		if len(labels) > 0 {
+			return nil, fail.InvalidRequestError("cannot filter by labels hosts not managed by SafeScale")
+		}
 		return handler.service.ListHosts()
 	}
 
@@ -1045,6 +1193,21 @@ func (handler *HostHandler) List(ctx context.Context, all bool) (hosts []*abstra
 	}
 	err = m.Browse(
 		func(host *abstract.Host) error {
+			if len(labels) > 0 {
+				var hostLabels map[string]string
+				inErr := host.Properties.LockForRead(hostproperty.LabelsV1).ThenUse(
+					func(clonable data.Clonable) error {
+						hostLabels = clonable.(*propsv1.HostLabels).Labels
+						return nil
+					},
+				)
+				if inErr != nil {
+					return inErr
+				}
+				if !abstract.MatchLabels(hostLabels, labels) {
+					return nil
+				}
+			}
 			hosts = append(hosts, host)
 			return nil
 		},
@@ -1169,6 +1332,7 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "host.delete", "host:"+ref, nil)(&err)
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -1280,31 +1444,25 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 	}
 
 	// Update networks property prosv1.NetworkHosts to remove the reference to the host
-	netHandler := NewNetworkHandler(handler.service)
 	err = host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(
 		func(clonable data.Clonable) error {
 			hostNetworkV1 := clonable.(*propsv1.HostNetwork)
 			for k := range hostNetworkV1.NetworksByID {
-				network, err := netHandler.Inspect(ctx, k)
-				if err != nil {
-					logrus.Errorf(err.Error())
-					continue
-				}
-				err = network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(
-					func(clonable data.Clonable) error {
-						networkHostsV1 := clonable.(*propsv1.NetworkHosts)
-						delete(networkHostsV1.ByID, host.ID)
-						delete(networkHostsV1.ByName, host.Name)
-						return nil
+				err := metadata.UpdateNetwork(
+					handler.service, k, func(network *abstract.Network) error {
+						return network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(
+							func(clonable data.Clonable) error {
+								networkHostsV1 := clonable.(*propsv1.NetworkHosts)
+								delete(networkHostsV1.ByID, host.ID)
+								delete(networkHostsV1.ByName, host.Name)
+								return nil
+							},
+						)
 					},
 				)
 				if err != nil {
 					logrus.Errorf(err.Error())
 				}
-				_, err = metadata.SaveNetwork(handler.service, network)
-				if err != nil {
-					logrus.Errorf(err.Error())
-				}
 			}
 			return nil
 		},
@@ -1377,8 +1535,17 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 						return host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(
 							func(clonable data.Clonable) error {
 								hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+								var labels map[string]string
+								err3 := host.Properties.LockForRead(hostproperty.LabelsV1).ThenUse(
+									func(clonable data.Clonable) error {
+										labels = clonable.(*propsv1.HostLabels).Labels
+										return nil
+									},
+								)
+								if err3 != nil {
+									return err3
+								}
 								// FIXME: host's os name is not stored in metadata so we used ubuntu 18.04 by default
-								var err3 error
 								sizing := abstract.SizingRequirements{
 									MinCores:    hostSizingV1.AllocatedSize.Cores,
 									MaxCores:    hostSizingV1.AllocatedSize.Cores,
@@ -1391,7 +1558,7 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 								hostBis, err3 = handler.Create(
 									context.Background(), host.Name, hostNetworkV1.DefaultNetworkID, "ubuntu 18.04",
 									(len(hostNetworkV1.PublicIPv4)+len(hostNetworkV1.PublicIPv6)) != 0, &sizing, true,
-									hostDescriptionV1.Domain, false,
+									hostDescriptionV1.Domain, false, labels, abstract.HostPlacement{},
 								)
 								if err3 != nil {
 									return fail.Errorf(
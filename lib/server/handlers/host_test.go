@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// createTestHost creates the network and the host used by the tests with the handlers of svc
+func createTestHost(svc iaas.Service) (*abstract.Host, error) {
+	_, err := createTestNetwork(svc, "replayed_host_network")
+	if err != nil {
+		return nil, err
+	}
+	return NewHostHandler(svc).Create(
+		context.Background(), "replayed_host", "replayed_host_network", "Ubuntu 18.04", false, "tpl-small", false, "",
+		false, nil, abstract.HostPlacement{},
+	)
+}
+
+func TestHostHandler_Create_replayed(t *testing.T) {
+	cassette := newCassette(t)
+
+	recorded, err := createTestHost(newRecordingService(t, cassette))
+	require.Nil(t, err)
+	require.NotNil(t, recorded)
+
+	replayer, svc := newReplayingService(t, cassette)
+	replayed, err := createTestHost(svc)
+	require.Nil(t, err)
+	require.NotNil(t, replayed)
+
+	assert.Equal(t, recorded.ID, replayed.ID)
+	assert.Equal(t, recorded.Name, replayed.Name)
+	assert.Equal(t, recorded.GetPrivateIP(), replayed.GetPrivateIP())
+	assert.Equal(t, 0, replayer.Remaining())
+}
+
+func TestHostHandler_Estimate(t *testing.T) {
+	tenant := newTestTenant(t, "simulator")
+	tenant["pricing"] = map[string]interface{}{
+		"Templates": map[string]interface{}{"sim.medium": 0.08},
+	}
+	svc, err := iaas.NewServiceFromTenant(tenant)
+	require.Nil(t, err)
+	handler := NewHostHandler(svc)
+
+	estimate, err := handler.Estimate(
+		context.Background(), abstract.SizingRequirements{MinCores: 2, MinRAMSize: 4, MinGPU: -1}, false,
+	)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(estimate.Items))
+	assert.Equal(t, "sim.medium", estimate.Items[0].TemplateName)
+	assert.InDelta(t, 0.08*abstract.HoursPerMonth, estimate.MonthlyCost(), 0.0001)
+
+	_, err = handler.Estimate(
+		context.Background(), abstract.SizingRequirements{MinCores: 4, MinRAMSize: 8, MinGPU: -1}, false,
+	)
+	assert.IsType(t, fail.ErrNotFound{}, err)
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
 
@@ -36,6 +37,7 @@ type ImageAPI interface {
 	List(ctx context.Context, all bool) ([]abstract.Image, error)
 	Select(ctx context.Context, osfilter string) (*abstract.Image, error)
 	Filter(ctx context.Context, osfilter string) ([]abstract.Image, error)
+	Delete(ctx context.Context, ref string) error
 }
 
 // ImageHandler image service
@@ -61,10 +63,44 @@ func (handler *ImageHandler) List(ctx context.Context, all bool) (images []abstr
 
 // Select selects the image that best fits osname
 func (handler *ImageHandler) Select(ctx context.Context, osname string) (image *abstract.Image, err error) {
-	return nil, nil
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", osname), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return handler.service.SearchImage(osname)
 }
 
 // Filter filters the images that do not fit osname
 func (handler *ImageHandler) Filter(ctx context.Context, osname string) (image []abstract.Image, err error) {
-	return nil, nil
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", osname), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return handler.service.FilterImages(osname)
+}
+
+// Delete deletes the image referenced by ref (id or name)
+func (handler *ImageHandler) Delete(ctx context.Context, ref string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "image.delete", "image:"+ref, nil)(&err)
+
+	images, err := handler.service.ListImages(true)
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		if img.ID == ref || img.Name == ref {
+			return handler.service.DeleteImage(img.ID)
+		}
+	}
+	return abstract.ResourceNotFoundError("image", ref)
 }
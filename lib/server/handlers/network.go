@@ -58,8 +58,8 @@ import (
 
 // NetworkAPI defines API to manage networks
 type NetworkAPI interface {
-	Create(context.Context, string, string, ipversion.Enum, abstract.SizingRequirements, string, string, bool, string, bool) (*abstract.Network, error)
-	List(context.Context, bool) ([]*abstract.Network, error)
+	Create(context.Context, string, string, ipversion.Enum, abstract.SizingRequirements, string, string, bool, string, bool, map[string]string) (*abstract.Network, error)
+	List(context.Context, bool, map[string]string) ([]*abstract.Network, error)
 	Inspect(context.Context, string) (*abstract.Network, error)
 	Delete(context.Context, string) error
 	Destroy(context.Context, string) error
@@ -83,7 +83,7 @@ func (handler *NetworkHandler) Create(
 	ctx context.Context,
 	name string, cidr string, ipVersion ipversion.Enum,
 	sizing abstract.SizingRequirements, theos string, gwname string,
-	failover bool, domain string, keeponfailure bool,
+	failover bool, domain string, keeponfailure bool, labels map[string]string,
 ) (network *abstract.Network, err error) {
 	if handler == nil {
 		return nil, fail.InvalidInstanceError()
@@ -94,6 +94,9 @@ func (handler *NetworkHandler) Create(
 	if failover && gwname != "" {
 		return nil, fail.InvalidParameterError("gwname", "cannot be set if failover is set")
 	}
+	if err = abstract.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
 
 	tracer := debug.NewTracer(
 		nil,
@@ -104,6 +107,10 @@ func (handler *NetworkHandler) Create(
 	).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "network.create", "network:"+name,
+		map[string]string{"cidr": cidr, "os": theos, "gateway": gwname, "failover": fmt.Sprint(failover)},
+	)(&err)
 
 	// Verify that the network doesn't exist first and manage by SafeScale
 	_, err = metadata.LoadNetwork(handler.service, name)
@@ -141,6 +148,56 @@ func (handler *NetworkHandler) Create(
 		return nil, fmt.Errorf("cannot create such a network, CIDR must be not routable; please provide an appropriate CIDR (RFC1918)")
 	}
 
+	var template *abstract.HostTemplate
+	tpls, err := handler.service.SelectTemplatesBySize(sizing, false)
+	if err != nil {
+		logrus.Warn("error creating network: error reading machine templates")
+		switch err.(type) {
+		case fail.ErrNotFound, fail.ErrTimeout:
+			return nil, err
+		default:
+			return nil, err
+		}
+	}
+	if len(tpls) > 0 {
+		template = tpls[0]
+		msg := fmt.Sprintf(
+			"Selected host template: '%s' (%d core%s", template.Name, template.Cores, utils.Plural(template.Cores),
+		)
+		if template.CPUFreq > 0 {
+			msg += fmt.Sprintf(" at %.01f GHz", template.CPUFreq)
+		}
+		msg += fmt.Sprintf(", %.01f GB RAM, %d GB disk", template.RAMSize, template.DiskSize)
+		if template.GPUNumber > 0 {
+			msg += fmt.Sprintf(", %d GPU%s", template.GPUNumber, utils.Plural(template.GPUNumber))
+			if template.GPUType != "" {
+				msg += fmt.Sprintf(" %s", template.GPUType)
+			}
+		}
+		msg += ")"
+		logrus.Infof(msg)
+	} else {
+		return nil, fmt.Errorf("error creating network: no host template matching requirements for gateway")
+	}
+
+	// Refuses early if the tenant has not enough resources left for the network and its gateway(s)
+	gatewayCount := 1
+	if failover && handler.service.GetCapabilities().PrivateVirtualIP {
+		gatewayCount = 2
+	}
+	err = handler.service.CheckQuotas(
+		abstract.Usage{
+			Cores:     gatewayCount * template.Cores,
+			RAMSize:   float32(gatewayCount) * template.RAMSize,
+			Instances: gatewayCount,
+			Networks:  1,
+			PublicIPs: gatewayCount,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the network
 	logrus.Debugf("Creating network '%s' ...", name)
 	network, err = handler.service.CreateNetwork(
@@ -149,6 +206,7 @@ func (handler *NetworkHandler) Create(
 			IPVersion: ipVersion,
 			CIDR:      cidr,
 			Domain:    domain,
+			Labels:    labels,
 		},
 	)
 	if err != nil {
@@ -160,6 +218,17 @@ func (handler *NetworkHandler) Create(
 		}
 	}
 	network.Domain = domain
+	if len(labels) > 0 {
+		err = network.Properties.LockForWrite(networkproperty.LabelsV1).ThenUse(
+			func(clonable data.Clonable) error {
+				clonable.(*propsv1.NetworkLabels).Labels = labels
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	newNetwork := network
 	// Starting from here, delete network if exiting with error
@@ -252,37 +321,6 @@ func (handler *NetworkHandler) Create(
 		}
 	}()
 
-	var template *abstract.HostTemplate
-	tpls, err := handler.service.SelectTemplatesBySize(sizing, false)
-	if err != nil {
-		logrus.Warn("error creating network: error reading machine templates")
-		switch err.(type) {
-		case fail.ErrNotFound, fail.ErrTimeout:
-			return nil, err
-		default:
-			return nil, err
-		}
-	}
-	if len(tpls) > 0 {
-		template = tpls[0]
-		msg := fmt.Sprintf(
-			"Selected host template: '%s' (%d core%s", template.Name, template.Cores, utils.Plural(template.Cores),
-		)
-		if template.CPUFreq > 0 {
-			msg += fmt.Sprintf(" at %.01f GHz", template.CPUFreq)
-		}
-		msg += fmt.Sprintf(", %.01f GB RAM, %d GB disk", template.RAMSize, template.DiskSize)
-		if template.GPUNumber > 0 {
-			msg += fmt.Sprintf(", %d GPU%s", template.GPUNumber, utils.Plural(template.GPUNumber))
-			if template.GPUType != "" {
-				msg += fmt.Sprintf(" %s", template.GPUType)
-			}
-		}
-		msg += ")"
-		logrus.Infof(msg)
-	} else {
-		return nil, fmt.Errorf("error creating network: no host template matching requirements for gateway")
-	}
 	img, err := handler.service.SearchImage(theos)
 	if err != nil {
 		switch err.(type) {
@@ -315,6 +353,7 @@ func (handler *NetworkHandler) Create(
 		OriginalOsRequest: theos,
 		TemplateID:        template.ID,
 		CIDR:              network.CIDR,
+		Labels:            labels,
 	}
 
 	var (
@@ -808,6 +847,12 @@ func (handler *NetworkHandler) waitForInstallPhase1OnGateway(
 ) (result concurrency.TaskResult, err error) {
 	gw := params.(*abstract.Host)
 
+	// Gateways of a simulated provider are not reachable through SSH
+	if handler.service.GetCapabilities().SimulatedHosts {
+		logrus.Debugf("Gateway '%s' is simulated, skipping SSH provisioning", gw.Name)
+		return nil, nil
+	}
+
 	// A host claimed ready by a Cloud provider is not necessarily ready
 	// to be used until ssh service is up and running. So we wait for it before
 	// claiming host is created
@@ -862,6 +907,10 @@ func (handler *NetworkHandler) installPhase2OnGateway(task concurrency.Task, par
 	if userData, ok = params.(data.Map)["userdata"].(*userdata.Content); !ok {
 		return nil, fail.InvalidParameterError("params", "missing field 'userdata'")
 	}
+	if handler.service.GetCapabilities().SimulatedHosts {
+		logrus.Debugf("Gateway '%s' is simulated, skipping configuration phase 2", gw.Name)
+		return nil, nil
+	}
 
 	// Executes userdata phase2 script to finalize host installation
 	tracer := debug.NewTracer(nil, fmt.Sprintf("(%s)", gw.Name), true).WithStopwatch().GoingIn()
@@ -991,12 +1040,16 @@ func (handler *NetworkHandler) unbindHostFromVIP(vip *abstract.VirtualIP, host *
 }
 
 // List returns the network list
-func (handler *NetworkHandler) List(ctx context.Context, all bool) (netList []*abstract.Network, err error) {
-	tracer := debug.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+// If labels is not empty, only the networks carrying all of these labels are returned
+func (handler *NetworkHandler) List(ctx context.Context, all bool, labels map[string]string) (netList []*abstract.Network, err error) {
+	tracer := debug.NewTracer(nil, fmt.Sprintf("(%v, %v)", all, labels), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
 
 	if all {
+		if len(labels) > 0 {
+			return nil, fail.InvalidRequestError("cannot filter by labels networks not managed by SafeScale")
+		}
 		return handler.service.ListNetworks()
 	}
 
@@ -1006,6 +1059,21 @@ func (handler *NetworkHandler) List(ctx context.Context, all bool) (netList []*a
 	}
 	err = mn.Browse(
 		func(network *abstract.Network) error {
+			if len(labels) > 0 {
+				var networkLabels map[string]string
+				inErr := network.Properties.LockForRead(networkproperty.LabelsV1).ThenUse(
+					func(clonable data.Clonable) error {
+						networkLabels = clonable.(*propsv1.NetworkLabels).Labels
+						return nil
+					},
+				)
+				if inErr != nil {
+					return inErr
+				}
+				if !abstract.MatchLabels(networkLabels, labels) {
+					return nil
+				}
+			}
 			netList = append(netList, network)
 			return nil
 		},
@@ -1037,6 +1105,7 @@ func (handler *NetworkHandler) Delete(ctx context.Context, ref string) (err erro
 	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "network.delete", "network:"+ref, nil)(&err)
 
 	mn, err := metadata.LoadNetwork(handler.service, ref)
 	if err != nil {
@@ -16,6 +16,134 @@
 
 package handlers
 
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers/api"
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/simulator"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// newTestTenant returns the parameters of a tenant storing its objects in a temporary folder
+func newTestTenant(t *testing.T, name string) map[string]interface{} {
+	folder, err := ioutil.TempDir("", "safescale-handlers")
+	require.Nil(t, err)
+	return map[string]interface{}{
+		"name":     name,
+		"client":   "simulator",
+		"identity": map[string]interface{}{},
+		"compute": map[string]interface{}{
+			"Region": "local",
+		},
+		"objectstorage": map[string]interface{}{
+			"Type":     "local",
+			"Endpoint": folder,
+		},
+	}
+}
+
+// newCassette returns the path of a cassette file in a temporary folder
+func newCassette(t *testing.T) string {
+	folder, err := ioutil.TempDir("", "safescale-cassette")
+	require.Nil(t, err)
+	return filepath.Join(folder, "cassette.json")
+}
+
+// newRecordingService returns a service recording in cassette the calls made to a simulator provider
+func newRecordingService(t *testing.T, cassette string) iaas.Service {
+	simulated, err := iaas.NewServiceFromTenant(newTestTenant(t, "simulator"))
+	require.Nil(t, err)
+	recorder, err := api.NewRecordingProvider(simulated, "simulator", cassette)
+	require.Nil(t, err)
+	svc, err := iaas.NewServiceFromProvider(recorder, newTestTenant(t, "recording"))
+	require.Nil(t, err)
+	return svc
+}
+
+// newReplayingService returns a service serving the calls recorded in cassette, without any provider behind
+func newReplayingService(t *testing.T, cassette string) (*api.ReplayingProvider, iaas.Service) {
+	replayer, err := api.NewReplayingProvider("simulator", cassette)
+	require.Nil(t, err)
+	svc, err := iaas.NewServiceFromProvider(replayer, newTestTenant(t, "replaying"))
+	require.Nil(t, err)
+	return replayer, svc
+}
+
+// createTestNetwork creates the network used by the tests with the handler of svc
+func createTestNetwork(svc iaas.Service, name string) (*abstract.Network, error) {
+	return createLabeledTestNetwork(svc, name, "192.168.20.0/24", nil)
+}
+
+// createLabeledTestNetwork creates a network carrying labels with the handler of svc
+func createLabeledTestNetwork(svc iaas.Service, name, cidr string, labels map[string]string) (*abstract.Network, error) {
+	return NewNetworkHandler(svc).Create(
+		context.Background(), name, cidr, ipversion.IPv4,
+		abstract.SizingRequirements{MinCores: 1, MinRAMSize: 1, MinGPU: -1}, "Ubuntu 18.04", "", false, "", false,
+		labels,
+	)
+}
+
+func TestNetworkHandler_Create_replayed(t *testing.T) {
+	cassette := newCassette(t)
+
+	recorded, err := createTestNetwork(newRecordingService(t, cassette), "replayed_network")
+	require.Nil(t, err)
+	require.NotNil(t, recorded)
+
+	replayer, svc := newReplayingService(t, cassette)
+	replayed, err := createTestNetwork(svc, "replayed_network")
+	require.Nil(t, err)
+	require.NotNil(t, replayed)
+
+	assert.Equal(t, recorded.ID, replayed.ID)
+	assert.Equal(t, recorded.CIDR, replayed.CIDR)
+	assert.Equal(t, recorded.GatewayID, replayed.GatewayID)
+	assert.Equal(t, 0, replayer.Remaining())
+
+	// The network is now known in metadata of the replaying service
+	_, err = createTestNetwork(svc, "replayed_network")
+	assert.NotNil(t, err)
+}
+
+func TestNetworkHandler_List_labels(t *testing.T) {
+	svc, err := iaas.NewServiceFromTenant(newTestTenant(t, "simulator"))
+	require.Nil(t, err)
+
+	_, err = createLabeledTestNetwork(svc, "labeled_network", "192.168.21.0/24", map[string]string{"env": "test", "team": "a"})
+	require.Nil(t, err)
+	_, err = createLabeledTestNetwork(svc, "unlabeled_network", "192.168.22.0/24", nil)
+	require.Nil(t, err)
+
+	handler := NewNetworkHandler(svc)
+	networks, err := handler.List(context.Background(), false, nil)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(networks))
+
+	networks, err = handler.List(context.Background(), false, map[string]string{"env": "test"})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(networks))
+	assert.Equal(t, "labeled_network", networks[0].Name)
+
+	networks, err = handler.List(context.Background(), false, map[string]string{"env": "prod"})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(networks))
+
+	_, err = handler.List(context.Background(), true, map[string]string{"env": "test"})
+	assert.NotNil(t, err)
+
+	_, err = createLabeledTestNetwork(svc, "invalid_network", "192.168.23.0/24", map[string]string{"name": "forbidden"})
+	assert.NotNil(t, err)
+}
+
 // FIXME: iaas.Service became an interface, so cannot be used as before.
 //       Need to write a service struct satisfying iaas.Service interface
 //       and then initializes an instance of this service struct
@@ -71,3 +199,17 @@ package handlers
 
 // 	assert.Nil(t, result)
 // }
+
+func TestNetworkHandler_Create_quotas(t *testing.T) {
+	tenant := newTestTenant(t, "simulator")
+	tenant["compute"].(map[string]interface{})["Quotas"] = map[string]interface{}{"Networks": 1}
+	svc, err := iaas.NewServiceFromTenant(tenant)
+	require.Nil(t, err)
+
+	_, err = createLabeledTestNetwork(svc, "first_network", "192.168.23.0/24", nil)
+	require.Nil(t, err)
+
+	_, err = createLabeledTestNetwork(svc, "second_network", "192.168.24.0/24", nil)
+	require.NotNil(t, err)
+	assert.IsType(t, fail.ErrOverload{}, err)
+}
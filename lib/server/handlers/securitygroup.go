@@ -0,0 +1,306 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+//go:generate mockgen -destination=../mocks/mock_securitygroupapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers SecurityGroupAPI
+
+// SecurityGroupAPI defines API to manipulate security groups
+type SecurityGroupAPI interface {
+	List(ctx context.Context) ([]*abstract.SecurityGroup, error)
+	Create(ctx context.Context, req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, error)
+	Inspect(ctx context.Context, ref string) (*abstract.SecurityGroup, error)
+	Delete(ctx context.Context, ref string) error
+	AddRule(ctx context.Context, ref string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, error)
+	DeleteRule(ctx context.Context, ref string, ruleID string) (*abstract.SecurityGroup, error)
+	BindToHost(ctx context.Context, ref string, hostRef string) error
+	UnbindFromHost(ctx context.Context, ref string, hostRef string) error
+	BindToNetwork(ctx context.Context, ref string, networkRef string) error
+	UnbindFromNetwork(ctx context.Context, ref string, networkRef string) error
+}
+
+// SecurityGroupHandler security group service
+type SecurityGroupHandler struct {
+	service iaas.Service
+}
+
+// NewSecurityGroupHandler creates a SecurityGroup service
+func NewSecurityGroupHandler(svc iaas.Service) SecurityGroupAPI {
+	return &SecurityGroupHandler{service: svc}
+}
+
+// List returns the security groups available on the tenant
+func (handler *SecurityGroupHandler) List(ctx context.Context) (list []*abstract.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return handler.service.ListSecurityGroups()
+}
+
+// Create creates a security group following the request
+func (handler *SecurityGroupHandler) Create(ctx context.Context, req abstract.SecurityGroupRequest) (sg *abstract.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nil, fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	for _, r := range req.Rules {
+		if !r.OK() {
+			return nil, fail.InvalidParameterError("req.Rules", "contains an invalid rule")
+		}
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", req.Name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "securitygroup.create", "securitygroup:"+req.Name, nil)(&err)
+
+	_, err = handler.service.InspectSecurityGroup(req.Name)
+	if err == nil {
+		return nil, fail.DuplicateError(fmt.Sprintf("security group '%s' already exists", req.Name))
+	}
+	if _, ok := err.(fail.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	return handler.service.CreateSecurityGroup(req)
+}
+
+// Inspect returns the security group identified by ref (id or name)
+func (handler *SecurityGroupHandler) Inspect(ctx context.Context, ref string) (sg *abstract.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return handler.service.InspectSecurityGroup(ref)
+}
+
+// Delete deletes the security group identified by ref (id or name)
+func (handler *SecurityGroupHandler) Delete(ctx context.Context, ref string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "securitygroup.delete", "securitygroup:"+ref, nil)(&err)
+
+	sg, err := handler.service.InspectSecurityGroup(ref)
+	if err != nil {
+		return err
+	}
+	return handler.service.DeleteSecurityGroup(sg.ID)
+}
+
+// AddRule adds a rule to the security group identified by ref (id or name)
+func (handler *SecurityGroupHandler) AddRule(ctx context.Context, ref string, rule abstract.SecurityGroupRule) (sg *abstract.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if !rule.OK() {
+		return nil, fail.InvalidParameterError("rule", "is invalid")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	sg, err = handler.service.InspectSecurityGroup(ref)
+	if err != nil {
+		return nil, err
+	}
+	return handler.service.AddRuleToSecurityGroup(sg.ID, rule)
+}
+
+// DeleteRule removes the rule identified by ruleID from the security group identified by ref (id or name)
+func (handler *SecurityGroupHandler) DeleteRule(ctx context.Context, ref string, ruleID string) (sg *abstract.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if ruleID == "" {
+		return nil, fail.InvalidParameterError("ruleID", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, ruleID), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	sg, err = handler.service.InspectSecurityGroup(ref)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, r := range sg.Rules {
+		if r.ID == ruleID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fail.NotFoundError(fmt.Sprintf("failed to find rule '%s' in security group '%s'", ruleID, ref))
+	}
+	return handler.service.DeleteRuleFromSecurityGroup(sg.ID, ruleID)
+}
+
+// BindToHost applies the security group identified by ref to the host identified by hostRef
+func (handler *SecurityGroupHandler) BindToHost(ctx context.Context, ref string, hostRef string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, hostRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	sgID, hostID, err := handler.resolveHost(ref, hostRef)
+	if err != nil {
+		return err
+	}
+	return handler.service.BindSecurityGroupToHost(sgID, hostID)
+}
+
+// UnbindFromHost removes the security group identified by ref from the host identified by hostRef
+func (handler *SecurityGroupHandler) UnbindFromHost(ctx context.Context, ref string, hostRef string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, hostRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	sgID, hostID, err := handler.resolveHost(ref, hostRef)
+	if err != nil {
+		return err
+	}
+	return handler.service.UnbindSecurityGroupFromHost(sgID, hostID)
+}
+
+// BindToNetwork applies the security group identified by ref to the network identified by networkRef
+func (handler *SecurityGroupHandler) BindToNetwork(ctx context.Context, ref string, networkRef string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, networkRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	sgID, networkID, err := handler.resolveNetwork(ref, networkRef)
+	if err != nil {
+		return err
+	}
+	return handler.service.BindSecurityGroupToNetwork(sgID, networkID)
+}
+
+// UnbindFromNetwork removes the security group identified by ref from the network identified by networkRef
+func (handler *SecurityGroupHandler) UnbindFromNetwork(ctx context.Context, ref string, networkRef string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, networkRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	sgID, networkID, err := handler.resolveNetwork(ref, networkRef)
+	if err != nil {
+		return err
+	}
+	return handler.service.UnbindSecurityGroupFromNetwork(sgID, networkID)
+}
+
+// resolveHost returns the IDs of the security group and of the host referenced
+func (handler *SecurityGroupHandler) resolveHost(ref string, hostRef string) (string, string, error) {
+	if ref == "" {
+		return "", "", fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if hostRef == "" {
+		return "", "", fail.InvalidParameterError("hostRef", "cannot be empty string")
+	}
+
+	sg, err := handler.service.InspectSecurityGroup(ref)
+	if err != nil {
+		return "", "", err
+	}
+	mh, err := metadata.LoadHost(handler.service, hostRef)
+	if err != nil {
+		return "", "", err
+	}
+	host, err := mh.Get()
+	if err != nil {
+		return "", "", err
+	}
+	return sg.ID, host.ID, nil
+}
+
+// resolveNetwork returns the IDs of the security group and of the network referenced
+func (handler *SecurityGroupHandler) resolveNetwork(ref string, networkRef string) (string, string, error) {
+	if ref == "" {
+		return "", "", fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if networkRef == "" {
+		return "", "", fail.InvalidParameterError("networkRef", "cannot be empty string")
+	}
+
+	sg, err := handler.service.InspectSecurityGroup(ref)
+	if err != nil {
+		return "", "", err
+	}
+	mn, err := metadata.LoadNetwork(handler.service, networkRef)
+	if err != nil {
+		return "", "", err
+	}
+	network, err := mn.Get()
+	if err != nil {
+		return "", "", err
+	}
+	return sg.ID, network.ID, nil
+}
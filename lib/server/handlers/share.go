@@ -89,6 +89,9 @@ func (handler *ShareHandler) Create(
 	tracer := debug.NewTracer(nil, fmt.Sprintf("(%s)", shareName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "share.create", "share:"+shareName, map[string]string{"host": hostName, "path": path},
+	)(&err)
 
 	// Check if a share already exists with the same name
 	server, _, _, err := handler.Inspect(ctx, shareName)
@@ -265,6 +268,7 @@ func (handler *ShareHandler) Delete(ctx context.Context, name string) (err error
 	tracer := debug.NewTracer(nil, fmt.Sprintf("(%s)", name), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "share.delete", "share:"+name, nil)(&err)
 
 	// Retrieve info about the share
 	server, share, _, err := handler.ForceInspect(ctx, name)
@@ -428,6 +432,9 @@ func (handler *ShareHandler) Mount(
 	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", shareName, hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "share.mount", "share:"+shareName, map[string]string{"host": hostName, "path": path},
+	)(&err)
 
 	// Retrieve info about the share
 	server, share, _, err := handler.Inspect(ctx, shareName)
@@ -671,6 +678,9 @@ func (handler *ShareHandler) Unmount(ctx context.Context, shareName, hostName st
 	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", shareName, hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "share.unmount", "share:"+shareName, map[string]string{"host": hostName},
+	)(&err)
 
 	server, share, _, err := handler.ForceInspect(ctx, shareName)
 	if err != nil {
@@ -0,0 +1,594 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
+	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/networkproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumeproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+//go:generate mockgen -destination=../mocks/mock_tenantapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers TenantAPI
+
+// TenantAPI defines API to maintain the metadata of a tenant
+type TenantAPI interface {
+	Fsck(ctx context.Context, repair bool) (*abstract.FsckReport, error)
+}
+
+// TenantHandler tenant service
+type TenantHandler struct {
+	service iaas.Service
+}
+
+// NewTenantHandler creates a Tenant service
+func NewTenantHandler(svc iaas.Service) TenantAPI {
+	return &TenantHandler{service: svc}
+}
+
+// fsckState contains what is known of the tenant during a consistency check
+type fsckState struct {
+	service iaas.Service
+	report  *abstract.FsckReport
+
+	// hosts, networks and volumes contain the resources described by metadata which exist at the provider, indexed by ID
+	hosts    map[string]*abstract.Host
+	networks map[string]*abstract.Network
+	volumes  map[string]*abstract.Volume
+}
+
+// Fsck cross-checks the metadata of hosts, networks, volumes, shares and clusters against the resources of the provider
+// With repair, the metadata are fixed: metadata of missing resources and dangling references are removed, the lists
+// of hosts of networks are updated. Resources of the provider without metadata are only reported, never deleted.
+func (handler *TenantHandler) Fsck(ctx context.Context, repair bool) (report *abstract.FsckReport, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("(%v)", repair), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	state := &fsckState{
+		service:  handler.service,
+		report:   &abstract.FsckReport{Repair: repair},
+		hosts:    map[string]*abstract.Host{},
+		networks: map[string]*abstract.Network{},
+		volumes:  map[string]*abstract.Volume{},
+	}
+	for _, step := range []func() error{
+		state.checkHosts, state.checkNetworks, state.checkVolumes,
+		state.checkHostReferences, state.checkNetworkReferences, state.checkVolumeReferences,
+		state.checkShares, state.checkClusters,
+	} {
+		if ctx != nil && ctx.Err() != nil {
+			return state.report, ctx.Err()
+		}
+		if err = step(); err != nil {
+			return state.report, err
+		}
+	}
+	return state.report, nil
+}
+
+// addIssue records an issue in the report, and tries to repair it with 'repairFunc' if requested
+func (state *fsckState) addIssue(kind, resource, id, name, message string, repairFunc func() error) {
+	issue := abstract.FsckIssue{
+		Kind:     kind,
+		Resource: resource,
+		ID:       id,
+		Name:     name,
+		Message:  message,
+	}
+	if state.report.Repair && repairFunc != nil {
+		if err := repairFunc(); err != nil {
+			logrus.Warnf("failed to repair %s '%s': %v", resource, name, err)
+			issue.Message += fmt.Sprintf(" (repair failed: %v)", err)
+		} else {
+			issue.Repaired = true
+		}
+	}
+	state.report.Issues = append(state.report.Issues, issue)
+}
+
+// checkHosts compares the hosts in metadata with the hosts of the provider
+func (state *fsckState) checkHosts() error {
+	found, err := state.service.ListHosts()
+	if err != nil {
+		return fail.Wrap(err, "failed to list hosts of provider")
+	}
+	existing := map[string]*abstract.Host{}
+	for _, h := range found {
+		existing[h.ID] = h
+	}
+
+	mh, err := metadata.NewHost(state.service)
+	if err != nil {
+		return err
+	}
+	var orphans []*abstract.Host
+	err = mh.Browse(
+		func(host *abstract.Host) error {
+			if _, ok := existing[host.ID]; ok {
+				state.hosts[host.ID] = host
+			} else {
+				orphans = append(orphans, host)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, host := range orphans {
+		host := host
+		state.addIssue(
+			abstract.FsckOrphanMetadata, "host", host.ID, host.Name, "host does not exist anymore at the provider",
+			func() error { return metadata.RemoveHost(state.service, host) },
+		)
+	}
+	for _, h := range found {
+		if _, ok := state.hosts[h.ID]; !ok {
+			state.addIssue(abstract.FsckOrphanResource, "host", h.ID, h.Name, "host of the provider has no metadata", nil)
+		}
+	}
+	return nil
+}
+
+// checkNetworks compares the networks in metadata with the networks of the provider
+func (state *fsckState) checkNetworks() error {
+	found, err := state.service.ListNetworks()
+	if err != nil {
+		return fail.Wrap(err, "failed to list networks of provider")
+	}
+	existing := map[string]*abstract.Network{}
+	for _, n := range found {
+		existing[n.ID] = n
+	}
+
+	mn, err := metadata.NewNetwork(state.service)
+	if err != nil {
+		return err
+	}
+	var orphans []*abstract.Network
+	err = mn.Browse(
+		func(network *abstract.Network) error {
+			if _, ok := existing[network.ID]; ok {
+				state.networks[network.ID] = network
+			} else {
+				orphans = append(orphans, network)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, network := range orphans {
+		network := network
+		state.addIssue(
+			abstract.FsckOrphanMetadata, "network", network.ID, network.Name, "network does not exist anymore at the provider",
+			func() error { return metadata.RemoveNetwork(state.service, network) },
+		)
+	}
+	for _, n := range found {
+		if _, ok := state.networks[n.ID]; !ok {
+			state.addIssue(abstract.FsckOrphanResource, "network", n.ID, n.Name, "network of the provider has no metadata", nil)
+		}
+	}
+	return nil
+}
+
+// checkVolumes compares the volumes in metadata with the volumes of the provider
+func (state *fsckState) checkVolumes() error {
+	found, err := state.service.ListVolumes()
+	if err != nil {
+		return fail.Wrap(err, "failed to list volumes of provider")
+	}
+	existing := map[string]bool{}
+	for _, v := range found {
+		existing[v.ID] = true
+	}
+
+	mv, err := metadata.NewVolume(state.service)
+	if err != nil {
+		return err
+	}
+	var orphans []*abstract.Volume
+	err = mv.Browse(
+		func(volume *abstract.Volume) error {
+			if existing[volume.ID] {
+				state.volumes[volume.ID] = volume
+			} else {
+				orphans = append(orphans, volume)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range orphans {
+		volume := volume
+		state.addIssue(
+			abstract.FsckOrphanMetadata, "volume", volume.ID, volume.Name, "volume does not exist anymore at the provider",
+			func() error { return metadata.RemoveVolume(state.service, volume.ID) },
+		)
+	}
+	for _, v := range found {
+		if _, ok := state.volumes[v.ID]; !ok {
+			state.addIssue(abstract.FsckOrphanResource, "volume", v.ID, v.Name, "volume of the provider has no metadata", nil)
+		}
+	}
+	return nil
+}
+
+// checkHostReferences looks for references in the properties of hosts to volumes, networks and hosts which don't exist anymore
+func (state *fsckState) checkHostReferences() error {
+	for _, host := range state.hosts {
+		host := host
+		var (
+			messages []string
+			fixes    []func() error
+		)
+
+		err := host.Properties.LockForRead(hostproperty.VolumesV1).ThenUse(
+			func(clonable data.Clonable) error {
+				hostVolumesV1 := clonable.(*propsv1.HostVolumes)
+				for id := range hostVolumesV1.VolumesByID {
+					if _, ok := state.volumes[id]; ok {
+						continue
+					}
+					id := id
+					messages = append(messages, fmt.Sprintf("volume '%s' does not exist anymore", id))
+					fixes = append(
+						fixes, func() error {
+							return host.Properties.LockForWrite(hostproperty.VolumesV1).ThenUse(
+								func(clonable data.Clonable) error {
+									hostVolumesV1 := clonable.(*propsv1.HostVolumes)
+									if device, ok := hostVolumesV1.DevicesByID[id]; ok {
+										delete(hostVolumesV1.VolumesByDevice, device)
+									}
+									for name, v := range hostVolumesV1.VolumesByName {
+										if v == id {
+											delete(hostVolumesV1.VolumesByName, name)
+										}
+									}
+									delete(hostVolumesV1.DevicesByID, id)
+									delete(hostVolumesV1.VolumesByID, id)
+									return nil
+								},
+							)
+						},
+					)
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		err = host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(
+			func(clonable data.Clonable) error {
+				for id, name := range clonable.(*propsv1.HostNetwork).NetworksByID {
+					if _, ok := state.networks[id]; ok {
+						continue
+					}
+					id, name := id, name
+					messages = append(messages, fmt.Sprintf("network '%s' does not exist anymore", name))
+					fixes = append(
+						fixes, func() error {
+							return host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(
+								func(clonable data.Clonable) error {
+									hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+									delete(hostNetworkV1.NetworksByID, id)
+									delete(hostNetworkV1.NetworksByName, name)
+									return nil
+								},
+							)
+						},
+					)
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		err = host.Properties.LockForRead(hostproperty.SharesV1).ThenUse(
+			func(clonable data.Clonable) error {
+				for shareID, share := range clonable.(*propsv1.HostShares).ByID {
+					for id, name := range share.ClientsByID {
+						if _, ok := state.hosts[id]; ok {
+							continue
+						}
+						shareID, id, name := shareID, id, name
+						messages = append(messages, fmt.Sprintf("client '%s' of share '%s' does not exist anymore", name, share.Name))
+						fixes = append(
+							fixes, func() error {
+								return host.Properties.LockForWrite(hostproperty.SharesV1).ThenUse(
+									func(clonable data.Clonable) error {
+										if s, ok := clonable.(*propsv1.HostShares).ByID[shareID]; ok {
+											delete(s.ClientsByID, id)
+											delete(s.ClientsByName, name)
+										}
+										return nil
+									},
+								)
+							},
+						)
+					}
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			continue
+		}
+		state.addIssue(
+			abstract.FsckDanglingReference, "host", host.ID, host.Name, strings.Join(messages, "; "),
+			func() error {
+				for _, fix := range fixes {
+					if err := fix(); err != nil {
+						return err
+					}
+				}
+				_, err := metadata.SaveHost(state.service, host)
+				return err
+			},
+		)
+	}
+	return nil
+}
+
+// checkNetworkReferences looks for hosts and gateways of networks which don't exist anymore, and for hosts attached
+// to a network but not listed in it
+func (state *fsckState) checkNetworkReferences() error {
+	attached := map[string]map[string]string{}
+	for _, host := range state.hosts {
+		host := host
+		err := host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(
+			func(clonable data.Clonable) error {
+				hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+				if hostNetworkV1.IsGateway {
+					return nil
+				}
+				for id := range hostNetworkV1.NetworksByID {
+					if attached[id] == nil {
+						attached[id] = map[string]string{}
+					}
+					attached[id][host.ID] = host.Name
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, network := range state.networks {
+		network := network
+		for _, gwID := range []string{network.GatewayID, network.SecondaryGatewayID} {
+			if _, ok := state.hosts[gwID]; gwID != "" && !ok {
+				state.addIssue(
+					abstract.FsckDanglingReference, "network", network.ID, network.Name,
+					fmt.Sprintf("gateway '%s' of network does not exist anymore", gwID), nil,
+				)
+			}
+		}
+
+		var stale, missing []string
+		err := network.Properties.LockForRead(networkproperty.HostsV1).ThenUse(
+			func(clonable data.Clonable) error {
+				networkHostsV1 := clonable.(*propsv1.NetworkHosts)
+				for id := range networkHostsV1.ByID {
+					if _, ok := attached[network.ID][id]; !ok {
+						stale = append(stale, id)
+					}
+				}
+				for id := range attached[network.ID] {
+					if _, ok := networkHostsV1.ByID[id]; !ok {
+						missing = append(missing, id)
+					}
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if len(stale) == 0 && len(missing) == 0 {
+			continue
+		}
+
+		state.addIssue(
+			abstract.FsckStaleNetworkHosts, "network", network.ID, network.Name,
+			fmt.Sprintf("network lists %d host(s) not attached to it, and misses %d attached host(s)", len(stale), len(missing)),
+			func() error {
+				err := network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(
+					func(clonable data.Clonable) error {
+						networkHostsV1 := clonable.(*propsv1.NetworkHosts)
+						for _, id := range stale {
+							delete(networkHostsV1.ByName, networkHostsV1.ByID[id])
+							delete(networkHostsV1.ByID, id)
+						}
+						for _, id := range missing {
+							name := attached[network.ID][id]
+							networkHostsV1.ByID[id] = name
+							networkHostsV1.ByName[name] = id
+						}
+						return nil
+					},
+				)
+				if err != nil {
+					return err
+				}
+				_, err = metadata.SaveNetwork(state.service, network)
+				return err
+			},
+		)
+	}
+	return nil
+}
+
+// checkVolumeReferences looks for hosts attaching volumes which don't exist anymore
+func (state *fsckState) checkVolumeReferences() error {
+	for _, volume := range state.volumes {
+		volume := volume
+		var dangling []string
+		err := volume.Properties.LockForRead(volumeproperty.AttachedV1).ThenUse(
+			func(clonable data.Clonable) error {
+				for id := range clonable.(*propsv1.VolumeAttachments).Hosts {
+					if _, ok := state.hosts[id]; !ok {
+						dangling = append(dangling, id)
+					}
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if len(dangling) == 0 {
+			continue
+		}
+
+		state.addIssue(
+			abstract.FsckDanglingReference, "volume", volume.ID, volume.Name,
+			fmt.Sprintf("volume is attached to %d host(s) which do not exist anymore", len(dangling)),
+			func() error {
+				err := volume.Properties.LockForWrite(volumeproperty.AttachedV1).ThenUse(
+					func(clonable data.Clonable) error {
+						volumeAttachedV1 := clonable.(*propsv1.VolumeAttachments)
+						for _, id := range dangling {
+							delete(volumeAttachedV1.Hosts, id)
+						}
+						return nil
+					},
+				)
+				if err != nil {
+					return err
+				}
+				_, err = metadata.SaveVolume(state.service, volume)
+				return err
+			},
+		)
+	}
+	return nil
+}
+
+// checkShares looks for shares served by hosts which don't exist anymore
+func (state *fsckState) checkShares() error {
+	ms, err := metadata.NewShare(state.service)
+	if err != nil {
+		return err
+	}
+	type shareEntry struct {
+		hostID, hostName, shareID, shareName string
+	}
+	var orphans []shareEntry
+	err = ms.BrowseAll(
+		func(hostID, hostName, shareID, shareName string) error {
+			if _, ok := state.hosts[hostID]; !ok {
+				orphans = append(orphans, shareEntry{hostID, hostName, shareID, shareName})
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range orphans {
+		s := s
+		state.addIssue(
+			abstract.FsckOrphanMetadata, "share", s.shareID, s.shareName,
+			fmt.Sprintf("host '%s' serving the share does not exist anymore", s.hostName),
+			func() error { return metadata.RemoveShare(state.service, s.hostID, s.hostName, s.shareID, s.shareName) },
+		)
+	}
+	return nil
+}
+
+// checkClusters looks for nodes and networks of clusters which don't exist anymore
+// These issues are not repaired, the cluster has to be shrunk or deleted.
+func (state *fsckState) checkClusters() error {
+	m, err := control.NewMetadata(state.service)
+	if err != nil {
+		return err
+	}
+	return m.Browse(
+		func(c *control.Controller) error {
+			err := c.Properties.LockForRead(property.NodesV2).ThenUse(
+				func(clonable data.Clonable) error {
+					nodesV2 := clonable.(*clusterpropsv2.Nodes)
+					for _, list := range [][]*clusterpropsv2.Node{nodesV2.Masters, nodesV2.PrivateNodes, nodesV2.PublicNodes} {
+						for _, node := range list {
+							if _, ok := state.hosts[node.ID]; !ok {
+								state.addIssue(
+									abstract.FsckDanglingReference, "cluster", "", c.Name,
+									fmt.Sprintf("node '%s' of cluster does not exist anymore", node.Name), nil,
+								)
+							}
+						}
+					}
+					return nil
+				},
+			)
+			if err != nil {
+				return err
+			}
+			return c.Properties.LockForRead(property.NetworkV2).ThenUse(
+				func(clonable data.Clonable) error {
+					networkV2 := clonable.(*clusterpropsv2.Network)
+					if _, ok := state.networks[networkV2.NetworkID]; networkV2.NetworkID != "" && !ok {
+						state.addIssue(
+							abstract.FsckDanglingReference, "cluster", "", c.Name,
+							fmt.Sprintf("network '%s' of cluster does not exist anymore", networkV2.NetworkID), nil,
+						)
+					}
+					return nil
+				},
+			)
+		},
+	)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+)
+
+// countFsckIssues returns the number of issues of report of the kind and resource given, and how many were repaired
+func countFsckIssues(report *abstract.FsckReport, kind, resource string) (count, repaired int) {
+	for _, i := range report.Issues {
+		if i.Kind == kind && i.Resource == resource {
+			count++
+			if i.Repaired {
+				repaired++
+			}
+		}
+	}
+	return count, repaired
+}
+
+func TestTenantHandler_Fsck(t *testing.T) {
+	svc, err := iaas.NewServiceFromTenant(newTestTenant(t, "simulator"))
+	require.Nil(t, err)
+
+	network, err := createTestNetwork(svc, "fsck_network")
+	require.Nil(t, err)
+	handler := NewTenantHandler(svc)
+	report, err := handler.Fsck(context.Background(), false)
+	require.Nil(t, err)
+	assert.Empty(t, report.Issues)
+
+	// gateway deleted at the provider, volume without metadata, metadata of a volume which doesn't exist
+	require.Nil(t, svc.DeleteHost(network.GatewayID))
+	_, err = svc.CreateVolume(abstract.VolumeRequest{Name: "fsck_volume", Size: 10, Speed: volumespeed.HDD})
+	require.Nil(t, err)
+	missing := abstract.NewVolume()
+	missing.ID, missing.Name = "missing", "missing"
+	_, err = metadata.SaveVolume(svc, missing)
+	require.Nil(t, err)
+
+	report, err = handler.Fsck(context.Background(), false)
+	require.Nil(t, err)
+	count, _ := countFsckIssues(report, abstract.FsckOrphanMetadata, "host")
+	assert.Equal(t, 1, count)
+	count, _ = countFsckIssues(report, abstract.FsckOrphanMetadata, "volume")
+	assert.Equal(t, 1, count)
+	count, _ = countFsckIssues(report, abstract.FsckOrphanResource, "volume")
+	assert.Equal(t, 1, count)
+	count, _ = countFsckIssues(report, abstract.FsckDanglingReference, "network")
+	assert.Equal(t, 1, count)
+
+	report, err = handler.Fsck(context.Background(), true)
+	require.Nil(t, err)
+	_, repaired := countFsckIssues(report, abstract.FsckOrphanMetadata, "host")
+	assert.Equal(t, 1, repaired)
+	_, repaired = countFsckIssues(report, abstract.FsckOrphanMetadata, "volume")
+	assert.Equal(t, 1, repaired)
+
+	// resources of the provider are never deleted
+	report, err = handler.Fsck(context.Background(), false)
+	require.Nil(t, err)
+	count, _ = countFsckIssues(report, abstract.FsckOrphanMetadata, "host")
+	assert.Equal(t, 0, count)
+	count, _ = countFsckIssues(report, abstract.FsckOrphanResource, "volume")
+	assert.Equal(t, 1, count)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hostproperty"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumeproperty"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumestate"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/server/metadata"
 	"github.com/CS-SI/SafeScale/lib/system/nfs"
@@ -50,13 +51,17 @@ import (
 // VolumeAPI defines API to manipulate hosts
 type VolumeAPI interface {
 	Delete(ctx context.Context, ref string) error
-	List(ctx context.Context, all bool) ([]abstract.Volume, error)
+	List(ctx context.Context, all bool, labels map[string]string) ([]abstract.Volume, error)
 	Inspect(ctx context.Context, ref string) (*abstract.Volume, map[string]*propsv1.HostLocalMount, error)
-	Create(ctx context.Context, name string, size int, speed volumespeed.Enum) (*abstract.Volume, error)
+	Create(ctx context.Context, name string, size int, speed volumespeed.Enum, labels map[string]string) (*abstract.Volume, error)
 	Attach(ctx context.Context, volume string, host string, path string, format string, doNotFormat bool) (string, error)
 	Detach(ctx context.Context, volume string, host string) error
 	Expand(ctx context.Context, volume string, host string, increment uint32, incrementType string) error
 	Shrink(ctx context.Context, volume string, host string, increment uint32, incrementType string) error
+	CreateSnapshot(ctx context.Context, volume string, name string, description string, force bool) (*abstract.VolumeSnapshot, error)
+	ListSnapshots(ctx context.Context, volume string) ([]abstract.VolumeSnapshot, error)
+	DeleteSnapshot(ctx context.Context, ref string) error
+	RestoreSnapshot(ctx context.Context, snapshot string, name string, speed volumespeed.Enum) (*abstract.Volume, error)
 }
 
 // VolumeHandler volume service
@@ -72,17 +77,21 @@ func NewVolumeHandler(svc iaas.Service) VolumeAPI {
 }
 
 // List returns the network list
-func (handler *VolumeHandler) List(ctx context.Context, all bool) (volumes []abstract.Volume, err error) {
+// If labels is not empty, only the volumes carrying all of these labels are returned
+func (handler *VolumeHandler) List(ctx context.Context, all bool, labels map[string]string) (volumes []abstract.Volume, err error) {
 	if handler == nil {
 		return nil, fail.InvalidInstanceError()
 	}
 	// FIXME: validate parameters
 
-	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	tracer := debug.NewTracer(nil, fmt.Sprintf("(%v, %v)", all, labels), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
 
 	if all {
+		if len(labels) > 0 {
+			return nil, fail.InvalidRequestError("cannot filter by labels volumes not managed by SafeScale")
+		}
 		volumes, err := handler.service.ListVolumes()
 		return volumes, err
 	}
@@ -93,6 +102,9 @@ func (handler *VolumeHandler) List(ctx context.Context, all bool) (volumes []abs
 	}
 	err = mv.Browse(
 		func(volume *abstract.Volume) error {
+			if len(labels) > 0 && !abstract.MatchLabels(volumeLabels(volume), labels) {
+				return nil
+			}
 			volumes = append(volumes, *volume)
 			return nil
 		},
@@ -115,6 +127,7 @@ func (handler *VolumeHandler) Delete(ctx context.Context, ref string) (err error
 	tracer := debug.NewTracer(nil, fmt.Sprintf("(%s)", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "volume.delete", "volume:"+ref, nil)(&err)
 
 	mv, err := metadata.LoadVolume(handler.service, ref)
 	if err != nil {
@@ -170,7 +183,7 @@ func (handler *VolumeHandler) Delete(ctx context.Context, ref string) (err error
 	select {
 	case <-ctx.Done():
 		logrus.Warnf("Volume deletion cancelled by user")
-		volumeBis, err := handler.Create(context.Background(), volume.Name, volume.Size, volume.Speed)
+		volumeBis, err := handler.Create(context.Background(), volume.Name, volume.Size, volume.Speed, volumeLabels(volume))
 		if err != nil {
 			return fmt.Errorf("failed to stop volume deletion")
 		}
@@ -261,18 +274,48 @@ func (handler *VolumeHandler) Inspect(
 }
 
 // Create a volume
-func (handler *VolumeHandler) Create(ctx context.Context, name string, size int, speed volumespeed.Enum) (volume *abstract.Volume, err error) {
+func (handler *VolumeHandler) Create(ctx context.Context, name string, size int, speed volumespeed.Enum, labels map[string]string) (volume *abstract.Volume, err error) {
 	if handler == nil {
 		return nil, fail.InvalidInstanceError()
 	}
 	// FIXME: validate parameters
+	if err = abstract.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
 
 	tracer := debug.NewTracer(
 		nil, fmt.Sprintf("('%s', %d, %s)", name, size, speed.String()), true,
 	).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "volume.create", "volume:"+name, map[string]string{"size": fmt.Sprint(size), "speed": speed.String()},
+	)(&err)
+
+	return handler.create(
+		ctx, abstract.VolumeRequest{
+			Name:   name,
+			Size:   size,
+			Speed:  speed,
+			Labels: labels,
+		},
+	)
+}
+
+// volumeLabels returns the user-defined labels stored in volume properties
+func volumeLabels(volume *abstract.Volume) (labels map[string]string) {
+	_ = volume.Properties.LockForRead(volumeproperty.LabelsV1).ThenUse(
+		func(clonable data.Clonable) error {
+			labels = clonable.(*propsv1.VolumeLabels).Labels
+			return nil
+		},
+	)
+	return labels
+}
 
+// create creates a volume from request and saves its metadata
+func (handler *VolumeHandler) create(ctx context.Context, request abstract.VolumeRequest) (volume *abstract.Volume, err error) {
+	name := request.Name
 	_, err = metadata.LoadVolume(handler.service, name)
 	if err != nil {
 		if _, ok := err.(fail.ErrNotFound); !ok {
@@ -282,13 +325,7 @@ func (handler *VolumeHandler) Create(ctx context.Context, name string, size int,
 		return nil, fail.DuplicateError(fmt.Sprintf("volume '%s' already exists", name))
 	}
 
-	volume, err = handler.service.CreateVolume(
-		abstract.VolumeRequest{
-			Name:  name,
-			Size:  size,
-			Speed: speed,
-		},
-	)
+	volume, err = handler.service.CreateVolume(request)
 	if err != nil {
 		switch err.(type) {
 		case fail.ErrNotFound, fail.ErrInvalidRequest, fail.ErrTimeout:
@@ -317,6 +354,18 @@ func (handler *VolumeHandler) Create(ctx context.Context, name string, size int,
 		}
 	}()
 
+	if len(request.Labels) > 0 {
+		err = volume.Properties.LockForWrite(volumeproperty.LabelsV1).ThenUse(
+			func(clonable data.Clonable) error {
+				clonable.(*propsv1.VolumeLabels).Labels = request.Labels
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	md, err := metadata.SaveVolume(handler.service, volume)
 	if err != nil {
 		logrus.Debugf("Error creating volume: saving volume metadata: %+v", err)
@@ -394,6 +443,9 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 	)
 	defer tracer.WithStopwatch().GoingIn().OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "volume.attach", "volume:"+volumeName, map[string]string{"host": hostName, "path": path},
+	)(&err)
 
 	// Get volume data
 	volume, _, err := handler.Inspect(ctx, volumeName)
@@ -1275,6 +1327,11 @@ func getHostLocalMount(ctx context.Context, handler *VolumeHandler, volumeName,
 
 // Detach detach the volume identified by ref, ref can be the name or the id
 func (handler *VolumeHandler) Expand(ctx context.Context, volumeName, hostName string, increment uint32, incrementType string) (err error) {
+	defer metadata.Audit(
+		ctx, handler.service, "volume.expand", "volume:"+volumeName,
+		map[string]string{"host": hostName, "increment": fmt.Sprint(increment), "unit": incrementType},
+	)(&err)
+
 	// Load volume data
 	volume, _, err := handler.Inspect(ctx, volumeName)
 	if err != nil {
@@ -1602,6 +1659,9 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", volumeName, hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "volume.detach", "volume:"+volumeName, map[string]string{"host": hostName},
+	)(&err)
 
 	// Load volume data
 	volume, _, err := handler.Inspect(ctx, volumeName)
@@ -1757,3 +1817,248 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 
 	return nil
 }
+
+// CreateSnapshot creates a snapshot of the volume referenced by volumeRef
+func (handler *VolumeHandler) CreateSnapshot(
+	ctx context.Context, volumeRef, name, description string, force bool,
+) (snapshot *abstract.VolumeSnapshot, err error) {
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if volumeRef == "" {
+		return nil, fail.InvalidParameterError("volumeRef", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", volumeRef, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(
+		ctx, handler.service, "volume.snapshot.create", "volume:"+volumeRef, map[string]string{"snapshot": name},
+	)(&err)
+
+	_, err = metadata.LoadVolumeSnapshot(handler.service, name)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); !ok {
+			return nil, err
+		}
+	} else {
+		return nil, fail.DuplicateError(fmt.Sprintf("volume snapshot '%s' already exists", name))
+	}
+
+	mv, err := metadata.LoadVolume(handler.service, volumeRef)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("volume", volumeRef)
+		}
+		return nil, err
+	}
+	volume, err := mv.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err = handler.service.CreateVolumeSnapshot(
+		abstract.VolumeSnapshotRequest{
+			Name:        name,
+			Description: description,
+			VolumeID:    volume.ID,
+			Force:       force,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Name == "" {
+		snapshot.Name = name
+	}
+	if snapshot.Size == 0 {
+		snapshot.Size = volume.Size
+	}
+
+	// starting from here delete snapshot if function ends with failure
+	newSnapshot := snapshot
+	defer func() {
+		if err != nil {
+			derr := handler.service.DeleteVolumeSnapshot(newSnapshot.ID)
+			if derr != nil {
+				logrus.Errorf("Cleaning up on failure, failed to delete volume snapshot '%s': %v", name, derr)
+				err = fail.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	_, err = metadata.SaveVolumeSnapshot(handler.service, snapshot)
+	if err != nil {
+		logrus.Debugf("Error creating volume snapshot: saving snapshot metadata: %+v", err)
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		logrus.Warnf("Volume snapshot creation cancelled by user")
+		derr := metadata.RemoveVolumeSnapshot(handler.service, newSnapshot.ID)
+		if derr != nil {
+			logrus.Warnf("failed to delete metadata of volume snapshot '%s'", name)
+		}
+		err = fmt.Errorf("volume snapshot creation cancelled by user")
+		return nil, err
+	default:
+	}
+
+	return snapshot, nil
+}
+
+// ListSnapshots returns the snapshots registered in metadata, restricted to the ones of volumeRef if not empty
+// Snapshots still being created are refreshed from the provider and their metadata updated
+func (handler *VolumeHandler) ListSnapshots(ctx context.Context, volumeRef string) (snapshots []abstract.VolumeSnapshot, err error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", volumeRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	volumeID := ""
+	if volumeRef != "" {
+		mv, err := metadata.LoadVolume(handler.service, volumeRef)
+		if err != nil {
+			if _, ok := err.(fail.ErrNotFound); ok {
+				return nil, abstract.ResourceNotFoundError("volume", volumeRef)
+			}
+			return nil, err
+		}
+		volume, err := mv.Get()
+		if err != nil {
+			return nil, err
+		}
+		volumeID = volume.ID
+	}
+
+	ms, err := metadata.NewVolumeSnapshot(handler.service)
+	if err != nil {
+		return nil, err
+	}
+	var outdated []*abstract.VolumeSnapshot
+	err = ms.Browse(
+		func(snapshot *abstract.VolumeSnapshot) error {
+			if volumeID != "" && snapshot.VolumeID != volumeID {
+				return nil
+			}
+			if snapshot.State == volumestate.CREATING {
+				outdated = append(outdated, snapshot)
+				return nil
+			}
+			snapshots = append(snapshots, *snapshot)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range outdated {
+		current, err := handler.service.GetVolumeSnapshot(snapshot.ID)
+		if err != nil {
+			logrus.Warnf("failed to refresh state of volume snapshot '%s': %v", snapshot.Name, err)
+		} else if current.State != snapshot.State {
+			snapshot.State = current.State
+			_, err = metadata.SaveVolumeSnapshot(handler.service, snapshot)
+			if err != nil {
+				return nil, err
+			}
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshot deletes the volume snapshot referenced by ref
+func (handler *VolumeHandler) DeleteSnapshot(ctx context.Context, ref string) (err error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+	defer metadata.Audit(ctx, handler.service, "volume.snapshot.delete", "snapshot:"+ref, nil)(&err)
+
+	ms, err := metadata.LoadVolumeSnapshot(handler.service, ref)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return abstract.ResourceNotFoundError("volume snapshot", ref)
+		}
+		return err
+	}
+	snapshot, err := ms.Get()
+	if err != nil {
+		return err
+	}
+
+	err = handler.service.DeleteVolumeSnapshot(snapshot.ID)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); !ok {
+			return err
+		}
+		logrus.Warnf("Unable to find the volume snapshot on provider side, cleaning up metadata")
+	}
+	return ms.Delete()
+}
+
+// RestoreSnapshot creates a new volume named name from the volume snapshot referenced by snapshotRef
+func (handler *VolumeHandler) RestoreSnapshot(
+	ctx context.Context, snapshotRef, name string, speed volumespeed.Enum,
+) (volume *abstract.Volume, err error) {
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if snapshotRef == "" {
+		return nil, fail.InvalidParameterError("snapshotRef", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s', %s)", snapshotRef, name, speed.String()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ms, err := metadata.LoadVolumeSnapshot(handler.service, snapshotRef)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("volume snapshot", snapshotRef)
+		}
+		return nil, err
+	}
+	snapshot, err := ms.Get()
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.State != volumestate.AVAILABLE {
+		current, err := handler.service.GetVolumeSnapshot(snapshot.ID)
+		if err != nil {
+			return nil, err
+		}
+		if current.State != volumestate.AVAILABLE {
+			return nil, fail.InvalidRequestError(fmt.Sprintf("volume snapshot '%s' is not available (state '%s')", snapshot.Name, current.State.String()))
+		}
+	}
+
+	return handler.create(
+		ctx, abstract.VolumeRequest{
+			Name:       name,
+			Size:       snapshot.Size,
+			Speed:      speed,
+			SnapshotID: snapshot.ID,
+		},
+	)
+}
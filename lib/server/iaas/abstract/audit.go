@@ -0,0 +1,55 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// AuditSuccess is the outcome of an operation which succeeded
+	AuditSuccess = "success"
+	// AuditFailure is the outcome of an operation which failed
+	AuditFailure = "failure"
+
+	// DefaultAuditRetention is the default delay after which the records of the audit log are removed
+	DefaultAuditRetention = 90 * 24 * time.Hour
+)
+
+// AuditRecord describes an operation changing the infrastructure of a tenant
+type AuditRecord struct {
+	Date   time.Time `json:"date"`
+	Actor  string    `json:"actor"` // user and host declared by the client, and address of the client
+	Tenant string    `json:"tenant"`
+	// Operation is '<resource>.<action>', for example 'host.delete'
+	Operation string `json:"operation"`
+	// Target is '<resource>:<name or id>', for example 'host:foo'
+	Target     string            `json:"target"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Outcome    string            `json:"outcome"`
+	Error      string            `json:"error,omitempty"`
+	Duration   time.Duration     `json:"duration"`
+}
+
+// MatchResource tells if the record concerns 'resource', either '<resource>' or '<resource>:<name or id>'
+func (r *AuditRecord) MatchResource(resource string) bool {
+	if resource == "" || r.Target == resource {
+		return true
+	}
+	return !strings.Contains(resource, ":") && strings.HasPrefix(r.Target, resource+":")
+}
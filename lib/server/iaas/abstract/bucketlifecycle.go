@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// DefaultBucketLifecycleInterval is the delay between two enforcements of the lifecycle rules of buckets by the daemon
+const DefaultBucketLifecycleInterval = time.Hour
+
+// BucketLifecycleRule defines the objects of a bucket to delete
+// Both criteria can be combined: objects older than ExpireAfterDays are deleted, then only the KeepLast
+// most recent of the remaining ones are kept.
+type BucketLifecycleRule struct {
+	// Prefix restricts the rule to the objects whose name starts with it; empty means all the objects of the bucket
+	Prefix string `json:"prefix,omitempty"`
+	// ExpireAfterDays is the number of days after its last update an object is deleted; 0 disables expiration
+	ExpireAfterDays int `json:"expire_after_days,omitempty"`
+	// KeepLast is the number of most recently updated objects kept under prefix; 0 disables the limit
+	KeepLast int `json:"keep_last,omitempty"`
+}
+
+// Validate checks the rule is meaningful
+func (blr BucketLifecycleRule) Validate() error {
+	if blr.ExpireAfterDays < 0 {
+		return fail.InvalidParameterError("ExpireAfterDays", "cannot be negative")
+	}
+	if blr.KeepLast < 0 {
+		return fail.InvalidParameterError("KeepLast", "cannot be negative")
+	}
+	if blr.ExpireAfterDays == 0 && blr.KeepLast == 0 {
+		return fail.InvalidParameterError("rule", fmt.Sprintf("rule for prefix '%s' neither expires objects nor limits their count", blr.Prefix))
+	}
+	return nil
+}
+
+// BucketLifecycle contains the lifecycle rules of a bucket
+type BucketLifecycle struct {
+	Bucket string                `json:"bucket"`
+	Rules  []BucketLifecycleRule `json:"rules"`
+	// LastEnforcement is the date the rules were last applied
+	LastEnforcement time.Time `json:"last_enforcement,omitempty"`
+}
+
+// NewBucketLifecycle ...
+func NewBucketLifecycle(bucket string) *BucketLifecycle {
+	return &BucketLifecycle{
+		Bucket: bucket,
+		Rules:  []BucketLifecycleRule{},
+	}
+}
+
+// Serialize serializes BucketLifecycle instance into bytes (output json code)
+func (bl *BucketLifecycle) Serialize() ([]byte, error) {
+	return serialize.ToJSON(bl)
+}
+
+// Deserialize reads json code and restores a BucketLifecycle
+func (bl *BucketLifecycle) Deserialize(buf []byte) error {
+	return serialize.FromJSON(buf, bl)
+}
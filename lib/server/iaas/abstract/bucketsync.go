@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// DefaultBucketSyncConcurrency is the number of objects copied in parallel by a bucket synchronization
+const DefaultBucketSyncConcurrency = 4
+
+// BucketSyncOptions tunes a synchronization of buckets
+type BucketSyncOptions struct {
+	// Delete propagates to the destination the deletion of objects previously synchronized
+	Delete bool
+	// Concurrency is the number of objects copied in parallel
+	Concurrency int
+}
+
+// BucketSyncState records the objects replicated by the synchronizations of a bucket to another,
+// allowing to resume an interrupted synchronization and to propagate deletions
+type BucketSyncState struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	// Objects contains the ETag of the source objects replicated in destination, indexed by name
+	Objects  map[string]string `json:"objects,omitempty"`
+	LastSync time.Time         `json:"last_sync,omitempty"`
+}
+
+// NewBucketSyncState ...
+func NewBucketSyncState(source, destination DataBucket) *BucketSyncState {
+	return &BucketSyncState{
+		Source:      source.String(),
+		Destination: destination.String(),
+		Objects:     map[string]string{},
+	}
+}
+
+// GetName returns the name identifying the synchronization
+func (bss *BucketSyncState) GetName() string {
+	return bss.Source + " > " + bss.Destination
+}
+
+// Serialize serializes BucketSyncState instance into bytes (output json code)
+func (bss *BucketSyncState) Serialize() ([]byte, error) {
+	return serialize.ToJSON(bss)
+}
+
+// Deserialize reads json code and restores a BucketSyncState
+func (bss *BucketSyncState) Deserialize(buf []byte) error {
+	err := serialize.FromJSON(buf, bss)
+	if err != nil {
+		return err
+	}
+	if bss.Objects == nil {
+		bss.Objects = map[string]string{}
+	}
+	return nil
+}
+
+// BucketSyncReport sums up a pass of synchronization
+type BucketSyncReport struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Copied      int    `json:"copied"`
+	Skipped     int    `json:"skipped"`
+	Deleted     int    `json:"deleted"`
+	Bytes       int64  `json:"bytes"`
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hostproperty"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/utils/crypt"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
@@ -145,6 +146,30 @@ type HostRequest struct {
 	DiskSize int
 	// Use spot instance
 	Spot bool
+	// Labels contains the user-defined key/value pairs to attach to the host
+	Labels map[string]string
+	// HostPlacement tells where the host has to be created
+	HostPlacement
+}
+
+// HostPlacement represents the placement constraints of a host
+type HostPlacement struct {
+	// AvailabilityZone contains the zone where to create the host (if empty, the provider default zone is used)
+	AvailabilityZone string
+	// ServerGroup contains the name of the group of hosts to which the placement policy applies
+	ServerGroup string
+	// PlacementPolicy tells how the hosts of ServerGroup have to be placed relatively to each other
+	PlacementPolicy placementpolicy.Enum
+}
+
+// Validate checks the placement constraints are consistent
+func (hp HostPlacement) Validate() fail.Error {
+	if hp.PlacementPolicy != placementpolicy.NONE && hp.ServerGroup == "" {
+		return fail.InvalidRequestError(
+			fmt.Sprintf("placement policy '%s' needs a server group", hp.PlacementPolicy.String()),
+		)
+	}
+	return nil
 }
 
 // HostDefinition ...
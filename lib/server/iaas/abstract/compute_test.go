@@ -0,0 +1,36 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func TestHostPlacement_Validate(t *testing.T) {
+	assert.Nil(t, HostPlacement{}.Validate())
+	assert.Nil(t, HostPlacement{AvailabilityZone: "zone-1"}.Validate())
+	assert.Nil(t, HostPlacement{ServerGroup: "masters", PlacementPolicy: placementpolicy.ANTIAFFINITY}.Validate())
+
+	err := HostPlacement{PlacementPolicy: placementpolicy.AFFINITY}.Validate()
+	assert.NotNil(t, err)
+	assert.IsType(t, fail.ErrInvalidRequest{}, err)
+}
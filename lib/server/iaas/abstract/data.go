@@ -0,0 +1,171 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+const (
+	// DefaultDataChunkSize is the size of the chunks a file is cut in by the data service (10 MB)
+	DefaultDataChunkSize = 10 * 1024 * 1024
+	// MaxDataShards is the maximum number of shards (data + parity) of a chunk
+	MaxDataShards = 256
+)
+
+// DataBucket identifies a bucket used by the data service, possibly in another tenant than the current one
+type DataBucket struct {
+	Tenant string `json:"tenant,omitempty"` // empty means the current tenant
+	Name   string `json:"name"`
+}
+
+// ParseDataBucket parses a bucket reference in the form [<tenant>:]<bucket>
+func ParseDataBucket(ref string) (DataBucket, error) {
+	var db DataBucket
+	parts := strings.SplitN(strings.TrimSpace(ref), ":", 2)
+	if len(parts) == 2 {
+		db.Tenant, db.Name = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if db.Tenant == "" {
+			return db, fail.InvalidParameterError("ref", fmt.Sprintf("missing tenant in '%s'", ref))
+		}
+	} else {
+		db.Name = parts[0]
+	}
+	if db.Name == "" {
+		return db, fail.InvalidParameterError("ref", fmt.Sprintf("missing bucket name in '%s'", ref))
+	}
+	return db, nil
+}
+
+// String returns the reference of the bucket in the form [<tenant>:]<bucket>
+func (db DataBucket) String() string {
+	if db.Tenant == "" {
+		return db.Name
+	}
+	return db.Tenant + ":" + db.Name
+}
+
+// DataRedundancy defines how the chunks of a file survive the loss of buckets
+// With erasure coding, each chunk is split in DataShards shards completed by ParityShards parity shards,
+// and can be rebuilt from any DataShards of them. With replication, each chunk is copied Replicas times.
+type DataRedundancy struct {
+	DataShards   int `json:"data_shards,omitempty"`
+	ParityShards int `json:"parity_shards,omitempty"`
+	Replicas     int `json:"replicas,omitempty"`
+}
+
+// DefaultDataRedundancy returns the redundancy used when none is requested: a parity shard for 2 buckets or more,
+// allowing to lose one bucket
+func DefaultDataRedundancy(bucketCount int) DataRedundancy {
+	if bucketCount < 2 {
+		return DataRedundancy{Replicas: 1}
+	}
+	return DataRedundancy{DataShards: bucketCount - 1, ParityShards: 1}
+}
+
+// IsReplication tells if chunks are replicated instead of erasure coded
+func (dr DataRedundancy) IsReplication() bool {
+	return dr.Replicas > 0
+}
+
+// ShardCount returns the number of shards stored per chunk
+func (dr DataRedundancy) ShardCount() int {
+	if dr.IsReplication() {
+		return dr.Replicas
+	}
+	return dr.DataShards + dr.ParityShards
+}
+
+// Validate checks the redundancy is usable with bucketCount buckets
+func (dr DataRedundancy) Validate(bucketCount int) fail.Error {
+	if bucketCount <= 0 {
+		return fail.InvalidParameterError("bucketCount", "must be greater than 0")
+	}
+	if dr.IsReplication() {
+		if dr.DataShards != 0 || dr.ParityShards != 0 {
+			return fail.InvalidRequestError("cannot use replication and erasure coding simultaneously")
+		}
+		if dr.Replicas > bucketCount {
+			return fail.InvalidRequestError(
+				fmt.Sprintf("cannot store %d replicas of chunks in %d bucket(s)", dr.Replicas, bucketCount),
+			)
+		}
+		return nil
+	}
+	if dr.DataShards <= 0 {
+		return fail.InvalidRequestError("the number of data shards must be greater than 0")
+	}
+	if dr.ParityShards < 0 {
+		return fail.InvalidRequestError("the number of parity shards cannot be negative")
+	}
+	if dr.ShardCount() > MaxDataShards {
+		return fail.InvalidRequestError(fmt.Sprintf("cannot use more than %d shards per chunk", MaxDataShards))
+	}
+	return nil
+}
+
+// DataShard locates a piece of chunk stored in a bucket
+type DataShard struct {
+	Bucket   int    `json:"bucket"`   // index of the bucket in DataFile.Buckets
+	Object   string `json:"object"`   // name of the object in the bucket
+	Checksum string `json:"checksum"` // SHA-256 of the content of the object
+}
+
+// DataChunk describes an encrypted chunk of file
+type DataChunk struct {
+	CipherSize int         `json:"cipher_size"` // size of the encrypted chunk, before sharding
+	Shards     []DataShard `json:"shards"`
+}
+
+// DataFile is the manifest of a file stored by the data service
+type DataFile struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Size       int64          `json:"size"`
+	Date       time.Time      `json:"date"`
+	Key        []byte         `json:"key"` // key used to encrypt the chunks
+	ChunkSize  int            `json:"chunk_size"`
+	Redundancy DataRedundancy `json:"redundancy"`
+	Buckets    []DataBucket   `json:"buckets"`
+	Chunks     []DataChunk    `json:"chunks,omitempty"`
+}
+
+// GetKey returns the encryption key of the chunks
+func (df *DataFile) GetKey() (*crypt.Key, fail.Error) {
+	if len(df.Key) != len(crypt.Key{}) {
+		return nil, fail.InconsistentError(fmt.Sprintf("invalid encryption key in manifest of file '%s'", df.Name))
+	}
+	var key crypt.Key
+	copy(key[:], df.Key)
+	return &key, nil
+}
+
+// Serialize serializes DataFile instance into bytes (output json code)
+func (df *DataFile) Serialize() ([]byte, error) {
+	return serialize.ToJSON(df)
+}
+
+// Deserialize reads json code and restores a DataFile
+func (df *DataFile) Deserialize(buf []byte) error {
+	return serialize.FromJSON(buf, df)
+}
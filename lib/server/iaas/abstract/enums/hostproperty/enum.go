@@ -31,4 +31,6 @@ const (
 	SharesV1 = "6"
 	// MountsV1 contains optional additional info about mounted devices (locally attached or remote filesystem)
 	MountsV1 = "7"
+	// LabelsV1 contains the user-defined key/value pairs attached to the host
+	LabelsV1 = "8"
 )
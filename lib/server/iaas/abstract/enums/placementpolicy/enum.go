@@ -0,0 +1,55 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package placementpolicy defines an enum to represent the placement policy of hosts inside a server group
+package placementpolicy
+
+import (
+	"fmt"
+	"strings"
+)
+
+//go:generate stringer -type=Enum
+
+// Enum represents the placement policy applied to the hosts of a server group
+type Enum int
+
+const (
+	// NONE means no placement constraint
+	NONE Enum = iota
+	// AFFINITY means the hosts of the group are placed as close as possible (same hypervisor if possible)
+	AFFINITY
+	// ANTIAFFINITY means the hosts of the group are spread over different hypervisors
+	ANTIAFFINITY
+)
+
+var stringMap = map[string]Enum{
+	"":              NONE,
+	"none":          NONE,
+	"affinity":      AFFINITY,
+	"anti-affinity": ANTIAFFINITY,
+	"antiaffinity":  ANTIAFFINITY,
+}
+
+// Parse returns an Enum corresponding to the string parameter
+// If the string doesn't correspond to any Enum, returns an error (nil otherwise)
+func Parse(v string) (Enum, error) {
+	e, ok := stringMap[strings.ToLower(v)]
+	if !ok {
+		return NONE, fmt.Errorf("failed to find a placement policy matching with '%s'", v)
+	}
+	return e, nil
+}
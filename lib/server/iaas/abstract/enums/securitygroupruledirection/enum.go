@@ -0,0 +1,52 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package securitygroupruledirection defines an enum to represent the direction of a security group rule
+package securitygroupruledirection
+
+import (
+	"fmt"
+	"strings"
+)
+
+//go:generate stringer -type=Enum
+
+// Enum represents the direction of a security group rule
+type Enum int
+
+const (
+	// UNKNOWN direction
+	UNKNOWN Enum = iota
+	// INGRESS means the rule applies to incoming traffic
+	INGRESS
+	// EGRESS means the rule applies to outgoing traffic
+	EGRESS
+)
+
+var stringMap = map[string]Enum{
+	"ingress": INGRESS,
+	"egress":  EGRESS,
+}
+
+// Parse returns an Enum corresponding to the string parameter
+// If the string doesn't correspond to any Enum, returns an error (nil otherwise)
+func Parse(v string) (Enum, error) {
+	e, ok := stringMap[strings.ToLower(v)]
+	if !ok {
+		return UNKNOWN, fmt.Errorf("failed to find a security group rule direction matching with '%s'", v)
+	}
+	return e, nil
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+const (
+	// FsckOrphanMetadata is the kind of the issues about metadata describing a resource absent from the provider
+	FsckOrphanMetadata = "orphan-metadata"
+	// FsckOrphanResource is the kind of the issues about a resource of the provider without metadata
+	FsckOrphanResource = "orphan-resource"
+	// FsckDanglingReference is the kind of the issues about a reference in metadata to a resource which doesn't exist anymore
+	FsckDanglingReference = "dangling-reference"
+	// FsckStaleNetworkHosts is the kind of the issues about the list of hosts of a network not matching the hosts attached to it
+	FsckStaleNetworkHosts = "stale-network-hosts"
+)
+
+// FsckIssue describes an inconsistency between the metadata of a tenant and the resources of its provider
+type FsckIssue struct {
+	Kind     string `json:"kind"`
+	Resource string `json:"resource"` // type of the resource concerned: host, network, volume, share or cluster
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Message  string `json:"message"`
+	Repaired bool   `json:"repaired,omitempty"`
+}
+
+// FsckReport sums up a consistency check of the metadata of a tenant
+type FsckReport struct {
+	Repair bool        `json:"repair"`
+	Issues []FsckIssue `json:"issues,omitempty"`
+}
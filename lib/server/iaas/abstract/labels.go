@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	// LabelKeyMaxLength is the maximum length of a label key
+	LabelKeyMaxLength = 63
+	// LabelValueMaxLength is the maximum length of a label value
+	LabelValueMaxLength = 255
+)
+
+// labelKeyRegexp defines the characters allowed in a label key
+var labelKeyRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.\-/]*[a-zA-Z0-9])?$`)
+
+// ValidateLabels checks the user-defined labels are usable on every provider
+// The key 'name' is reserved, as some providers use it to store the name of the resource
+func ValidateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if len(k) > LabelKeyMaxLength {
+			return fail.InvalidParameterError("labels", fmt.Sprintf("key '%s' is longer than %d characters", k, LabelKeyMaxLength))
+		}
+		if !labelKeyRegexp.MatchString(k) {
+			return fail.InvalidParameterError("labels", fmt.Sprintf("key '%s' contains invalid characters", k))
+		}
+		if strings.ToLower(k) == "name" {
+			return fail.InvalidParameterError("labels", fmt.Sprintf("key '%s' is reserved", k))
+		}
+		if len(v) > LabelValueMaxLength {
+			return fail.InvalidParameterError("labels", fmt.Sprintf("value of key '%s' is longer than %d characters", k, LabelValueMaxLength))
+		}
+	}
+	return nil
+}
+
+// ParseLabels converts a list of 'key=value' strings to labels
+func ParseLabels(list []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, v := range list {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fail.SyntaxError(fmt.Sprintf("invalid label '%s', must be 'key=value'", v))
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels, ValidateLabels(labels)
+}
+
+// MatchLabels tells if labels contains all the key/value pairs of selector
+func MatchLabels(labels map[string]string, selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
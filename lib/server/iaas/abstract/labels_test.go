@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels([]string{"env=prod", "team=data=science", "empty="})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "data=science", "empty": ""}, labels)
+
+	_, err = ParseLabels([]string{"env"})
+	assert.NotNil(t, err)
+	_, err = ParseLabels([]string{"=prod"})
+	assert.NotNil(t, err)
+	_, err = ParseLabels([]string{"Name=host1"})
+	assert.NotNil(t, err)
+	_, err = ParseLabels([]string{"env prod=1"})
+	assert.NotNil(t, err)
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "data"}
+
+	assert.True(t, MatchLabels(labels, nil))
+	assert.True(t, MatchLabels(labels, map[string]string{"env": "prod"}))
+	assert.True(t, MatchLabels(labels, map[string]string{"env": "prod", "team": "data"}))
+	assert.False(t, MatchLabels(labels, map[string]string{"env": "dev"}))
+	assert.False(t, MatchLabels(labels, map[string]string{"owner": "me"}))
+	assert.False(t, MatchLabels(nil, map[string]string{"env": "prod"}))
+}
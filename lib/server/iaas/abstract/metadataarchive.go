@@ -0,0 +1,35 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import "time"
+
+const (
+	// DefaultMetadataBackupInterval is the default delay between two backups of metadata by the daemon
+	DefaultMetadataBackupInterval = 24 * time.Hour
+)
+
+// MetadataArchiveReport sums up an export, an import or a backup of the metadata of a tenant
+type MetadataArchiveReport struct {
+	// Path is the path of the archive, local to the daemon or inside Bucket
+	Path string `json:"path"`
+	// Bucket is the bucket containing the archive, for a backup
+	Bucket  string    `json:"bucket,omitempty"`
+	Date    time.Time `json:"date"`
+	Folders []string  `json:"folders,omitempty"`
+	Objects int       `json:"objects"`
+}
@@ -0,0 +1,30 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+// MigratedMetadata describes the migrations of properties applied to an object of metadata
+type MigratedMetadata struct {
+	Path       string   `json:"path"`
+	Migrations []string `json:"migrations"`
+}
+
+// MetadataMigrationReport sums up a migration of the metadata of a tenant
+type MetadataMigrationReport struct {
+	DryRun   bool               `json:"dry_run"`
+	Scanned  int                `json:"scanned"`
+	Migrated []MigratedMetadata `json:"migrated,omitempty"`
+}
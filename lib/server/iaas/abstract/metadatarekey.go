@@ -0,0 +1,27 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+// MetadataRekeyReport sums up the re-encryption of the metadata of a tenant with a new key
+type MetadataRekeyReport struct {
+	// KeyID is the ID of the new key, recorded in the header of each object re-encrypted
+	KeyID   string `json:"key_id"`
+	Scanned int    `json:"scanned"`
+	Rekeyed int    `json:"rekeyed"`
+	// Skipped counts the objects already encrypted with the new key, by a previous run or since the rotation started
+	Skipped int `json:"skipped"`
+}
@@ -38,6 +38,8 @@ type GatewayRequest struct {
 
 	// OriginalOsRequest is the original os requested
 	OriginalOsRequest string
+	// Labels contains the user-defined key/value pairs to attach to the gateway
+	Labels map[string]string
 }
 
 // NetworkRequest represents network requirements to create a subnet where Mask is defined in CIDR notation
@@ -54,6 +56,8 @@ type NetworkRequest struct {
 	Domain string
 	// HA tells if 2 gateways and a VIP needs to be created; the VIP IP address will be used as gateway
 	HA bool
+	// Labels contains the user-defined key/value pairs to attach to the network
+	Labels map[string]string
 }
 
 type SubNetwork struct {
@@ -31,6 +31,16 @@ type Bucket struct {
 	Host       string `json:"host,omitempty"`
 	MountPoint string `json:"mountPoint,omitempty"`
 	// NbItems    int    `json:"nbitems,omitempty"`
+	Encrypted bool   `json:"encrypted,omitempty"`
+	KeyID     string `json:"key_id,omitempty"`
+}
+
+// BucketEncryption describes the client-side encryption requested for the objects of a bucket
+type BucketEncryption struct {
+	Enabled bool
+	// Passphrase is the secret supplied by the user to build the key; if empty, the key is derived from
+	// the tenant setting 'CryptKey' of section 'objectstorage'
+	Passphrase string
 }
 
 // Object object to put in a container
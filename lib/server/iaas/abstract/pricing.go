@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+// HoursPerMonth is the number of hours used to compute monthly costs (365 days of 24 hours divided by 12 months)
+const HoursPerMonth = 730.0
+
+// CostItem is the estimated cost of a group of identical hosts
+type CostItem struct {
+	Role         string  `json:"role"` // role of the hosts (host, gateway, master, node)
+	Count        int     `json:"count"`
+	TemplateID   string  `json:"template_id"`
+	TemplateName string  `json:"template_name"`
+	PricePerHour float64 `json:"price_per_hour"` // price of one host, in dollars
+}
+
+// CostEstimate is the estimated cost of the hosts needed to honor a request
+type CostEstimate struct {
+	Items []CostItem `json:"items"`
+}
+
+// Add adds to the estimate count hosts of role using template, priced pricePerHour each
+func (e *CostEstimate) Add(role string, count int, template *HostTemplate, pricePerHour float64) {
+	e.Items = append(
+		e.Items, CostItem{
+			Role:         role,
+			Count:        count,
+			TemplateID:   template.ID,
+			TemplateName: template.Name,
+			PricePerHour: pricePerHour,
+		},
+	)
+}
+
+// HourlyCost returns the cost of one hour of all the hosts of the estimate, in dollars
+func (e *CostEstimate) HourlyCost() float64 {
+	total := 0.0
+	for _, i := range e.Items {
+		total += float64(i.Count) * i.PricePerHour
+	}
+	return total
+}
+
+// MonthlyCost returns the cost of one month of all the hosts of the estimate, in dollars
+func (e *CostEstimate) MonthlyCost() float64 {
+	return e.HourlyCost() * HoursPerMonth
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostEstimate_Add(t *testing.T) {
+	estimate := CostEstimate{}
+	estimate.Add("gateway", 1, &HostTemplate{ID: "t1", Name: "small"}, 0.5)
+	estimate.Add("node", 3, &HostTemplate{ID: "t2", Name: "large"}, 1.5)
+
+	assert.Equal(t, 2, len(estimate.Items))
+	assert.Equal(t, "large", estimate.Items[1].TemplateName)
+	assert.InDelta(t, 5.0, estimate.HourlyCost(), 0.0001)
+	assert.InDelta(t, 5.0*HoursPerMonth, estimate.MonthlyCost(), 0.0001)
+}
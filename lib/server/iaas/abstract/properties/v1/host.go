@@ -710,6 +710,51 @@ func (hf *HostFeatures) Replace(p data.Clonable) data.Clonable {
 	return hf
 }
 
+// HostLabels contains the user-defined key/value pairs attached to the host
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type HostLabels struct {
+	Labels map[string]string `json:"labels,omitempty"` // user-defined labels, indexed by key
+}
+
+// NewHostLabels ...
+func NewHostLabels() *HostLabels {
+	return &HostLabels{
+		Labels: map[string]string{},
+	}
+}
+
+// Reset resets the content of the property
+func (hl *HostLabels) Reset() {
+	*hl = HostLabels{
+		Labels: map[string]string{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (hl *HostLabels) Content() data.Clonable {
+	return hl
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (hl *HostLabels) Clone() data.Clonable {
+	return NewHostLabels().Replace(hl)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (hl *HostLabels) Replace(p data.Clonable) data.Clonable {
+	src := p.(*HostLabels)
+	hl.Labels = make(map[string]string, len(src.Labels))
+	for k, v := range src.Labels {
+		hl.Labels[k] = v
+	}
+	return hl
+}
+
 func init() {
 	serialize.PropertyTypeRegistry.Register("abstract.host", hostproperty.DescriptionV1, NewHostDescription())
 	serialize.PropertyTypeRegistry.Register("abstract.host", hostproperty.NetworkV1, NewHostNetwork())
@@ -718,4 +763,5 @@ func init() {
 	serialize.PropertyTypeRegistry.Register("abstract.host", hostproperty.VolumesV1, NewHostVolumes())
 	serialize.PropertyTypeRegistry.Register("abstract.host", hostproperty.MountsV1, NewHostMounts())
 	serialize.PropertyTypeRegistry.Register("abstract.host", hostproperty.FeaturesV1, NewHostFeatures())
+	serialize.PropertyTypeRegistry.Register("abstract.host", hostproperty.LabelsV1, NewHostLabels())
 }
@@ -188,3 +188,22 @@ func TestHostFeatures_Clone(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestHostLabels_Clone(t *testing.T) {
+	ct := NewHostLabels()
+	ct.Labels["env"] = "prod"
+
+	clonedCt, ok := ct.Clone().(*HostLabels)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.Labels["env"] = "dev"
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
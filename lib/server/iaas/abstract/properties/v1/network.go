@@ -111,7 +111,53 @@ func (nh *NetworkHosts) Replace(p data.Clonable) data.Clonable {
 	return nh
 }
 
+// NetworkLabels contains the user-defined key/value pairs attached to the network
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type NetworkLabels struct {
+	Labels map[string]string `json:"labels,omitempty"` // user-defined labels, indexed by key
+}
+
+// NewNetworkLabels ...
+func NewNetworkLabels() *NetworkLabels {
+	return &NetworkLabels{
+		Labels: map[string]string{},
+	}
+}
+
+// Reset resets the content of the property
+func (nl *NetworkLabels) Reset() {
+	*nl = NetworkLabels{
+		Labels: map[string]string{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (nl *NetworkLabels) Content() data.Clonable {
+	return nl
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (nl *NetworkLabels) Clone() data.Clonable {
+	return NewNetworkLabels().Replace(nl)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (nl *NetworkLabels) Replace(p data.Clonable) data.Clonable {
+	src := p.(*NetworkLabels)
+	nl.Labels = make(map[string]string, len(src.Labels))
+	for k, v := range src.Labels {
+		nl.Labels[k] = v
+	}
+	return nl
+}
+
 func init() {
 	serialize.PropertyTypeRegistry.Register("abstract.network", networkproperty.HostsV1, NewNetworkHosts())
 	serialize.PropertyTypeRegistry.Register("abstract.network", networkproperty.DescriptionV1, NewNetworkDescription())
+	serialize.PropertyTypeRegistry.Register("abstract.network", networkproperty.LabelsV1, NewNetworkLabels())
 }
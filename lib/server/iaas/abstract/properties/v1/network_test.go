@@ -44,3 +44,22 @@ func TestNetworkHosts_Clone(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestNetworkLabels_Clone(t *testing.T) {
+	ct := NewNetworkLabels()
+	ct.Labels["env"] = "prod"
+
+	clonedCt, ok := ct.Clone().(*NetworkLabels)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.Labels["env"] = "dev"
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
@@ -106,7 +106,53 @@ func (va *VolumeAttachments) Replace(p data.Clonable) data.Clonable {
 	return va
 }
 
+// VolumeLabels contains the user-defined key/value pairs attached to the volume
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type VolumeLabels struct {
+	Labels map[string]string `json:"labels,omitempty"` // user-defined labels, indexed by key
+}
+
+// NewVolumeLabels ...
+func NewVolumeLabels() *VolumeLabels {
+	return &VolumeLabels{
+		Labels: map[string]string{},
+	}
+}
+
+// Reset resets the content of the property
+func (vl *VolumeLabels) Reset() {
+	*vl = VolumeLabels{
+		Labels: map[string]string{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (vl *VolumeLabels) Content() data.Clonable {
+	return vl
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (vl *VolumeLabels) Clone() data.Clonable {
+	return NewVolumeLabels().Replace(vl)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (vl *VolumeLabels) Replace(p data.Clonable) data.Clonable {
+	src := p.(*VolumeLabels)
+	vl.Labels = make(map[string]string, len(src.Labels))
+	for k, v := range src.Labels {
+		vl.Labels[k] = v
+	}
+	return vl
+}
+
 func init() {
 	serialize.PropertyTypeRegistry.Register("abstract.volume", volumeproperty.DescriptionV1, NewVolumeDescription())
 	serialize.PropertyTypeRegistry.Register("abstract.volume", volumeproperty.AttachedV1, NewVolumeAttachments())
+	serialize.PropertyTypeRegistry.Register("abstract.volume", volumeproperty.LabelsV1, NewVolumeLabels())
 }
@@ -44,3 +44,22 @@ func TestVolumeAttachments_Clone(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestVolumeLabels_Clone(t *testing.T) {
+	ct := NewVolumeLabels()
+	ct.Labels["env"] = "prod"
+
+	clonedCt, ok := ct.Clone().(*VolumeLabels)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.Labels["env"] = "dev"
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Unlimited is the value of a quota without limit (or whose limit is not reported by the provider)
+const Unlimited = -1
+
+// Quotas contains the maximum amount of resources usable in a tenant
+type Quotas struct {
+	Cores      int     `json:"cores"`
+	RAMSize    float32 `json:"ram_size"` // in GB
+	Instances  int     `json:"instances"`
+	Volumes    int     `json:"volumes"`
+	VolumeSize int     `json:"volume_size"` // in GB
+	Networks   int     `json:"networks"`
+	PublicIPs  int     `json:"public_ips"`
+}
+
+// NewQuotas returns quotas without any limit
+func NewQuotas() *Quotas {
+	return &Quotas{
+		Cores:      Unlimited,
+		RAMSize:    Unlimited,
+		Instances:  Unlimited,
+		Volumes:    Unlimited,
+		VolumeSize: Unlimited,
+		Networks:   Unlimited,
+		PublicIPs:  Unlimited,
+	}
+}
+
+// Usage contains the amount of resources used in a tenant, or needed to honor a request
+type Usage struct {
+	Cores      int     `json:"cores"`
+	RAMSize    float32 `json:"ram_size"` // in GB
+	Instances  int     `json:"instances"`
+	Volumes    int     `json:"volumes"`
+	VolumeSize int     `json:"volume_size"` // in GB
+	Networks   int     `json:"networks"`
+	PublicIPs  int     `json:"public_ips"`
+}
+
+// Add adds the resources of other to the usage
+func (u *Usage) Add(other Usage) {
+	u.Cores += other.Cores
+	u.RAMSize += other.RAMSize
+	u.Instances += other.Instances
+	u.Volumes += other.Volumes
+	u.VolumeSize += other.VolumeSize
+	u.Networks += other.Networks
+	u.PublicIPs += other.PublicIPs
+}
+
+// Check verifies the resources needed can be added to the resources used without exceeding the quotas
+// Returns fail.ErrOverload listing the exceeded quotas if not
+func (q *Quotas) Check(usage *Usage, needed Usage) error {
+	if q == nil {
+		return fail.InvalidInstanceError()
+	}
+	if usage == nil {
+		return fail.InvalidParameterError("usage", "cannot be nil")
+	}
+
+	var exceeded []string
+	checkInt := func(what string, quota, used, wanted int) {
+		if quota != Unlimited && wanted > 0 && used+wanted > quota {
+			exceeded = append(exceeded, fmt.Sprintf("%s (needs %d, %d left)", what, wanted, quota-used))
+		}
+	}
+	checkInt("cores", q.Cores, usage.Cores, needed.Cores)
+	if q.RAMSize != Unlimited && needed.RAMSize > 0 && usage.RAMSize+needed.RAMSize > q.RAMSize {
+		exceeded = append(exceeded, fmt.Sprintf("RAM (needs %.1f GB, %.1f GB left)", needed.RAMSize, q.RAMSize-usage.RAMSize))
+	}
+	checkInt("instances", q.Instances, usage.Instances, needed.Instances)
+	checkInt("volumes", q.Volumes, usage.Volumes, needed.Volumes)
+	checkInt("volume size in GB", q.VolumeSize, usage.VolumeSize, needed.VolumeSize)
+	checkInt("networks", q.Networks, usage.Networks, needed.Networks)
+	checkInt("public IPs", q.PublicIPs, usage.PublicIPs, needed.PublicIPs)
+
+	if len(exceeded) > 0 {
+		return fail.OverloadError(fmt.Sprintf("not enough resources left in tenant: %s", strings.Join(exceeded, ", ")))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func TestQuotas_Check(t *testing.T) {
+	quotas := NewQuotas()
+	quotas.Cores = 10
+	quotas.Instances = 3
+	usage := &Usage{Cores: 8, Instances: 1}
+
+	assert.Nil(t, quotas.Check(usage, Usage{Cores: 2, Instances: 1, Volumes: 100}))
+
+	err := quotas.Check(usage, Usage{Cores: 4, Instances: 1})
+	assert.NotNil(t, err)
+	assert.IsType(t, fail.ErrOverload{}, err)
+	assert.Contains(t, err.Error(), "cores")
+	assert.NotContains(t, err.Error(), "instances")
+
+	assert.Nil(t, NewQuotas().Check(usage, Usage{Cores: 1000, RAMSize: 1000}))
+}
+
+func TestUsage_Add(t *testing.T) {
+	usage := Usage{Cores: 2, RAMSize: 4}
+	usage.Add(Usage{Cores: 2, RAMSize: 3.5, Instances: 1})
+	assert.Equal(t, Usage{Cores: 4, RAMSize: 7.5, Instances: 1}, usage)
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/securitygroupruledirection"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// SecurityGroupRule represents a rule of a security group
+type SecurityGroupRule struct {
+	ID          string                          `json:"id,omitempty"`          // ID of the rule (from provider)
+	Description string                          `json:"description,omitempty"` // Description of the rule
+	Direction   securitygroupruledirection.Enum `json:"direction"`             // Direction tells if the rule applies to ingress or egress traffic
+	EtherType   ipversion.Enum                  `json:"ether_type,omitempty"`  // EtherType is IPv4 or IPv6
+	Protocol    string                          `json:"protocol,omitempty"`    // Protocol is tcp, udp, icmp or empty for any
+	PortFrom    int                             `json:"port_from,omitempty"`   // PortFrom is the first port of the range (0 for any)
+	PortTo      int                             `json:"port_to,omitempty"`     // PortTo is the last port of the range (0 for PortFrom)
+	CIDR        string                          `json:"cidr,omitempty"`        // CIDR is the remote network concerned by the rule
+}
+
+// NewSecurityGroupRule ...
+func NewSecurityGroupRule() *SecurityGroupRule {
+	return &SecurityGroupRule{}
+}
+
+// OK ...
+func (sgr *SecurityGroupRule) OK() bool {
+	if sgr == nil {
+		return false
+	}
+	result := sgr.Direction != securitygroupruledirection.UNKNOWN
+	if !result {
+		logrus.Debug("Security group rule without direction")
+	}
+	if sgr.PortTo != 0 && sgr.PortTo < sgr.PortFrom {
+		logrus.Debug("Security group rule with invalid port range")
+		result = false
+	}
+	return result
+}
+
+// SecurityGroupRequest represents the requirements to create a security group
+type SecurityGroupRequest struct {
+	// Name is the name of the security group
+	Name string
+	// Description is a description of the security group
+	Description string
+	// Rules contains the rules to add at creation
+	Rules []SecurityGroupRule
+}
+
+// SecurityGroup represents a security group
+type SecurityGroup struct {
+	ID          string              `json:"id,omitempty"`          // ID of the security group (from provider)
+	Name        string              `json:"name,omitempty"`        // Name of the security group
+	Description string              `json:"description,omitempty"` // Description of the security group
+	Rules       []SecurityGroupRule `json:"rules,omitempty"`       // Rules of the security group
+}
+
+// NewSecurityGroup ...
+func NewSecurityGroup() *SecurityGroup {
+	return &SecurityGroup{}
+}
+
+// OK ...
+func (sg *SecurityGroup) OK() bool {
+	if sg == nil {
+		return false
+	}
+	result := sg.ID != ""
+	if !result {
+		logrus.Debug("Security group without ID")
+	}
+	if sg.Name == "" {
+		logrus.Debug("Security group without name")
+		result = false
+	}
+	return result
+}
+
+// Serialize serializes SecurityGroup instance into bytes (output json code)
+func (sg *SecurityGroup) Serialize() ([]byte, error) {
+	return serialize.ToJSON(sg)
+}
+
+// Deserialize reads json code and reinstantiates a SecurityGroup
+func (sg *SecurityGroup) Deserialize(buf []byte) error {
+	return serialize.FromJSON(buf, sg)
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (sg *SecurityGroup) Content() data.Clonable {
+	return sg
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (sg *SecurityGroup) Clone() data.Clonable {
+	return NewSecurityGroup().Replace(sg)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (sg *SecurityGroup) Replace(p data.Clonable) data.Clonable {
+	if p != nil {
+		src := p.(*SecurityGroup)
+		*sg = *src
+		sg.Rules = make([]SecurityGroupRule, len(src.Rules))
+		copy(sg.Rules, src.Rules)
+	}
+	return sg
+}
@@ -17,6 +17,8 @@
 package abstract
 
 import (
+	"time"
+
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumespeed"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumestate"
 	"github.com/CS-SI/SafeScale/lib/utils/serialize"
@@ -29,6 +31,10 @@ type VolumeRequest struct {
 	Speed  volumespeed.Enum `json:"speed,omitempty"`
 	InLVM  bool             `json:"lvm,omitempty"`
 	SizeVU int              `json:"sizevu,omitempty"`
+	// SnapshotID is the ID of the snapshot to create the volume from (optional)
+	SnapshotID string `json:"snapshot_id,omitempty"`
+	// Labels contains the user-defined key/value pairs to attach to the volume
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // Volume represents a block volume
@@ -90,6 +96,50 @@ func (v *Volume) Deserialize(buf []byte) error {
 	return nil
 }
 
+// VolumeSnapshotRequest represents a volume snapshot request
+type VolumeSnapshotRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	VolumeID    string `json:"volume_id,omitempty"`
+	// Force allows to snapshot a volume attached to a host
+	Force bool `json:"force,omitempty"`
+}
+
+// VolumeSnapshot represents a snapshot of a block volume
+type VolumeSnapshot struct {
+	ID          string           `json:"id,omitempty"`
+	Name        string           `json:"name,omitempty"`
+	Description string           `json:"description,omitempty"`
+	VolumeID    string           `json:"volume_id,omitempty"`
+	Size        int              `json:"size,omitempty"`
+	State       volumestate.Enum `json:"state,omitempty"`
+	CreatedAt   time.Time        `json:"created_at,omitempty"`
+}
+
+// NewVolumeSnapshot ...
+func NewVolumeSnapshot() *VolumeSnapshot {
+	return &VolumeSnapshot{}
+}
+
+// OK ...
+func (vs *VolumeSnapshot) OK() bool {
+	result := true
+	result = result && vs.ID != ""
+	result = result && vs.Name != ""
+	result = result && vs.VolumeID != ""
+	return result
+}
+
+// Serialize serializes VolumeSnapshot instance into bytes (output json code)
+func (vs *VolumeSnapshot) Serialize() ([]byte, error) {
+	return serialize.ToJSON(vs)
+}
+
+// Deserialize reads json code and restores a VolumeSnapshot
+func (vs *VolumeSnapshot) Deserialize(buf []byte) error {
+	return serialize.FromJSON(buf, vs)
+}
+
 // VolumeAttachmentRequest represents a volume attachment request
 type VolumeAttachmentRequest struct {
 	Name     string `json:"name,omitempty"`
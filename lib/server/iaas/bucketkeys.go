@@ -0,0 +1,236 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iaas
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// bucketKeysFolder is the folder of the metadata bucket containing the encryption settings of the buckets
+const bucketKeysFolder = "buckets"
+
+// bucketKeyRecord describes the encryption of a bucket, as stored in metadata
+type bucketKeyRecord struct {
+	Bucket string `json:"bucket"`
+	KeyID  string `json:"key_id"`
+	// Salt is the random salt used to derive the key
+	Salt []byte `json:"salt"`
+	// Key is set only when supplied by the user; otherwise the key is derived from the secret of the tenant
+	Key []byte `json:"key,omitempty"`
+}
+
+// bucketKeyring keeps the keys of the encrypted buckets of a tenant
+type bucketKeyring struct {
+	// secret is the value of 'CryptKey' in section 'objectstorage' of the tenant
+	secret string
+
+	mu   sync.Mutex
+	keys map[string]*crypt.Key // a nil value marks a bucket known as not encrypted
+}
+
+func newBucketKeyring(secret string) *bucketKeyring {
+	return &bucketKeyring{secret: secret, keys: map[string]*crypt.Key{}}
+}
+
+// EnableBucketEncryption makes the objects written in the bucket encrypted client-side, and returns the ID of the key
+// If passphrase is empty, the key is derived from the setting 'CryptKey' of the section 'objectstorage' of the tenant;
+// otherwise the key is derived from passphrase and kept in metadata.
+func (svc *service) EnableBucketEncryption(bucketName, passphrase string) (string, error) {
+	if svc == nil {
+		return "", fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return "", fail.InvalidParameterError("bucketName", "cannot be empty string")
+	}
+	if svc.metadataStore == nil || svc.bucketKeys == nil {
+		return "", fail.NotAvailableError("bucket encryption needs a metadata storage")
+	}
+
+	current, err := svc.readBucketKeyRecord(bucketName)
+	if err != nil {
+		return "", err
+	}
+	record := bucketKeyRecord{Bucket: bucketName}
+	if current != nil {
+		// derives with the salt of the bucket to tell if the key requested is the one already used
+		record.Salt = current.Salt
+	} else {
+		record.Salt, err = objectstorage.NewBucketKeySalt()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var key *crypt.Key
+	if passphrase != "" {
+		key, err = objectstorage.DeriveBucketKey(passphrase, record.Salt)
+		if err != nil {
+			return "", err
+		}
+		record.Key = key[:]
+	} else {
+		if svc.bucketKeys.secret == "" {
+			return "", fail.InvalidRequestError(
+				"no passphrase given and no 'CryptKey' in section 'objectstorage' of the tenant to derive the key of the bucket",
+			)
+		}
+		key, err = objectstorage.DeriveBucketKey(svc.bucketKeys.secret, record.Salt)
+		if err != nil {
+			return "", err
+		}
+	}
+	record.KeyID = objectstorage.BuildKeyID(key)
+
+	if current != nil {
+		if current.KeyID == record.KeyID {
+			return record.KeyID, nil
+		}
+		return "", fail.DuplicateError(fmt.Sprintf("bucket '%s' is already encrypted with another key", bucketName))
+	}
+
+	if record.Key != nil && svc.metadataKeys == nil {
+		log.Warnf(
+			"the key of bucket '%s' is stored unencrypted in metadata; consider setting 'CryptKey' in section 'metadata' of the tenant",
+			bucketName,
+		)
+	}
+
+	err = svc.writeBucketKeyRecord(record)
+	if err != nil {
+		return "", err
+	}
+
+	svc.bucketKeys.mu.Lock()
+	svc.bucketKeys.keys[bucketName] = key
+	svc.bucketKeys.mu.Unlock()
+	return record.KeyID, nil
+}
+
+// GetBucketKey returns the key encrypting the objects of the bucket, or nil if the bucket is not encrypted
+func (svc *service) GetBucketKey(bucketName string) (*crypt.Key, error) {
+	if svc == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if svc.metadataStore == nil || svc.bucketKeys == nil {
+		return nil, nil
+	}
+
+	svc.bucketKeys.mu.Lock()
+	defer svc.bucketKeys.mu.Unlock()
+
+	if key, ok := svc.bucketKeys.keys[bucketName]; ok {
+		return key, nil
+	}
+
+	record, err := svc.readBucketKeyRecord(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	var key *crypt.Key
+	if record != nil {
+		if len(record.Key) > 0 {
+			key = &crypt.Key{}
+			copy(key[:], record.Key)
+		} else {
+			if svc.bucketKeys.secret == "" {
+				return nil, fail.NotAvailableError(
+					fmt.Sprintf(
+						"bucket '%s' is encrypted with a key derived from 'CryptKey' of section 'objectstorage', which is missing", bucketName,
+					),
+				)
+			}
+			key, err = objectstorage.DeriveBucketKey(svc.bucketKeys.secret, record.Salt)
+			if err != nil {
+				return nil, fail.Wrap(err, fmt.Sprintf("failed to derive the key of bucket '%s'", bucketName))
+			}
+		}
+		if keyID := objectstorage.BuildKeyID(key); keyID != record.KeyID {
+			return nil, fail.InconsistentError(
+				fmt.Sprintf("the key of bucket '%s' does not match the key '%s' recorded; has 'CryptKey' changed?", bucketName, record.KeyID),
+			)
+		}
+	}
+	svc.bucketKeys.keys[bucketName] = key
+	return key, nil
+}
+
+// ForgetBucketKey removes the encryption settings of a bucket, once the bucket is deleted
+func (svc *service) ForgetBucketKey(bucketName string) error {
+	if svc == nil {
+		return fail.InvalidInstanceError()
+	}
+	if svc.metadataStore == nil || svc.bucketKeys == nil {
+		return nil
+	}
+
+	svc.bucketKeys.mu.Lock()
+	defer svc.bucketKeys.mu.Unlock()
+
+	delete(svc.bucketKeys.keys, bucketName)
+	record, err := svc.readBucketKeyRecord(bucketName)
+	if err != nil || record == nil {
+		return err
+	}
+	return svc.metadataStore.Delete(bucketKeysFolder + "/" + record.Bucket)
+}
+
+// readBucketKeyRecord reads the encryption settings of a bucket from metadata; returns nil, nil if there is none
+func (svc *service) readBucketKeyRecord(bucketName string) (*bucketKeyRecord, error) {
+	data, err := svc.metadataStore.Read(bucketKeysFolder + "/" + bucketName)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if svc.metadataKeys != nil {
+		data, err = svc.metadataKeys.Open(data)
+		if err != nil {
+			return nil, fail.Wrap(err, fmt.Sprintf("failed to decrypt encryption settings of bucket '%s'", bucketName))
+		}
+	}
+	record := &bucketKeyRecord{}
+	err = json.Unmarshal(data, record)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("invalid encryption settings of bucket '%s'", bucketName))
+	}
+	return record, nil
+}
+
+// writeBucketKeyRecord writes the encryption settings of a bucket in metadata
+func (svc *service) writeBucketKeyRecord(record bucketKeyRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if svc.metadataKeys != nil {
+		data, err = svc.metadataKeys.Seal(data)
+		if err != nil {
+			return err
+		}
+	}
+	return svc.metadataStore.Write(bucketKeysFolder+"/"+record.Bucket, data)
+}
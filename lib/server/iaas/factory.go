@@ -22,12 +22,15 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/metadatastore"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/pricing"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/providers/api"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
@@ -68,7 +71,8 @@ func GetTenants() ([]interface{}, error) {
 }
 
 // UseService return the service referenced by the given name.
-// If necessary, this function try to load service from configuration file
+// If necessary, this function try to load service from configuration file; the service is built once per tenant and
+// reused by the next calls, as long as the configuration of the tenant doesn't change
 func UseService(tenantName string) (newService Service, err error) {
 	defer fail.OnPanic(&err)()
 
@@ -113,150 +117,240 @@ func UseService(tenantName string) (newService Service, err error) {
 			continue
 		}
 
-		// tenantIdentity, found := tenant["identity"].(map[string]interface{})
-		// if !found {
-		// 	logrus.Debugf("No section 'identity' found in tenant '%s', continuing.", name)
-		// }
-		// tenantCompute, found := tenant["compute"].(map[string]interface{})
-		// if !found {
-		// 	logrus.Debugf("No section 'compute' found in tenant '%s', continuing.", name)
-		// }
-		// tenantNetwork, found := tenant["network"].(map[string]interface{})
-		// if !found {
-		// 	logrus.Debugf("No section 'network' found in tenant '%s', continuing.", name)
-		// }
-		_, found = tenant["identity"].(map[string]interface{})
+		return usedServices.use(
+			tenantName, tenant, func() (Service, error) {
+				return buildService(tenantName, provider, svc, tenant)
+			},
+		)
+	}
+
+	if !tenantInCfg {
+		return nil, fail.Errorf(fmt.Sprintf("tenant '%s' not found in configuration", tenantName), nil)
+	}
+	return nil, abstract.ResourceNotFoundError("provider builder for", svcProvider)
+}
+
+// NewServiceFromTenant builds the service described by the tenant parameters passed as parameter, without
+// reading the configuration file (the tenant content follows the format of a tenant in tenants.toml)
+func NewServiceFromTenant(tenant map[string]interface{}) (newService Service, err error) {
+	defer fail.OnPanic(&err)()
+
+	name, found := tenant["name"].(string)
+	if !found {
+		return nil, fail.InvalidParameterError("tenant['name']", "is missing")
+	}
+	provider, found := tenant["provider"].(string)
+	if !found {
+		provider, found = tenant["client"].(string)
 		if !found {
-			logrus.Debugf("No section 'identity' found in tenant '%s', continuing.", name)
+			return nil, fail.InvalidParameterError("tenant['provider']", "is missing")
 		}
-		_, found = tenant["compute"].(map[string]interface{})
-		if !found {
-			logrus.Debugf("No section 'compute' found in tenant '%s', continuing.", name)
+	}
+	svc, found := allProviders[provider]
+	if !found {
+		return nil, abstract.ResourceNotFoundError("provider builder for", provider)
+	}
+	return buildService(name, provider, svc, tenant)
+}
+
+// NewServiceFromProvider builds the service described by the tenant parameters passed as parameter, using provider
+// instead of the one registered for the tenant client (allows to use wrappers like api.ReplayingProvider)
+func NewServiceFromProvider(provider api.Provider, tenant map[string]interface{}) (newService Service, err error) {
+	defer fail.OnPanic(&err)()
+
+	if provider == nil {
+		return nil, fail.InvalidParameterError("provider", "cannot be nil")
+	}
+	name, found := tenant["name"].(string)
+	if !found {
+		return nil, fail.InvalidParameterError("tenant['name']", "is missing")
+	}
+	return buildService(name, provider.GetName(), &service{Provider: provider}, tenant)
+}
+
+// buildService initializes the provider, the object storage and the metadata storage of the tenant
+func buildService(tenantName, provider string, svc Service, tenant map[string]interface{}) (Service, error) {
+	var found bool
+
+	// tenantIdentity, found := tenant["identity"].(map[string]interface{})
+	// if !found {
+	// 	logrus.Debugf("No section 'identity' found in tenant '%s', continuing.", tenantName)
+	// }
+	// tenantCompute, found := tenant["compute"].(map[string]interface{})
+	// if !found {
+	// 	logrus.Debugf("No section 'compute' found in tenant '%s', continuing.", tenantName)
+	// }
+	// tenantNetwork, found := tenant["network"].(map[string]interface{})
+	// if !found {
+	// 	logrus.Debugf("No section 'network' found in tenant '%s', continuing.", tenantName)
+	// }
+	_, found = tenant["identity"].(map[string]interface{})
+	if !found {
+		logrus.Debugf("No section 'identity' found in tenant '%s', continuing.", tenantName)
+	}
+	_, found = tenant["compute"].(map[string]interface{})
+	if !found {
+		logrus.Debugf("No section 'compute' found in tenant '%s', continuing.", tenantName)
+	}
+	_, found = tenant["network"].(map[string]interface{})
+	if !found {
+		logrus.Debugf("No section 'network' found in tenant '%s', continuing.", tenantName)
+	}
+	// tenantClient := map[string]interface{}{
+	// 	"identity": tenantIdentity,
+	// 	"compute":  tenantCompute,
+	// 	"network":  tenantNetwork,
+	// }
+	_, tenantObjectStorageFound := tenant["objectstorage"]
+	_, tenantMetadataFound := tenant["metadata"]
+
+	// Initializes Provider
+	providerInstance, err := svc.Build( /*tenantClient*/ tenant)
+	if err != nil {
+		return nil, fail.Errorf(
+			fmt.Sprintf(
+				"error creating tenant '%s' on provider '%s': %s", tenantName, provider, err.Error(),
+			), nil,
+		)
+	}
+	serviceCfg, err := providerInstance.GetConfigurationOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Initializes Object Storage
+	var (
+		objectStorageLocation objectstorage.Location
+		authOpts              providers.Config
+	)
+	authOpts, err = providerInstance.GetAuthenticationOptions()
+	if err != nil {
+		return nil, err
+	}
+	if tenantObjectStorageFound {
+		objectStorageConfig, err := initObjectStorageLocationConfig(authOpts, tenant)
+		if err != nil {
+			return nil, err
 		}
-		_, found = tenant["network"].(map[string]interface{})
-		if !found {
-			logrus.Debugf("No section 'network' found in tenant '%s', continuing.", name)
-		}
-		// tenantClient := map[string]interface{}{
-		// 	"identity": tenantIdentity,
-		// 	"compute":  tenantCompute,
-		// 	"network":  tenantNetwork,
-		// }
-		_, tenantObjectStorageFound := tenant["objectstorage"]
-		_, tenantMetadataFound := tenant["metadata"]
-
-		// Initializes Provider
-		providerInstance, err := svc.Build( /*tenantClient*/ tenant)
+		objectStorageLocation, err = objectstorage.NewLocation(objectStorageConfig)
 		if err != nil {
+			return nil, fail.Errorf(
+				fmt.Sprintf("error connecting to Object Storage Location: %s", err.Error()), nil,
+			)
+		}
+	} else {
+		logrus.Warnf("missing section 'objectstorage' in configuration file for tenant '%s'", tenantName)
+	}
+
+	// Initializes Metadata Storage (may be different than the Object Storage)
+	var (
+		metadataBucket  objectstorage.Bucket
+		metadataStore   metadatastore.Store
+		metadataKeyring *crypt.Keyring
+	)
+	metadataConfig, _ := tenant["metadata"].(map[string]interface{})
+	metadataBackend, _ := metadataConfig["Backend"].(string)
+	if metadataBackend == "" || strings.ToLower(metadataBackend) == metadatastore.BucketBackend {
+		if !tenantMetadataFound && !tenantObjectStorageFound {
 			return nil, fail.Errorf(
 				fmt.Sprintf(
-					"error creating tenant '%s' on provider '%s': %s", tenantName, provider, err.Error(),
+					"failed to build service: 'metadata' section (and 'objectstorage' as fallback) is missing in configuration file for tenant '%s'",
+					tenantName,
 				), nil,
 			)
 		}
-		serviceCfg, err := providerInstance.GetConfigurationOptions()
+
+		// FIXME: This requires tuning too
+		metadataLocationConfig, err := initMetadataLocationConfig(authOpts, tenant)
 		if err != nil {
 			return nil, err
 		}
-
-		// Initializes Object Storage
-		var (
-			objectStorageLocation objectstorage.Location
-			authOpts              providers.Config
-		)
-		if tenantObjectStorageFound {
-			authOpts, err = providerInstance.GetAuthenticationOptions()
+		metadataLocation, err := objectstorage.NewLocation(metadataLocationConfig)
+		if err != nil {
+			return nil, fail.Errorf(
+				fmt.Sprintf(
+					"error connecting to Object Storage Location to store metadata: %s", err.Error(),
+				), nil,
+			)
+		}
+		bucketName, err := getMetadataBucketName(serviceCfg)
+		if err != nil {
+			return nil, err
+		}
+		found, err = metadataLocation.FindBucket(bucketName)
+		if err != nil {
+			return nil, fail.Errorf(fmt.Sprintf("error accessing metadata location: %s", err.Error()), nil)
+		}
+		if found {
+			metadataBucket, err = metadataLocation.GetBucket(bucketName)
 			if err != nil {
 				return nil, err
 			}
-			objectStorageConfig, err := initObjectStorageLocationConfig(authOpts, tenant)
+		} else {
+			metadataBucket, err = metadataLocation.CreateBucket(bucketName)
 			if err != nil {
 				return nil, err
 			}
-			objectStorageLocation, err = objectstorage.NewLocation(objectStorageConfig)
-			if err != nil {
-				return nil, fail.Errorf(
-					fmt.Sprintf("error connecting to Object Storage Location: %s", err.Error()), nil,
-				)
-			}
-		} else {
-			logrus.Warnf("missing section 'objectstorage' in configuration file for tenant '%s'", tenantName)
 		}
-
-		// Initializes Metadata Object Storage (may be different than the Object Storage)
-		var (
-			metadataBucket   objectstorage.Bucket
-			metadataCryptKey *crypt.Key
-		)
-		if tenantMetadataFound || tenantObjectStorageFound {
-			// FIXME: This requires tuning too
-			metadataLocationConfig, err := initMetadataLocationConfig(authOpts, tenant)
+		metadataStore, err = metadatastore.NewBucketStore(metadataBucket)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		bucketName, err := getMetadataBucketName(serviceCfg)
+		if err != nil {
+			return nil, err
+		}
+		metadataStore, err = metadatastore.New(initMetadataStoreConfig(metadataBackend, bucketName, metadataConfig))
+		if err != nil {
+			return nil, fail.Errorf(fmt.Sprintf("error connecting to metadata backend '%s': %s", metadataBackend, err.Error()), nil)
+		}
+	}
+	if key, ok := metadataConfig["CryptKey"]; ok {
+		ek, err := crypt.NewEncryptionKey([]byte(key.(string)))
+		if err != nil {
+			return nil, err
+		}
+		// The previous key is kept to read the metadata not yet re-encrypted by 'safescale tenant metadata rekey'
+		var previous *crypt.Key
+		if key, ok := metadataConfig["PreviousCryptKey"].(string); ok && key != "" {
+			previous, err = crypt.NewEncryptionKey([]byte(key))
 			if err != nil {
 				return nil, err
 			}
-			metadataLocation, err := objectstorage.NewLocation(metadataLocationConfig)
-			if err != nil {
-				return nil, fail.Errorf(
-					fmt.Sprintf(
-						"error connecting to Object Storage Location to store metadata: %s", err.Error(),
-					), nil,
-				)
-			}
-			anon, found := serviceCfg.Get("MetadataBucketName")
-			if !found {
-				return nil, fail.Errorf(fmt.Sprintf("missing configuration option 'MetadataBucketName'"), nil)
-			}
-			bucketName, ok := anon.(string)
-			if !ok {
-				return nil, fail.Errorf(fmt.Sprintf("invalid bucket name, it's not a string"), nil)
-			}
-			found, err = metadataLocation.FindBucket(bucketName)
-			if err != nil {
-				return nil, fail.Errorf(fmt.Sprintf("error accessing metadata location: %s", err.Error()), nil)
-			}
-			if found {
-				metadataBucket, err = metadataLocation.GetBucket(bucketName)
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				metadataBucket, err = metadataLocation.CreateBucket(bucketName)
-				if err != nil {
-					return nil, err
-				}
-			}
-			if metadataConfig, ok := tenant["metadata"].(map[string]interface{}); ok {
-				if key, ok := metadataConfig["CryptKey"]; ok {
-					ek, err := crypt.NewEncryptionKey([]byte(key.(string)))
-					if err != nil {
-						return nil, err
-					}
-					metadataCryptKey = ek
-				}
-			}
-		} else {
-			return nil, fail.Errorf(
-				fmt.Sprintf(
-					"failed to build service: 'metadata' section (and 'objectstorage' as fallback) is missing in configuration file for tenant '%s'",
-					tenantName,
-				), nil,
-			)
 		}
+		metadataKeyring = crypt.NewKeyring(ek, previous)
+	}
 
-		// Service is ready
-		newS := &service{
-			Provider:       providerInstance,
-			Location:       objectStorageLocation,
-			metadataBucket: metadataBucket,
-			metadataKey:    metadataCryptKey,
-		}
-		return newS, validateRegexps(newS /*tenantClient*/, tenant)
+	// Initializes the source of prices
+	pricingSource, err := pricing.New(provider, tenant)
+	if err != nil {
+		return nil, err
 	}
 
-	if !tenantInCfg {
-		return nil, fail.Errorf(fmt.Sprintf("tenant '%s' not found in configuration", tenantName), nil)
+	// Service is ready
+	newS := &service{
+		Provider:       providerInstance,
+		Location:       objectStorageLocation,
+		metadataBucket: metadataBucket,
+		metadataStore:  metadataStore,
+		metadataKeys:   metadataKeyring,
+		pricingSource:  pricingSource,
 	}
-	return nil, abstract.ResourceNotFoundError("provider builder for", svcProvider)
+
+	// Objects of buckets having a key are encrypted client-side
+	if objectStorageLocation != nil {
+		secret := ""
+		if objectStorageConfig, ok := tenant["objectstorage"].(map[string]interface{}); ok {
+			secret, _ = objectStorageConfig["CryptKey"].(string)
+		}
+		newS.bucketKeys = newBucketKeyring(secret)
+		newS.Location, err = objectstorage.NewEncryptedLocation(objectStorageLocation, newS.GetBucketKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newS, validateRegexps(newS /*tenantClient*/, tenant)
 }
 
 // validatRegexps validates regexp values from tenants file
@@ -317,6 +411,54 @@ func validateRegexps(svc *service, tenant map[string]interface{}) error {
 	return nil
 }
 
+// getMetadataBucketName returns the name of the metadata bucket from the configuration options of the provider
+func getMetadataBucketName(serviceCfg providers.Config) (string, error) {
+	anon, found := serviceCfg.Get("MetadataBucketName")
+	if !found {
+		return "", fail.Errorf(fmt.Sprintf("missing configuration option 'MetadataBucketName'"), nil)
+	}
+	bucketName, ok := anon.(string)
+	if !ok {
+		return "", fail.Errorf(fmt.Sprintf("invalid bucket name, it's not a string"), nil)
+	}
+	return bucketName, nil
+}
+
+// initMetadataStoreConfig initializes metadatastore.Config struct with the section 'metadata' of the tenant, for
+// the backends not using a bucket
+func initMetadataStoreConfig(backend, bucketName string, metadata map[string]interface{}) metadatastore.Config {
+	config := metadatastore.Config{
+		Backend: strings.ToLower(backend),
+		Name:    bucketName,
+	}
+	switch endpoints := metadata["Endpoints"].(type) {
+	case string:
+		for _, v := range strings.Split(endpoints, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				config.Endpoints = append(config.Endpoints, v)
+			}
+		}
+	case []interface{}:
+		for _, v := range endpoints {
+			if e, ok := v.(string); ok && e != "" {
+				config.Endpoints = append(config.Endpoints, e)
+			}
+		}
+	}
+	config.Username, _ = metadata["Username"].(string)
+	config.Password, _ = metadata["Password"].(string)
+	config.Prefix, _ = metadata["Prefix"].(string)
+	config.Path, _ = metadata["Path"].(string)
+	if value, ok := metadata["DialTimeout"].(string); ok {
+		if d, err := time.ParseDuration(value); err == nil {
+			config.DialTimeout = d
+		} else {
+			logrus.Warnf("invalid value '%s' for field 'DialTimeout' of section 'metadata', using default", value)
+		}
+	}
+	return config
+}
+
 // initObjectStorageLocationConfig initializes objectstorage.Config struct with map
 func initObjectStorageLocationConfig(authOpts providers.Config, tenant map[string]interface{}) (objectstorage.Config, error) {
 	var (
@@ -471,11 +613,11 @@ func initMetadataLocationConfig(authOpts providers.Config, tenant map[string]int
 	if !ok {
 		return config, fail.Errorf(fmt.Sprintf("problem parsing tenants.toml"), nil)
 	}
+	metadata, metadataFound := tenant["metadata"].(map[string]interface{})
 	ostorage, ok := tenant["objectstorage"].(map[string]interface{})
-	if !ok {
+	if !ok && !metadataFound {
 		return config, fail.Errorf(fmt.Sprintf("problem parsing tenants.toml"), nil)
 	}
-	metadata, _ := tenant["metadata"].(map[string]interface{})
 
 	if config.Type, ok = metadata["Type"].(string); !ok {
 		if config.Type, ok = ostorage["Type"].(string); !ok {
@@ -525,7 +667,13 @@ func initMetadataLocationConfig(authOpts providers.Config, tenant map[string]int
 	}
 
 	if config.Endpoint, ok = metadata["Endpoint"].(string); !ok {
-		config.Endpoint, _ = ostorage["Endpoint"].(string)
+		// A local metadata storage cannot use the endpoint of another kind of object storage
+		if config.Type != "local" || ostorage["Type"] == "local" {
+			config.Endpoint, _ = ostorage["Endpoint"].(string)
+		}
+	}
+	if config.Type == "local" && config.Endpoint == "" {
+		return config, fail.Errorf(fmt.Sprintf("missing setting 'Endpoint' in 'metadata' section for a local metadata storage"), nil)
 	}
 
 	if config.User, ok = metadata["AccessKey"].(string); !ok {
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadatastore
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// boltStore is a Store keeping the keys in a bucket of an embedded bbolt database
+// The database file is locked by the process opening it: it cannot be shared by several safescaled.
+type boltStore struct {
+	db     *bolt.DB
+	name   string
+	bucket []byte
+}
+
+// newBoltStore opens (and creates if needed) the bbolt database conf.Path (default $HOME/.safescale/metadata/<conf.Name>.db)
+func newBoltStore(conf Config) (*boltStore, error) {
+	path := conf.Path
+	if path == "" {
+		path = fmt.Sprintf("$HOME/.safescale/metadata/%s.db", conf.Name)
+	}
+	path = utils.AbsPathify(path)
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fail.Errorf(fmt.Sprintf("failed to create folder of metadata database '%s'", path), err)
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: temporal.GetConnectionTimeout()})
+	if err != nil {
+		return nil, fail.Errorf(fmt.Sprintf("failed to open metadata database '%s'", path), err)
+	}
+	s := &boltStore{db: db, name: conf.Name, bucket: []byte(conf.Name)}
+	err = db.Update(
+		func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(s.bucket)
+			return err
+		},
+	)
+	if err != nil {
+		_ = db.Close()
+		return nil, fail.Errorf(fmt.Sprintf("failed to initialize metadata database '%s'", path), err)
+	}
+	return s, nil
+}
+
+// GetBackend returns BoltBackend
+func (s *boltStore) GetBackend() string {
+	return BoltBackend
+}
+
+// GetName returns the name of the metadata bucket
+func (s *boltStore) GetName() string {
+	return s.name
+}
+
+// List returns the keys under path
+func (s *boltStore) List(path string) ([]string, error) {
+	prefix := []byte(folderPrefix(path))
+	var list []string
+	err := s.db.View(
+		func(tx *bolt.Tx) error {
+			c := tx.Bucket(s.bucket).Cursor()
+			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+				list = append(list, string(k))
+			}
+			return nil
+		},
+	)
+	return list, err
+}
+
+// Exists tells if the key exists
+func (s *boltStore) Exists(key string) (bool, error) {
+	var found bool
+	err := s.db.View(
+		func(tx *bolt.Tx) error {
+			found = tx.Bucket(s.bucket).Get([]byte(trimKey(key))) != nil
+			return nil
+		},
+	)
+	return found, err
+}
+
+// Read returns the content of the key
+func (s *boltStore) Read(key string) ([]byte, error) {
+	var data []byte
+	err := s.db.View(
+		func(tx *bolt.Tx) error {
+			value := tx.Bucket(s.bucket).Get([]byte(trimKey(key)))
+			if value == nil {
+				return fail.NotFoundError(fmt.Sprintf("failed to find '%s'", key))
+			}
+			// value is valid only during the transaction
+			data = append([]byte{}, value...)
+			return nil
+		},
+	)
+	return data, err
+}
+
+// Write writes the content of the key
+func (s *boltStore) Write(key string, data []byte) error {
+	return s.db.Update(
+		func(tx *bolt.Tx) error {
+			return tx.Bucket(s.bucket).Put([]byte(trimKey(key)), data)
+		},
+	)
+}
+
+// Update replaces the content of the key by the content returned by callback, in a single transaction
+func (s *boltStore) Update(key string, callback func([]byte) ([]byte, error)) error {
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	return s.db.Update(
+		func(tx *bolt.Tx) error {
+			b := tx.Bucket(s.bucket)
+			var current []byte
+			if value := b.Get([]byte(trimKey(key))); value != nil {
+				current = append([]byte{}, value...)
+			}
+			data, err := callback(current)
+			if err != nil || data == nil {
+				return err
+			}
+			return b.Put([]byte(trimKey(key)), data)
+		},
+	)
+}
+
+// Delete removes the key
+func (s *boltStore) Delete(key string) error {
+	return s.db.Update(
+		func(tx *bolt.Tx) error {
+			return tx.Bucket(s.bucket).Delete([]byte(trimKey(key)))
+		},
+	)
+}
+
+// Close closes the database, releasing its lock
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadatastore
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/graymeta/stow"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// bucketStore is a Store keeping each key as an object of the metadata bucket
+type bucketStore struct {
+	bucket objectstorage.Bucket
+	name   string
+}
+
+// NewBucketStore creates a Store on the metadata bucket
+func NewBucketStore(bucket objectstorage.Bucket) (Store, error) {
+	if bucket == nil {
+		return nil, fail.InvalidParameterError("bucket", "cannot be nil")
+	}
+	name, err := bucket.GetName()
+	if err != nil {
+		return nil, err
+	}
+	return &bucketStore{bucket: bucket, name: name}, nil
+}
+
+// GetBackend returns BucketBackend
+func (s *bucketStore) GetBackend() string {
+	return BucketBackend
+}
+
+// GetName returns the name of the metadata bucket
+func (s *bucketStore) GetName() string {
+	return s.name
+}
+
+// List returns the names of the objects under path
+func (s *bucketStore) List(path string) ([]string, error) {
+	return s.bucket.List(strings.Trim(path, "/"), objectstorage.NoPrefix)
+}
+
+// Exists tells if the object exists, searching it in the listing of its folder
+func (s *bucketStore) Exists(key string) (bool, error) {
+	key = strings.Trim(key, "/")
+	folder := ""
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		folder = key[:i]
+	}
+	list, err := s.bucket.List(folder, objectstorage.NoPrefix)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range list {
+		if item == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Read returns the content of the object
+// Object storages don't report a missing object the same way: on failure, the object is searched in the listing of
+// its folder to tell if it's missing.
+func (s *bucketStore) Read(key string) ([]byte, error) {
+	var buffer bytes.Buffer
+	_, err := s.bucket.ReadObject(strings.Trim(key, "/"), &buffer, 0, 0)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok || err == stow.ErrNotFound {
+			return nil, fail.NotFoundError(fmt.Sprintf("failed to find '%s'", key))
+		}
+		found, xerr := s.Exists(key)
+		if xerr == nil && !found {
+			return nil, fail.NotFoundError(fmt.Sprintf("failed to find '%s'", key))
+		}
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Write writes the content of the object
+func (s *bucketStore) Write(key string, data []byte) error {
+	_, err := s.bucket.WriteObject(strings.Trim(key, "/"), bytes.NewReader(data), int64(len(data)), nil)
+	return err
+}
+
+// Update reads the object then writes the content returned by callback; object storages don't offer conditional
+// writes, so a concurrent write between the read and the write is lost
+func (s *bucketStore) Update(key string, callback func([]byte) ([]byte, error)) error {
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	current, err := s.Read(key)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); !ok {
+			return err
+		}
+		current = nil
+	}
+	data, err := callback(current)
+	if err != nil || data == nil {
+		return err
+	}
+	return s.Write(key, data)
+}
+
+// Delete removes the object
+func (s *bucketStore) Delete(key string) error {
+	err := s.bucket.DeleteObject(strings.Trim(key, "/"))
+	if err != nil {
+		return fmt.Errorf("failed to remove metadata in Object Storage: %s", err.Error())
+	}
+	return nil
+}
+
+// Close does nothing, the bucket having nothing to release
+func (s *bucketStore) Close() error {
+	return nil
+}
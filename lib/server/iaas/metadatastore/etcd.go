@@ -0,0 +1,184 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadatastore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.etcd.io/etcd/clientv3"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// etcdStore is a Store keeping the keys in an etcd cluster, under the prefix '<conf.Prefix>/'
+type etcdStore struct {
+	client *clientv3.Client
+	name   string
+	prefix string
+}
+
+// newEtcdStore connects to the etcd cluster conf.Endpoints
+func newEtcdStore(conf Config) (*etcdStore, error) {
+	if len(conf.Endpoints) == 0 {
+		return nil, fail.InvalidParameterError("conf.Endpoints", "cannot be empty for an etcd metadata store")
+	}
+	dialTimeout := conf.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = temporal.GetConnectionTimeout()
+	}
+	prefix := strings.Trim(conf.Prefix, "/")
+	if prefix == "" {
+		prefix = conf.Name
+	}
+
+	client, err := clientv3.New(
+		clientv3.Config{
+			Endpoints:   conf.Endpoints,
+			Username:    conf.Username,
+			Password:    conf.Password,
+			DialTimeout: dialTimeout,
+		},
+	)
+	if err != nil {
+		return nil, fail.Errorf(fmt.Sprintf("failed to connect to etcd '%s'", strings.Join(conf.Endpoints, ",")), err)
+	}
+	return &etcdStore{client: client, name: conf.Name, prefix: prefix + "/"}, nil
+}
+
+// GetBackend returns EtcdBackend
+func (s *etcdStore) GetBackend() string {
+	return EtcdBackend
+}
+
+// GetName returns the name of the metadata bucket
+func (s *etcdStore) GetName() string {
+	return s.name
+}
+
+// List returns the keys under path
+func (s *etcdStore) List(path string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), temporal.GetContextTimeout())
+	defer cancel()
+
+	resp, err := s.client.Get(ctx, s.prefix+folderPrefix(path), clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+	list := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		list = append(list, strings.TrimPrefix(string(kv.Key), s.prefix))
+	}
+	return list, nil
+}
+
+// Exists tells if the key exists
+func (s *etcdStore) Exists(key string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), temporal.GetContextTimeout())
+	defer cancel()
+
+	resp, err := s.client.Get(ctx, s.prefix+trimKey(key), clientv3.WithCountOnly())
+	if err != nil {
+		return false, err
+	}
+	return resp.Count > 0, nil
+}
+
+// Read returns the content of the key
+func (s *etcdStore) Read(key string) ([]byte, error) {
+	data, _, err := s.get(key)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fail.NotFoundError(fmt.Sprintf("failed to find '%s'", key))
+	}
+	return data, nil
+}
+
+// get returns the content of the key (nil if not found) and its revision (0 if not found)
+func (s *etcdStore) get(key string) ([]byte, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), temporal.GetContextTimeout())
+	defer cancel()
+
+	resp, err := s.client.Get(ctx, s.prefix+trimKey(key))
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, 0, nil
+	}
+	return resp.Kvs[0].Value, resp.Kvs[0].ModRevision, nil
+}
+
+// Write writes the content of the key
+func (s *etcdStore) Write(key string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), temporal.GetContextTimeout())
+	defer cancel()
+
+	_, err := s.client.Put(ctx, s.prefix+trimKey(key), string(data))
+	return err
+}
+
+// Update replaces the content of the key by the content returned by callback; the write succeeds only if the key has
+// not been modified since read, otherwise callback is called again with the new content
+func (s *etcdStore) Update(key string, callback func([]byte) ([]byte, error)) error {
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	for {
+		current, revision, err := s.get(key)
+		if err != nil {
+			return err
+		}
+		data, err := callback(current)
+		if err != nil || data == nil {
+			return err
+		}
+
+		fullKey := s.prefix + trimKey(key)
+		ctx, cancel := context.WithTimeout(context.Background(), temporal.GetContextTimeout())
+		resp, err := s.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(fullKey), "=", revision)).
+			Then(clientv3.OpPut(fullKey, string(data))).
+			Commit()
+		cancel()
+		if err != nil {
+			return err
+		}
+		if resp.Succeeded {
+			return nil
+		}
+	}
+}
+
+// Delete removes the key
+func (s *etcdStore) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), temporal.GetContextTimeout())
+	defer cancel()
+
+	_, err := s.client.Delete(ctx, s.prefix+trimKey(key))
+	return err
+}
+
+// Close closes the connection to etcd
+func (s *etcdStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadatastore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	// BucketBackend stores metadata as objects of the metadata bucket (default)
+	BucketBackend = "bucket"
+	// EtcdBackend stores metadata as keys of an etcd cluster
+	EtcdBackend = "etcd"
+	// BoltBackend stores metadata in an embedded bbolt database file
+	BoltBackend = "bbolt"
+)
+
+//go:generate mockgen -destination=../mocks/mock_metadatastore.go -package=mocks github.com/CS-SI/SafeScale/lib/server/iaas/metadatastore Store
+
+// Store is the storage of the metadata of a tenant
+// Keys are paths of the form '<folder>/.../<name>'; the content is stored as is, encryption is done by the caller.
+type Store interface {
+	// GetBackend returns the kind of backend (BucketBackend, EtcdBackend or BoltBackend)
+	GetBackend() string
+	// GetName returns the name of the store (the name of the metadata bucket)
+	GetName() string
+
+	// List returns the keys under the path
+	List(string) ([]string, error)
+	// Exists tells if the key exists
+	Exists(string) (bool, error)
+	// Read returns the content of the key, or fails with fail.ErrNotFound
+	Read(string) ([]byte, error)
+	// Write writes the content of the key
+	Write(string, []byte) error
+	// Update replaces the content of the key by the content returned by the callback, called with the current content
+	// (nil if the key doesn't exist); nothing is written if the callback returns nil content.
+	// The update is transactional, except with BucketBackend.
+	Update(string, func([]byte) ([]byte, error)) error
+	// Delete removes the key
+	Delete(string) error
+
+	// Close releases the resources used by the store
+	Close() error
+}
+
+// Config contains the settings of a metadata store not using a bucket
+type Config struct {
+	Backend string
+	// Name is the name of the metadata bucket; used as prefix of the keys in etcd and as bucket in a bbolt database
+	Name string
+
+	// Endpoints are the URLs of the members of the etcd cluster
+	Endpoints   []string
+	Username    string
+	Password    string
+	DialTimeout time.Duration // default: temporal.GetConnectionTimeout()
+	// Prefix is prepended to the keys in etcd (default: Name)
+	Prefix string
+
+	// Path is the file of the bbolt database
+	Path string
+}
+
+// New creates the metadata store described by conf; a store on a bucket is created with NewBucketStore
+func New(conf Config) (Store, error) {
+	if conf.Name == "" {
+		return nil, fail.InvalidParameterError("conf.Name", "cannot be empty string")
+	}
+
+	switch strings.ToLower(conf.Backend) {
+	case EtcdBackend:
+		return newEtcdStore(conf)
+	case BoltBackend:
+		return newBoltStore(conf)
+	case "", BucketBackend:
+		return nil, fail.InvalidRequestError("a metadata store on a bucket has to be created with NewBucketStore")
+	default:
+		return nil, fail.InvalidParameterError("conf.Backend", fmt.Sprintf("unknown metadata backend '%s'", conf.Backend))
+	}
+}
+
+// folderPrefix returns the prefix of the keys under path
+func folderPrefix(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return ""
+	}
+	return path + "/"
+}
+
+// trimKey removes the leading and trailing '/' of a key
+func trimKey(key string) string {
+	return strings.Trim(key, "/")
+}
@@ -0,0 +1,101 @@
+package metadatastore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func testStore(t *testing.T, s Store) {
+	_, err := s.Read("hosts/byID/missing")
+	assert.IsType(t, fail.ErrNotFound{}, err)
+	found, err := s.Exists("hosts/byID/missing")
+	require.Nil(t, err)
+	assert.False(t, found)
+
+	require.Nil(t, s.Write("hosts/byID/1", []byte("host1")))
+	require.Nil(t, s.Write("hosts/byName/host1", []byte("host1")))
+	require.Nil(t, s.Write("networks/byID/1", []byte("network1")))
+
+	data, err := s.Read("hosts/byID/1")
+	require.Nil(t, err)
+	assert.Equal(t, "host1", string(data))
+	found, err = s.Exists("/hosts/byName/host1")
+	require.Nil(t, err)
+	assert.True(t, found)
+
+	list, err := s.List("hosts/byID")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"hosts/byID/1"}, list)
+
+	err = s.Update(
+		"hosts/byID/1", func(current []byte) ([]byte, error) {
+			assert.Equal(t, "host1", string(current))
+			return []byte("host1-updated"), nil
+		},
+	)
+	require.Nil(t, err)
+	err = s.Update(
+		"hosts/byID/2", func(current []byte) ([]byte, error) {
+			assert.Nil(t, current)
+			return nil, nil
+		},
+	)
+	require.Nil(t, err)
+	data, err = s.Read("hosts/byID/1")
+	require.Nil(t, err)
+	assert.Equal(t, "host1-updated", string(data))
+	found, err = s.Exists("hosts/byID/2")
+	require.Nil(t, err)
+	assert.False(t, found)
+
+	require.Nil(t, s.Delete("hosts/byID/1"))
+	_, err = s.Read("hosts/byID/1")
+	assert.IsType(t, fail.ErrNotFound{}, err)
+
+	assert.Nil(t, s.Close())
+}
+
+func TestBucketStore(t *testing.T) {
+	folder, err := ioutil.TempDir("", "safescale-metadatastore")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(folder) }()
+	l, err := objectstorage.NewLocation(objectstorage.Config{Type: "local", Endpoint: folder})
+	require.Nil(t, err)
+	b, err := l.CreateBucket("metadata")
+	require.Nil(t, err)
+
+	s, err := NewBucketStore(b)
+	require.Nil(t, err)
+	assert.Equal(t, BucketBackend, s.GetBackend())
+	assert.Equal(t, "metadata", s.GetName())
+	testStore(t, s)
+}
+
+func TestBoltStore(t *testing.T) {
+	folder, err := ioutil.TempDir("", "safescale-metadatastore")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(folder) }()
+
+	s, err := New(Config{Backend: BoltBackend, Name: "metadata", Path: filepath.Join(folder, "metadata.db")})
+	require.Nil(t, err)
+	assert.Equal(t, BoltBackend, s.GetBackend())
+	assert.Equal(t, "metadata", s.GetName())
+	testStore(t, s)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{Backend: "unknown", Name: "metadata"})
+	assert.IsType(t, fail.ErrInvalidParameter{}, err)
+	_, err = New(Config{Backend: EtcdBackend, Name: "metadata"})
+	assert.IsType(t, fail.ErrInvalidParameter{}, err)
+	_, err = New(Config{Backend: BoltBackend})
+	assert.IsType(t, fail.ErrInvalidParameter{}, err)
+}
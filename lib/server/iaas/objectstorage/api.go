@@ -55,6 +55,8 @@ type Location interface {
 
 	// ListObjects lists the objects in a Bucket
 	ListObjects(string, string, string) ([]string, error)
+	// BrowseBucket walks through the objects in a Bucket and executes a callback on each Object found
+	BrowseBucket(string, string, string, func(Object) error) error
 	// GetObject ...
 	GetObject(string, string) (Object, error)
 	// ReadObject ...
@@ -67,6 +69,8 @@ type Location interface {
 	// CopyObject(string, string, string) error
 	// DeleteObject delete an object from a container
 	DeleteObject(string, string) error
+	// PresignObject returns an URL allowing to download (method GET) or upload (method PUT) an object without credentials
+	PresignObject(string, string, string, time.Duration) (string, error)
 	// FilterItemsByMetadata(ContainerName string, key string, pattern string) (map[string][]string, error)
 
 	// // ItemSize ?
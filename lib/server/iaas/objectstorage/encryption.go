@@ -0,0 +1,525 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// KeyIDMetadataKey is the entry of the object metadata containing the ID of the key used to encrypt the content
+const KeyIDMetadataKey = "safescale-key-id"
+
+// BucketKeySaltSize is the size in bytes of the salt used to derive the key of a bucket
+const BucketKeySaltSize = 16
+
+// cost parameters of scrypt deriving the keys of the buckets
+const (
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+)
+
+// BucketKeyFunc returns the key encrypting the objects of a bucket, or nil if the bucket is not encrypted
+type BucketKeyFunc func(bucketName string) (*crypt.Key, error)
+
+// BuildKeyID returns an identifier of the key, which does not reveal the key
+func BuildKeyID(key *crypt.Key) string {
+	return crypt.KeyID(key)
+}
+
+// NewBucketKeySalt returns a random salt to derive the key of a bucket
+func NewBucketKeySalt() ([]byte, error) {
+	salt := make([]byte, BucketKeySaltSize)
+	_, err := io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		return nil, fail.Wrap(err, "cannot read enough random bytes")
+	}
+	return salt, nil
+}
+
+// DeriveBucketKey derives the key of a bucket from a secret and the salt of the bucket, so that each bucket has its own key
+// The salt has to be kept with the settings of the bucket to derive the same key again.
+func DeriveBucketKey(secret string, salt []byte) (*crypt.Key, error) {
+	if secret == "" {
+		return nil, fail.InvalidParameterError("secret", "cannot be empty string")
+	}
+	if len(salt) == 0 {
+		return nil, fail.InvalidParameterError("salt", "cannot be empty")
+	}
+
+	derived, err := scrypt.Key([]byte(secret), salt, scryptN, scryptR, scryptP, len(crypt.Key{}))
+	if err != nil {
+		return nil, err
+	}
+	key := crypt.Key{}
+	copy(key[:], derived)
+	return &key, nil
+}
+
+// getKeyID returns the ID of the encryption key stored in metadata; some object storages change the case of the entries
+func getKeyID(metadata ObjectMetadata) (string, bool) {
+	for k, v := range metadata {
+		if strings.EqualFold(k, KeyIDMetadataKey) {
+			keyID, ok := v.(string)
+			return keyID, ok && keyID != ""
+		}
+	}
+	return "", false
+}
+
+// encryptedBucket is a Bucket encrypting the content of the objects written, and decrypting it when read
+// Objects without key ID in their metadata (written before encryption was enabled) are read as-is.
+type encryptedBucket struct {
+	Bucket
+	key   *crypt.Key
+	keyID string
+}
+
+// NewEncryptedBucket returns a Bucket encrypting objects of 'bucket' with 'key'
+func NewEncryptedBucket(bucket Bucket, key *crypt.Key) (Bucket, error) {
+	if bucket == nil {
+		return nil, fail.InvalidParameterError("bucket", "cannot be nil")
+	}
+	if key == nil {
+		return nil, fail.InvalidParameterError("key", "cannot be nil")
+	}
+	return &encryptedBucket{Bucket: bucket, key: key, keyID: BuildKeyID(key)}, nil
+}
+
+// GetObject returns Object instance of an object in the Bucket, reading and writing decrypted content
+func (b *encryptedBucket) GetObject(objectName string) (Object, error) {
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	o, err := b.Bucket.GetObject(objectName)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedObject{Object: o, bucket: b}, nil
+}
+
+// Browse walks through the objects in the Bucket and executes callback on each Object found, reading and writing
+// decrypted content
+func (b *encryptedBucket) Browse(path, prefix string, callback func(Object) error) error {
+	if b == nil {
+		return fail.InvalidInstanceError()
+	}
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	return b.Bucket.Browse(
+		path, prefix, func(o Object) error {
+			return callback(&encryptedObject{Object: o, bucket: b})
+		},
+	)
+}
+
+// CreateObject creates a new object in the bucket, reading and writing decrypted content
+func (b *encryptedBucket) CreateObject(objectName string) (Object, error) {
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	o, err := b.Bucket.CreateObject(objectName)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedObject{Object: o, bucket: b}, nil
+}
+
+// ReadObject reads the decrypted content of an object, from byte 'from' to byte 'to' (excluded; 0 means up to the end)
+func (b *encryptedBucket) ReadObject(objectName string, target io.Writer, from int64, to int64) (Object, error) {
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if target == nil {
+		return nil, fail.InvalidParameterError("target", "cannot be nil")
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', %d, %d)", objectName, from, to), false, /* Trace.ObjectStorage */
+	).GoingIn().OnExitTrace()()
+
+	var buffer bytes.Buffer
+	o, err := b.Bucket.ReadObject(objectName, &buffer, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	content, err := b.decrypt(o, buffer.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	size := int64(len(content))
+	end := size
+	if to > 0 && to > from {
+		end = to
+	}
+	if from < 0 || from > end || end > size {
+		return nil, fail.InvalidParameterError(
+			"to", fmt.Sprintf("range [%d, %d[ is beyond the size of object '%s' (%d)", from, end, objectName, size),
+		)
+	}
+	_, err = target.Write(content[from:end])
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedObject{Object: o, bucket: b}, nil
+}
+
+// decrypt returns the plain content of the object
+func (b *encryptedBucket) decrypt(o Object, content []byte) ([]byte, error) {
+	metadata, err := o.GetMetadata()
+	if err != nil {
+		return nil, err
+	}
+	keyID, ok := getKeyID(metadata)
+	if !ok {
+		return content, nil
+	}
+	name, _ := o.GetName()
+	if keyID != b.keyID {
+		return nil, fail.ForbiddenError(
+			fmt.Sprintf("object '%s' has been encrypted with key '%s', not with the key '%s' of the bucket", name, keyID, b.keyID),
+		)
+	}
+	plain, err := crypt.Decrypt(content, b.key)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to decrypt object '%s'", name))
+	}
+	return plain, nil
+}
+
+// encrypt returns the encrypted content read from source, and the metadata completed with the key ID
+func (b *encryptedBucket) encrypt(source io.Reader, sourceSize int64, metadata ObjectMetadata) ([]byte, ObjectMetadata, error) {
+	if source == nil {
+		return nil, nil, fail.InvalidParameterError("source", "cannot be nil")
+	}
+
+	var (
+		plain []byte
+		err   error
+	)
+	if sourceSize >= 0 {
+		plain = make([]byte, sourceSize)
+		_, err = io.ReadFull(source, plain)
+	} else {
+		plain, err = ioutil.ReadAll(source)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	cipher, err := crypt.Encrypt(plain, b.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptedMetadata := metadata.Clone()
+	encryptedMetadata[KeyIDMetadataKey] = b.keyID
+	return cipher, encryptedMetadata, nil
+}
+
+// WriteObject encrypts the content of source and writes it into an object
+func (b *encryptedBucket) WriteObject(objectName string, source io.Reader, sourceSize int64, metadata ObjectMetadata) (Object, error) {
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', %d)", objectName, sourceSize), false, /* Trace.ObjectStorage */
+	).GoingIn().OnExitTrace()()
+
+	cipher, encryptedMetadata, err := b.encrypt(source, sourceSize, metadata)
+	if err != nil {
+		return nil, err
+	}
+	o, err := b.Bucket.WriteObject(objectName, bytes.NewReader(cipher), int64(len(cipher)), encryptedMetadata)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedObject{Object: o, bucket: b}, nil
+}
+
+// WriteMultiPartObject writes a lot of data into objects, cut in pieces encrypted separately
+func (b *encryptedBucket) WriteMultiPartObject(
+	objectName string,
+	source io.Reader, sourceSize int64,
+	chunkSize int,
+	metadata ObjectMetadata,
+) (Object, error) {
+
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if chunkSize <= 0 {
+		return nil, fail.InvalidParameterError("chunkSize", "must be greater than 0")
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', <source>, %d, %d, <metadata>)", objectName, sourceSize, chunkSize),
+		false, /* Trace.ObjectStorage */
+	).GoingIn().OnExitTrace()()
+
+	partMetadata := metadata.Clone()
+	partMetadata["Split"] = objectName
+	remaining := sourceSize
+	for chunkIndex := 0; ; chunkIndex++ {
+		if remaining < int64(chunkSize) {
+			chunkSize = int(remaining)
+		}
+		_, err := b.WriteObject(
+			objectName+strconv.Itoa(chunkIndex), io.LimitReader(source, int64(chunkSize)), int64(chunkSize), partMetadata,
+		)
+		if err != nil {
+			return nil, err
+		}
+		remaining -= int64(chunkSize)
+		if remaining <= 0 {
+			break
+		}
+	}
+	return b.GetObject(objectName)
+}
+
+// encryptedObject is an Object of an encryptedBucket
+// Note: GetSize() and GetETag() describe the encrypted content
+type encryptedObject struct {
+	Object
+	bucket *encryptedBucket
+}
+
+// Read reads the decrypted content of the object and writes it in 'target'
+func (o *encryptedObject) Read(target io.Writer, from, to int64) error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	name, err := o.GetName()
+	if err != nil {
+		return err
+	}
+	_, err = o.bucket.ReadObject(name, target, from, to)
+	return err
+}
+
+// Write encrypts the source and writes it to the object
+func (o *encryptedObject) Write(source io.Reader, sourceSize int64) error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	metadata, err := o.GetMetadata()
+	if err != nil {
+		return err
+	}
+	cipher, encryptedMetadata, err := o.bucket.encrypt(source, sourceSize, metadata)
+	if err != nil {
+		return err
+	}
+	err = o.ReplaceMetadata(encryptedMetadata)
+	if err != nil {
+		return err
+	}
+	return o.Object.Write(bytes.NewReader(cipher), int64(len(cipher)))
+}
+
+// WriteMultiPart writes big data to Object, by parts encrypted separately
+func (o *encryptedObject) WriteMultiPart(source io.Reader, sourceSize int64, chunkSize int) error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	name, err := o.GetName()
+	if err != nil {
+		return err
+	}
+	metadata, err := o.GetMetadata()
+	if err != nil {
+		return err
+	}
+	_, err = o.bucket.WriteMultiPartObject(name, source, sourceSize, chunkSize, metadata)
+	return err
+}
+
+// encryptedLocation is a Location encrypting the objects of the buckets having a key
+type encryptedLocation struct {
+	Location
+	keys BucketKeyFunc
+}
+
+// NewEncryptedLocation returns a Location encrypting the objects of the buckets for which 'keys' returns a key
+func NewEncryptedLocation(location Location, keys BucketKeyFunc) (Location, error) {
+	if location == nil {
+		return nil, fail.InvalidParameterError("location", "cannot be nil")
+	}
+	if keys == nil {
+		return nil, fail.InvalidParameterError("keys", "cannot be nil")
+	}
+	return &encryptedLocation{Location: location, keys: keys}, nil
+}
+
+// encryptedBucket returns the bucket 'bucketName' wrapped to be encrypted, or nil, nil if the bucket is not encrypted
+func (l *encryptedLocation) encryptedBucket(bucketName string) (Bucket, error) {
+	key, err := l.keys(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, nil
+	}
+	b, err := l.Location.GetBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptedBucket(b, key)
+}
+
+// GetBucket returns info of the Bucket
+func (l *encryptedLocation) GetBucket(bucketName string) (Bucket, error) {
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	b, err := l.encryptedBucket(bucketName)
+	if err != nil || b != nil {
+		return b, err
+	}
+	return l.Location.GetBucket(bucketName)
+}
+
+// GetObject ...
+func (l *encryptedLocation) GetObject(bucketName string, objectName string) (Object, error) {
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	b, err := l.encryptedBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return l.Location.GetObject(bucketName, objectName)
+	}
+	return b.GetObject(objectName)
+}
+
+// BrowseBucket walks through the objects in a Bucket and applies callback to each object, decrypted if the bucket is
+// encrypted
+func (l *encryptedLocation) BrowseBucket(bucketName string, path, prefix string, callback func(o Object) error) error {
+	if l == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	b, err := l.encryptedBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return l.Location.BrowseBucket(bucketName, path, prefix, callback)
+	}
+	return b.Browse(path, prefix, callback)
+}
+
+// ReadObject reads the content of an object, decrypted if the bucket is encrypted
+func (l *encryptedLocation) ReadObject(bucketName, objectName string, writer io.Writer, from, to int64) error {
+	if l == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	b, err := l.encryptedBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return l.Location.ReadObject(bucketName, objectName, writer, from, to)
+	}
+	_, err = b.ReadObject(objectName, writer, from, to)
+	return err
+}
+
+// WriteObject writes the content of reader in the Object, encrypted if the bucket is encrypted
+func (l *encryptedLocation) WriteObject(
+	bucketName string, objectName string,
+	source io.Reader, size int64,
+	metadata ObjectMetadata,
+) (Object, error) {
+
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	b, err := l.encryptedBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return l.Location.WriteObject(bucketName, objectName, source, size, metadata)
+	}
+	return b.WriteObject(objectName, source, size, metadata)
+}
+
+// WriteMultiPartObject writes data from 'source' to objects, encrypted if the bucket is encrypted
+func (l *encryptedLocation) WriteMultiPartObject(
+	bucketName string, objectName string,
+	source io.Reader, sourceSize int64,
+	chunkSize int,
+	metadata ObjectMetadata,
+) (Object, error) {
+
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	b, err := l.encryptedBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return l.Location.WriteMultiPartObject(bucketName, objectName, source, sourceSize, chunkSize, metadata)
+	}
+	return b.WriteMultiPartObject(objectName, source, sourceSize, chunkSize, metadata)
+}
+
+// PresignObject refuses encrypted buckets, the URL would give access to the encrypted content
+func (l *encryptedLocation) PresignObject(bucketName, objectName, method string, ttl time.Duration) (string, error) {
+	if l == nil {
+		return "", fail.InvalidInstanceError()
+	}
+
+	key, err := l.keys(bucketName)
+	if err != nil {
+		return "", err
+	}
+	if key != nil {
+		return "", fail.NotAvailableError(fmt.Sprintf("bucket '%s' is encrypted client-side, its objects cannot be shared with presigned URLs", bucketName))
+	}
+	return l.Location.PresignObject(bucketName, objectName, method, ttl)
+}
@@ -0,0 +1,118 @@
+package objectstorage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+)
+
+func TestEncryptedLocation(t *testing.T) {
+	plain := newTestLocalLocation(t)
+	_, err := plain.CreateBucket("secret")
+	require.Nil(t, err)
+	_, err = plain.CreateBucket("public")
+	require.Nil(t, err)
+
+	salt, err := NewBucketKeySalt()
+	require.Nil(t, err)
+	key, err := DeriveBucketKey("tenant secret", salt)
+	require.Nil(t, err)
+	keys := func(bucketName string) (*crypt.Key, error) {
+		if bucketName == "secret" {
+			return key, nil
+		}
+		return nil, nil
+	}
+	l, err := NewEncryptedLocation(plain, keys)
+	require.Nil(t, err)
+
+	content := "sensitive dataset"
+	_, err = plain.WriteObject("secret", "legacy", strings.NewReader(content), int64(len(content)), nil)
+	require.Nil(t, err)
+	o, err := l.WriteObject("secret", "data", strings.NewReader(content), int64(len(content)), nil)
+	require.Nil(t, err)
+	metadata, err := o.GetMetadata()
+	require.Nil(t, err)
+	assert.Equal(t, BuildKeyID(key), metadata[KeyIDMetadataKey])
+
+	// Provider sees only the encrypted content
+	var buffer bytes.Buffer
+	require.Nil(t, plain.ReadObject("secret", "data", &buffer, 0, 0))
+	assert.NotContains(t, buffer.String(), content)
+
+	buffer.Reset()
+	require.Nil(t, l.ReadObject("secret", "data", &buffer, 0, 0))
+	assert.Equal(t, content, buffer.String())
+	buffer.Reset()
+	require.Nil(t, l.ReadObject("secret", "data", &buffer, 10, 0))
+	assert.Equal(t, "dataset", buffer.String())
+
+	// Objects written before encryption are still readable
+	buffer.Reset()
+	require.Nil(t, l.ReadObject("secret", "legacy", &buffer, 0, 0))
+	assert.Equal(t, content, buffer.String())
+
+	// Buckets without key are not encrypted
+	_, err = l.WriteObject("public", "data", strings.NewReader(content), int64(len(content)), nil)
+	require.Nil(t, err)
+	buffer.Reset()
+	require.Nil(t, plain.ReadObject("public", "data", &buffer, 0, 0))
+	assert.Equal(t, content, buffer.String())
+
+	// Another key is refused
+	b, err := plain.GetBucket("secret")
+	require.Nil(t, err)
+	otherKey, err := DeriveBucketKey("other secret", salt)
+	require.Nil(t, err)
+	other, err := NewEncryptedBucket(b, otherKey)
+	require.Nil(t, err)
+	buffer.Reset()
+	_, err = other.ReadObject("data", &buffer, 0, 0)
+	assert.NotNil(t, err)
+
+	// Objects browsed are decrypted as well
+	contents := map[string]string{}
+	err = l.BrowseBucket(
+		"secret", "", "", func(o Object) error {
+			name, err := o.GetName()
+			if err != nil {
+				return err
+			}
+			var buffer bytes.Buffer
+			err = o.Read(&buffer, 0, 0)
+			if err != nil {
+				return err
+			}
+			contents[name] = buffer.String()
+			return nil
+		},
+	)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"data": content, "legacy": content}, contents)
+}
+
+func TestDeriveBucketKey(t *testing.T) {
+	salt, err := NewBucketKeySalt()
+	require.Nil(t, err)
+	assert.Len(t, salt, BucketKeySaltSize)
+
+	key, err := DeriveBucketKey("tenant secret", salt)
+	require.Nil(t, err)
+	again, err := DeriveBucketKey("tenant secret", salt)
+	require.Nil(t, err)
+	assert.Equal(t, key, again)
+
+	otherSalt, err := NewBucketKeySalt()
+	require.Nil(t, err)
+	other, err := DeriveBucketKey("tenant secret", otherSalt)
+	require.Nil(t, err)
+	assert.NotEqual(t, key, other)
+
+	_, err = DeriveBucketKey("tenant secret", nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,329 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// localBucket is a Bucket stored in a folder of a localLocation
+type localBucket struct {
+	location *localLocation
+
+	Name string `json:"name,omitempty"`
+}
+
+// path returns the folder of the bucket
+func (b *localBucket) path() string {
+	return filepath.Join(b.location.root, b.Name)
+}
+
+// objectsPath returns the folder containing the content of the objects
+func (b *localBucket) objectsPath() string {
+	return filepath.Join(b.path(), localObjectsFolder)
+}
+
+// metadataPath returns the folder containing the metadata of the objects
+func (b *localBucket) metadataPath() string {
+	return filepath.Join(b.path(), localMetadataFolder)
+}
+
+// localFileName converts an object name to a file name; object names may contain '/', files are stored flat
+func localFileName(objectName string) string {
+	name := url.PathEscape(objectName)
+	// Protects names like '.' and '..', and keeps names beginning with '.' for temporary files
+	if strings.HasPrefix(name, ".") {
+		name = "%2E" + name[1:]
+	}
+	return name
+}
+
+// walk calls 'callback' with the name of each object beginning with 'path' and 'prefix', in lexical order
+func (b *localBucket) walk(path, prefix string, callback func(string) error) error {
+	entries, err := ioutil.ReadDir(b.objectsPath())
+	if err != nil {
+		return err
+	}
+
+	fullPath := buildFullPath(path, prefix)
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		name, err := url.PathUnescape(e.Name())
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(name, fullPath) {
+			err = callback(name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CreateObject ...
+func (b *localBucket) CreateObject(objectName string) (Object, error) {
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", objectName), false /*Trace.Controller*/).GoingIn().OnExitTrace()()
+
+	return newLocalObject(b, objectName)
+}
+
+// GetObject ...
+func (b *localBucket) GetObject(objectName string) (Object, error) {
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", objectName), false /*Trace.Controller*/).GoingIn().OnExitTrace()()
+
+	return newLocalObject(b, objectName)
+}
+
+// List lists the objects of the Bucket beginning with 'path' and 'prefix'
+func (b *localBucket) List(path, prefix string) ([]string, error) {
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", path, prefix), false /*Trace.Controller*/).GoingIn().OnExitTrace()()
+
+	var list []string
+	err := b.walk(
+		path, prefix, func(name string) error {
+			list = append(list, name)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Browse walks through the objects in the Bucket and executes callback on each Object found
+func (b *localBucket) Browse(path, prefix string, callback func(Object) error) error {
+	if b == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", path, prefix), false /*Trace.Controller*/).GoingIn().OnExitTrace()()
+
+	return b.walk(
+		path, prefix, func(name string) error {
+			o, err := newLocalObject(b, name)
+			if err != nil {
+				return err
+			}
+			return callback(o)
+		},
+	)
+}
+
+// Clear removes the objects of the Bucket beginning with 'path' and 'prefix'
+func (b *localBucket) Clear(path, prefix string) error {
+	if b == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", path, prefix), false /* Trace.ObjectStorage */).GoingIn().OnExitTrace()()
+
+	return b.walk(path, prefix, b.DeleteObject)
+}
+
+// DeleteObject deletes an object from a bucket
+func (b *localBucket) DeleteObject(objectName string) error {
+	if b == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", objectName), false /* Trace.ObjectStorage */).GoingIn().OnExitTrace()()
+
+	o, err := newLocalObject(b, objectName)
+	if err != nil {
+		return err
+	}
+	return o.Delete()
+}
+
+// ReadObject reads the content of an object, from byte 'from' to byte 'to' (excluded; 0 means up to the end)
+func (b *localBucket) ReadObject(objectName string, target io.Writer, from int64, to int64) (Object, error) {
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', %d, %d)", objectName, from, to), false, /* Trace.ObjectStorage */
+	).GoingIn().OnExitTrace()()
+
+	o, err := newLocalObject(b, objectName)
+	if err != nil {
+		return nil, err
+	}
+	err = o.Read(target, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// WriteObject ...
+func (b *localBucket) WriteObject(objectName string, source io.Reader, sourceSize int64, metadata ObjectMetadata) (Object, error) {
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', %d)", objectName, sourceSize), false, /* Trace.ObjectStorage */
+	).GoingIn().OnExitTrace()()
+
+	o, err := newLocalObject(b, objectName)
+	if err != nil {
+		return nil, err
+	}
+	err = o.ReplaceMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	err = o.Write(source, sourceSize)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// WriteMultiPartObject ...
+func (b *localBucket) WriteMultiPartObject(
+	objectName string,
+	source io.Reader, sourceSize int64,
+	chunkSize int,
+	metadata ObjectMetadata,
+) (Object, error) {
+
+	if b == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', <source>, %d, %d, <metadata>)", objectName, sourceSize, chunkSize),
+		false, /* Trace.ObjectStorage */
+	).GoingIn().OnExitTrace()()
+
+	o, err := newLocalObject(b, objectName)
+	if err != nil {
+		return nil, err
+	}
+	err = o.ReplaceMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	err = o.WriteMultiPart(source, sourceSize, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// GetName returns the name of the Bucket
+func (b *localBucket) GetName() (string, error) {
+	if b == nil {
+		return "", fail.InvalidInstanceError()
+	}
+
+	return b.Name, nil
+}
+
+// GetCount returns the count of objects in the Bucket
+func (b *localBucket) GetCount(path, prefix string) (int64, error) {
+	if b == nil {
+		return 0, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", path, prefix), false /* Trace.ObjectStorage */).GoingIn().OnExitTrace()()
+
+	var count int64
+	err := b.walk(
+		path, prefix, func(string) error {
+			count++
+			return nil
+		},
+	)
+	if err != nil {
+		return -1, err
+	}
+	return count, nil
+}
+
+// GetSize returns the total size of the Objects inside the Bucket
+func (b *localBucket) GetSize(path, prefix string) (int64, string, error) {
+	if b == nil {
+		return 0, "", fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", path, prefix), false /* Trace.ObjectStorage */).GoingIn().OnExitTrace()()
+
+	var totalSize int64
+	err := b.walk(
+		path, prefix, func(name string) error {
+			info, err := os.Stat(filepath.Join(b.objectsPath(), localFileName(name)))
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			totalSize += info.Size()
+			return nil
+		},
+	)
+	if err != nil {
+		return -1, "", err
+	}
+	return totalSize, humanReadableSize(totalSize), nil
+}
+
+// writeLocalPart writes the part #index of a multi-part object
+func writeLocalPart(b *localBucket, objectName string, index int, data []byte, metadata ObjectMetadata) error {
+	part, err := newLocalObject(b, objectName+strconv.Itoa(index))
+	if err != nil {
+		return err
+	}
+	partMetadata := metadata.Clone()
+	partMetadata["Split"] = objectName
+	err = part.ReplaceMetadata(partMetadata)
+	if err != nil {
+		return err
+	}
+	return part.Write(bytes.NewReader(data), int64(len(data)))
+}
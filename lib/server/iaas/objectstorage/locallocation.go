@@ -0,0 +1,364 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/graymeta/stow"
+
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	// localObjectsFolder is the folder of a local bucket containing the content of the objects
+	localObjectsFolder = "objects"
+	// localMetadataFolder is the folder of a local bucket containing the metadata of the objects
+	localMetadataFolder = "metadata"
+)
+
+// localLocation is a Location storing buckets as folders of a local directory
+// Layout of the directory:
+//
+//	<Endpoint>/<bucket>/objects/<escaped object name>     content of the object
+//	<Endpoint>/<bucket>/metadata/<escaped object name>    ETag and metadata of the object, in JSON
+//
+// Missing buckets and objects are reported with stow.ErrNotFound, as the stow-based Location does.
+type localLocation struct {
+	config Config
+	root   string
+}
+
+// newLocalLocation creates a Location on the local directory given by conf.Endpoint
+func newLocalLocation(conf Config) (*localLocation, error) {
+	if conf.Endpoint == "" {
+		return nil, fail.InvalidParameterError("conf.Endpoint", "cannot be empty string for a local object storage")
+	}
+	root, err := filepath.Abs(conf.Endpoint)
+	if err != nil {
+		return nil, fail.Errorf(fmt.Sprintf("invalid local object storage folder '%s'", conf.Endpoint), err)
+	}
+	err = os.MkdirAll(root, 0700)
+	if err != nil {
+		return nil, fail.Errorf(fmt.Sprintf("failed to create local object storage folder '%s'", root), err)
+	}
+	return &localLocation{config: conf, root: root}, nil
+}
+
+// validateLocalBucketName checks that the bucket name can be used as a folder name
+func validateLocalBucketName(bucketName string) error {
+	if bucketName == "" {
+		return fail.InvalidParameterError("bucketName", "cannot be empty string")
+	}
+	if bucketName == "." || bucketName == ".." || strings.ContainsAny(bucketName, `/\`) {
+		return fail.InvalidParameterError("bucketName", fmt.Sprintf("'%s' is not a valid local bucket name", bucketName))
+	}
+	return nil
+}
+
+// bucket returns the local bucket named 'bucketName', failing with stow.ErrNotFound if it does not exist
+func (l *localLocation) bucket(bucketName string) (*localBucket, error) {
+	err := validateLocalBucketName(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	b := &localBucket{location: l, Name: bucketName}
+	info, err := os.Stat(b.objectsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, stow.ErrNotFound
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fail.InconsistentError(fmt.Sprintf("'%s' is not a folder", b.objectsPath()))
+	}
+	return b, nil
+}
+
+// GetType returns the type of ObjectStorage
+func (l *localLocation) GetType() string {
+	return l.config.Type
+}
+
+// ListBuckets returns the buckets whose name begins with 'prefix'
+func (l *localLocation) ListBuckets(prefix string) ([]string, error) {
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", prefix), false /*Trace.Location*/).GoingIn().OnExitTrace()()
+
+	entries, err := ioutil.ReadDir(l.root)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), prefix) {
+			list = append(list, e.Name())
+		}
+	}
+	return list, nil
+}
+
+// FindBucket returns true if a bucket with the name exists in location
+func (l *localLocation) FindBucket(bucketName string) (bool, error) {
+	if l == nil {
+		return false, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", bucketName), false /*Trace.Location*/).GoingIn().OnExitTrace()()
+
+	_, err := l.bucket(bucketName)
+	if err != nil {
+		if err == stow.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetBucket ...
+func (l *localLocation) GetBucket(bucketName string) (Bucket, error) {
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", bucketName), false /*Trace.Location*/).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		// Note: No errors.Wrap here; error needs to be transmitted as-is
+		return nil, err
+	}
+	return b, nil
+}
+
+// CreateBucket creates the folders of a bucket; does nothing if the bucket already exists
+func (l *localLocation) CreateBucket(bucketName string) (Bucket, error) {
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	err := validateLocalBucketName(bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", bucketName), false /*Trace.Location*/).GoingIn().OnExitTrace()()
+
+	b := &localBucket{location: l, Name: bucketName}
+	for _, path := range []string{b.objectsPath(), b.metadataPath()} {
+		err = os.MkdirAll(path, 0700)
+		if err != nil {
+			return nil, fail.Errorf(fmt.Sprintf("failed to create bucket '%s'", bucketName), err)
+		}
+	}
+	return b, nil
+}
+
+// DeleteBucket removes a bucket, which has to be empty
+func (l *localLocation) DeleteBucket(bucketName string) error {
+	if l == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", bucketName), false /*Trace.Location*/).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	count, err := b.GetCount(RootPath, NoPrefix)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fail.NotAvailableError(fmt.Sprintf("bucket '%s' is not empty", bucketName))
+	}
+	return os.RemoveAll(b.path())
+}
+
+// ClearBucket removes the objects of a bucket matching 'path' and 'prefix'
+func (l *localLocation) ClearBucket(bucketName string, path, prefix string) error {
+	if l == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', '%s')", bucketName, path, prefix), false, /*Trace.Location*/
+	).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	return b.Clear(path, prefix)
+}
+
+// ListObjects lists the objects in a Bucket
+func (l *localLocation) ListObjects(bucketName string, path, prefix string) ([]string, error) {
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', '%s')", bucketName, path, prefix), false, /*Trace.Location*/
+	).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return b.List(path, prefix)
+}
+
+// BrowseBucket walks through the objects in a Bucket and apply callback to each object
+func (l *localLocation) BrowseBucket(bucketName string, path, prefix string, callback func(o Object) error) error {
+	if l == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', '%s')", bucketName, path, prefix), false, /*Trace.Location*/
+	).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	return b.Browse(path, prefix, callback)
+}
+
+// GetObject ...
+func (l *localLocation) GetObject(bucketName string, objectName string) (Object, error) {
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s')", bucketName, objectName), false, /*Trace.Location*/
+	).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return b.GetObject(objectName)
+}
+
+// ReadObject reads the content of an object and put it in an io.Writer
+func (l *localLocation) ReadObject(bucketName, objectName string, writer io.Writer, from, to int64) error {
+	if l == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', %d, %d)", bucketName, objectName, from, to), false, /*Trace.Location*/
+	).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	_, err = b.ReadObject(objectName, writer, from, to)
+	return err
+}
+
+// WriteObject writes the content of reader in the Object
+func (l *localLocation) WriteObject(
+	bucketName string, objectName string,
+	source io.Reader, size int64,
+	metadata ObjectMetadata,
+) (Object, error) {
+
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', %d)", bucketName, objectName, size), false, /*Trace.Location*/
+	).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return b.WriteObject(objectName, source, size, metadata)
+}
+
+// WriteMultiPartObject writes data from 'source' to an object in Object Storage, splitting data in parts of 'chunkSize' bytes
+// Note: nothing to do with multi-chunk abilities of various object storage technologies
+func (l *localLocation) WriteMultiPartObject(
+	bucketName string, objectName string,
+	source io.Reader, sourceSize int64,
+	chunkSize int,
+	metadata ObjectMetadata,
+) (Object, error) {
+
+	if l == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', %d, %d)", bucketName, objectName, sourceSize, chunkSize),
+		false, /*Trace.Location*/
+	).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return b.WriteMultiPartObject(objectName, source, sourceSize, chunkSize, metadata)
+}
+
+// DeleteObject ...
+func (l *localLocation) DeleteObject(bucketName, objectName string) error {
+	if l == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s')", bucketName, objectName), false, /*Trace.Location*/
+	).GoingIn().OnExitTrace()()
+
+	b, err := l.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	return b.DeleteObject(objectName)
+}
+
+// PresignObject is not available: the objects of a local location are not reachable through HTTP
+func (l *localLocation) PresignObject(bucketName, objectName, method string, ttl time.Duration) (string, error) {
+	if l == nil {
+		return "", fail.InvalidInstanceError()
+	}
+	err := checkPresignParameters(bucketName, objectName, method, ttl)
+	if err != nil {
+		return "", err
+	}
+	return "", fail.NotImplementedError("presigned URLs are not supported by Object Storage of type 'local'")
+}
@@ -0,0 +1,75 @@
+package objectstorage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/graymeta/stow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalLocation(t *testing.T) Location {
+	folder, err := ioutil.TempDir("", "safescale-objectstorage")
+	require.Nil(t, err)
+	l, err := NewLocation(Config{Type: "local", Endpoint: folder})
+	require.Nil(t, err)
+	return l
+}
+
+func TestLocalLocation_Objects(t *testing.T) {
+	l := newTestLocalLocation(t)
+
+	_, err := l.GetBucket("missing")
+	assert.Equal(t, stow.ErrNotFound, err)
+
+	_, err = l.CreateBucket("bucket")
+	require.Nil(t, err)
+	found, err := l.FindBucket("bucket")
+	require.Nil(t, err)
+	assert.True(t, found)
+
+	content := "0123456789"
+	o, err := l.WriteObject("bucket", "hosts/byID/1", strings.NewReader(content), int64(len(content)), ObjectMetadata{"owner": "me"})
+	require.Nil(t, err)
+	size, err := o.GetSize()
+	require.Nil(t, err)
+	assert.Equal(t, int64(len(content)), size)
+	etag, err := o.GetETag()
+	require.Nil(t, err)
+	assert.Equal(t, "781e5e245d69b566979b86e28d23f2c7", etag)
+
+	b, err := l.GetBucket("bucket")
+	require.Nil(t, err)
+	o, err = b.GetObject("hosts/byID/1")
+	require.Nil(t, err)
+	metadata, err := o.GetMetadata()
+	require.Nil(t, err)
+	assert.Equal(t, "me", metadata["owner"])
+
+	var buffer bytes.Buffer
+	require.Nil(t, l.ReadObject("bucket", "hosts/byID/1", &buffer, 2, 5))
+	assert.Equal(t, "234", buffer.String())
+	buffer.Reset()
+	require.Nil(t, l.ReadObject("bucket", "hosts/byID/1", &buffer, 7, 0))
+	assert.Equal(t, "789", buffer.String())
+	assert.Equal(t, stow.ErrNotFound, l.ReadObject("bucket", "hosts/byID/2", &buffer, 0, 0))
+
+	_, err = l.WriteMultiPartObject("bucket", "hosts/byName/big", strings.NewReader(content), int64(len(content)), 4, nil)
+	require.Nil(t, err)
+	list, err := l.ListObjects("bucket", "hosts/byName", NoPrefix)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"hosts/byName/big0", "hosts/byName/big1", "hosts/byName/big2"}, list)
+	buffer.Reset()
+	require.Nil(t, l.ReadObject("bucket", "hosts/byName/big2", &buffer, 0, 0))
+	assert.Equal(t, "89", buffer.String())
+
+	assert.NotNil(t, l.DeleteBucket("bucket"))
+	require.Nil(t, l.ClearBucket("bucket", "hosts", NoPrefix))
+	require.Nil(t, l.DeleteBucket("bucket"))
+	found, err = l.FindBucket("bucket")
+	require.Nil(t, err)
+	assert.False(t, found)
+}
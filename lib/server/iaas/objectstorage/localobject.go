@@ -0,0 +1,439 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/graymeta/stow"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// localObjectHeader is the content of the metadata file of a local object
+type localObjectHeader struct {
+	ETag     string         `json:"etag"`
+	Metadata ObjectMetadata `json:"metadata,omitempty"`
+}
+
+// localObject is an Object stored in a file of a localBucket
+type localObject struct {
+	bucket *localBucket
+
+	Name     string         `json:"name,omitempty"`
+	Metadata ObjectMetadata `json:"metadata,omitempty"`
+
+	stored  bool
+	size    int64
+	etag    string
+	lastMod time.Time
+}
+
+// newLocalObject returns the object 'objectName' of the bucket, loaded if it is already stored
+func newLocalObject(b *localBucket, objectName string) (*localObject, error) {
+	if objectName == "" {
+		return nil, fail.InvalidParameterError("objectName", "cannot be empty string")
+	}
+
+	o := &localObject{
+		bucket:   b,
+		Name:     objectName,
+		Metadata: ObjectMetadata{},
+	}
+	err := o.Reload()
+	if err != nil && err != stow.ErrNotFound {
+		return nil, err
+	}
+	return o, nil
+}
+
+// contentPath returns the path of the file containing the content of the object
+func (o *localObject) contentPath() string {
+	return filepath.Join(o.bucket.objectsPath(), localFileName(o.Name))
+}
+
+// headerPath returns the path of the file containing the ETag and the metadata of the object
+func (o *localObject) headerPath() string {
+	return filepath.Join(o.bucket.metadataPath(), localFileName(o.Name))
+}
+
+// Stored return true if the object exists in Object Storage
+func (o *localObject) Stored() (bool, error) {
+	if o == nil {
+		return false, fail.InvalidInstanceError()
+	}
+
+	return o.stored, nil
+}
+
+// Reload reloads the data of the Object from the local folder
+func (o *localObject) Reload() error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	info, err := os.Stat(o.contentPath())
+	if err != nil {
+		o.stored = false
+		if os.IsNotExist(err) {
+			return stow.ErrNotFound
+		}
+		return err
+	}
+
+	header := localObjectHeader{}
+	content, err := ioutil.ReadFile(o.headerPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		err = json.Unmarshal(content, &header)
+		if err != nil {
+			return fail.Wrap(err, fmt.Sprintf("invalid metadata of object '%s'", o.Name))
+		}
+	}
+	if header.Metadata == nil {
+		header.Metadata = ObjectMetadata{}
+	}
+
+	o.stored = true
+	o.size = info.Size()
+	o.lastMod = info.ModTime()
+	o.etag = header.ETag
+	o.Metadata = header.Metadata
+	return nil
+}
+
+// Read reads the content of the object, from byte 'from' to byte 'to' (excluded; 0 means up to the end), and writes it in 'target'
+func (o *localObject) Read(target io.Writer, from, to int64) error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+	if target == nil {
+		return fail.InvalidParameterError("target", "cannot be nil")
+	}
+	if from < 0 {
+		return fail.InvalidParameterError("from", "cannot be negative")
+	}
+	if to > 0 && from > to {
+		return fail.InvalidParameterError("from", "cannot be greater than 'to'")
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("(%d, %d)", from, to), false /*Trace.Controller*/).GoingIn().OnExitTrace()()
+
+	// 1st reload information about object, to be sure to have the last
+	err := o.Reload()
+	if err != nil {
+		return err
+	}
+
+	length := o.size - from
+	if to > 0 && to > from {
+		length = to - from
+	}
+	if from+length > o.size {
+		return fail.InvalidParameterError(
+			"to", fmt.Sprintf("range [%d, %d[ is beyond the size of object '%s' (%d)", from, from+length, o.Name, o.size),
+		)
+	}
+
+	source, err := os.Open(o.contentPath())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		clerr := source.Close()
+		if clerr != nil {
+			log.Error("Error closing item")
+		}
+	}()
+
+	if from > 0 {
+		_, err = source.Seek(from, io.SeekStart)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = io.CopyN(target, source, length)
+	return err
+}
+
+// Write the source to the object; the file is replaced only once completely written
+func (o *localObject) Write(source io.Reader, sourceSize int64) error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+	if source == nil {
+		return fail.InvalidParameterError("source", "cannot be nil")
+	}
+	if o.bucket == nil {
+		return fail.InvalidParameterError("o.bucket", "cannot be nil")
+	}
+
+	defer debug.NewTracer(nil, fmt.Sprintf("(%d)", sourceSize), false /*Trace.Controller*/).GoingIn().OnExitTrace()()
+
+	tmpContent, err := ioutil.TempFile(o.bucket.objectsPath(), ".write-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmpContent.Name())
+	}()
+
+	hasher := md5.New()
+	writer := io.MultiWriter(tmpContent, hasher)
+	if sourceSize >= 0 {
+		_, err = io.CopyN(writer, source, sourceSize)
+	} else {
+		_, err = io.Copy(writer, source)
+	}
+	clerr := tmpContent.Close()
+	if err != nil {
+		return fail.Wrap(err, fmt.Sprintf("failed to write object '%s'", o.Name))
+	}
+	if clerr != nil {
+		return clerr
+	}
+
+	metadata := o.Metadata
+	if metadata == nil {
+		metadata = ObjectMetadata{}
+	}
+	content, err := json.Marshal(localObjectHeader{ETag: hex.EncodeToString(hasher.Sum(nil)), Metadata: metadata})
+	if err != nil {
+		return err
+	}
+	tmpHeader, err := ioutil.TempFile(o.bucket.metadataPath(), ".write-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmpHeader.Name())
+	}()
+	_, err = tmpHeader.Write(content)
+	clerr = tmpHeader.Close()
+	if err != nil {
+		return err
+	}
+	if clerr != nil {
+		return clerr
+	}
+
+	err = os.Rename(tmpHeader.Name(), o.headerPath())
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmpContent.Name(), o.contentPath())
+	if err != nil {
+		return err
+	}
+	return o.Reload()
+}
+
+// WriteMultiPart writes big data to Object, by parts (also called chunks)
+// Note: nothing to do with multi-chunk abilities of various object storage technologies
+func (o *localObject) WriteMultiPart(source io.Reader, sourceSize int64, chunkSize int) error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+	if source == nil {
+		return fail.InvalidParameterError("source", "cannot be nil")
+	}
+	if chunkSize <= 0 {
+		return fail.InvalidParameterError("chunkSize", "must be greater than 0")
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("(%d, %d)", sourceSize, chunkSize), false, /*Trace.Controller*/
+	).GoingIn().OnExitTrace()()
+
+	metadata, err := o.GetMetadata()
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, chunkSize)
+	remaining := sourceSize
+	for chunkIndex := 0; ; chunkIndex++ {
+		if remaining < int64(chunkSize) {
+			chunkSize = int(remaining)
+		}
+		nBytesRead, err := io.ReadFull(source, buf[:chunkSize])
+		if err != nil {
+			return fail.Wrap(
+				err, fmt.Sprintf(
+					"failed to read data from source to write in chunk of object '%s' in bucket '%s'", o.Name,
+					o.bucket.Name,
+				),
+			)
+		}
+		err = writeLocalPart(o.bucket, o.Name, chunkIndex, buf[:nBytesRead], metadata)
+		if err != nil {
+			return err
+		}
+		log.Debugf(
+			"written chunk #%d (%d bytes) of data in object '%s:%s'", chunkIndex, nBytesRead, o.bucket.Name, o.Name,
+		)
+		remaining -= int64(nBytesRead)
+		if remaining <= 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// Delete deletes the object from the local folder
+func (o *localObject) Delete() error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, "", false /*Trace.Controller*/).GoingIn().OnExitTrace()()
+
+	err := os.Remove(o.contentPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stow.ErrNotFound
+		}
+		return err
+	}
+	err = os.Remove(o.headerPath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	o.stored = false
+	return nil
+}
+
+// ForceAddMetadata overwrites the metadata entries of the object by the ones provided in parameter
+func (o *localObject) ForceAddMetadata(newMetadata ObjectMetadata) error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	if o.Metadata == nil {
+		o.Metadata = ObjectMetadata{}
+	}
+	for k, v := range newMetadata {
+		o.Metadata[k] = v
+	}
+	return nil
+}
+
+// AddMetadata adds missing entries in object metadata
+func (o *localObject) AddMetadata(newMetadata ObjectMetadata) error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	if o.Metadata == nil {
+		o.Metadata = ObjectMetadata{}
+	}
+	for k, v := range newMetadata {
+		if _, found := o.Metadata[k]; !found {
+			o.Metadata[k] = v
+		}
+	}
+	return nil
+}
+
+// ReplaceMetadata replaces object metadata with the ones provided in parameter
+func (o *localObject) ReplaceMetadata(newMetadata ObjectMetadata) error {
+	if o == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	o.Metadata = newMetadata.Clone()
+	return nil
+}
+
+// GetID returns the ID of the object, which is its name
+func (o *localObject) GetID() (string, error) {
+	if o == nil {
+		return "", fail.InvalidInstanceError()
+	}
+
+	if !o.stored {
+		return "", fail.Errorf("metadata item without id", nil)
+	}
+	return o.Name, nil
+}
+
+// GetName returns the name of the object
+func (o *localObject) GetName() (string, error) {
+	if o == nil {
+		return "", fail.InvalidInstanceError()
+	}
+
+	return o.Name, nil
+}
+
+// GetLastUpdate returns the date of last update
+func (o *localObject) GetLastUpdate() (time.Time, error) {
+	if o == nil {
+		return time.Time{}, fail.InvalidInstanceError()
+	}
+
+	if !o.stored {
+		return time.Now(), fmt.Errorf("object metadata not found")
+	}
+	return o.lastMod, nil
+}
+
+// GetSize returns the size of the content of the object
+func (o *localObject) GetSize() (int64, error) {
+	if o == nil {
+		return -1, fail.InvalidInstanceError()
+	}
+
+	if !o.stored {
+		return -1, fail.Errorf("metadata item without size", nil)
+	}
+	return o.size, nil
+}
+
+// GetETag returns the md5sum of the content of the object
+func (o *localObject) GetETag() (string, error) {
+	if o == nil {
+		return "", fail.InvalidInstanceError()
+	}
+
+	if !o.stored || o.etag == "" {
+		return "", fail.Errorf("metadata item without etag", nil)
+	}
+	return o.etag, nil
+}
+
+// GetMetadata returns the metadata of the object
+func (o *localObject) GetMetadata() (ObjectMetadata, error) {
+	if o == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	return o.Metadata.Clone(), nil
+}
@@ -49,6 +49,10 @@ type location struct {
 
 // NewLocation creates an Object Storage Location based on config
 func NewLocation(conf Config) (Location, error) {
+	if conf.Type == "local" {
+		return newLocalLocation(conf)
+	}
+
 	location := &location{
 		config: conf,
 	}
@@ -68,12 +72,13 @@ func (l *location) connect() error {
 	// FIXME: GCP Remove specific driver code, Google requires a custom cfg here..., this will require a refactoring based on stow.ConfigMap
 	var config stow.ConfigMap
 
-	if l.config.Type == "google" {
+	switch l.config.Type {
+	case "google":
 		config = stow.ConfigMap{
 			"json":       l.config.Credentials,
 			"project_id": l.config.ProjectID,
 		}
-	} else {
+	default:
 		config = stow.ConfigMap{
 			"access_key_id":   l.config.User,
 			"secret_key":      l.config.SecretKey,
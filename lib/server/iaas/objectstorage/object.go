@@ -57,8 +57,9 @@ type object struct {
 // NewObject ...
 func newObject(bucket *bucket, objectName string) (*object, error) {
 	o := &object{
-		bucket: bucket,
-		Name:   objectName,
+		bucket:   bucket,
+		Name:     objectName,
+		Metadata: ObjectMetadata{},
 	}
 	item, err := bucket.container.Item(objectName)
 	if err != nil {
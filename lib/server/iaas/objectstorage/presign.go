@@ -0,0 +1,218 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/accounts"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	"golang.org/x/oauth2/google"
+
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	// MaxPresignTTL is the longest validity of a presigned URL (the limit of S3 signature v4)
+	MaxPresignTTL = 7 * 24 * time.Hour
+
+	// googleStorageURL is the root URL of the objects of Google Cloud Storage
+	googleStorageURL = "https://storage.googleapis.com"
+)
+
+// checkPresignParameters validates the parameters of PresignObject
+func checkPresignParameters(bucketName, objectName, method string, ttl time.Duration) error {
+	if bucketName == "" {
+		return fail.InvalidParameterError("bucketName", "cannot be empty string")
+	}
+	if objectName == "" {
+		return fail.InvalidParameterError("objectName", "cannot be empty string")
+	}
+	if method != http.MethodGet && method != http.MethodPut {
+		return fail.InvalidParameterError("method", fmt.Sprintf("must be '%s' or '%s'", http.MethodGet, http.MethodPut))
+	}
+	if ttl <= 0 || ttl > MaxPresignTTL {
+		return fail.InvalidParameterError("ttl", fmt.Sprintf("must be positive and at most %s", MaxPresignTTL))
+	}
+	return nil
+}
+
+// PresignObject returns an URL allowing anyone to download (method GET) or upload (method PUT) the object
+// during ttl, without credentials
+func (l *location) PresignObject(bucketName, objectName, method string, ttl time.Duration) (string, error) {
+	if l == nil {
+		return "", fail.InvalidInstanceError()
+	}
+	err := checkPresignParameters(bucketName, objectName, method, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	defer debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', '%s', %s)", bucketName, objectName, method, ttl), false, /*Trace.Location*/
+	).GoingIn().OnExitTrace()()
+
+	switch l.config.Type {
+	case "s3":
+		return l.presignS3(bucketName, objectName, method, ttl)
+	case "swift":
+		return l.presignSwift(bucketName, objectName, method, ttl)
+	case "google":
+		return l.presignGoogle(bucketName, objectName, method, ttl, time.Now())
+	default:
+		return "", fail.NotImplementedError(fmt.Sprintf("presigned URLs are not supported by Object Storage of type '%s'", l.config.Type))
+	}
+}
+
+// presignS3 builds a presigned URL with signature v4
+func (l *location) presignS3(bucketName, objectName, method string, ttl time.Duration) (string, error) {
+	sess, err := session.NewSession(
+		&aws.Config{
+			Credentials:      credentials.NewStaticCredentials(l.config.User, l.config.SecretKey, ""),
+			S3ForcePathStyle: aws.Bool(true),
+			Region:           aws.String(l.config.Region),
+			Endpoint:         aws.String(l.config.Endpoint),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	service := s3.New(sess)
+
+	var req *request.Request
+	switch method {
+	case http.MethodPut:
+		req, _ = service.PutObjectRequest(&s3.PutObjectInput{Bucket: aws.String(bucketName), Key: aws.String(objectName)})
+	default:
+		req, _ = service.GetObjectRequest(&s3.GetObjectInput{Bucket: aws.String(bucketName), Key: aws.String(objectName)})
+	}
+	return req.Presign(ttl)
+}
+
+// presignSwift builds a Swift temporary URL, signed with the Temp-URL-Key of the account
+func (l *location) presignSwift(bucketName, objectName, method string, ttl time.Duration) (string, error) {
+	provider, err := openstack.AuthenticatedClient(
+		gophercloud.AuthOptions{
+			IdentityEndpoint: l.config.AuthURL,
+			Username:         l.config.User,
+			Password:         l.config.SecretKey,
+			TenantName:       l.config.Tenant,
+			DomainName:       l.config.TenantDomain,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	client, err := openstack.NewObjectStorageV1(provider, gophercloud.EndpointOpts{Region: l.config.Region})
+	if err != nil {
+		return "", err
+	}
+
+	// Without key, Swift would refuse the URL when used; better tell it now
+	metadata, err := accounts.Get(client, nil).ExtractMetadata()
+	if err != nil {
+		return "", err
+	}
+	if metadata["Temp-Url-Key"] == "" && metadata["Temp-Url-Key-2"] == "" {
+		return "", fail.NotAvailableError(
+			"the Swift account has no Temp-URL-Key; set one with 'swift post -m \"Temp-URL-Key:<key>\"' to allow presigned URLs",
+		)
+	}
+
+	return objects.CreateTempURL(
+		client, bucketName, objectName, objects.CreateTempURLOpts{
+			Method: objects.HTTPMethod(method),
+			TTL:    int(ttl.Seconds()),
+		},
+	)
+}
+
+// presignGoogle builds a Google Cloud Storage signed URL (signature v2), signed with the key of the service account
+func (l *location) presignGoogle(bucketName, objectName, method string, ttl time.Duration, now time.Time) (string, error) {
+	conf, err := google.JWTConfigFromJSON([]byte(l.config.Credentials))
+	if err != nil {
+		return "", fail.Wrap(err, "invalid credentials of Google service account")
+	}
+	key, err := parseRSAPrivateKey(conf.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	resource := "/" + bucketName + "/" + escapeObjectName(objectName)
+	expires := strconv.FormatInt(now.Add(ttl).Unix(), 10)
+	toSign := method + "\n\n\n" + expires + "\n" + resource
+	digest := sha256.Sum256([]byte(toSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("GoogleAccessId", conf.Email)
+	query.Set("Expires", expires)
+	query.Set("Signature", base64.StdEncoding.EncodeToString(signature))
+	return googleStorageURL + resource + "?" + query.Encode(), nil
+}
+
+// parseRSAPrivateKey decodes a PEM encoded RSA private key, in PKCS#8 or PKCS#1 format
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fail.InvalidParameterError("data", "no PEM encoded key found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to parse private key")
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fail.InvalidParameterError("data", "private key is not a RSA key")
+	}
+	return key, nil
+}
+
+// escapeObjectName escapes the name of an object to be used in the path of an URL, keeping its '/'
+func escapeObjectName(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
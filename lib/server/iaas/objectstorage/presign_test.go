@@ -0,0 +1,61 @@
+package objectstorage
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocation_PresignGoogle(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	credentials, err := json.Marshal(
+		map[string]string{
+			"type":         "service_account",
+			"client_email": "safescale@project.iam.gserviceaccount.com",
+			"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		},
+	)
+	require.Nil(t, err)
+
+	l := &location{config: Config{Type: "google", Credentials: string(credentials)}}
+	now := time.Unix(1600000000, 0)
+	signed, err := l.presignGoogle("bucket", "dir/my file", http.MethodGet, time.Hour, now)
+	require.Nil(t, err)
+
+	u, err := url.Parse(signed)
+	require.Nil(t, err)
+	assert.Equal(t, "/bucket/dir/my%20file", u.EscapedPath())
+	query := u.Query()
+	assert.Equal(t, "safescale@project.iam.gserviceaccount.com", query.Get("GoogleAccessId"))
+	assert.Equal(t, "1600003600", query.Get("Expires"))
+
+	signature, err := base64.StdEncoding.DecodeString(query.Get("Signature"))
+	require.Nil(t, err)
+	digest := sha256.Sum256([]byte("GET\n\n\n1600003600\n/bucket/dir/my%20file"))
+	assert.Nil(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+}
+
+func TestLocalLocation_PresignObject(t *testing.T) {
+	l := newTestLocalLocation(t)
+
+	_, err := l.PresignObject("bucket", "object", http.MethodDelete, time.Hour)
+	assert.True(t, strings.Contains(err.Error(), "method"))
+	_, err = l.PresignObject("bucket", "object", http.MethodGet, MaxPresignTTL+time.Second)
+	assert.True(t, strings.Contains(err.Error(), "ttl"))
+	_, err = l.PresignObject("bucket", "object", http.MethodGet, time.Hour)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pricing
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Source is the interface of a source of host prices
+type Source interface {
+	// GetName returns the name of the source
+	GetName() string
+	// GetPricePerHour returns the price in dollars of one hour of a host using template
+	// Returns fail.ErrNotFound if the source does not know the template
+	GetPricePerHour(template abstract.HostTemplate) (float64, error)
+}
+
+// Builder builds the source of prices of a provider from the parameters of a tenant
+type Builder func(tenant map[string]interface{}) (Source, error)
+
+var allBuilders = map[string]Builder{}
+
+// Register registers the builder of the source of prices of a provider
+func Register(provider string, builder Builder) {
+	if _, ok := allBuilders[provider]; ok {
+		return
+	}
+	allBuilders[provider] = builder
+}
+
+// New returns the source of prices of a tenant using provider
+// Prices are searched in the section 'pricing' of the tenant first, then in the source registered for the provider,
+// and at last in the database filled by the scanner
+func New(provider string, tenant map[string]interface{}) (Source, error) {
+	static, err := newStaticSource(tenant)
+	if err != nil {
+		return nil, err
+	}
+	sources := chain{static}
+
+	if builder, ok := allBuilders[provider]; ok {
+		source, err := builder(tenant)
+		if err != nil {
+			logrus.Warnf("failed to build pricing source of provider '%s', ignoring it: %v", provider, err)
+		} else {
+			sources = append(sources, source)
+		}
+	}
+
+	return append(sources, newScannerSource(provider, tenant)), nil
+}
+
+// chain is a Source asking each of its sources in turn until one knows the price
+type chain []Source
+
+// GetName returns the name of the source
+func (c chain) GetName() string {
+	return "chain"
+}
+
+// GetPricePerHour returns the price found by the first source knowing template
+func (c chain) GetPricePerHour(template abstract.HostTemplate) (float64, error) {
+	for _, s := range c {
+		price, err := s.GetPricePerHour(template)
+		if err == nil {
+			logrus.Debugf("price of template '%s' found in pricing source '%s'", template.Name, s.GetName())
+			return price, nil
+		}
+		if _, ok := err.(fail.ErrNotFound); !ok {
+			logrus.Warnf("failed to get price of template '%s' from pricing source '%s': %v", template.Name, s.GetName(), err)
+		}
+	}
+	return 0, fail.NotFoundError(
+		fmt.Sprintf(
+			"no price found for template '%s'; prices can be set in section 'pricing' of the tenant", template.Name,
+		),
+	)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+type fixedSource float64
+
+func (s fixedSource) GetName() string {
+	return "fixed"
+}
+
+func (s fixedSource) GetPricePerHour(template abstract.HostTemplate) (float64, error) {
+	return float64(s), nil
+}
+
+func TestNew_precedence(t *testing.T) {
+	Register("test-provider", func(tenant map[string]interface{}) (Source, error) {
+		return fixedSource(2.5), nil
+	})
+	tenant := map[string]interface{}{
+		"pricing": map[string]interface{}{
+			"Templates": map[string]interface{}{"small": 0.1},
+		},
+	}
+
+	source, err := New("test-provider", tenant)
+	require.Nil(t, err)
+
+	price, err := source.GetPricePerHour(abstract.HostTemplate{ID: "t1", Name: "small"})
+	require.Nil(t, err)
+	assert.Equal(t, 0.1, price)
+
+	price, err = source.GetPricePerHour(abstract.HostTemplate{ID: "t2", Name: "large"})
+	require.Nil(t, err)
+	assert.Equal(t, 2.5, price)
+}
+
+func TestNew_notFound(t *testing.T) {
+	source, err := New("unknown-provider", map[string]interface{}{})
+	require.Nil(t, err)
+
+	_, err = source.GetPricePerHour(abstract.HostTemplate{ID: "t1", Name: "small"})
+	assert.IsType(t, fail.ErrNotFound{}, err)
+}
+
+func TestNew_invalidPrice(t *testing.T) {
+	tenant := map[string]interface{}{
+		"pricing": map[string]interface{}{
+			"Templates": map[string]interface{}{"small": "cheap"},
+		},
+	}
+	_, err := New("unknown-provider", tenant)
+	assert.NotNil(t, err)
+}
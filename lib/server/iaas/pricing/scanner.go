@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+
+	scribble "github.com/nanobox-io/golang-scribble"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// scannerSource is a Source using the prices stored by the scanner
+type scannerSource struct {
+	folder string
+}
+
+// newScannerSource returns a Source reading the prices stored by the scanner for the region of the tenant
+func newScannerSource(provider string, tenant map[string]interface{}) *scannerSource {
+	region := ""
+	if compute, ok := tenant["compute"].(map[string]interface{}); ok {
+		region, _ = compute["Region"].(string)
+	}
+	return &scannerSource{folder: fmt.Sprintf("images/%s/%s", provider, region)}
+}
+
+// GetName returns the name of the source
+func (s *scannerSource) GetName() string {
+	return "scanner"
+}
+
+// GetPricePerHour returns the price stored by the scanner for the template, if any
+func (s *scannerSource) GetPricePerHour(template abstract.HostTemplate) (float64, error) {
+	db, err := scribble.New(utils.AbsPathify("$HOME/.safescale/scanner/db"), nil)
+	if err != nil {
+		return 0, err
+	}
+	records, err := db.ReadAll(s.folder)
+	if err != nil {
+		return 0, fail.NotFoundError(fmt.Sprintf("no scanner data found in '%s'", s.folder))
+	}
+	for _, r := range records {
+		info := abstract.StoredCPUInfo{}
+		if err := json.Unmarshal([]byte(r), &info); err != nil {
+			continue
+		}
+		if info.TemplateID == template.ID && info.PricePerHour > 0 {
+			return info.PricePerHour, nil
+		}
+	}
+	return 0, fail.NotFoundError(fmt.Sprintf("no price stored by scanner for template '%s'", template.Name))
+}
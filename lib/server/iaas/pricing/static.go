@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pricing
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// staticSource is a Source using the prices set in the section 'pricing' of the tenant, indexed by template name
+type staticSource struct {
+	prices map[string]float64
+}
+
+// newStaticSource reads the prices in the field 'Templates' of the section 'pricing' of tenant
+func newStaticSource(tenant map[string]interface{}) (*staticSource, error) {
+	s := &staticSource{prices: map[string]float64{}}
+
+	section, ok := tenant["pricing"].(map[string]interface{})
+	if !ok {
+		return s, nil
+	}
+	templates, ok := section["Templates"].(map[string]interface{})
+	if !ok {
+		return s, nil
+	}
+	for k, v := range templates {
+		switch price := v.(type) {
+		case float64:
+			s.prices[k] = price
+		case int64:
+			s.prices[k] = float64(price)
+		case int:
+			s.prices[k] = float64(price)
+		default:
+			return nil, fail.InvalidParameterError("pricing.Templates", fmt.Sprintf("price of '%s' must be a number", k))
+		}
+	}
+	return s, nil
+}
+
+// GetName returns the name of the source
+func (s *staticSource) GetName() string {
+	return "tenant"
+}
+
+// GetPricePerHour returns the price set for the template, looked up by name then by id
+func (s *staticSource) GetPricePerHour(template abstract.HostTemplate) (float64, error) {
+	if price, ok := s.prices[template.Name]; ok {
+		return price, nil
+	}
+	if price, ok := s.prices[template.ID]; ok {
+		return price, nil
+	}
+	return 0, fail.NotFoundError(fmt.Sprintf("no price set for template '%s' in tenant", template.Name))
+}
@@ -0,0 +1,362 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// maskedValue replaces the value of sensitive parameters in cassettes
+const maskedValue = "********"
+
+// sensitiveKeys contains the (lowercase) parts of parameter names whose values are not written in cassettes
+var sensitiveKeys = []string{"password", "secret", "applicationkey", "consumerkey"}
+
+// Cassette contains the calls made to a provider, as recorded by RecordingProvider and served by ReplayingProvider
+// Credentials found in options and tenant parameters are masked, but the results of the calls (host passwords, private
+// keys, ...) are stored as is: review a cassette before committing it
+type Cassette struct {
+	Provider              string                 `json:"provider"`
+	Capabilities          providers.Capabilities `json:"capabilities"`
+	AuthenticationOptions map[string]interface{} `json:"authentication_options,omitempty"`
+	ConfigurationOptions  map[string]interface{} `json:"configuration_options,omitempty"`
+	TenantParameters      map[string]interface{} `json:"tenant_parameters,omitempty"`
+	Interactions          []Interaction          `json:"interactions"`
+}
+
+// Interaction is a call to a provider method, with its arguments, its results and its error
+type Interaction struct {
+	Method  string            `json:"method"`
+	Args    []json.RawMessage `json:"args,omitempty"`
+	Results []json.RawMessage `json:"results,omitempty"`
+	Error   *RecordedError    `json:"error,omitempty"`
+}
+
+// RecordedError is the serializable form of an error returned by a provider, keeping its fail type
+type RecordedError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// LoadCassette reads a cassette from file
+func LoadCassette(path string) (*Cassette, fail.Error) {
+	if path == "" {
+		return nil, fail.InvalidParameterError("path", "cannot be empty string")
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fail.Errorf(fmt.Sprintf("failed to read cassette '%s'", path), err)
+	}
+	cassette := &Cassette{}
+	err = json.Unmarshal(content, cassette)
+	if err != nil {
+		return nil, fail.Errorf(fmt.Sprintf("failed to decode cassette '%s'", path), err)
+	}
+	return cassette, nil
+}
+
+// Save writes the cassette to file
+func (c *Cassette) Save(path string) fail.Error {
+	if c == nil {
+		return fail.InvalidInstanceError()
+	}
+	if path == "" {
+		return fail.InvalidParameterError("path", "cannot be empty string")
+	}
+
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fail.Errorf(fmt.Sprintf("failed to encode cassette '%s'", path), err)
+	}
+	err = ioutil.WriteFile(path, content, 0600)
+	if err != nil {
+		return fail.Errorf(fmt.Sprintf("failed to write cassette '%s'", path), err)
+	}
+	return nil
+}
+
+// newRecordedError converts an error to its serializable form
+func newRecordedError(err error) *RecordedError {
+	if err == nil {
+		return nil
+	}
+
+	var kind string
+	switch err.(type) {
+	case fail.ErrTimeout:
+		kind = "ErrTimeout"
+	case fail.ErrNotFound:
+		kind = "ErrNotFound"
+	case fail.ErrNotAvailable:
+		kind = "ErrNotAvailable"
+	case fail.ErrDuplicate:
+		kind = "ErrDuplicate"
+	case fail.ErrInvalidRequest:
+		kind = "ErrInvalidRequest"
+	case fail.ErrUnauthorized:
+		kind = "ErrUnauthorized"
+	case fail.ErrForbidden:
+		kind = "ErrForbidden"
+	case fail.ErrAborted:
+		kind = "ErrAborted"
+	case fail.ErrOverflow:
+		kind = "ErrOverflow"
+	case fail.ErrOverload:
+		kind = "ErrOverload"
+	case fail.ErrNotImplemented:
+		kind = "ErrNotImplemented"
+	case fail.ErrRuntimePanic:
+		kind = "ErrRuntimePanic"
+	case fail.ErrInvalidInstance:
+		kind = "ErrInvalidInstance"
+	case fail.ErrInvalidParameter:
+		kind = "ErrInvalidParameter"
+	case fail.ErrInvalidInstanceContent:
+		kind = "ErrInvalidInstanceContent"
+	case fail.ErrInconsistent:
+		kind = "ErrInconsistent"
+	case fail.ErrSyntax:
+		kind = "ErrSyntax"
+	case fail.ErrUnknown:
+		kind = "ErrUnknown"
+	default:
+		kind = "error"
+	}
+	return &RecordedError{Type: kind, Message: err.Error()}
+}
+
+// ToError rebuilds the error recorded, with the same fail type
+func (e *RecordedError) ToError() fail.Error {
+	if e == nil {
+		return nil
+	}
+
+	// The fields of fail.ErrCore are not exported; borrows the core of an error built from the raw message
+	core := fail.SyntaxError(e.Message).ErrCore
+	switch e.Type {
+	case "ErrTimeout":
+		return fail.TimeoutError(e.Message, 0, nil)
+	case "ErrNotFound":
+		return fail.NotFoundError(e.Message)
+	case "ErrNotAvailable":
+		return fail.NotAvailableError(e.Message)
+	case "ErrDuplicate":
+		return fail.DuplicateError(e.Message)
+	case "ErrInvalidRequest":
+		return fail.InvalidRequestError(e.Message)
+	case "ErrUnauthorized":
+		return fail.UnauthorizedError(e.Message)
+	case "ErrForbidden":
+		return fail.ForbiddenError(e.Message)
+	case "ErrAborted":
+		return fail.AbortedError(e.Message, nil)
+	case "ErrOverflow":
+		return fail.OverflowError(e.Message, 0, nil)
+	case "ErrOverload":
+		return fail.OverloadError(e.Message)
+	case "ErrNotImplemented":
+		return fail.ErrNotImplemented{ErrCore: core}
+	case "ErrRuntimePanic":
+		return fail.RuntimePanicError(e.Message)
+	case "ErrInvalidInstance":
+		return fail.ErrInvalidInstance{ErrCore: core}
+	case "ErrInvalidParameter":
+		return fail.ErrInvalidParameter{ErrCore: core}
+	case "ErrInvalidInstanceContent":
+		return fail.ErrInvalidInstanceContent{ErrCore: core}
+	case "ErrInconsistent":
+		return fail.ErrInconsistent{ErrCore: core}
+	case "ErrSyntax":
+		return fail.SyntaxError(e.Message)
+	case "ErrUnknown":
+		return fail.ErrUnknown{ErrCore: core}
+	default:
+		return fmt.Errorf("%s", e.Message)
+	}
+}
+
+// configToMap converts a providers.Config to a map, masking the sensitive values
+func configToMap(cfg providers.Config) map[string]interface{} {
+	cfgMap, ok := cfg.(providers.ConfigMap)
+	if !ok {
+		return nil
+	}
+	return maskSensitiveValues(cfgMap)
+}
+
+// mapToConfig converts a map decoded from JSON to a providers.Config, restoring the slices and maps of strings
+// expected by the users of the configuration (JSON decoding produces []interface{} and map[string]interface{})
+func mapToConfig(in map[string]interface{}) providers.Config {
+	cfg := providers.ConfigMap{}
+	for k, v := range in {
+		cfg.Set(k, restoreStrings(v))
+	}
+	return cfg
+}
+
+// restoreStrings converts []interface{} and map[string]interface{} containing only strings to []string and
+// map[string]string
+func restoreStrings(value interface{}) interface{} {
+	switch value := value.(type) {
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, v := range value {
+			s, ok := v.(string)
+			if !ok {
+				return value
+			}
+			list = append(list, s)
+		}
+		return list
+	case map[string]interface{}:
+		dict := make(map[string]string, len(value))
+		for k, v := range value {
+			s, ok := v.(string)
+			if !ok {
+				return value
+			}
+			dict[k] = s
+		}
+		return dict
+	default:
+		return value
+	}
+}
+
+// maskSensitiveValues returns a copy of in where the values of sensitive keys are masked, recursively
+func maskSensitiveValues(in map[string]interface{}) map[string]interface{} {
+	if in == nil {
+		return nil
+	}
+
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		if isSensitiveKey(k) {
+			out[k] = maskedValue
+			continue
+		}
+		if sub, ok := v.(map[string]interface{}); ok {
+			out[k] = maskSensitiveValues(sub)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// isSensitiveKey tells if the value of a parameter named key must not be written in cassettes
+func isSensitiveKey(key string) bool {
+	lowered := strings.ToLower(key)
+	for _, v := range sensitiveKeys {
+		if strings.Contains(lowered, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostList decodes a list of hosts, initializing their properties
+type hostList []*abstract.Host
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *hostList) UnmarshalJSON(b []byte) error {
+	var raws []json.RawMessage
+	err := json.Unmarshal(b, &raws)
+	if err != nil {
+		return err
+	}
+	if raws == nil {
+		*l = nil
+		return nil
+	}
+
+	list := make(hostList, 0, len(raws))
+	for _, raw := range raws {
+		item := abstract.NewHost()
+		err = json.Unmarshal(raw, &item)
+		if err != nil {
+			return err
+		}
+		list = append(list, item)
+	}
+	*l = list
+	return nil
+}
+
+// networkList decodes a list of networks, initializing their properties
+type networkList []*abstract.Network
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *networkList) UnmarshalJSON(b []byte) error {
+	var raws []json.RawMessage
+	err := json.Unmarshal(b, &raws)
+	if err != nil {
+		return err
+	}
+	if raws == nil {
+		*l = nil
+		return nil
+	}
+
+	list := make(networkList, 0, len(raws))
+	for _, raw := range raws {
+		item := abstract.NewNetwork()
+		err = json.Unmarshal(raw, &item)
+		if err != nil {
+			return err
+		}
+		list = append(list, item)
+	}
+	*l = list
+	return nil
+}
+
+// volumeList decodes a list of volumes, initializing their properties
+type volumeList []abstract.Volume
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *volumeList) UnmarshalJSON(b []byte) error {
+	var raws []json.RawMessage
+	err := json.Unmarshal(b, &raws)
+	if err != nil {
+		return err
+	}
+	if raws == nil {
+		*l = nil
+		return nil
+	}
+
+	list := make(volumeList, 0, len(raws))
+	for _, raw := range raws {
+		item := abstract.NewVolume()
+		err = json.Unmarshal(raw, item)
+		if err != nil {
+			return err
+		}
+		list = append(list, *item)
+	}
+	*l = list
+	return nil
+}
@@ -96,6 +96,18 @@ func (w LoggedProvider) GetImage(id string) (*abstract.Image, fail.Error) {
 	return w.InnerProvider.GetImage(id)
 }
 
+// CreateImageFromHost ...
+func (w LoggedProvider) CreateImageFromHost(hostID string, name string) (*abstract.Image, fail.Error) {
+	defer w.prepare(w.trace("CreateImageFromHost"))
+	return w.InnerProvider.CreateImageFromHost(hostID, name)
+}
+
+// DeleteImage ...
+func (w LoggedProvider) DeleteImage(id string) fail.Error {
+	defer w.prepare(w.trace("DeleteImage"))
+	return w.InnerProvider.DeleteImage(id)
+}
+
 // GetTemplate ...
 func (w LoggedProvider) GetTemplate(id string) (*abstract.HostTemplate, fail.Error) {
 	defer w.prepare(w.trace("GetTemplate"))
@@ -198,6 +210,66 @@ func (w LoggedProvider) DeleteVIP(vip *abstract.VirtualIP) error {
 	return w.InnerProvider.DeleteVIP(vip)
 }
 
+// ListSecurityGroups ...
+func (w LoggedProvider) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	defer w.prepare(w.trace("ListSecurityGroups"))
+	return w.InnerProvider.ListSecurityGroups()
+}
+
+// CreateSecurityGroup ...
+func (w LoggedProvider) CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, fail.Error) {
+	defer w.prepare(w.trace("CreateSecurityGroup"))
+	return w.InnerProvider.CreateSecurityGroup(req)
+}
+
+// InspectSecurityGroup ...
+func (w LoggedProvider) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	defer w.prepare(w.trace("InspectSecurityGroup"))
+	return w.InnerProvider.InspectSecurityGroup(ref)
+}
+
+// DeleteSecurityGroup ...
+func (w LoggedProvider) DeleteSecurityGroup(id string) fail.Error {
+	defer w.prepare(w.trace("DeleteSecurityGroup"))
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w LoggedProvider) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	defer w.prepare(w.trace("AddRuleToSecurityGroup"))
+	return w.InnerProvider.AddRuleToSecurityGroup(sgID, rule)
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w LoggedProvider) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	defer w.prepare(w.trace("DeleteRuleFromSecurityGroup"))
+	return w.InnerProvider.DeleteRuleFromSecurityGroup(sgID, ruleID)
+}
+
+// BindSecurityGroupToHost ...
+func (w LoggedProvider) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	defer w.prepare(w.trace("BindSecurityGroupToHost"))
+	return w.InnerProvider.BindSecurityGroupToHost(sgID, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w LoggedProvider) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	defer w.prepare(w.trace("UnbindSecurityGroupFromHost"))
+	return w.InnerProvider.UnbindSecurityGroupFromHost(sgID, hostID)
+}
+
+// BindSecurityGroupToNetwork ...
+func (w LoggedProvider) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	defer w.prepare(w.trace("BindSecurityGroupToNetwork"))
+	return w.InnerProvider.BindSecurityGroupToNetwork(sgID, networkID)
+}
+
+// UnbindSecurityGroupFromNetwork ...
+func (w LoggedProvider) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	defer w.prepare(w.trace("UnbindSecurityGroupFromNetwork"))
+	return w.InnerProvider.UnbindSecurityGroupFromNetwork(sgID, networkID)
+}
+
 // CreateHost ...
 func (w LoggedProvider) CreateHost(request abstract.HostRequest) (*abstract.Host, *userdata.Content, fail.Error) {
 	defer w.prepare(w.trace("CreateHost"))
@@ -282,6 +354,30 @@ func (w LoggedProvider) DeleteVolume(id string) error {
 	return w.InnerProvider.DeleteVolume(id)
 }
 
+// CreateVolumeSnapshot ...
+func (w LoggedProvider) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	defer w.prepare(w.trace("CreateVolumeSnapshot"))
+	return w.InnerProvider.CreateVolumeSnapshot(request)
+}
+
+// GetVolumeSnapshot ...
+func (w LoggedProvider) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	defer w.prepare(w.trace("GetVolumeSnapshot"))
+	return w.InnerProvider.GetVolumeSnapshot(id)
+}
+
+// ListVolumeSnapshots ...
+func (w LoggedProvider) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	defer w.prepare(w.trace("ListVolumeSnapshots"))
+	return w.InnerProvider.ListVolumeSnapshots()
+}
+
+// DeleteVolumeSnapshot ...
+func (w LoggedProvider) DeleteVolumeSnapshot(id string) fail.Error {
+	defer w.prepare(w.trace("DeleteVolumeSnapshot"))
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
 // CreateVolumeAttachment ...
 func (w LoggedProvider) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	defer w.prepare(w.trace("CreateVolumeAttachment"))
@@ -306,6 +402,18 @@ func (w LoggedProvider) DeleteVolumeAttachment(serverID, id string) error {
 	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
 }
 
+// GetQuotas ...
+func (w LoggedProvider) GetQuotas() (*abstract.Quotas, fail.Error) {
+	defer w.prepare(w.trace("GetQuotas"))
+	return w.InnerProvider.GetQuotas()
+}
+
+// GetUsage ...
+func (w LoggedProvider) GetUsage() (*abstract.Usage, fail.Error) {
+	defer w.prepare(w.trace("GetUsage"))
+	return w.InnerProvider.GetUsage()
+}
+
 // GetCapabilities returns the capabilities of the provider
 func (w LoggedProvider) GetCapabilities() providers.Capabilities {
 	defer w.prepare(w.trace("Getcapabilities"))
@@ -0,0 +1,491 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/userdata"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// RecordingProvider forwards the calls to the inner provider and records each of them (method, arguments, results
+// and error) in a cassette file, that can be served later by a ReplayingProvider
+// The cassette file is rewritten after each call, so it stays usable if the recording session is interrupted
+type RecordingProvider struct {
+	WrappedProvider
+	path     string
+	lock     sync.Mutex
+	cassette Cassette
+}
+
+// NewRecordingProvider creates a RecordingProvider around the (already built) innerProvider, writing cassette in path
+func NewRecordingProvider(innerProvider Provider, name string, path string) (*RecordingProvider, fail.Error) {
+	if innerProvider == nil {
+		return nil, fail.InvalidParameterError("innerProvider", "cannot be nil")
+	}
+	if path == "" {
+		return nil, fail.InvalidParameterError("path", "cannot be empty string")
+	}
+
+	authOpts, xerr := innerProvider.GetAuthenticationOptions()
+	if xerr != nil {
+		return nil, xerr
+	}
+	cfgOpts, xerr := innerProvider.GetConfigurationOptions()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	w := &RecordingProvider{
+		WrappedProvider: WrappedProvider{InnerProvider: innerProvider, Name: name},
+		path:            path,
+		cassette: Cassette{
+			Provider:              innerProvider.GetName(),
+			Capabilities:          innerProvider.GetCapabilities(),
+			AuthenticationOptions: configToMap(authOpts),
+			ConfigurationOptions:  configToMap(cfgOpts),
+			TenantParameters:      maskSensitiveValues(innerProvider.GetTenantParameters()),
+			Interactions:          []Interaction{},
+		},
+	}
+	return w, w.cassette.Save(path)
+}
+
+// record appends the call of method to the cassette and saves it
+func (w *RecordingProvider) record(method string, args []interface{}, results []interface{}, xerr *fail.Error) {
+	interaction := Interaction{
+		Method:  method,
+		Args:    make([]json.RawMessage, 0, len(args)),
+		Results: make([]json.RawMessage, 0, len(results)),
+	}
+	for _, v := range args {
+		interaction.Args = append(interaction.Args, w.marshal(method, v))
+	}
+	for _, v := range results {
+		interaction.Results = append(interaction.Results, w.marshal(method, v))
+	}
+	if xerr != nil {
+		interaction.Error = newRecordedError(*xerr)
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.cassette.Interactions = append(w.cassette.Interactions, interaction)
+	if err := w.cassette.Save(w.path); err != nil {
+		logrus.Errorf("failed to record call of '%s': %v", method, err)
+	}
+}
+
+// marshal encodes value, recording null if it cannot be encoded
+func (w *RecordingProvider) marshal(method string, value interface{}) json.RawMessage {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		logrus.Warnf("failed to encode a value exchanged with '%s', recording null: %v", method, err)
+		return json.RawMessage("null")
+	}
+	return raw
+}
+
+// Provider specific functions
+
+// Build returns the RecordingProvider itself, the inner provider being already built
+func (w *RecordingProvider) Build(something map[string]interface{}) (Provider, fail.Error) {
+	return w, nil
+}
+
+// GetAuthenticationOptions ...
+func (w *RecordingProvider) GetAuthenticationOptions() (providers.Config, fail.Error) {
+	return w.InnerProvider.GetAuthenticationOptions()
+}
+
+// GetConfigurationOptions ...
+func (w *RecordingProvider) GetConfigurationOptions() (providers.Config, fail.Error) {
+	return w.InnerProvider.GetConfigurationOptions()
+}
+
+// GetName ...
+func (w *RecordingProvider) GetName() string {
+	return w.InnerProvider.GetName()
+}
+
+// GetCapabilities ...
+func (w *RecordingProvider) GetCapabilities() providers.Capabilities {
+	return w.InnerProvider.GetCapabilities()
+}
+
+// GetTenantParameters ...
+func (w *RecordingProvider) GetTenantParameters() map[string]interface{} {
+	return w.InnerProvider.GetTenantParameters()
+}
+
+// Stack specific functions
+
+// ListImages ...
+func (w *RecordingProvider) ListImages(all bool) (res []abstract.Image, xerr fail.Error) {
+	defer w.record("ListImages", []interface{}{all}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListImages(all)
+}
+
+// ListTemplates ...
+func (w *RecordingProvider) ListTemplates(all bool) (res []abstract.HostTemplate, xerr fail.Error) {
+	defer w.record("ListTemplates", []interface{}{all}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListTemplates(all)
+}
+
+// ListAvailabilityZones ...
+func (w *RecordingProvider) ListAvailabilityZones() (res map[string]bool, xerr fail.Error) {
+	defer w.record("ListAvailabilityZones", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListAvailabilityZones()
+}
+
+// ListRegions ...
+func (w *RecordingProvider) ListRegions() (res []string, xerr fail.Error) {
+	defer w.record("ListRegions", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListRegions()
+}
+
+// GetImage ...
+func (w *RecordingProvider) GetImage(id string) (res *abstract.Image, xerr fail.Error) {
+	defer w.record("GetImage", []interface{}{id}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetImage(id)
+}
+
+// CreateImageFromHost ...
+func (w *RecordingProvider) CreateImageFromHost(hostID string, name string) (res *abstract.Image, xerr fail.Error) {
+	defer w.record("CreateImageFromHost", []interface{}{hostID, name}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.CreateImageFromHost(hostID, name)
+}
+
+// DeleteImage ...
+func (w *RecordingProvider) DeleteImage(id string) (xerr fail.Error) {
+	defer w.record("DeleteImage", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.DeleteImage(id)
+}
+
+// GetTemplate ...
+func (w *RecordingProvider) GetTemplate(id string) (res *abstract.HostTemplate, xerr fail.Error) {
+	defer w.record("GetTemplate", []interface{}{id}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetTemplate(id)
+}
+
+// CreateKeyPair ...
+func (w *RecordingProvider) CreateKeyPair(name string) (res *abstract.KeyPair, xerr fail.Error) {
+	defer w.record("CreateKeyPair", []interface{}{name}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.CreateKeyPair(name)
+}
+
+// GetKeyPair ...
+func (w *RecordingProvider) GetKeyPair(id string) (res *abstract.KeyPair, xerr fail.Error) {
+	defer w.record("GetKeyPair", []interface{}{id}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetKeyPair(id)
+}
+
+// ListKeyPairs ...
+func (w *RecordingProvider) ListKeyPairs() (res []abstract.KeyPair, xerr fail.Error) {
+	defer w.record("ListKeyPairs", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListKeyPairs()
+}
+
+// DeleteKeyPair ...
+func (w *RecordingProvider) DeleteKeyPair(id string) (xerr fail.Error) {
+	defer w.record("DeleteKeyPair", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.DeleteKeyPair(id)
+}
+
+// CreateNetwork ...
+func (w *RecordingProvider) CreateNetwork(req abstract.NetworkRequest) (res *abstract.Network, xerr fail.Error) {
+	defer w.record("CreateNetwork", []interface{}{req}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.CreateNetwork(req)
+}
+
+// GetNetwork ...
+func (w *RecordingProvider) GetNetwork(id string) (res *abstract.Network, xerr fail.Error) {
+	defer w.record("GetNetwork", []interface{}{id}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetNetwork(id)
+}
+
+// GetNetworkByName ...
+func (w *RecordingProvider) GetNetworkByName(name string) (res *abstract.Network, xerr fail.Error) {
+	defer w.record("GetNetworkByName", []interface{}{name}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetNetworkByName(name)
+}
+
+// ListNetworks ...
+func (w *RecordingProvider) ListNetworks() (res []*abstract.Network, xerr fail.Error) {
+	defer w.record("ListNetworks", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListNetworks()
+}
+
+// DeleteNetwork ...
+func (w *RecordingProvider) DeleteNetwork(id string) (xerr fail.Error) {
+	defer w.record("DeleteNetwork", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.DeleteNetwork(id)
+}
+
+// CreateGateway ...
+func (w *RecordingProvider) CreateGateway(req abstract.GatewayRequest, sizing *abstract.SizingRequirements) (res0 *abstract.Host, res1 *userdata.Content, xerr fail.Error) {
+	defer w.record("CreateGateway", []interface{}{req, sizing}, []interface{}{&res0, &res1}, &xerr)
+	return w.InnerProvider.CreateGateway(req, sizing)
+}
+
+// DeleteGateway ...
+func (w *RecordingProvider) DeleteGateway(networkID string) (xerr fail.Error) {
+	defer w.record("DeleteGateway", []interface{}{networkID}, nil, &xerr)
+	return w.InnerProvider.DeleteGateway(networkID)
+}
+
+// CreateVIP ...
+func (w *RecordingProvider) CreateVIP(networkID string, name string) (res *abstract.VirtualIP, xerr fail.Error) {
+	defer w.record("CreateVIP", []interface{}{networkID, name}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.CreateVIP(networkID, name)
+}
+
+// AddPublicIPToVIP ...
+func (w *RecordingProvider) AddPublicIPToVIP(vip *abstract.VirtualIP) (xerr fail.Error) {
+	defer w.record("AddPublicIPToVIP", []interface{}{vip}, nil, &xerr)
+	return w.InnerProvider.AddPublicIPToVIP(vip)
+}
+
+// BindHostToVIP ...
+func (w *RecordingProvider) BindHostToVIP(vip *abstract.VirtualIP, hostID string) (xerr fail.Error) {
+	defer w.record("BindHostToVIP", []interface{}{vip, hostID}, nil, &xerr)
+	return w.InnerProvider.BindHostToVIP(vip, hostID)
+}
+
+// UnbindHostFromVIP ...
+func (w *RecordingProvider) UnbindHostFromVIP(vip *abstract.VirtualIP, hostID string) (xerr fail.Error) {
+	defer w.record("UnbindHostFromVIP", []interface{}{vip, hostID}, nil, &xerr)
+	return w.InnerProvider.UnbindHostFromVIP(vip, hostID)
+}
+
+// DeleteVIP ...
+func (w *RecordingProvider) DeleteVIP(vip *abstract.VirtualIP) (xerr fail.Error) {
+	defer w.record("DeleteVIP", []interface{}{vip}, nil, &xerr)
+	return w.InnerProvider.DeleteVIP(vip)
+}
+
+// ListSecurityGroups ...
+func (w *RecordingProvider) ListSecurityGroups() (res []*abstract.SecurityGroup, xerr fail.Error) {
+	defer w.record("ListSecurityGroups", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListSecurityGroups()
+}
+
+// CreateSecurityGroup ...
+func (w *RecordingProvider) CreateSecurityGroup(req abstract.SecurityGroupRequest) (res *abstract.SecurityGroup, xerr fail.Error) {
+	defer w.record("CreateSecurityGroup", []interface{}{req}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.CreateSecurityGroup(req)
+}
+
+// InspectSecurityGroup ...
+func (w *RecordingProvider) InspectSecurityGroup(ref string) (res *abstract.SecurityGroup, xerr fail.Error) {
+	defer w.record("InspectSecurityGroup", []interface{}{ref}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.InspectSecurityGroup(ref)
+}
+
+// DeleteSecurityGroup ...
+func (w *RecordingProvider) DeleteSecurityGroup(id string) (xerr fail.Error) {
+	defer w.record("DeleteSecurityGroup", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w *RecordingProvider) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (res *abstract.SecurityGroup, xerr fail.Error) {
+	defer w.record("AddRuleToSecurityGroup", []interface{}{sgID, rule}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.AddRuleToSecurityGroup(sgID, rule)
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w *RecordingProvider) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (res *abstract.SecurityGroup, xerr fail.Error) {
+	defer w.record("DeleteRuleFromSecurityGroup", []interface{}{sgID, ruleID}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.DeleteRuleFromSecurityGroup(sgID, ruleID)
+}
+
+// BindSecurityGroupToHost ...
+func (w *RecordingProvider) BindSecurityGroupToHost(sgID string, hostID string) (xerr fail.Error) {
+	defer w.record("BindSecurityGroupToHost", []interface{}{sgID, hostID}, nil, &xerr)
+	return w.InnerProvider.BindSecurityGroupToHost(sgID, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w *RecordingProvider) UnbindSecurityGroupFromHost(sgID string, hostID string) (xerr fail.Error) {
+	defer w.record("UnbindSecurityGroupFromHost", []interface{}{sgID, hostID}, nil, &xerr)
+	return w.InnerProvider.UnbindSecurityGroupFromHost(sgID, hostID)
+}
+
+// BindSecurityGroupToNetwork ...
+func (w *RecordingProvider) BindSecurityGroupToNetwork(sgID string, networkID string) (xerr fail.Error) {
+	defer w.record("BindSecurityGroupToNetwork", []interface{}{sgID, networkID}, nil, &xerr)
+	return w.InnerProvider.BindSecurityGroupToNetwork(sgID, networkID)
+}
+
+// UnbindSecurityGroupFromNetwork ...
+func (w *RecordingProvider) UnbindSecurityGroupFromNetwork(sgID string, networkID string) (xerr fail.Error) {
+	defer w.record("UnbindSecurityGroupFromNetwork", []interface{}{sgID, networkID}, nil, &xerr)
+	return w.InnerProvider.UnbindSecurityGroupFromNetwork(sgID, networkID)
+}
+
+// CreateHost ...
+func (w *RecordingProvider) CreateHost(request abstract.HostRequest) (res0 *abstract.Host, res1 *userdata.Content, xerr fail.Error) {
+	defer w.record("CreateHost", []interface{}{request}, []interface{}{&res0, &res1}, &xerr)
+	return w.InnerProvider.CreateHost(request)
+}
+
+// InspectHost ...
+func (w *RecordingProvider) InspectHost(hostParam interface{}) (res *abstract.Host, xerr fail.Error) {
+	defer w.record("InspectHost", []interface{}{hostParam}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.InspectHost(hostParam)
+}
+
+// GetHostByName ...
+func (w *RecordingProvider) GetHostByName(name string) (res *abstract.Host, xerr fail.Error) {
+	defer w.record("GetHostByName", []interface{}{name}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetHostByName(name)
+}
+
+// GetHostState ...
+func (w *RecordingProvider) GetHostState(hostParam interface{}) (res hoststate.Enum, xerr fail.Error) {
+	defer w.record("GetHostState", []interface{}{hostParam}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetHostState(hostParam)
+}
+
+// ListHosts ...
+func (w *RecordingProvider) ListHosts() (res []*abstract.Host, xerr fail.Error) {
+	defer w.record("ListHosts", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListHosts()
+}
+
+// DeleteHost ...
+func (w *RecordingProvider) DeleteHost(id string) (xerr fail.Error) {
+	defer w.record("DeleteHost", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.DeleteHost(id)
+}
+
+// StopHost ...
+func (w *RecordingProvider) StopHost(id string) (xerr fail.Error) {
+	defer w.record("StopHost", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.StopHost(id)
+}
+
+// StartHost ...
+func (w *RecordingProvider) StartHost(id string) (xerr fail.Error) {
+	defer w.record("StartHost", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.StartHost(id)
+}
+
+// RebootHost ...
+func (w *RecordingProvider) RebootHost(id string) (xerr fail.Error) {
+	defer w.record("RebootHost", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.RebootHost(id)
+}
+
+// ResizeHost ...
+func (w *RecordingProvider) ResizeHost(id string, request abstract.SizingRequirements) (res *abstract.Host, xerr fail.Error) {
+	defer w.record("ResizeHost", []interface{}{id, request}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ResizeHost(id, request)
+}
+
+// CreateVolume ...
+func (w *RecordingProvider) CreateVolume(request abstract.VolumeRequest) (res *abstract.Volume, xerr fail.Error) {
+	defer w.record("CreateVolume", []interface{}{request}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.CreateVolume(request)
+}
+
+// GetVolume ...
+func (w *RecordingProvider) GetVolume(id string) (res *abstract.Volume, xerr fail.Error) {
+	defer w.record("GetVolume", []interface{}{id}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetVolume(id)
+}
+
+// ListVolumes ...
+func (w *RecordingProvider) ListVolumes() (res []abstract.Volume, xerr fail.Error) {
+	defer w.record("ListVolumes", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListVolumes()
+}
+
+// DeleteVolume ...
+func (w *RecordingProvider) DeleteVolume(id string) (xerr fail.Error) {
+	defer w.record("DeleteVolume", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.DeleteVolume(id)
+}
+
+// CreateVolumeSnapshot ...
+func (w *RecordingProvider) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (res *abstract.VolumeSnapshot, xerr fail.Error) {
+	defer w.record("CreateVolumeSnapshot", []interface{}{request}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.CreateVolumeSnapshot(request)
+}
+
+// GetVolumeSnapshot ...
+func (w *RecordingProvider) GetVolumeSnapshot(id string) (res *abstract.VolumeSnapshot, xerr fail.Error) {
+	defer w.record("GetVolumeSnapshot", []interface{}{id}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetVolumeSnapshot(id)
+}
+
+// ListVolumeSnapshots ...
+func (w *RecordingProvider) ListVolumeSnapshots() (res []abstract.VolumeSnapshot, xerr fail.Error) {
+	defer w.record("ListVolumeSnapshots", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListVolumeSnapshots()
+}
+
+// DeleteVolumeSnapshot ...
+func (w *RecordingProvider) DeleteVolumeSnapshot(id string) (xerr fail.Error) {
+	defer w.record("DeleteVolumeSnapshot", []interface{}{id}, nil, &xerr)
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
+// CreateVolumeAttachment ...
+func (w *RecordingProvider) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (res string, xerr fail.Error) {
+	defer w.record("CreateVolumeAttachment", []interface{}{request}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.CreateVolumeAttachment(request)
+}
+
+// GetVolumeAttachment ...
+func (w *RecordingProvider) GetVolumeAttachment(serverID, id string) (res *abstract.VolumeAttachment, xerr fail.Error) {
+	defer w.record("GetVolumeAttachment", []interface{}{serverID, id}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetVolumeAttachment(serverID, id)
+}
+
+// ListVolumeAttachments ...
+func (w *RecordingProvider) ListVolumeAttachments(serverID string) (res []abstract.VolumeAttachment, xerr fail.Error) {
+	defer w.record("ListVolumeAttachments", []interface{}{serverID}, []interface{}{&res}, &xerr)
+	return w.InnerProvider.ListVolumeAttachments(serverID)
+}
+
+// DeleteVolumeAttachment ...
+func (w *RecordingProvider) DeleteVolumeAttachment(serverID, id string) (xerr fail.Error) {
+	defer w.record("DeleteVolumeAttachment", []interface{}{serverID, id}, nil, &xerr)
+	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
+}
+
+// GetQuotas ...
+func (w *RecordingProvider) GetQuotas() (res *abstract.Quotas, xerr fail.Error) {
+	defer w.record("GetQuotas", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetQuotas()
+}
+
+// GetUsage ...
+func (w *RecordingProvider) GetUsage() (res *abstract.Usage, xerr fail.Error) {
+	defer w.record("GetUsage", nil, []interface{}{&res}, &xerr)
+	return w.InnerProvider.GetUsage()
+}
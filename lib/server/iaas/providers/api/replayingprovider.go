@@ -0,0 +1,529 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/userdata"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// ReplayingProvider serves the calls recorded in a cassette by a RecordingProvider, without contacting any provider
+// The interactions are served in the order of the recording, method by method: the arguments of a call are not
+// compared with the recorded ones, as some of them are random (passwords, names, ...)
+type ReplayingProvider struct {
+	Name     string
+	cassette *Cassette
+	lock     sync.Mutex
+	pending  map[string][]Interaction
+}
+
+// NewReplayingProvider creates a ReplayingProvider serving the cassette stored in path
+func NewReplayingProvider(name string, path string) (*ReplayingProvider, fail.Error) {
+	cassette, xerr := LoadCassette(path)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	w := &ReplayingProvider{
+		Name:     name,
+		cassette: cassette,
+		pending:  map[string][]Interaction{},
+	}
+	for _, v := range cassette.Interactions {
+		w.pending[v.Method] = append(w.pending[v.Method], v)
+	}
+	return w, nil
+}
+
+// Remaining returns the number of recorded interactions not served yet
+func (w *ReplayingProvider) Remaining() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	count := 0
+	for _, v := range w.pending {
+		count += len(v)
+	}
+	return count
+}
+
+// replay decodes the results of the next recorded call of method in targets, and returns the recorded error
+// targets are pointers to the variables receiving the results; they are zeroed if the call cannot be replayed
+func (w *ReplayingProvider) replay(method string, targets ...interface{}) fail.Error {
+	interaction, xerr := w.next(method)
+	if xerr == nil {
+		xerr = interaction.decode(targets...)
+	}
+	if xerr != nil {
+		for _, v := range targets {
+			target := reflect.ValueOf(v).Elem()
+			target.Set(reflect.Zero(target.Type()))
+		}
+		return xerr
+	}
+	return interaction.Error.ToError()
+}
+
+// next pops the next recorded call of method
+func (w *ReplayingProvider) next(method string) (Interaction, fail.Error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	queue := w.pending[method]
+	if len(queue) == 0 {
+		return Interaction{}, fail.InconsistentError(fmt.Sprintf("no more recorded call of '%s' in cassette", method))
+	}
+	w.pending[method] = queue[1:]
+	return queue[0], nil
+}
+
+// decode decodes the results of the interaction in targets
+func (i Interaction) decode(targets ...interface{}) fail.Error {
+	if len(i.Results) != len(targets) {
+		return fail.InconsistentError(
+			fmt.Sprintf(
+				"recorded call of '%s' has %d results, %d expected", i.Method, len(i.Results), len(targets),
+			),
+		)
+	}
+	for k, v := range targets {
+		err := json.Unmarshal(i.Results[k], v)
+		if err != nil {
+			return fail.InconsistentError(
+				fmt.Sprintf("failed to decode result #%d of recorded call of '%s': %v", k, i.Method, err),
+			)
+		}
+	}
+	return nil
+}
+
+// Provider specific functions
+
+// Build returns the ReplayingProvider itself
+func (w *ReplayingProvider) Build(something map[string]interface{}) (Provider, fail.Error) {
+	return w, nil
+}
+
+// GetAuthenticationOptions returns the authentication options recorded (sensitive values are masked)
+func (w *ReplayingProvider) GetAuthenticationOptions() (providers.Config, fail.Error) {
+	return mapToConfig(w.cassette.AuthenticationOptions), nil
+}
+
+// GetConfigurationOptions returns the configuration options recorded
+func (w *ReplayingProvider) GetConfigurationOptions() (providers.Config, fail.Error) {
+	return mapToConfig(w.cassette.ConfigurationOptions), nil
+}
+
+// GetName returns the name of the provider recorded
+func (w *ReplayingProvider) GetName() string {
+	return w.cassette.Provider
+}
+
+// GetCapabilities returns the capabilities recorded
+// The hosts replayed do not exist, so they are always reported as simulated
+func (w *ReplayingProvider) GetCapabilities() providers.Capabilities {
+	capabilities := w.cassette.Capabilities
+	capabilities.SimulatedHosts = true
+	return capabilities
+}
+
+// GetTenantParameters returns the tenant parameters recorded (sensitive values are masked)
+func (w *ReplayingProvider) GetTenantParameters() map[string]interface{} {
+	return w.cassette.TenantParameters
+}
+
+// Stack specific functions
+
+// ListImages ...
+func (w *ReplayingProvider) ListImages(all bool) ([]abstract.Image, fail.Error) {
+	var res []abstract.Image
+	xerr := w.replay("ListImages", &res)
+	return res, xerr
+}
+
+// ListTemplates ...
+func (w *ReplayingProvider) ListTemplates(all bool) ([]abstract.HostTemplate, fail.Error) {
+	var res []abstract.HostTemplate
+	xerr := w.replay("ListTemplates", &res)
+	return res, xerr
+}
+
+// ListAvailabilityZones ...
+func (w *ReplayingProvider) ListAvailabilityZones() (map[string]bool, fail.Error) {
+	var res map[string]bool
+	xerr := w.replay("ListAvailabilityZones", &res)
+	return res, xerr
+}
+
+// ListRegions ...
+func (w *ReplayingProvider) ListRegions() ([]string, fail.Error) {
+	var res []string
+	xerr := w.replay("ListRegions", &res)
+	return res, xerr
+}
+
+// GetImage ...
+func (w *ReplayingProvider) GetImage(id string) (*abstract.Image, fail.Error) {
+	var res *abstract.Image
+	xerr := w.replay("GetImage", &res)
+	return res, xerr
+}
+
+// CreateImageFromHost ...
+func (w *ReplayingProvider) CreateImageFromHost(hostID string, name string) (*abstract.Image, fail.Error) {
+	var res *abstract.Image
+	xerr := w.replay("CreateImageFromHost", &res)
+	return res, xerr
+}
+
+// DeleteImage ...
+func (w *ReplayingProvider) DeleteImage(id string) fail.Error {
+	return w.replay("DeleteImage")
+}
+
+// GetTemplate ...
+func (w *ReplayingProvider) GetTemplate(id string) (*abstract.HostTemplate, fail.Error) {
+	var res *abstract.HostTemplate
+	xerr := w.replay("GetTemplate", &res)
+	return res, xerr
+}
+
+// CreateKeyPair ...
+func (w *ReplayingProvider) CreateKeyPair(name string) (*abstract.KeyPair, fail.Error) {
+	var res *abstract.KeyPair
+	xerr := w.replay("CreateKeyPair", &res)
+	return res, xerr
+}
+
+// GetKeyPair ...
+func (w *ReplayingProvider) GetKeyPair(id string) (*abstract.KeyPair, fail.Error) {
+	var res *abstract.KeyPair
+	xerr := w.replay("GetKeyPair", &res)
+	return res, xerr
+}
+
+// ListKeyPairs ...
+func (w *ReplayingProvider) ListKeyPairs() ([]abstract.KeyPair, fail.Error) {
+	var res []abstract.KeyPair
+	xerr := w.replay("ListKeyPairs", &res)
+	return res, xerr
+}
+
+// DeleteKeyPair ...
+func (w *ReplayingProvider) DeleteKeyPair(id string) fail.Error {
+	return w.replay("DeleteKeyPair")
+}
+
+// CreateNetwork ...
+func (w *ReplayingProvider) CreateNetwork(req abstract.NetworkRequest) (*abstract.Network, fail.Error) {
+	res := abstract.NewNetwork()
+	xerr := w.replay("CreateNetwork", &res)
+	return res, xerr
+}
+
+// GetNetwork ...
+func (w *ReplayingProvider) GetNetwork(id string) (*abstract.Network, fail.Error) {
+	res := abstract.NewNetwork()
+	xerr := w.replay("GetNetwork", &res)
+	return res, xerr
+}
+
+// GetNetworkByName ...
+func (w *ReplayingProvider) GetNetworkByName(name string) (*abstract.Network, fail.Error) {
+	res := abstract.NewNetwork()
+	xerr := w.replay("GetNetworkByName", &res)
+	return res, xerr
+}
+
+// ListNetworks ...
+func (w *ReplayingProvider) ListNetworks() ([]*abstract.Network, fail.Error) {
+	var res networkList
+	xerr := w.replay("ListNetworks", &res)
+	return []*abstract.Network(res), xerr
+}
+
+// DeleteNetwork ...
+func (w *ReplayingProvider) DeleteNetwork(id string) fail.Error {
+	return w.replay("DeleteNetwork")
+}
+
+// CreateGateway ...
+func (w *ReplayingProvider) CreateGateway(req abstract.GatewayRequest, sizing *abstract.SizingRequirements) (*abstract.Host, *userdata.Content, fail.Error) {
+	var res1 *userdata.Content
+	res0 := abstract.NewHost()
+	xerr := w.replay("CreateGateway", &res0, &res1)
+	return res0, res1, xerr
+}
+
+// DeleteGateway ...
+func (w *ReplayingProvider) DeleteGateway(networkID string) fail.Error {
+	return w.replay("DeleteGateway")
+}
+
+// CreateVIP ...
+func (w *ReplayingProvider) CreateVIP(networkID string, name string) (*abstract.VirtualIP, fail.Error) {
+	var res *abstract.VirtualIP
+	xerr := w.replay("CreateVIP", &res)
+	return res, xerr
+}
+
+// AddPublicIPToVIP ...
+func (w *ReplayingProvider) AddPublicIPToVIP(vip *abstract.VirtualIP) fail.Error {
+	return w.replay("AddPublicIPToVIP")
+}
+
+// BindHostToVIP ...
+func (w *ReplayingProvider) BindHostToVIP(vip *abstract.VirtualIP, hostID string) fail.Error {
+	return w.replay("BindHostToVIP")
+}
+
+// UnbindHostFromVIP ...
+func (w *ReplayingProvider) UnbindHostFromVIP(vip *abstract.VirtualIP, hostID string) fail.Error {
+	return w.replay("UnbindHostFromVIP")
+}
+
+// DeleteVIP ...
+func (w *ReplayingProvider) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
+	return w.replay("DeleteVIP")
+}
+
+// ListSecurityGroups ...
+func (w *ReplayingProvider) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	var res []*abstract.SecurityGroup
+	xerr := w.replay("ListSecurityGroups", &res)
+	return res, xerr
+}
+
+// CreateSecurityGroup ...
+func (w *ReplayingProvider) CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, fail.Error) {
+	var res *abstract.SecurityGroup
+	xerr := w.replay("CreateSecurityGroup", &res)
+	return res, xerr
+}
+
+// InspectSecurityGroup ...
+func (w *ReplayingProvider) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	var res *abstract.SecurityGroup
+	xerr := w.replay("InspectSecurityGroup", &res)
+	return res, xerr
+}
+
+// DeleteSecurityGroup ...
+func (w *ReplayingProvider) DeleteSecurityGroup(id string) fail.Error {
+	return w.replay("DeleteSecurityGroup")
+}
+
+// AddRuleToSecurityGroup ...
+func (w *ReplayingProvider) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	var res *abstract.SecurityGroup
+	xerr := w.replay("AddRuleToSecurityGroup", &res)
+	return res, xerr
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w *ReplayingProvider) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	var res *abstract.SecurityGroup
+	xerr := w.replay("DeleteRuleFromSecurityGroup", &res)
+	return res, xerr
+}
+
+// BindSecurityGroupToHost ...
+func (w *ReplayingProvider) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	return w.replay("BindSecurityGroupToHost")
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w *ReplayingProvider) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	return w.replay("UnbindSecurityGroupFromHost")
+}
+
+// BindSecurityGroupToNetwork ...
+func (w *ReplayingProvider) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	return w.replay("BindSecurityGroupToNetwork")
+}
+
+// UnbindSecurityGroupFromNetwork ...
+func (w *ReplayingProvider) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	return w.replay("UnbindSecurityGroupFromNetwork")
+}
+
+// CreateHost ...
+func (w *ReplayingProvider) CreateHost(request abstract.HostRequest) (*abstract.Host, *userdata.Content, fail.Error) {
+	var res1 *userdata.Content
+	res0 := abstract.NewHost()
+	xerr := w.replay("CreateHost", &res0, &res1)
+	return res0, res1, xerr
+}
+
+// InspectHost ...
+// If hostParam is an *abstract.Host, its content is updated, as providers do
+func (w *ReplayingProvider) InspectHost(hostParam interface{}) (*abstract.Host, fail.Error) {
+	res, ok := hostParam.(*abstract.Host)
+	if !ok || res == nil || res.Properties == nil {
+		res = abstract.NewHost()
+	}
+	xerr := w.replay("InspectHost", &res)
+	return res, xerr
+}
+
+// GetHostByName ...
+func (w *ReplayingProvider) GetHostByName(name string) (*abstract.Host, fail.Error) {
+	res := abstract.NewHost()
+	xerr := w.replay("GetHostByName", &res)
+	return res, xerr
+}
+
+// GetHostState ...
+func (w *ReplayingProvider) GetHostState(hostParam interface{}) (hoststate.Enum, fail.Error) {
+	var res hoststate.Enum
+	xerr := w.replay("GetHostState", &res)
+	return res, xerr
+}
+
+// ListHosts ...
+func (w *ReplayingProvider) ListHosts() ([]*abstract.Host, fail.Error) {
+	var res hostList
+	xerr := w.replay("ListHosts", &res)
+	return []*abstract.Host(res), xerr
+}
+
+// DeleteHost ...
+func (w *ReplayingProvider) DeleteHost(id string) fail.Error {
+	return w.replay("DeleteHost")
+}
+
+// StopHost ...
+func (w *ReplayingProvider) StopHost(id string) fail.Error {
+	return w.replay("StopHost")
+}
+
+// StartHost ...
+func (w *ReplayingProvider) StartHost(id string) fail.Error {
+	return w.replay("StartHost")
+}
+
+// RebootHost ...
+func (w *ReplayingProvider) RebootHost(id string) fail.Error {
+	return w.replay("RebootHost")
+}
+
+// ResizeHost ...
+func (w *ReplayingProvider) ResizeHost(id string, request abstract.SizingRequirements) (*abstract.Host, fail.Error) {
+	res := abstract.NewHost()
+	xerr := w.replay("ResizeHost", &res)
+	return res, xerr
+}
+
+// CreateVolume ...
+func (w *ReplayingProvider) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	res := abstract.NewVolume()
+	xerr := w.replay("CreateVolume", &res)
+	return res, xerr
+}
+
+// GetVolume ...
+func (w *ReplayingProvider) GetVolume(id string) (*abstract.Volume, fail.Error) {
+	res := abstract.NewVolume()
+	xerr := w.replay("GetVolume", &res)
+	return res, xerr
+}
+
+// ListVolumes ...
+func (w *ReplayingProvider) ListVolumes() ([]abstract.Volume, fail.Error) {
+	var res volumeList
+	xerr := w.replay("ListVolumes", &res)
+	return []abstract.Volume(res), xerr
+}
+
+// DeleteVolume ...
+func (w *ReplayingProvider) DeleteVolume(id string) fail.Error {
+	return w.replay("DeleteVolume")
+}
+
+// CreateVolumeSnapshot ...
+func (w *ReplayingProvider) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	var res *abstract.VolumeSnapshot
+	xerr := w.replay("CreateVolumeSnapshot", &res)
+	return res, xerr
+}
+
+// GetVolumeSnapshot ...
+func (w *ReplayingProvider) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	var res *abstract.VolumeSnapshot
+	xerr := w.replay("GetVolumeSnapshot", &res)
+	return res, xerr
+}
+
+// ListVolumeSnapshots ...
+func (w *ReplayingProvider) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	var res []abstract.VolumeSnapshot
+	xerr := w.replay("ListVolumeSnapshots", &res)
+	return res, xerr
+}
+
+// DeleteVolumeSnapshot ...
+func (w *ReplayingProvider) DeleteVolumeSnapshot(id string) fail.Error {
+	return w.replay("DeleteVolumeSnapshot")
+}
+
+// CreateVolumeAttachment ...
+func (w *ReplayingProvider) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
+	var res string
+	xerr := w.replay("CreateVolumeAttachment", &res)
+	return res, xerr
+}
+
+// GetVolumeAttachment ...
+func (w *ReplayingProvider) GetVolumeAttachment(serverID, id string) (*abstract.VolumeAttachment, fail.Error) {
+	var res *abstract.VolumeAttachment
+	xerr := w.replay("GetVolumeAttachment", &res)
+	return res, xerr
+}
+
+// ListVolumeAttachments ...
+func (w *ReplayingProvider) ListVolumeAttachments(serverID string) ([]abstract.VolumeAttachment, fail.Error) {
+	var res []abstract.VolumeAttachment
+	xerr := w.replay("ListVolumeAttachments", &res)
+	return res, xerr
+}
+
+// DeleteVolumeAttachment ...
+func (w *ReplayingProvider) DeleteVolumeAttachment(serverID, id string) fail.Error {
+	return w.replay("DeleteVolumeAttachment")
+}
+
+// GetQuotas ...
+func (w *ReplayingProvider) GetQuotas() (*abstract.Quotas, fail.Error) {
+	var res *abstract.Quotas
+	xerr := w.replay("GetQuotas", &res)
+	return res, xerr
+}
+
+// GetUsage ...
+func (w *ReplayingProvider) GetUsage() (*abstract.Usage, fail.Error) {
+	var res *abstract.Usage
+	xerr := w.replay("GetUsage", &res)
+	return res, xerr
+}
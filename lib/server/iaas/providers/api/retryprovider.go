@@ -155,6 +155,296 @@ func (w RetryProvider) DeleteVIP(vip *abstract.VirtualIP) (xerr fail.Error) {
 	return xerr
 }
 
+// ListSecurityGroups ...
+func (w RetryProvider) ListSecurityGroups() (res []*abstract.SecurityGroup, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.ListSecurityGroups()
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// CreateSecurityGroup ...
+func (w RetryProvider) CreateSecurityGroup(req abstract.SecurityGroupRequest) (res *abstract.SecurityGroup, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.CreateSecurityGroup(req)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// InspectSecurityGroup ...
+func (w RetryProvider) InspectSecurityGroup(ref string) (res *abstract.SecurityGroup, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.InspectSecurityGroup(ref)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// DeleteSecurityGroup ...
+func (w RetryProvider) DeleteSecurityGroup(id string) (xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			xerr = w.InnerProvider.DeleteSecurityGroup(id)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return xerr
+}
+
+// AddRuleToSecurityGroup ...
+func (w RetryProvider) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (res *abstract.SecurityGroup, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.AddRuleToSecurityGroup(sgID, rule)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w RetryProvider) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (res *abstract.SecurityGroup, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.DeleteRuleFromSecurityGroup(sgID, ruleID)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// BindSecurityGroupToHost ...
+func (w RetryProvider) BindSecurityGroupToHost(sgID string, hostID string) (xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			xerr = w.InnerProvider.BindSecurityGroupToHost(sgID, hostID)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return xerr
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w RetryProvider) UnbindSecurityGroupFromHost(sgID string, hostID string) (xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			xerr = w.InnerProvider.UnbindSecurityGroupFromHost(sgID, hostID)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return xerr
+}
+
+// BindSecurityGroupToNetwork ...
+func (w RetryProvider) BindSecurityGroupToNetwork(sgID string, networkID string) (xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			xerr = w.InnerProvider.BindSecurityGroupToNetwork(sgID, networkID)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return xerr
+}
+
+// UnbindSecurityGroupFromNetwork ...
+func (w RetryProvider) UnbindSecurityGroupFromNetwork(sgID string, networkID string) (xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			xerr = w.InnerProvider.UnbindSecurityGroupFromNetwork(sgID, networkID)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return xerr
+}
+
 func (w RetryProvider) GetCapabilities() providers.Capabilities {
 	return w.InnerProvider.GetCapabilities()
 }
@@ -355,6 +645,64 @@ func (w RetryProvider) GetImage(id string) (res *abstract.Image, xerr fail.Error
 	return res, xerr
 }
 
+// CreateImageFromHost ...
+func (w RetryProvider) CreateImageFromHost(hostID string, name string) (res *abstract.Image, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.CreateImageFromHost(hostID, name)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// DeleteImage ...
+func (w RetryProvider) DeleteImage(id string) (xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			xerr = w.InnerProvider.DeleteImage(id)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return xerr
+}
+
 // GetTemplate ...
 func (w RetryProvider) GetTemplate(id string) (res *abstract.HostTemplate, xerr fail.Error) {
 	retryErr := retry.WhileUnsuccessful(
@@ -1109,6 +1457,122 @@ func (w RetryProvider) DeleteVolume(id string) (xerr fail.Error) {
 	return xerr
 }
 
+// CreateVolumeSnapshot ...
+func (w RetryProvider) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (res *abstract.VolumeSnapshot, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.CreateVolumeSnapshot(request)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// GetVolumeSnapshot ...
+func (w RetryProvider) GetVolumeSnapshot(id string) (res *abstract.VolumeSnapshot, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.GetVolumeSnapshot(id)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// ListVolumeSnapshots ...
+func (w RetryProvider) ListVolumeSnapshots() (res []abstract.VolumeSnapshot, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.ListVolumeSnapshots()
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// DeleteVolumeSnapshot ...
+func (w RetryProvider) DeleteVolumeSnapshot(id string) (xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			xerr = w.InnerProvider.DeleteVolumeSnapshot(id)
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return xerr
+}
+
 // CreateVolumeAttachment ...
 func (w RetryProvider) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (res string, xerr fail.Error) {
 	retryErr := retry.WhileUnsuccessful(
@@ -1224,3 +1688,61 @@ func (w RetryProvider) DeleteVolumeAttachment(serverID, id string) (xerr fail.Er
 
 	return xerr
 }
+
+// GetQuotas ...
+func (w RetryProvider) GetQuotas() (res *abstract.Quotas, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.GetQuotas()
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
+
+// GetUsage ...
+func (w RetryProvider) GetUsage() (res *abstract.Usage, xerr fail.Error) {
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			res, xerr = w.InnerProvider.GetUsage()
+			if xerr != nil {
+				switch xerr.(type) {
+				case fail.ErrTimeout:
+					return xerr
+				case *net.DNSError:
+					return xerr
+				case fail.ErrInvalidRequest:
+					return xerr
+				default:
+					return nil
+				}
+			}
+			return nil
+		},
+		0,
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		return res, retryErr
+	}
+
+	return res, xerr
+}
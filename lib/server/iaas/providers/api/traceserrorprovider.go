@@ -115,6 +115,26 @@ func (w ErrorTraceProvider) GetImage(id string) (images *abstract.Image, xerr fa
 	return w.InnerProvider.GetImage(id)
 }
 
+// CreateImageFromHost ...
+func (w ErrorTraceProvider) CreateImageFromHost(hostID string, name string) (_ *abstract.Image, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:CreateImageFromHost", w.Name))
+	return w.InnerProvider.CreateImageFromHost(hostID, name)
+}
+
+// DeleteImage ...
+func (w ErrorTraceProvider) DeleteImage(id string) (xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:DeleteImage", w.Name))
+	return w.InnerProvider.DeleteImage(id)
+}
+
 // GetTemplate ...
 func (w ErrorTraceProvider) GetTemplate(id string) (templates *abstract.HostTemplate, xerr fail.Error) {
 	defer func(prefix string) {
@@ -285,6 +305,106 @@ func (w ErrorTraceProvider) DeleteVIP(vip *abstract.VirtualIP) (xerr fail.Error)
 	return w.InnerProvider.DeleteVIP(vip)
 }
 
+// ListSecurityGroups ...
+func (w ErrorTraceProvider) ListSecurityGroups() (_ []*abstract.SecurityGroup, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:ListSecurityGroups", w.Name))
+	return w.InnerProvider.ListSecurityGroups()
+}
+
+// CreateSecurityGroup ...
+func (w ErrorTraceProvider) CreateSecurityGroup(req abstract.SecurityGroupRequest) (_ *abstract.SecurityGroup, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:CreateSecurityGroup", w.Name))
+	return w.InnerProvider.CreateSecurityGroup(req)
+}
+
+// InspectSecurityGroup ...
+func (w ErrorTraceProvider) InspectSecurityGroup(ref string) (_ *abstract.SecurityGroup, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:InspectSecurityGroup", w.Name))
+	return w.InnerProvider.InspectSecurityGroup(ref)
+}
+
+// DeleteSecurityGroup ...
+func (w ErrorTraceProvider) DeleteSecurityGroup(id string) (xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:DeleteSecurityGroup", w.Name))
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w ErrorTraceProvider) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (_ *abstract.SecurityGroup, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:AddRuleToSecurityGroup", w.Name))
+	return w.InnerProvider.AddRuleToSecurityGroup(sgID, rule)
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w ErrorTraceProvider) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (_ *abstract.SecurityGroup, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:DeleteRuleFromSecurityGroup", w.Name))
+	return w.InnerProvider.DeleteRuleFromSecurityGroup(sgID, ruleID)
+}
+
+// BindSecurityGroupToHost ...
+func (w ErrorTraceProvider) BindSecurityGroupToHost(sgID string, hostID string) (xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:BindSecurityGroupToHost", w.Name))
+	return w.InnerProvider.BindSecurityGroupToHost(sgID, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w ErrorTraceProvider) UnbindSecurityGroupFromHost(sgID string, hostID string) (xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:UnbindSecurityGroupFromHost", w.Name))
+	return w.InnerProvider.UnbindSecurityGroupFromHost(sgID, hostID)
+}
+
+// BindSecurityGroupToNetwork ...
+func (w ErrorTraceProvider) BindSecurityGroupToNetwork(sgID string, networkID string) (xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:BindSecurityGroupToNetwork", w.Name))
+	return w.InnerProvider.BindSecurityGroupToNetwork(sgID, networkID)
+}
+
+// UnbindSecurityGroupFromNetwork ...
+func (w ErrorTraceProvider) UnbindSecurityGroupFromNetwork(sgID string, networkID string) (xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:UnbindSecurityGroupFromNetwork", w.Name))
+	return w.InnerProvider.UnbindSecurityGroupFromNetwork(sgID, networkID)
+}
+
 // CreateHost ...
 func (w ErrorTraceProvider) CreateHost(request abstract.HostRequest) (_ *abstract.Host, _ *userdata.Content, xerr fail.Error) {
 	defer func(prefix string) {
@@ -425,6 +545,46 @@ func (w ErrorTraceProvider) DeleteVolume(id string) (xerr fail.Error) {
 	return w.InnerProvider.DeleteVolume(id)
 }
 
+// CreateVolumeSnapshot ...
+func (w ErrorTraceProvider) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (_ *abstract.VolumeSnapshot, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:CreateVolumeSnapshot", w.Name))
+	return w.InnerProvider.CreateVolumeSnapshot(request)
+}
+
+// GetVolumeSnapshot ...
+func (w ErrorTraceProvider) GetVolumeSnapshot(id string) (_ *abstract.VolumeSnapshot, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:GetVolumeSnapshot", w.Name))
+	return w.InnerProvider.GetVolumeSnapshot(id)
+}
+
+// ListVolumeSnapshots ...
+func (w ErrorTraceProvider) ListVolumeSnapshots() (_ []abstract.VolumeSnapshot, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:ListVolumeSnapshots", w.Name))
+	return w.InnerProvider.ListVolumeSnapshots()
+}
+
+// DeleteVolumeSnapshot ...
+func (w ErrorTraceProvider) DeleteVolumeSnapshot(id string) (xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:DeleteVolumeSnapshot", w.Name))
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
 // CreateVolumeAttachment ...
 func (w ErrorTraceProvider) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (_ string, xerr fail.Error) {
 	defer func(prefix string) {
@@ -465,6 +625,26 @@ func (w ErrorTraceProvider) DeleteVolumeAttachment(serverID, id string) (xerr fa
 	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
 }
 
+// GetQuotas ...
+func (w ErrorTraceProvider) GetQuotas() (_ *abstract.Quotas, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:GetQuotas", w.Name))
+	return w.InnerProvider.GetQuotas()
+}
+
+// GetUsage ...
+func (w ErrorTraceProvider) GetUsage() (_ *abstract.Usage, xerr fail.Error) {
+	defer func(prefix string) {
+		if xerr != nil {
+			logrus.Debugf("%s : Intercepted error: %v", prefix, xerr)
+		}
+	}(fmt.Sprintf("%s:GetUsage", w.Name))
+	return w.InnerProvider.GetUsage()
+}
+
 // GetCapabilities ...
 func (w ErrorTraceProvider) GetCapabilities() providers.Capabilities {
 	return w.InnerProvider.GetCapabilities()
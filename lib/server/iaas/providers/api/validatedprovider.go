@@ -61,6 +61,172 @@ func (w ValidatedProvider) DeleteVIP(vip *abstract.VirtualIP) (xerr fail.Error)
 	return w.InnerProvider.DeleteVIP(vip)
 }
 
+// ListSecurityGroups ...
+func (w ValidatedProvider) ListSecurityGroups() (res []*abstract.SecurityGroup, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	res, xerr = w.InnerProvider.ListSecurityGroups()
+	if xerr != nil {
+		for _, item := range res {
+			if item != nil {
+				if !item.OK() {
+					logrus.Warnf("Invalid security group: %v", *item)
+				}
+			}
+		}
+	}
+	return res, xerr
+}
+
+// CreateSecurityGroup ...
+func (w ValidatedProvider) CreateSecurityGroup(req abstract.SecurityGroupRequest) (res *abstract.SecurityGroup, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if req.Name == "" {
+		return nil, fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+
+	res, xerr = w.InnerProvider.CreateSecurityGroup(req)
+	if xerr != nil {
+		if res != nil {
+			if !res.OK() {
+				logrus.Warnf("Invalid security group: %v", *res)
+			}
+		}
+	}
+	return res, xerr
+}
+
+// InspectSecurityGroup ...
+func (w ValidatedProvider) InspectSecurityGroup(ref string) (res *abstract.SecurityGroup, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if ref == "" {
+		return nil, fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	res, xerr = w.InnerProvider.InspectSecurityGroup(ref)
+	if xerr != nil {
+		if res != nil {
+			if !res.OK() {
+				logrus.Warnf("Invalid security group: %v", *res)
+			}
+		}
+	}
+	return res, xerr
+}
+
+// DeleteSecurityGroup ...
+func (w ValidatedProvider) DeleteSecurityGroup(id string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w ValidatedProvider) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (res *abstract.SecurityGroup, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if sgID == "" {
+		return nil, fail.InvalidParameterError("sgID", "cannot be empty string")
+	}
+	if !rule.OK() {
+		return nil, fail.InvalidParameterError("rule", "is invalid")
+	}
+
+	res, xerr = w.InnerProvider.AddRuleToSecurityGroup(sgID, rule)
+	if xerr != nil {
+		if res != nil {
+			if !res.OK() {
+				logrus.Warnf("Invalid security group: %v", *res)
+			}
+		}
+	}
+	return res, xerr
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w ValidatedProvider) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (res *abstract.SecurityGroup, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if sgID == "" {
+		return nil, fail.InvalidParameterError("sgID", "cannot be empty string")
+	}
+	if ruleID == "" {
+		return nil, fail.InvalidParameterError("ruleID", "cannot be empty string")
+	}
+
+	res, xerr = w.InnerProvider.DeleteRuleFromSecurityGroup(sgID, ruleID)
+	if xerr != nil {
+		if res != nil {
+			if !res.OK() {
+				logrus.Warnf("Invalid security group: %v", *res)
+			}
+		}
+	}
+	return res, xerr
+}
+
+// BindSecurityGroupToHost ...
+func (w ValidatedProvider) BindSecurityGroupToHost(sgID string, hostID string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if sgID == "" {
+		return fail.InvalidParameterError("sgID", "cannot be empty string")
+	}
+	if hostID == "" {
+		return fail.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	return w.InnerProvider.BindSecurityGroupToHost(sgID, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w ValidatedProvider) UnbindSecurityGroupFromHost(sgID string, hostID string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if sgID == "" {
+		return fail.InvalidParameterError("sgID", "cannot be empty string")
+	}
+	if hostID == "" {
+		return fail.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	return w.InnerProvider.UnbindSecurityGroupFromHost(sgID, hostID)
+}
+
+// BindSecurityGroupToNetwork ...
+func (w ValidatedProvider) BindSecurityGroupToNetwork(sgID string, networkID string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if sgID == "" {
+		return fail.InvalidParameterError("sgID", "cannot be empty string")
+	}
+	if networkID == "" {
+		return fail.InvalidParameterError("networkID", "cannot be empty string")
+	}
+
+	return w.InnerProvider.BindSecurityGroupToNetwork(sgID, networkID)
+}
+
+// UnbindSecurityGroupFromNetwork ...
+func (w ValidatedProvider) UnbindSecurityGroupFromNetwork(sgID string, networkID string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if sgID == "" {
+		return fail.InvalidParameterError("sgID", "cannot be empty string")
+	}
+	if networkID == "" {
+		return fail.InvalidParameterError("networkID", "cannot be empty string")
+	}
+
+	return w.InnerProvider.UnbindSecurityGroupFromNetwork(sgID, networkID)
+}
+
 func (w ValidatedProvider) GetCapabilities() providers.Capabilities {
 	return w.InnerProvider.GetCapabilities()
 }
@@ -162,6 +328,39 @@ func (w ValidatedProvider) GetImage(id string) (res *abstract.Image, xerr fail.E
 	return res, xerr
 }
 
+// CreateImageFromHost ...
+func (w ValidatedProvider) CreateImageFromHost(hostID string, name string) (res *abstract.Image, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if hostID == "" {
+		return nil, fail.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	res, xerr = w.InnerProvider.CreateImageFromHost(hostID, name)
+	if xerr != nil {
+		if res != nil {
+			if !res.OK() {
+				logrus.Warnf("Invalid image: %v", *res)
+			}
+		}
+	}
+	return res, xerr
+}
+
+// DeleteImage ...
+func (w ValidatedProvider) DeleteImage(id string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	return w.InnerProvider.DeleteImage(id)
+}
+
 // GetTemplate ...
 func (w ValidatedProvider) GetTemplate(id string) (res *abstract.HostTemplate, xerr fail.Error) {
 	defer fail.OnPanic(&xerr)()
@@ -558,6 +757,70 @@ func (w ValidatedProvider) DeleteVolume(id string) (xerr fail.Error) {
 	return w.InnerProvider.DeleteVolume(id)
 }
 
+// CreateVolumeSnapshot ...
+func (w ValidatedProvider) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (res *abstract.VolumeSnapshot, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if request.VolumeID == "" {
+		return nil, fail.InvalidParameterError("request.VolumeID", "cannot be empty string")
+	}
+
+	res, xerr = w.InnerProvider.CreateVolumeSnapshot(request)
+	if xerr != nil {
+		if res != nil {
+			if !res.OK() {
+				logrus.Warnf("Invalid volume snapshot: %v", *res)
+			}
+		}
+	}
+	return res, xerr
+}
+
+// GetVolumeSnapshot ...
+func (w ValidatedProvider) GetVolumeSnapshot(id string) (res *abstract.VolumeSnapshot, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if id == "" {
+		return nil, fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	res, xerr = w.InnerProvider.GetVolumeSnapshot(id)
+	if xerr != nil {
+		if res != nil {
+			if !res.OK() {
+				logrus.Warnf("Invalid volume snapshot: %v", *res)
+			}
+		}
+	}
+	return res, xerr
+}
+
+// ListVolumeSnapshots ...
+func (w ValidatedProvider) ListVolumeSnapshots() (res []abstract.VolumeSnapshot, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	res, xerr = w.InnerProvider.ListVolumeSnapshots()
+	if xerr != nil {
+		for _, item := range res {
+			if !item.OK() {
+				logrus.Warnf("Invalid volume snapshot: %v", item)
+			}
+		}
+	}
+	return res, xerr
+}
+
+// DeleteVolumeSnapshot ...
+func (w ValidatedProvider) DeleteVolumeSnapshot(id string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
 // CreateVolumeAttachment ...
 func (w ValidatedProvider) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (id string, xerr fail.Error) {
 	defer fail.OnPanic(&xerr)()
@@ -629,3 +892,17 @@ func (w ValidatedProvider) DeleteVolumeAttachment(serverID, vaID string) (xerr f
 
 	return w.InnerProvider.DeleteVolumeAttachment(serverID, vaID)
 }
+
+// GetQuotas ...
+func (w ValidatedProvider) GetQuotas() (res *abstract.Quotas, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	return w.InnerProvider.GetQuotas()
+}
+
+// GetUsage ...
+func (w ValidatedProvider) GetUsage() (res *abstract.Usage, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)()
+
+	return w.InnerProvider.GetUsage()
+}
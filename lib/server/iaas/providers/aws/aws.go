@@ -27,6 +27,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumespeed"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/pricing"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
 	apiprovider "github.com/CS-SI/SafeScale/lib/server/iaas/providers/api"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
@@ -259,4 +260,5 @@ func (p *provider) GetCapabilities() providers.Capabilities {
 
 func init() {
 	iaas.Register("aws", &provider{})
+	pricing.Register("aws", aws.NewPricingSource)
 }
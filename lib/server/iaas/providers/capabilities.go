@@ -24,4 +24,6 @@ type Capabilities struct {
 	PrivateVirtualIP bool
 	// Layer3Networking indicates if the provider uses Layer3 networking
 	Layer3Networking bool
+	// SimulatedHosts indicates if the hosts of the provider are simulated, and as such cannot be reached through SSH
+	SimulatedHosts bool
 }
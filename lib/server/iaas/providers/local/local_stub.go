@@ -70,6 +70,12 @@ func (provider *provider) ListImages(all bool) ([]abstract.Image, error) {
 func (provider *provider) GetImage(id string) (*abstract.Image, error) {
 	return nil, fmt.Errorf(errorStr)
 }
+func (provider *provider) CreateImageFromHost(hostID string, name string) (*abstract.Image, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) DeleteImage(id string) error {
+	return fmt.Errorf(errorStr)
+}
 
 func (provider *provider) GetTemplate(id string) (*abstract.HostTemplate, error) {
 	return nil, fmt.Errorf(errorStr)
@@ -127,6 +133,36 @@ func (provider *provider) UnbindHostFromVIP(vip *abstract.VirtualIP, hostID stri
 func (provider *provider) DeleteVIP(vip *abstract.VirtualIP) error {
 	return fmt.Errorf(errorStr)
 }
+func (provider *provider) ListSecurityGroups() ([]*abstract.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) DeleteSecurityGroup(id string) error {
+	return fmt.Errorf(errorStr)
+}
+func (provider *provider) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) BindSecurityGroupToHost(sgID string, hostID string) error {
+	return fmt.Errorf(errorStr)
+}
+func (provider *provider) UnbindSecurityGroupFromHost(sgID string, hostID string) error {
+	return fmt.Errorf(errorStr)
+}
+func (provider *provider) BindSecurityGroupToNetwork(sgID string, networkID string) error {
+	return fmt.Errorf(errorStr)
+}
+func (provider *provider) UnbindSecurityGroupFromNetwork(sgID string, networkID string) error {
+	return fmt.Errorf(errorStr)
+}
 
 func (provider *provider) CreateHost(request abstract.HostRequest) (*abstract.Host, *userdata.Content, error) {
 	return nil, nil, fmt.Errorf(errorStr)
@@ -171,6 +207,18 @@ func (provider *provider) ListVolumes() ([]abstract.Volume, error) {
 func (provider *provider) DeleteVolume(id string) error {
 	return fmt.Errorf(errorStr)
 }
+func (provider *provider) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) DeleteVolumeSnapshot(id string) error {
+	return fmt.Errorf(errorStr)
+}
 
 func (provider *provider) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, error) {
 	return "", fmt.Errorf(errorStr)
@@ -184,6 +232,12 @@ func (provider *provider) ListVolumeAttachments(serverID string) ([]abstract.Vol
 func (provider *provider) DeleteVolumeAttachment(serverID, id string) error {
 	return fmt.Errorf(errorStr)
 }
+func (provider *provider) GetQuotas() (*abstract.Quotas, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) GetUsage() (*abstract.Usage, error) {
+	return nil, fmt.Errorf(errorStr)
+}
 func (provider *provider) GetName() string {
 	return "local_disabled"
 }
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	apiprovider "github.com/CS-SI/SafeScale/lib/server/iaas/providers/api"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/simulator"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// provider is the provider implementation of the in-memory simulator respecting api.Provider
+//
+// A tenant using it may look like this in tenants.toml:
+//
+//	[[tenants]]
+//	    name = "laptop"
+//	    client = "simulator"
+//
+//	    [tenants.identity]
+//
+//	    [tenants.compute]
+//	        Region = "local"
+//	        Latency = "100ms"
+//	        FailureRate = 0.05
+//	        FailOn = [ "CreateVolume" ]
+//
+//	    [tenants.objectstorage]
+//	        Type = "local"
+//	        Endpoint = "/tmp/safescale-simulator"
+type provider struct {
+	*simulator.Stack
+
+	tenantParameters map[string]interface{}
+}
+
+// New creates a new instance of simulator provider
+func New() apiprovider.Provider {
+	return &provider{}
+}
+
+// Build builds a new simulator provider from configuration parameters
+func (p *provider) Build(params map[string]interface{}) (apiprovider.Provider, error) {
+	compute, _ := params["compute"].(map[string]interface{})
+	metadata, _ := params["metadata"].(map[string]interface{})
+
+	region, _ := compute["Region"].(string)
+	if region == "" {
+		region = "local"
+	}
+	zone, _ := compute["AvailabilityZone"].(string)
+	if zone == "" {
+		zone = region + "-1"
+	}
+	defaultImage, _ := compute["DefaultImage"].(string)
+	operatorUsername := abstract.DefaultUser
+	if operatorUsernameIf, ok := compute["OperatorUsername"]; ok {
+		operatorUsername = operatorUsernameIf.(string)
+		if operatorUsername == "" {
+			logrus.Warnf("OperatorUsername is empty ! Check your tenants.toml file ! Using 'safescale' user instead.")
+			operatorUsername = abstract.DefaultUser
+		}
+	}
+
+	simCfg := stacks.SimulatorConfiguration{}
+	if latency, ok := compute["Latency"].(string); ok && latency != "" {
+		duration, err := time.ParseDuration(latency)
+		if err != nil {
+			return nil, fail.InvalidParameterError("Latency", fmt.Sprintf("invalid duration '%s': %v", latency, err))
+		}
+		simCfg.Latency = duration
+	}
+	switch rate := compute["FailureRate"].(type) {
+	case float64:
+		simCfg.FailureRate = rate
+	case int64:
+		simCfg.FailureRate = float64(rate)
+	}
+	if failOn, ok := compute["FailOn"].([]interface{}); ok {
+		for _, v := range failOn {
+			if op, ok := v.(string); ok {
+				simCfg.FailOn = append(simCfg.FailOn, op)
+			}
+		}
+	}
+	if seed, ok := compute["Seed"].(int64); ok {
+		simCfg.Seed = seed
+	}
+	if quotas, ok := compute["Quotas"].(map[string]interface{}); ok {
+		simCfg.Quotas = map[string]int{}
+		for k, v := range quotas {
+			switch value := v.(type) {
+			case int64:
+				simCfg.Quotas[k] = int(value)
+			case float64:
+				simCfg.Quotas[k] = int(value)
+			case int:
+				simCfg.Quotas[k] = value
+			default:
+				return nil, fail.InvalidParameterError("Quotas", fmt.Sprintf("value of '%s' must be a number", k))
+			}
+		}
+	}
+
+	authOptions := stacks.AuthenticationOptions{
+		Region:           region,
+		AvailabilityZone: zone,
+	}
+
+	providerName := "simulator"
+
+	var (
+		metadataBucketName string
+		err                error
+		ok                 bool
+	)
+	if metadataBucketName, ok = metadata["Bucket"].(string); !ok || metadataBucketName == "" {
+		metadataBucketName, err = objectstorage.BuildMetadataBucketName(providerName, region, "", "0")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cfgOptions := stacks.ConfigurationOptions{
+		UseFloatingIP:             true,
+		UseLayer3Networking:       true,
+		AutoHostNetworkInterfaces: true,
+		VolumeSpeeds: map[string]volumespeed.Enum{
+			"standard":   volumespeed.COLD,
+			"performant": volumespeed.HDD,
+			"fast":       volumespeed.SSD,
+		},
+		DNSList:          []string{"1.1.1.1"},
+		DefaultImage:     defaultImage,
+		MetadataBucket:   metadataBucketName,
+		OperatorUsername: operatorUsername,
+		ProviderName:     providerName,
+	}
+
+	stack, err := simulator.New(authOptions, simCfg, cfgOptions)
+	if err != nil {
+		return nil, err
+	}
+	newP := &provider{
+		Stack:            stack,
+		tenantParameters: params,
+	}
+
+	evalid := apiprovider.NewValidatedProvider(newP, providerName)
+	etrace := apiprovider.NewErrorTraceProvider(evalid, providerName)
+	prov := apiprovider.NewLoggedProvider(etrace, providerName)
+	return prov, nil
+}
+
+// GetAuthenticationOptions returns the auth options
+func (p *provider) GetAuthenticationOptions() (providers.Config, error) {
+	opts := p.Stack.GetAuthenticationOptions()
+	cfg := providers.ConfigMap{}
+	cfg.Set("Region", opts.Region)
+	cfg.Set("AvailabilityZone", opts.AvailabilityZone)
+
+	return cfg, nil
+}
+
+// GetConfigurationOptions return configuration parameters
+func (p *provider) GetConfigurationOptions() (providers.Config, error) {
+	cfg := providers.ConfigMap{}
+
+	opts := p.Stack.GetConfigurationOptions()
+	cfg.Set("DNSList", opts.DNSList)
+	cfg.Set("AutoHostNetworkInterfaces", opts.AutoHostNetworkInterfaces)
+	cfg.Set("UseLayer3Networking", opts.UseLayer3Networking)
+	cfg.Set("DefaultImage", opts.DefaultImage)
+	cfg.Set("MetadataBucketName", opts.MetadataBucket)
+	cfg.Set("OperatorUsername", opts.OperatorUsername)
+	cfg.Set("ProviderName", p.GetName())
+
+	return cfg, nil
+}
+
+// ListTemplates ...
+// Value of all has no impact on the result
+func (p *provider) ListTemplates(all bool) ([]abstract.HostTemplate, error) {
+	return p.Stack.ListTemplates()
+}
+
+// ListImages ...
+// Value of all has no impact on the result
+func (p *provider) ListImages(all bool) ([]abstract.Image, error) {
+	return p.Stack.ListImages()
+}
+
+// GetName returns the providerName
+func (p *provider) GetName() string {
+	return "simulator"
+}
+
+// GetTenantParameters returns the tenant parameters as-is
+func (p *provider) GetTenantParameters() map[string]interface{} {
+	return p.tenantParameters
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (p *provider) GetCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		PrivateVirtualIP: true,
+		SimulatedHosts:   true,
+	}
+}
+
+// init registers the simulator provider
+func init() {
+	iaas.Register("simulator", &provider{})
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulator_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/tests"
+)
+
+var (
+	tester  *tests.ServiceTester
+	service iaas.Service
+)
+
+func getTester() (*tests.ServiceTester, error) {
+	if tester == nil {
+		theService, err := getService()
+		if err != nil {
+			tester = nil
+			return nil, err
+		}
+		tester = &tests.ServiceTester{
+			Service: theService,
+		}
+
+	}
+	return tester, nil
+}
+
+// getService builds a simulator service storing its metadata in a temporary folder, no tenants.toml needed
+func getService() (iaas.Service, error) {
+	if service == nil {
+		folder, err := ioutil.TempDir("", "safescale-simulator")
+		if err != nil {
+			return nil, err
+		}
+		service, err = iaas.NewServiceFromTenant(
+			map[string]interface{}{
+				"name":     "simulator",
+				"client":   "simulator",
+				"identity": map[string]interface{}{},
+				"compute": map[string]interface{}{
+					"Region": "local",
+				},
+				"objectstorage": map[string]interface{}{
+					"Type":     "local",
+					"Endpoint": folder,
+				},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return service, nil
+}
+
+func Test_ListImages(t *testing.T) {
+	tt, err := getTester()
+	require.Nil(t, err)
+	tt.ListImages(t)
+}
+
+func Test_ListHostTemplates(t *testing.T) {
+	tt, err := getTester()
+	require.Nil(t, err)
+	tt.ListHostTemplates(t)
+}
+
+func Test_CreateKeyPair(t *testing.T) {
+	tt, err := getTester()
+	require.Nil(t, err)
+	tt.CreateKeyPair(t)
+}
+
+func Test_GetKeyPair(t *testing.T) {
+	tt, err := getTester()
+	require.Nil(t, err)
+	tt.GetKeyPair(t)
+}
+
+func Test_ListKeyPairs(t *testing.T) {
+	tt, err := getTester()
+	require.Nil(t, err)
+	tt.ListKeyPairs(t)
+}
+
+func Test_Volume(t *testing.T) {
+	tt, err := getTester()
+	require.Nil(t, err)
+	tt.Volume(t)
+}
+
+// Test_HostLifecycle creates a network with its gateway and a host, then stops, starts and deletes them
+// Note: uses the templates and images of the simulator directly, the generic tests selecting templates
+// by size relying on the scanner database
+func Test_HostLifecycle(t *testing.T) {
+	svc, err := getService()
+	require.Nil(t, err)
+
+	network, err := svc.CreateNetwork(
+		abstract.NetworkRequest{
+			Name:      "unit_test_network_sim",
+			IPVersion: ipversion.IPv4,
+			CIDR:      "192.168.10.0/24",
+		},
+	)
+	require.Nil(t, err)
+	defer func() {
+		assert.Nil(t, svc.DeleteNetwork(network.ID))
+	}()
+
+	img, err := svc.SearchImage("Ubuntu 18.04")
+	require.Nil(t, err)
+	kp, err := svc.CreateKeyPair("kp_unit_test_sim")
+	require.Nil(t, err)
+	defer func() {
+		_ = svc.DeleteKeyPair(kp.ID)
+	}()
+
+	gw, _, err := svc.CreateGateway(
+		abstract.GatewayRequest{
+			Network:    network,
+			ImageID:    img.ID,
+			KeyPair:    kp,
+			TemplateID: "tpl-small",
+		}, nil,
+	)
+	require.Nil(t, err)
+	defer func() {
+		assert.Nil(t, svc.DeleteGateway(gw.ID))
+	}()
+	assert.Equal(t, "gw-"+network.Name, gw.Name)
+	assert.NotEmpty(t, gw.GetPublicIP())
+	assert.NotEmpty(t, gw.GetPrivateIP())
+
+	host, _, err := svc.CreateHost(
+		abstract.HostRequest{
+			ResourceName:   "unit_test_host_sim",
+			ImageID:        img.ID,
+			TemplateID:     "tpl-medium",
+			KeyPair:        kp,
+			Networks:       []*abstract.Network{network},
+			DefaultGateway: gw,
+		},
+	)
+	require.Nil(t, err)
+	assert.Empty(t, host.GetPublicIP())
+	assert.NotEqual(t, gw.GetPrivateIP(), host.GetPrivateIP())
+
+	// A network still used cannot be deleted
+	assert.NotNil(t, svc.DeleteNetwork(network.ID))
+
+	require.Nil(t, svc.StopHost(host.ID))
+	state, err := svc.GetHostState(host.ID)
+	require.Nil(t, err)
+	assert.Equal(t, hoststate.STOPPED, state)
+	require.Nil(t, svc.StartHost(host.ID))
+	state, err = svc.GetHostState(host.ID)
+	require.Nil(t, err)
+	assert.Equal(t, hoststate.STARTED, state)
+
+	require.Nil(t, svc.DeleteHost(host.ID))
+	_, err = svc.InspectHost(host.ID)
+	assert.NotNil(t, err)
+}
@@ -44,7 +44,9 @@ import (
 	imagefilters "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/filters/images"
 	templatefilters "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/filters/templates"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/userdata"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/metadatastore"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/pricing"
 	providers "github.com/CS-SI/SafeScale/lib/server/iaas/providers/api"
 	"github.com/CS-SI/SafeScale/lib/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/crypt"
@@ -57,10 +59,17 @@ import (
 type Service interface {
 	// --- from service ---
 
+	CheckQuotas(abstract.Usage) error
 	CreateHostWithKeyPair(abstract.HostRequest) (*abstract.Host, *userdata.Content, *abstract.KeyPair, error)
+	EnableBucketEncryption(string, string) (string, error)
 	FilterImages(string) ([]abstract.Image, error)
+	ForgetBucketKey(string) error
+	GetBucketKey(string) (*crypt.Key, error)
 	GetMetadataKey() *crypt.Key
+	GetMetadataKeyring() *crypt.Keyring
 	GetMetadataBucket() objectstorage.Bucket
+	GetMetadataStore() metadatastore.Store
+	GetPricePerHour(abstract.HostTemplate) (float64, error)
 	ListHostsByName() (map[string]*abstract.Host, error)
 	SearchImage(string) (*abstract.Image, error)
 	SelectTemplatesBySize(abstract.SizingRequirements, bool) ([]*abstract.HostTemplate, error)
@@ -81,7 +90,10 @@ type service struct {
 	providers.Provider
 	objectstorage.Location
 	metadataBucket objectstorage.Bucket
-	metadataKey    *crypt.Key
+	metadataStore  metadatastore.Store
+	metadataKeys   *crypt.Keyring
+	bucketKeys     *bucketKeyring
+	pricingSource  pricing.Source
 
 	whitelistTemplateRE *regexp.Regexp
 	blacklistTemplateRE *regexp.Regexp
@@ -137,8 +149,21 @@ func (svc *service) GetMetadataBucket() objectstorage.Bucket {
 	return svc.metadataBucket
 }
 
+// GetMetadataStore returns the storage of the metadata of the tenant
+func (svc *service) GetMetadataStore() metadatastore.Store {
+	return svc.metadataStore
+}
+
 func (svc *service) GetMetadataKey() *crypt.Key {
-	return svc.metadataKey
+	if svc.metadataKeys == nil {
+		return nil
+	}
+	return svc.metadataKeys.Current()
+}
+
+// GetMetadataKeyring returns the keys encrypting the metadata, or nil if the metadata are not encrypted
+func (svc *service) GetMetadataKeyring() *crypt.Keyring {
+	return svc.metadataKeys
 }
 
 // SetProvider allows to change provider interface of service object (mainly for test purposes)
@@ -240,6 +265,36 @@ func pollVolume(svc *service, volumeID string, state volumestate.Enum, cout chan
 	}
 }
 
+// CheckQuotas verifies the tenant has enough resources left to create the resources needed
+// Returns fail.ErrOverload if a quota would be exceeded. If the provider cannot report its quotas or usage,
+// the check is skipped: creation will fail later if the resources are really missing.
+func (svc *service) CheckQuotas(needed abstract.Usage) error {
+	quotas, err := svc.GetQuotas()
+	if err == nil {
+		var usage *abstract.Usage
+		usage, err = svc.GetUsage()
+		if err == nil {
+			return quotas.Check(usage, needed)
+		}
+	}
+	switch err.(type) {
+	case fail.ErrNotImplemented:
+		log.Debugf("quotas not available for provider '%s', skipping pre-flight check", svc.GetName())
+	default:
+		log.Warnf("failed to get quotas, skipping pre-flight check: %v", err)
+	}
+	return nil
+}
+
+// GetPricePerHour returns the price in dollars of one hour of a host using template
+// Returns fail.ErrNotFound if no pricing source of the tenant knows the template
+func (svc *service) GetPricePerHour(template abstract.HostTemplate) (float64, error) {
+	if svc.pricingSource == nil {
+		return 0, fail.NotFoundError(fmt.Sprintf("no pricing source for provider '%s'", svc.GetName()))
+	}
+	return svc.pricingSource.GetPricePerHour(template)
+}
+
 // ListTemplates lists available host templates
 // Host templates are sorted using Dominant Resource Fairness Algorithm
 func (svc *service) ListTemplates(all bool) ([]abstract.HostTemplate, error) {
@@ -604,6 +659,13 @@ func (svc *service) SearchImage(osname string) (*abstract.Image, error) {
 
 	log.Debugf("We are looking for an image for %s", svc.GetName())
 
+	// If is an exact match (by ID or by name) for an Image return that image (covers images captured from hosts)
+	for _, img := range imgs {
+		if img.ID == osname || img.Name == osname {
+			return &img, nil
+		}
+	}
+
 	if svc.GetName() == "aws" {
 		region := ""
 
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iaas
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// usedServices keeps the services returned by UseService, so that a tenant opens its metadata storage only once
+// (a bbolt database is locked by the first opening, an etcd client keeps connections)
+var usedServices = newServiceCache()
+
+// cachedService is a service built for a tenant, with the configuration of the tenant used to build it
+type cachedService struct {
+	config  string
+	service Service
+}
+
+// serviceCache keeps a service per tenant
+type serviceCache struct {
+	mu       sync.Mutex
+	services map[string]cachedService
+}
+
+func newServiceCache() *serviceCache {
+	return &serviceCache{services: map[string]cachedService{}}
+}
+
+// use returns the service of the tenant built previously, or calls build if the configuration of the tenant changed
+// since; the metadata storage of the service replaced is closed before, the new one possibly opening the same
+func (c *serviceCache) use(tenantName string, tenant map[string]interface{}, build func() (Service, error)) (Service, error) {
+	// fmt sorts the keys of maps, so the same configuration gives the same string
+	config := fmt.Sprintf("%v", tenant)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if previous, ok := c.services[tenantName]; ok {
+		if previous.config == config {
+			return previous.service, nil
+		}
+		closeMetadataStore(tenantName, previous.service)
+		delete(c.services, tenantName)
+	}
+
+	svc, err := build()
+	if err != nil {
+		if svc != nil {
+			closeMetadataStore(tenantName, svc)
+		}
+		return nil, err
+	}
+	c.services[tenantName] = cachedService{config: config, service: svc}
+	return svc, nil
+}
+
+// closeMetadataStore releases the metadata storage of the service of a tenant
+func closeMetadataStore(tenantName string, svc Service) {
+	store := svc.GetMetadataStore()
+	if store == nil {
+		return
+	}
+	err := store.Close()
+	if err != nil {
+		logrus.Warnf("failed to close metadata storage of tenant '%s': %v", tenantName, err)
+	}
+}
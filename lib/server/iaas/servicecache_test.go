@@ -0,0 +1,51 @@
+package iaas
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/metadatastore"
+)
+
+func TestServiceCache(t *testing.T) {
+	folder, err := ioutil.TempDir("", "safescale-servicecache")
+	require.Nil(t, err)
+	path := filepath.Join(folder, "metadata.db")
+
+	builds := 0
+	build := func() (Service, error) {
+		builds++
+		// a second opening of the bbolt database while the first one is not closed would wait for the lock
+		store, err := metadatastore.New(metadatastore.Config{Backend: metadatastore.BoltBackend, Name: "metadata", Path: path})
+		if err != nil {
+			return nil, err
+		}
+		return &service{metadataStore: store}, nil
+	}
+
+	c := newServiceCache()
+	tenant := map[string]interface{}{"name": "tenant", "client": "simulator"}
+	first, err := c.use("tenant", tenant, build)
+	require.Nil(t, err)
+	second, err := c.use("tenant", map[string]interface{}{"client": "simulator", "name": "tenant"}, build)
+	require.Nil(t, err)
+	assert.True(t, first == second)
+	assert.Equal(t, 1, builds)
+
+	require.Nil(t, first.GetMetadataStore().Write("key", []byte("value")))
+
+	// a change of configuration replaces the service, closing the metadata storage of the previous one
+	tenant["compute"] = map[string]interface{}{"Region": "local"}
+	third, err := c.use("tenant", tenant, build)
+	require.Nil(t, err)
+	assert.False(t, first == third)
+	assert.Equal(t, 2, builds)
+	data, err := third.GetMetadataStore().Read("key")
+	require.Nil(t, err)
+	assert.Equal(t, "value", string(data))
+	require.Nil(t, third.GetMetadataStore().Close())
+}
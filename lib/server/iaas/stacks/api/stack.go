@@ -36,6 +36,10 @@ type Stack interface {
 
 	// GetImage returns the Image referenced by id
 	GetImage(id string) (*abstract.Image, fail.Error)
+	// CreateImageFromHost creates an image named name from the root disk of the host identified by hostID
+	CreateImageFromHost(hostID string, name string) (*abstract.Image, fail.Error)
+	// DeleteImage deletes the image identified by id
+	DeleteImage(id string) fail.Error
 
 	// GetTemplate returns the Template referenced by id
 	GetTemplate(id string) (*abstract.HostTemplate, fail.Error)
@@ -75,6 +79,27 @@ type Stack interface {
 	// DeleteVIP deletes the port corresponding to the VIP
 	DeleteVIP(*abstract.VirtualIP) fail.Error
 
+	// ListSecurityGroups lists the security groups
+	ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error)
+	// CreateSecurityGroup creates a security group
+	CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, fail.Error)
+	// InspectSecurityGroup returns the security group identified by id or name
+	InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error)
+	// DeleteSecurityGroup deletes the security group identified by id
+	DeleteSecurityGroup(id string) fail.Error
+	// AddRuleToSecurityGroup adds a rule to the security group identified by sgID
+	AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error)
+	// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by sgID
+	DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error)
+	// BindSecurityGroupToHost applies the security group identified by sgID to the host identified by hostID
+	BindSecurityGroupToHost(sgID string, hostID string) fail.Error
+	// UnbindSecurityGroupFromHost removes the security group identified by sgID from the host identified by hostID
+	UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error
+	// BindSecurityGroupToNetwork applies the security group identified by sgID to the network identified by networkID
+	BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error
+	// UnbindSecurityGroupFromNetwork removes the security group identified by sgID from the network identified by networkID
+	UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error
+
 	// CreateHost creates an host that fulfils the request
 	CreateHost(request abstract.HostRequest) (*abstract.Host, *userdata.Content, fail.Error)
 	// GetHost returns the host identified by id or updates content of a *abstract.Host
@@ -96,7 +121,7 @@ type Stack interface {
 	// Resize host
 	ResizeHost(id string, request abstract.SizingRequirements) (*abstract.Host, fail.Error)
 
-	// CreateVolume creates a block volume
+	// CreateVolume creates a block volume, from a snapshot if request.SnapshotID is set
 	CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error)
 	// GetVolume returns the volume identified by id
 	GetVolume(id string) (*abstract.Volume, fail.Error)
@@ -105,6 +130,15 @@ type Stack interface {
 	// DeleteVolume deletes the volume identified by id
 	DeleteVolume(id string) fail.Error
 
+	// CreateVolumeSnapshot creates a snapshot of a block volume
+	CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error)
+	// GetVolumeSnapshot returns the volume snapshot identified by id
+	GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error)
+	// ListVolumeSnapshots lists available volume snapshots
+	ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error)
+	// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+	DeleteVolumeSnapshot(id string) fail.Error
+
 	// CreateVolumeAttachment attaches a volume to an host
 	CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error)
 	// GetVolumeAttachment returns the volume attachment identified by id
@@ -113,6 +147,11 @@ type Stack interface {
 	ListVolumeAttachments(serverID string) ([]abstract.VolumeAttachment, fail.Error)
 	// DeleteVolumeAttachment deletes the volume attachment identified by id
 	DeleteVolumeAttachment(serverID, id string) fail.Error
+
+	// GetQuotas returns the maximum amount of resources usable in the tenant
+	GetQuotas() (*abstract.Quotas, fail.Error)
+	// GetUsage returns the amount of resources currently used in the tenant
+	GetUsage() (*abstract.Usage, fail.Error)
 }
 
 // Reserved is an interface about the methods only available to providers internally
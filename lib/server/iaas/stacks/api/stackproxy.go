@@ -36,6 +36,16 @@ func (sp StackProxy) GetImage(id string) (*abstract.Image, fail.Error) {
 	return rv, errorTranslator(err)
 }
 
+func (sp StackProxy) CreateImageFromHost(hostID string, name string) (*abstract.Image, fail.Error) {
+	rv, err := sp.InnerStack.CreateImageFromHost(hostID, name)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) DeleteImage(id string) fail.Error {
+	err := sp.InnerStack.DeleteImage(id)
+	return errorTranslator(err)
+}
+
 func (sp StackProxy) GetTemplate(id string) (*abstract.HostTemplate, fail.Error) {
 	rv, err := sp.InnerStack.GetTemplate(id)
 	return rv, errorTranslator(err)
@@ -121,6 +131,56 @@ func (sp StackProxy) DeleteVIP(ip *abstract.VirtualIP) error {
 	return errorTranslator(err)
 }
 
+func (sp StackProxy) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	rv, err := sp.InnerStack.ListSecurityGroups()
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, fail.Error) {
+	rv, err := sp.InnerStack.CreateSecurityGroup(req)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	rv, err := sp.InnerStack.InspectSecurityGroup(ref)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) DeleteSecurityGroup(id string) fail.Error {
+	err := sp.InnerStack.DeleteSecurityGroup(id)
+	return errorTranslator(err)
+}
+
+func (sp StackProxy) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	rv, err := sp.InnerStack.AddRuleToSecurityGroup(sgID, rule)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	rv, err := sp.InnerStack.DeleteRuleFromSecurityGroup(sgID, ruleID)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	err := sp.InnerStack.BindSecurityGroupToHost(sgID, hostID)
+	return errorTranslator(err)
+}
+
+func (sp StackProxy) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	err := sp.InnerStack.UnbindSecurityGroupFromHost(sgID, hostID)
+	return errorTranslator(err)
+}
+
+func (sp StackProxy) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	err := sp.InnerStack.BindSecurityGroupToNetwork(sgID, networkID)
+	return errorTranslator(err)
+}
+
+func (sp StackProxy) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	err := sp.InnerStack.UnbindSecurityGroupFromNetwork(sgID, networkID)
+	return errorTranslator(err)
+}
+
 func (sp StackProxy) CreateHost(request abstract.HostRequest) (*abstract.Host, *userdata.Content, fail.Error) {
 	rv, rv2, err := sp.InnerStack.CreateHost(request)
 	return rv, rv2, errorTranslator(err)
@@ -191,6 +251,26 @@ func (sp StackProxy) DeleteVolume(id string) error {
 	return errorTranslator(err)
 }
 
+func (sp StackProxy) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	rv, err := sp.InnerStack.CreateVolumeSnapshot(request)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	rv, err := sp.InnerStack.GetVolumeSnapshot(id)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	rv, err := sp.InnerStack.ListVolumeSnapshots()
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) DeleteVolumeSnapshot(id string) fail.Error {
+	err := sp.InnerStack.DeleteVolumeSnapshot(id)
+	return errorTranslator(err)
+}
+
 func (sp StackProxy) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	rv, err := sp.InnerStack.CreateVolumeAttachment(request)
 	return rv, errorTranslator(err)
@@ -210,3 +290,13 @@ func (sp StackProxy) DeleteVolumeAttachment(serverID, id string) error {
 	err := sp.InnerStack.DeleteVolumeAttachment(serverID, id)
 	return errorTranslator(err)
 }
+
+func (sp StackProxy) GetQuotas() (*abstract.Quotas, fail.Error) {
+	rv, err := sp.InnerStack.GetQuotas()
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) GetUsage() (*abstract.Usage, fail.Error) {
+	rv, err := sp.InnerStack.GetUsage()
+	return rv, errorTranslator(err)
+}
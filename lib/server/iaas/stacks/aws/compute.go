@@ -34,6 +34,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hostproperty"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties"
 	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/userdata"
@@ -322,23 +323,30 @@ func (s *Stack) ListImages() ([]abstract.Image, fail.Error) {
 					logrus.Warnf("ENA filtering does NOT actually work !")
 				}
 
-				nextImage := abstract.Image{
-					ID:          aws.StringValue(image.ImageId),
-					Name:        aws.StringValue(image.Name),
-					Description: aws.StringValue(image.Description),
-					StorageType: aws.StringValue(image.RootDeviceType),
-					DiskSize:    0,
-				}
-
-				if len(image.BlockDeviceMappings) > 0 {
-					if image.BlockDeviceMappings[0].Ebs != nil {
-						if image.BlockDeviceMappings[0].Ebs.VolumeSize != nil {
-							nextImage.DiskSize = aws.Int64Value(image.BlockDeviceMappings[0].Ebs.VolumeSize)
-						}
-					}
-				}
+				images = append(images, toAbstractImage(image))
+			}
+		}
+	}
 
-				images = append(images, nextImage)
+	// Adds the images owned by the account (captured from hosts for example)
+	sout, err := s.EC2Service.DescribeImages(
+		&ec2.DescribeImagesInput{
+			Owners: []*string{aws.String("self")},
+			Filters: []*ec2.Filter{
+				&ec2.Filter{
+					Name:   aws.String("state"),
+					Values: []*string{aws.String("available")},
+				},
+			},
+		},
+	)
+	if err != nil {
+		return images, err
+	}
+	if sout != nil {
+		for _, image := range sout.Images {
+			if image != nil {
+				images = append(images, toAbstractImage(image))
 			}
 		}
 	}
@@ -346,6 +354,116 @@ func (s *Stack) ListImages() ([]abstract.Image, fail.Error) {
 	return images, nil
 }
 
+func toAbstractImage(image *ec2.Image) abstract.Image {
+	nextImage := abstract.Image{
+		ID:          aws.StringValue(image.ImageId),
+		Name:        aws.StringValue(image.Name),
+		Description: aws.StringValue(image.Description),
+		StorageType: aws.StringValue(image.RootDeviceType),
+		DiskSize:    0,
+	}
+
+	if len(image.BlockDeviceMappings) > 0 {
+		if image.BlockDeviceMappings[0].Ebs != nil {
+			if image.BlockDeviceMappings[0].Ebs.VolumeSize != nil {
+				nextImage.DiskSize = aws.Int64Value(image.BlockDeviceMappings[0].Ebs.VolumeSize)
+			}
+		}
+	}
+	return nextImage
+}
+
+func (s *Stack) CreateImageFromHost(hostID, name string) (*abstract.Image, fail.Error) {
+	out, err := s.EC2Service.CreateImage(
+		&ec2.CreateImageInput{
+			InstanceId: aws.String(hostID),
+			Name:       aws.String(name),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	imageID := aws.StringValue(out.ImageId)
+
+	// Waits for the image to be available before returning
+	var image *abstract.Image
+	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			iout, err := s.EC2Service.DescribeImages(
+				&ec2.DescribeImagesInput{
+					ImageIds: []*string{aws.String(imageID)},
+				},
+			)
+			if err != nil {
+				return err
+			}
+			if len(iout.Images) == 0 {
+				return abstract.ResourceNotFoundError("Image", imageID)
+			}
+			switch state := aws.StringValue(iout.Images[0].State); state {
+			case ec2.ImageStateAvailable:
+				img := toAbstractImage(iout.Images[0])
+				image = &img
+				return nil
+			case ec2.ImageStatePending:
+				return fail.NotAvailableError(fmt.Sprintf("image '%s' is not available yet", name))
+			default:
+				return retry.AbortedError("", fail.Errorf(fmt.Sprintf("image '%s' is in state '%s'", name, state), nil))
+			}
+		},
+		temporal.GetLongOperationTimeout(),
+	)
+	if retryErr != nil {
+		if aborted, ok := retryErr.(retry.ErrAborted); ok {
+			retryErr = aborted.Cause()
+		}
+		derr := s.DeleteImage(imageID)
+		if derr != nil {
+			retryErr = fail.AddConsequence(retryErr, derr)
+		}
+		return nil, retryErr
+	}
+	return image, nil
+}
+
+func (s *Stack) DeleteImage(id string) fail.Error {
+	iout, err := s.EC2Service.DescribeImages(
+		&ec2.DescribeImagesInput{
+			ImageIds: []*string{aws.String(id)},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if len(iout.Images) == 0 {
+		return abstract.ResourceNotFoundError("Image", id)
+	}
+
+	_, err = s.EC2Service.DeregisterImage(
+		&ec2.DeregisterImageInput{
+			ImageId: aws.String(id),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Deregistering an image does not delete the EBS snapshots backing it
+	for _, bdm := range iout.Images[0].BlockDeviceMappings {
+		if bdm != nil && bdm.Ebs != nil && bdm.Ebs.SnapshotId != nil {
+			_, err = s.EC2Service.DeleteSnapshot(
+				&ec2.DeleteSnapshotInput{
+					SnapshotId: bdm.Ebs.SnapshotId,
+				},
+			)
+			if err != nil {
+				logrus.Warnf("failed to delete snapshot '%s' of image '%s': %v", aws.StringValue(bdm.Ebs.SnapshotId), id, err)
+			}
+		}
+	}
+	return nil
+}
+
 func (s *Stack) ListTemplates() ([]abstract.HostTemplate, fail.Error) {
 	var templates []abstract.HostTemplate
 
@@ -595,6 +713,16 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 		s.AwsConfig.Zone = az
 		logrus.Debugf("Selected Availability Zone: '%s'", az)
 	}
+	zone := s.hostAvailabilityZone(request)
+
+	// Select the placement group enforcing the placement policy, if any
+	var placementGroup string
+	if request.ServerGroup != "" && request.PlacementPolicy != placementpolicy.NONE {
+		placementGroup, err = s.getPlacementGroup(request.ServerGroup, request.PlacementPolicy)
+		if err != nil {
+			return nil, userData, err
+		}
+	}
 
 	// --- Initializes abstract.Host ---
 
@@ -686,7 +814,7 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 				}
 
 				server, err = buildAwsSpotMachine(
-					s.EC2Service, keyPairName, request.ResourceName, rim.ID, s.AwsConfig.Zone, netID,
+					s.EC2Service, keyPairName, request.ResourceName, rim.ID, zone, placementGroup, netID,
 					string(userDataPhase1), isGateway, template, sgID,
 				)
 			} else {
@@ -700,8 +828,8 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 				}
 
 				server, err = buildAwsMachine(
-					s.EC2Service, keyPairName, request.ResourceName, rim.ID, s.AwsConfig.Zone, netID,
-					string(userDataPhase1), isGateway, template, sgID,
+					s.EC2Service, keyPairName, request.ResourceName, rim.ID, zone, placementGroup, netID,
+					string(userDataPhase1), isGateway, template, sgID, request.Labels,
 				)
 			}
 			if err != nil {
@@ -940,7 +1068,7 @@ func createSecurityGroup(EC2Service *ec2.EC2, vpcID string, name string) error {
 	return nil
 }
 
-func buildAwsSpotMachine(EC2Service *ec2.EC2, keypairName string, name string, imageId string, zone string, netID string, data string, isGateway bool, template *abstract.HostTemplate, sgID string) (*abstract.Host, fail.Error) {
+func buildAwsSpotMachine(EC2Service *ec2.EC2, keypairName string, name string, imageId string, zone string, placementGroup string, netID string, data string, isGateway bool, template *abstract.HostTemplate, sgID string) (*abstract.Host, fail.Error) {
 	ni := &ec2.InstanceNetworkInterfaceSpecification{
 		DeviceIndex:              aws.Int64(int64(0)),
 		SubnetId:                 aws.String(netID),
@@ -962,6 +1090,13 @@ func buildAwsSpotMachine(EC2Service *ec2.EC2, keypairName string, name string, i
 	lastPrice := dspho.SpotPriceHistory[len(dspho.SpotPriceHistory)-1]
 	logrus.Warnf("Last price detected %s", aws.StringValue(lastPrice.SpotPrice))
 
+	placement := &ec2.SpotPlacement{
+		AvailabilityZone: aws.String(zone),
+	}
+	if placementGroup != "" {
+		placement.GroupName = aws.String(placementGroup)
+	}
+
 	input := &ec2.RequestSpotInstancesInput{
 		InstanceCount: aws.Int64(1),
 		LaunchSpecification: &ec2.RequestSpotLaunchSpecification{
@@ -979,10 +1114,8 @@ func buildAwsSpotMachine(EC2Service *ec2.EC2, keypairName string, name string, i
 				},
 			},
 			NetworkInterfaces: []*ec2.InstanceNetworkInterfaceSpecification{ni},
-			Placement: &ec2.SpotPlacement{
-				AvailabilityZone: aws.String(zone),
-			},
-			UserData: aws.String(base64.StdEncoding.EncodeToString([]byte(data))),
+			Placement:         placement,
+			UserData:          aws.String(base64.StdEncoding.EncodeToString([]byte(data))),
 		},
 		SpotPrice: lastPrice.SpotPrice, // FIXME: Round up
 		Type:      aws.String("one-time"),
@@ -1013,7 +1146,7 @@ func buildAwsSpotMachine(EC2Service *ec2.EC2, keypairName string, name string, i
 	return &host, nil
 }
 
-func buildAwsMachine(EC2Service *ec2.EC2, keypairName string, name string, imageId string, zone string, netID string, data string, isGateway bool, template *abstract.HostTemplate, sgID string) (*abstract.Host, fail.Error) {
+func buildAwsMachine(EC2Service *ec2.EC2, keypairName string, name string, imageId string, zone string, placementGroup string, netID string, data string, isGateway bool, template *abstract.HostTemplate, sgID string, labels map[string]string) (*abstract.Host, fail.Error) {
 	logrus.Warnf("Using %s as subnetwork, looking for group %s", netID, sgID)
 
 	ni := &ec2.InstanceNetworkInterfaceSpecification{
@@ -1023,6 +1156,13 @@ func buildAwsMachine(EC2Service *ec2.EC2, keypairName string, name string, image
 		Groups:                   []*string{aws.String(sgID)},
 	}
 
+	placement := &ec2.Placement{
+		AvailabilityZone: aws.String(zone),
+	}
+	if placementGroup != "" {
+		placement.GroupName = aws.String(placementGroup)
+	}
+
 	// Run instance
 	out, err := EC2Service.RunInstances(
 		&ec2.RunInstancesInput{
@@ -1031,9 +1171,7 @@ func buildAwsMachine(EC2Service *ec2.EC2, keypairName string, name string, image
 			KeyName:      aws.String(keypairName),
 			MaxCount:     aws.Int64(1),
 			MinCount:     aws.Int64(1),
-			Placement: &ec2.Placement{
-				AvailabilityZone: aws.String(zone),
-			},
+			Placement:    placement,
 			BlockDeviceMappings: []*ec2.BlockDeviceMapping{
 				&ec2.BlockDeviceMapping{
 					DeviceName: aws.String("/dev/sda1"),
@@ -1048,12 +1186,7 @@ func buildAwsMachine(EC2Service *ec2.EC2, keypairName string, name string, image
 			TagSpecifications: []*ec2.TagSpecification{
 				&ec2.TagSpecification{
 					ResourceType: aws.String("instance"),
-					Tags: []*ec2.Tag{
-						{
-							Key:   aws.String("Name"),
-							Value: aws.String(name),
-						},
-					},
+					Tags:         toTags(name, labels),
 				},
 			},
 			UserData: aws.String(base64.StdEncoding.EncodeToString([]byte(data))),
@@ -227,12 +227,7 @@ func (s *Stack) CreateNetwork(req abstract.NetworkRequest) (res *abstract.Networ
 	_, err = s.EC2Service.CreateTags(
 		&ec2.CreateTagsInput{
 			Resources: subnetIds,
-			Tags: []*ec2.Tag{
-				{
-					Key:   aws.String("Name"),
-					Value: aws.String(req.Name),
-				},
-			},
+			Tags:      toTags(req.Name, req.Labels),
 		},
 	)
 	if err != nil {
@@ -727,6 +722,7 @@ func (s *Stack) CreateGateway(req abstract.GatewayRequest, sizing *abstract.Sizi
 		TemplateID:   req.TemplateID,
 		Networks:     []*abstract.Network{req.Network},
 		PublicIP:     true,
+		Labels:       req.Labels,
 	}
 	if sizing != nil && sizing.MinDiskSize > 0 {
 		hostReq.DiskSize = sizing.MinDiskSize
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// placementGroupLock prevents concurrent host creations from creating the same placement group twice
+var placementGroupLock sync.Mutex
+
+// toPlacementStrategy converts a placement policy to its EC2 placement group strategy
+func toPlacementStrategy(policy placementpolicy.Enum) (string, fail.Error) {
+	switch policy {
+	case placementpolicy.AFFINITY:
+		return ec2.PlacementStrategyCluster, nil
+	case placementpolicy.ANTIAFFINITY:
+		return ec2.PlacementStrategySpread, nil
+	default:
+		return "", fail.InvalidParameterError("policy", fmt.Sprintf("unsupported placement policy '%s'", policy.String()))
+	}
+}
+
+// hostAvailabilityZone returns the availability zone where the host described by request has to be created
+// The subnets of the networks are all created in the zone of the tenant, so a host cannot be created elsewhere;
+// in that case, the requested zone is ignored and the placement group alone spreads the hosts
+func (s *Stack) hostAvailabilityZone(request abstract.HostRequest) string {
+	if request.AvailabilityZone != "" && request.AvailabilityZone != s.AwsConfig.Zone {
+		logrus.Warnf(
+			"cannot create host '%s' in availability zone '%s': networks are bound to zone '%s'",
+			request.ResourceName, request.AvailabilityZone, s.AwsConfig.Zone,
+		)
+	}
+	return s.AwsConfig.Zone
+}
+
+// getPlacementGroup returns the name of the placement group 'name', creating it with the wanted policy if it doesn't exist
+func (s *Stack) getPlacementGroup(name string, policy placementpolicy.Enum) (string, fail.Error) {
+	strategy, xerr := toPlacementStrategy(policy)
+	if xerr != nil {
+		return "", xerr
+	}
+
+	placementGroupLock.Lock()
+	defer placementGroupLock.Unlock()
+
+	out, err := s.EC2Service.DescribePlacementGroups(
+		&ec2.DescribePlacementGroupsInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("group-name"),
+					Values: []*string{aws.String(name)},
+				},
+			},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	for _, group := range out.PlacementGroups {
+		if aws.StringValue(group.GroupName) != name {
+			continue
+		}
+		if aws.StringValue(group.Strategy) != strategy {
+			return "", fail.DuplicateError(
+				fmt.Sprintf("placement group '%s' already exists with strategy '%s'", name, aws.StringValue(group.Strategy)),
+			)
+		}
+		return name, nil
+	}
+
+	_, err = s.EC2Service.CreatePlacementGroup(
+		&ec2.CreatePlacementGroupInput{
+			GroupName: aws.String(name),
+			Strategy:  aws.String(strategy),
+		},
+	)
+	if err != nil {
+		return "", fail.Wrap(err, fmt.Sprintf("failed to create placement group '%s'", name))
+	}
+	logrus.Debugf("Placement group '%s' created with strategy '%s'", name, strategy)
+	return name, nil
+}
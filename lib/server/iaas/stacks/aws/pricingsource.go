@@ -0,0 +1,127 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/pricing"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	iaaspricing "github.com/CS-SI/SafeScale/lib/server/iaas/pricing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// onDemandPrice contains the on demand terms of a product of the AWS price list
+type onDemandPrice struct {
+	Terms struct {
+		OnDemand map[string]struct {
+			PriceDimensions map[string]struct {
+				PricePerUnit map[string]string `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+// pricingSource is a source of prices using the AWS Price List Service
+type pricingSource struct {
+	region  string
+	service *pricing.Pricing
+}
+
+// NewPricingSource returns a source of prices using the AWS Price List Service with the credentials of tenant
+func NewPricingSource(tenant map[string]interface{}) (iaaspricing.Source, error) {
+	identity, _ := tenant["identity"].(map[string]interface{})
+	compute, _ := tenant["compute"].(map[string]interface{})
+	accessKeyID, _ := identity["AccessKeyID"].(string)
+	secretAccessKey, _ := identity["SecretAccessKey"].(string)
+	region, _ := compute["Region"].(string)
+	if accessKeyID == "" || secretAccessKey == "" || region == "" {
+		return nil, fail.InvalidParameterError("tenant", "missing AccessKeyID, SecretAccessKey or Region")
+	}
+
+	// The Price List Service is only available in some regions, and serves prices of all the regions
+	sess, err := session.NewSession(
+		&aws.Config{
+			Credentials: credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""),
+			Region:      aws.String(endpoints.UsEast1RegionID),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &pricingSource{region: region, service: pricing.New(sess, &aws.Config{})}, nil
+}
+
+// GetName returns the name of the source
+func (s *pricingSource) GetName() string {
+	return "aws"
+}
+
+// GetPricePerHour returns the on demand price of a Linux instance of type template.ID in the region of the tenant
+func (s *pricingSource) GetPricePerHour(template abstract.HostTemplate) (float64, error) {
+	filter := func(field, value string) *pricing.Filter {
+		return &pricing.Filter{Field: aws.String(field), Type: aws.String("TERM_MATCH"), Value: aws.String(value)}
+	}
+	prods, err := s.service.GetProducts(
+		&pricing.GetProductsInput{
+			Filters: []*pricing.Filter{
+				filter("instanceType", template.ID),
+				filter("regionCode", s.region),
+				filter("operatingSystem", "Linux"),
+				filter("tenancy", "Shared"),
+				filter("preInstalledSw", "NA"),
+				filter("capacitystatus", "Used"),
+			},
+			MaxResults:  aws.Int64(10),
+			ServiceCode: aws.String("AmazonEC2"),
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range prods.PriceList {
+		jsonPrice, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+		price := onDemandPrice{}
+		if err = json.Unmarshal(jsonPrice, &price); err != nil {
+			continue
+		}
+		for _, term := range price.Terms.OnDemand {
+			for _, dimension := range term.PriceDimensions {
+				if usd, ok := dimension.PricePerUnit["USD"]; ok {
+					value, err := strconv.ParseFloat(usd, 64)
+					if err == nil && value > 0 {
+						return value, nil
+					}
+				}
+			}
+		}
+	}
+	return 0, fail.NotFoundError(
+		fmt.Sprintf("no on demand price found for instance type '%s' in region '%s'", template.ID, s.region),
+	)
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// GetQuotas returns the maximum amount of resources usable in the tenant
+// EC2 only reports the maximum number of instances and of elastic IPs, other resources are considered unlimited
+func (s *Stack) GetQuotas() (*abstract.Quotas, fail.Error) {
+	out, err := s.EC2Service.DescribeAccountAttributes(
+		&ec2.DescribeAccountAttributesInput{
+			AttributeNames: []*string{aws.String("max-instances"), aws.String("vpc-max-elastic-ips")},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := abstract.NewQuotas()
+	for _, attr := range out.AccountAttributes {
+		if attr == nil || len(attr.AttributeValues) == 0 || attr.AttributeValues[0] == nil {
+			continue
+		}
+		value, err := strconv.Atoi(aws.StringValue(attr.AttributeValues[0].AttributeValue))
+		if err != nil {
+			continue
+		}
+		switch aws.StringValue(attr.AttributeName) {
+		case "max-instances":
+			quotas.Instances = value
+		case "vpc-max-elastic-ips":
+			quotas.PublicIPs = value
+		}
+	}
+	return quotas, nil
+}
+
+// GetUsage returns the amount of resources currently used in the tenant
+func (s *Stack) GetUsage() (*abstract.Usage, fail.Error) {
+	usage := &abstract.Usage{}
+
+	err := s.EC2Service.DescribeInstancesPages(
+		&ec2.DescribeInstancesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("instance-state-name"),
+					Values: aws.StringSlice([]string{"pending", "running", "stopping", "stopped"}),
+				},
+			},
+		},
+		func(out *ec2.DescribeInstancesOutput, last bool) bool {
+			for _, reservation := range out.Reservations {
+				if reservation == nil {
+					continue
+				}
+				for _, instance := range reservation.Instances {
+					if instance == nil {
+						continue
+					}
+					usage.Instances++
+					if instance.CpuOptions != nil {
+						usage.Cores += int(aws.Int64Value(instance.CpuOptions.CoreCount) * aws.Int64Value(instance.CpuOptions.ThreadsPerCore))
+					}
+				}
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := s.EC2Service.DescribeVolumes(&ec2.DescribeVolumesInput{})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range volumes.Volumes {
+		if v != nil {
+			usage.Volumes++
+			usage.VolumeSize += int(aws.Int64Value(v.Size))
+		}
+	}
+
+	addresses, err := s.EC2Service.DescribeAddresses(&ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, err
+	}
+	usage.PublicIPs = len(addresses.Addresses)
+
+	vpcs, err := s.EC2Service.DescribeVpcs(&ec2.DescribeVpcsInput{})
+	if err != nil {
+		return nil, err
+	}
+	usage.Networks = len(vpcs.Vpcs)
+
+	return usage, nil
+}
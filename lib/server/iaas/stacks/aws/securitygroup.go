@@ -16,8 +16,381 @@
 
 package aws
 
-import "github.com/CS-SI/SafeScale/lib/utils/fail"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/securitygroupruledirection"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
 
 func (s *Stack) createSecurityGroup(vpcID string, name string) (string, fail.Error) {
 	return "", fail.NotImplementedError("createSecurityGroup() not implemented yet") // FIXME: Technical debt
 }
+
+// AWS does not give an ID to the rules of a security group (they are identified by their content),
+// so the ID of an abstract.SecurityGroupRule is built from its content:
+//     <direction>:<protocol>:<port from>:<port to>:<cidr>
+
+// ruleID builds the ID of a rule
+func ruleID(direction securitygroupruledirection.Enum, protocol string, from, to int, cidr string) string {
+	return strings.Join([]string{direction.String(), protocol, strconv.Itoa(from), strconv.Itoa(to), cidr}, ":")
+}
+
+// parseRuleID rebuilds a rule from its ID
+func parseRuleID(id string) (abstract.SecurityGroupRule, fail.Error) {
+	rule := abstract.SecurityGroupRule{ID: id}
+	parts := strings.SplitN(id, ":", 5)
+	if len(parts) != 5 {
+		return rule, fail.InvalidParameterError("ruleID", fmt.Sprintf("'%s' is not a valid rule ID", id))
+	}
+	var err error
+	rule.Direction, err = securitygroupruledirection.Parse(strings.ToLower(parts[0]))
+	if err != nil {
+		return rule, fail.InvalidParameterError("ruleID", err.Error())
+	}
+	rule.Protocol = parts[1]
+	if rule.PortFrom, err = strconv.Atoi(parts[2]); err != nil {
+		return rule, fail.InvalidParameterError("ruleID", fmt.Sprintf("'%s' is not a valid rule ID", id))
+	}
+	if rule.PortTo, err = strconv.Atoi(parts[3]); err != nil {
+		return rule, fail.InvalidParameterError("ruleID", fmt.Sprintf("'%s' is not a valid rule ID", id))
+	}
+	rule.CIDR = parts[4]
+	if strings.Contains(rule.CIDR, "::") || strings.Count(rule.CIDR, ":") > 1 {
+		rule.EtherType = ipversion.IPv6
+	} else {
+		rule.EtherType = ipversion.IPv4
+	}
+	return rule, nil
+}
+
+// toIPPermission converts an abstract.SecurityGroupRule to an ec2.IpPermission
+func toIPPermission(rule abstract.SecurityGroupRule) *ec2.IpPermission {
+	protocol := rule.Protocol
+	if protocol == "" {
+		protocol = "-1"
+	}
+	from, to := rule.PortFrom, rule.PortTo
+	if to == 0 {
+		to = from
+	}
+	if protocol == "-1" || (protocol == "icmp" && from == 0 && to == 0) {
+		from, to = -1, -1
+	}
+	cidr := rule.CIDR
+	perm := (&ec2.IpPermission{}).SetIpProtocol(protocol)
+	if protocol != "-1" {
+		perm.SetFromPort(int64(from)).SetToPort(int64(to))
+	}
+	if rule.EtherType == ipversion.IPv6 {
+		if cidr == "" {
+			cidr = "::/0"
+		}
+		perm.SetIpv6Ranges([]*ec2.Ipv6Range{{CidrIpv6: aws.String(cidr), Description: descriptionOrNil(rule.Description)}})
+	} else {
+		if cidr == "" {
+			cidr = "0.0.0.0/0"
+		}
+		perm.SetIpRanges([]*ec2.IpRange{{CidrIp: aws.String(cidr), Description: descriptionOrNil(rule.Description)}})
+	}
+	return perm
+}
+
+func descriptionOrNil(desc string) *string {
+	if desc == "" {
+		return nil
+	}
+	return aws.String(desc)
+}
+
+// toAbstractSecurityGroupRules converts ec2.IpPermissions to abstract.SecurityGroupRules
+func toAbstractSecurityGroupRules(direction securitygroupruledirection.Enum, perms []*ec2.IpPermission) []abstract.SecurityGroupRule {
+	var rules []abstract.SecurityGroupRule
+	for _, p := range perms {
+		protocol := aws.StringValue(p.IpProtocol)
+		from, to := int(aws.Int64Value(p.FromPort)), int(aws.Int64Value(p.ToPort))
+		for _, r := range p.IpRanges {
+			cidr := aws.StringValue(r.CidrIp)
+			rules = append(rules, abstract.SecurityGroupRule{
+				ID:          ruleID(direction, protocol, from, to, cidr),
+				Description: aws.StringValue(r.Description),
+				Direction:   direction,
+				EtherType:   ipversion.IPv4,
+				Protocol:    protocol,
+				PortFrom:    from,
+				PortTo:      to,
+				CIDR:        cidr,
+			})
+		}
+		for _, r := range p.Ipv6Ranges {
+			cidr := aws.StringValue(r.CidrIpv6)
+			rules = append(rules, abstract.SecurityGroupRule{
+				ID:          ruleID(direction, protocol, from, to, cidr),
+				Description: aws.StringValue(r.Description),
+				Direction:   direction,
+				EtherType:   ipversion.IPv6,
+				Protocol:    protocol,
+				PortFrom:    from,
+				PortTo:      to,
+				CIDR:        cidr,
+			})
+		}
+	}
+	return rules
+}
+
+// toAbstractSecurityGroup converts an ec2.SecurityGroup to an abstract.SecurityGroup
+func toAbstractSecurityGroup(sg *ec2.SecurityGroup) *abstract.SecurityGroup {
+	out := abstract.NewSecurityGroup()
+	out.ID = aws.StringValue(sg.GroupId)
+	out.Name = aws.StringValue(sg.GroupName)
+	out.Description = aws.StringValue(sg.Description)
+	out.Rules = append(out.Rules, toAbstractSecurityGroupRules(securitygroupruledirection.INGRESS, sg.IpPermissions)...)
+	out.Rules = append(out.Rules, toAbstractSecurityGroupRules(securitygroupruledirection.EGRESS, sg.IpPermissionsEgress)...)
+	return out
+}
+
+// ListSecurityGroups lists the security groups
+func (s *Stack) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	defer debug.NewTracer(nil, "", true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	out, err := s.EC2Service.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{})
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to list security groups")
+	}
+	var list []*abstract.SecurityGroup
+	for _, sg := range out.SecurityGroups {
+		list = append(list, toAbstractSecurityGroup(sg))
+	}
+	return list, nil
+}
+
+// CreateSecurityGroup creates a security group in the VPC of the tenant
+func (s *Stack) CreateSecurityGroup(req abstract.SecurityGroupRequest) (_ *abstract.SecurityGroup, xerr fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", req.Name), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	vpcnet, err := s.GetNetworkByName(s.AwsConfig.NetworkName)
+	if err != nil {
+		return nil, err
+	}
+
+	description := req.Description
+	if description == "" {
+		// AWS requires a description
+		description = req.Name
+	}
+	createRes, err := s.EC2Service.CreateSecurityGroup(
+		&ec2.CreateSecurityGroupInput{
+			Description: aws.String(description),
+			GroupName:   aws.String(req.Name),
+			VpcId:       aws.String(vpcnet.ID),
+		},
+	)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidGroup.Duplicate" {
+			return nil, fail.DuplicateError(fmt.Sprintf("security group '%s' already exists", req.Name))
+		}
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to create security group '%s'", req.Name))
+	}
+	sgID := aws.StringValue(createRes.GroupId)
+
+	defer func() {
+		if xerr != nil {
+			_, derr := s.EC2Service.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: aws.String(sgID)})
+			if derr != nil {
+				xerr = fail.AddConsequence(xerr, derr)
+			}
+		}
+	}()
+
+	for _, r := range req.Rules {
+		if _, xerr = s.AddRuleToSecurityGroup(sgID, r); xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	return s.InspectSecurityGroup(sgID)
+}
+
+// InspectSecurityGroup returns the security group identified by id or name
+func (s *Stack) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	filterName := "group-name"
+	if strings.HasPrefix(ref, "sg-") {
+		filterName = "group-id"
+	}
+	out, err := s.EC2Service.DescribeSecurityGroups(
+		&ec2.DescribeSecurityGroupsInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String(filterName),
+					Values: []*string{aws.String(ref)},
+				},
+			},
+		},
+	)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to get security group '%s'", ref))
+	}
+	switch len(out.SecurityGroups) {
+	case 0:
+		return nil, abstract.ResourceNotFoundError("security group", ref)
+	case 1:
+		return toAbstractSecurityGroup(out.SecurityGroups[0]), nil
+	default:
+		return nil, fail.InconsistentError(fmt.Sprintf("several security groups named '%s' found", ref))
+	}
+}
+
+// DeleteSecurityGroup deletes the security group identified by id
+func (s *Stack) DeleteSecurityGroup(id string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	_, err := s.EC2Service.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: aws.String(id)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidGroup.NotFound" {
+			return abstract.ResourceNotFoundError("security group", id)
+		}
+		return fail.Wrap(err, fmt.Sprintf("failed to delete security group '%s'", id))
+	}
+	return nil
+}
+
+// AddRuleToSecurityGroup adds a rule to the security group identified by sgID
+func (s *Stack) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s)", sgID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	perms := []*ec2.IpPermission{toIPPermission(rule)}
+	var err error
+	switch rule.Direction {
+	case securitygroupruledirection.INGRESS:
+		_, err = s.EC2Service.AuthorizeSecurityGroupIngress(
+			&ec2.AuthorizeSecurityGroupIngressInput{GroupId: aws.String(sgID), IpPermissions: perms},
+		)
+	case securitygroupruledirection.EGRESS:
+		_, err = s.EC2Service.AuthorizeSecurityGroupEgress(
+			&ec2.AuthorizeSecurityGroupEgressInput{GroupId: aws.String(sgID), IpPermissions: perms},
+		)
+	default:
+		return nil, fail.InvalidParameterError("rule.Direction", "must be INGRESS or EGRESS")
+	}
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to add rule to security group '%s'", sgID))
+	}
+	return s.InspectSecurityGroup(sgID)
+}
+
+// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by sgID
+func (s *Stack) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, ruleID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	rule, xerr := parseRuleID(ruleID)
+	if xerr != nil {
+		return nil, xerr
+	}
+	perms := []*ec2.IpPermission{toIPPermission(rule)}
+	var err error
+	switch rule.Direction {
+	case securitygroupruledirection.INGRESS:
+		_, err = s.EC2Service.RevokeSecurityGroupIngress(
+			&ec2.RevokeSecurityGroupIngressInput{GroupId: aws.String(sgID), IpPermissions: perms},
+		)
+	case securitygroupruledirection.EGRESS:
+		_, err = s.EC2Service.RevokeSecurityGroupEgress(
+			&ec2.RevokeSecurityGroupEgressInput{GroupId: aws.String(sgID), IpPermissions: perms},
+		)
+	}
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to delete rule '%s' from security group '%s'", ruleID, sgID))
+	}
+	return s.InspectSecurityGroup(sgID)
+}
+
+// BindSecurityGroupToHost applies the security group identified by sgID to the network interfaces of the host identified by hostID
+func (s *Stack) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, hostID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	return s.updateInterfacesSecurityGroup("attachment.instance-id", hostID, sgID, true)
+}
+
+// UnbindSecurityGroupFromHost removes the security group identified by sgID from the network interfaces of the host identified by hostID
+func (s *Stack) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, hostID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	return s.updateInterfacesSecurityGroup("attachment.instance-id", hostID, sgID, false)
+}
+
+// BindSecurityGroupToNetwork applies the security group identified by sgID to the network interfaces of the subnet identified by networkID
+// Only the network interfaces existing at the time of the call are concerned
+func (s *Stack) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, networkID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	return s.updateInterfacesSecurityGroup("subnet-id", networkID, sgID, true)
+}
+
+// UnbindSecurityGroupFromNetwork removes the security group identified by sgID from the network interfaces of the subnet identified by networkID
+func (s *Stack) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, networkID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	return s.updateInterfacesSecurityGroup("subnet-id", networkID, sgID, false)
+}
+
+// updateInterfacesSecurityGroup adds (if bind is true) or removes (if bind is false) the security group sgID
+// to/from the network interfaces matching the filter
+func (s *Stack) updateInterfacesSecurityGroup(filterName, filterValue, sgID string, bind bool) fail.Error {
+	out, err := s.EC2Service.DescribeNetworkInterfaces(
+		&ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String(filterName),
+					Values: []*string{aws.String(filterValue)},
+				},
+			},
+		},
+	)
+	if err != nil {
+		return fail.Wrap(err, "failed to list network interfaces")
+	}
+	for _, ni := range out.NetworkInterfaces {
+		var groups []*string
+		found := false
+		for _, g := range ni.Groups {
+			if aws.StringValue(g.GroupId) == sgID {
+				found = true
+				if !bind {
+					continue
+				}
+			}
+			groups = append(groups, g.GroupId)
+		}
+		if found == bind {
+			continue
+		}
+		if bind {
+			groups = append(groups, aws.String(sgID))
+		}
+		if len(groups) == 0 {
+			return fail.InvalidRequestError(fmt.Sprintf("cannot remove the last security group of network interface '%s'", aws.StringValue(ni.NetworkInterfaceId)))
+		}
+		_, err = s.EC2Service.ModifyNetworkInterfaceAttribute(
+			&ec2.ModifyNetworkInterfaceAttributeInput{
+				NetworkInterfaceId: ni.NetworkInterfaceId,
+				Groups:             groups,
+			},
+		)
+		if err != nil {
+			return fail.Wrap(err, fmt.Sprintf("failed to update security groups of network interface '%s'", aws.StringValue(ni.NetworkInterfaceId)))
+		}
+	}
+	return nil
+}
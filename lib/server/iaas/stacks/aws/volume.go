@@ -22,6 +22,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
@@ -30,13 +31,15 @@ import (
 )
 
 func (s *Stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
-	v, err := s.EC2Service.CreateVolume(
-		&ec2.CreateVolumeInput{
-			Size:             aws.Int64(int64(request.Size)),
-			VolumeType:       aws.String(toVolumeType(request.Speed)),
-			AvailabilityZone: aws.String(s.AwsConfig.Zone),
-		},
-	)
+	input := &ec2.CreateVolumeInput{
+		Size:             aws.Int64(int64(request.Size)),
+		VolumeType:       aws.String(toVolumeType(request.Speed)),
+		AvailabilityZone: aws.String(s.AwsConfig.Zone),
+	}
+	if request.SnapshotID != "" {
+		input.SnapshotId = aws.String(request.SnapshotID)
+	}
+	v, err := s.EC2Service.CreateVolume(input)
 	if err != nil {
 		return nil, err
 	}
@@ -46,12 +49,7 @@ func (s *Stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume,
 	_, err = s.EC2Service.CreateTags(
 		&ec2.CreateTagsInput{
 			Resources: []*string{v.VolumeId},
-			Tags: []*ec2.Tag{
-				{
-					Key:   aws.String("Name"),
-					Value: aws.String(request.Name),
-				},
-			},
+			Tags:      toTags(request.Name, request.Labels),
 		},
 	)
 	if err != nil {
@@ -193,6 +191,120 @@ func (s *Stack) DeleteVolume(id string) error {
 	return err
 }
 
+func (s *Stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	out, err := s.EC2Service.CreateSnapshot(
+		&ec2.CreateSnapshotInput{
+			VolumeId:    aws.String(request.VolumeID),
+			Description: aws.String(request.Description),
+			TagSpecifications: []*ec2.TagSpecification{
+				{
+					ResourceType: aws.String("snapshot"),
+					Tags: []*ec2.Tag{
+						{
+							Key:   aws.String("Name"),
+							Value: aws.String(request.Name),
+						},
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toVolumeSnapshot(out), nil
+}
+
+func (s *Stack) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	out, err := s.EC2Service.DescribeSnapshots(
+		&ec2.DescribeSnapshotsInput{
+			SnapshotIds: []*string{aws.String(id)},
+		},
+	)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidSnapshot.NotFound" {
+			return nil, abstract.ResourceNotFoundError("VolumeSnapshot", id)
+		}
+		return nil, err
+	}
+
+	if len(out.Snapshots) == 0 {
+		return nil, abstract.ResourceNotFoundError("VolumeSnapshot", id)
+	}
+
+	return toVolumeSnapshot(out.Snapshots[0]), nil
+}
+
+func (s *Stack) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	out, err := s.EC2Service.DescribeSnapshots(
+		&ec2.DescribeSnapshotsInput{
+			OwnerIds: []*string{aws.String("self")},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []abstract.VolumeSnapshot{}
+	for _, v := range out.Snapshots {
+		snapshots = append(snapshots, *toVolumeSnapshot(v))
+	}
+	return snapshots, nil
+}
+
+func (s *Stack) DeleteVolumeSnapshot(id string) fail.Error {
+	_, err := s.EC2Service.DeleteSnapshot(
+		&ec2.DeleteSnapshotInput{
+			SnapshotId: aws.String(id),
+		},
+	)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidSnapshot.NotFound" {
+			return abstract.ResourceNotFoundError("VolumeSnapshot", id)
+		}
+		return err
+	}
+	return nil
+}
+
+func toVolumeSnapshot(v *ec2.Snapshot) *abstract.VolumeSnapshot {
+	snapshotName := aws.StringValue(v.SnapshotId)
+	for _, tag := range v.Tags {
+		if tag != nil && aws.StringValue(tag.Key) == "Name" {
+			snapshotName = aws.StringValue(tag.Value)
+		}
+	}
+
+	return &abstract.VolumeSnapshot{
+		ID:          aws.StringValue(v.SnapshotId),
+		Name:        snapshotName,
+		Description: aws.StringValue(v.Description),
+		VolumeID:    aws.StringValue(v.VolumeId),
+		Size:        int(aws.Int64Value(v.VolumeSize)),
+		State:       toVolumeSnapshotState(v.State),
+		CreatedAt:   aws.TimeValue(v.StartTime),
+	}
+}
+
+func toVolumeSnapshotState(s *string) volumestate.Enum {
+	// SnapshotStatePending = "pending"
+	// SnapshotStateCompleted = "completed"
+	// SnapshotStateError = "error"
+	if s == nil {
+		return volumestate.ERROR
+	}
+	switch *s {
+	case "pending":
+		return volumestate.CREATING
+	case "completed":
+		return volumestate.AVAILABLE
+	case "error":
+		return volumestate.ERROR
+	}
+	return volumestate.OTHER
+}
+
 func (s *Stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	va, err := s.EC2Service.AttachVolume(
 		&ec2.AttachVolumeInput{
@@ -99,6 +99,16 @@ func (s *StackEbrc) GetImage(id string) (*abstract.Image, fail.Error) {
 	return nil, nil
 }
 
+// CreateImageFromHost ...
+func (s *StackEbrc) CreateImageFromHost(hostID string, name string) (*abstract.Image, fail.Error) {
+	return nil, fail.NotImplementedError("CreateImageFromHost() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteImage ...
+func (s *StackEbrc) DeleteImage(id string) fail.Error {
+	return fail.NotImplementedError("DeleteImage() not implemented yet") // FIXME: Technical debt
+}
+
 // -------------TEMPLATES------------------------------------------------------------------------------------------------
 
 // ListTemplates overload OpenStackEbrc ListTemplate method to filter wind and flex instance and add GPU configuration
@@ -944,3 +954,63 @@ func (s *StackEbrc) UnbindHostFromVIP(ip *abstract.VirtualIP, s2 string) error {
 func (s *StackEbrc) DeleteVIP(ip *abstract.VirtualIP) error {
 	return fail.NotImplementedError("DeleteVIP() not implemented yet") // FIXME: Technical debt
 }
+
+// ListSecurityGroups ...
+func (s *StackEbrc) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("ListSecurityGroups() not implemented yet") // FIXME: Technical debt
+}
+
+// CreateSecurityGroup ...
+func (s *StackEbrc) CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("CreateSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// InspectSecurityGroup ...
+func (s *StackEbrc) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("InspectSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteSecurityGroup ...
+func (s *StackEbrc) DeleteSecurityGroup(id string) fail.Error {
+	return fail.NotImplementedError("DeleteSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// AddRuleToSecurityGroup ...
+func (s *StackEbrc) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("AddRuleToSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (s *StackEbrc) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("DeleteRuleFromSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// BindSecurityGroupToHost ...
+func (s *StackEbrc) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	return fail.NotImplementedError("BindSecurityGroupToHost() not implemented yet") // FIXME: Technical debt
+}
+
+// UnbindSecurityGroupFromHost ...
+func (s *StackEbrc) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	return fail.NotImplementedError("UnbindSecurityGroupFromHost() not implemented yet") // FIXME: Technical debt
+}
+
+// BindSecurityGroupToNetwork ...
+func (s *StackEbrc) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	return fail.NotImplementedError("BindSecurityGroupToNetwork() not implemented yet") // FIXME: Technical debt
+}
+
+// UnbindSecurityGroupFromNetwork ...
+func (s *StackEbrc) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	return fail.NotImplementedError("UnbindSecurityGroupFromNetwork() not implemented yet") // FIXME: Technical debt
+}
+
+// GetQuotas ...
+func (s *StackEbrc) GetQuotas() (*abstract.Quotas, fail.Error) {
+	return nil, fail.NotImplementedError("GetQuotas() not implemented yet") // FIXME: Technical debt
+}
+
+// GetUsage ...
+func (s *StackEbrc) GetUsage() (*abstract.Usage, fail.Error) {
+	return nil, fail.NotImplementedError("GetUsage() not implemented yet") // FIXME: Technical debt
+}
@@ -744,6 +744,7 @@ func (s *StackEbrc) CreateGateway(req abstract.GatewayRequest, sizing *abstract.
 		TemplateID:   req.TemplateID,
 		Networks:     []*abstract.Network{req.Network},
 		PublicIP:     true,
+		Labels:       req.Labels,
 	}
 	if sizing != nil && sizing.MinDiskSize > 0 {
 		hostReq.DiskSize = sizing.MinDiskSize
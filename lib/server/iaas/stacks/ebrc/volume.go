@@ -37,6 +37,10 @@ import (
 // - size is the size of the volume in GB
 // - volumeType is the type of volume to create, if volumeType is empty the driver use a default type
 func (s *StackEbrc) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	if request.SnapshotID != "" {
+		return nil, fail.NotImplementedError("CreateVolume() from snapshot not implemented yet") // FIXME: Technical debt
+	}
+
 	diskCreateParams := &types.DiskCreateParams{
 		Disk: &types.Disk{
 			Name:       request.Name,
@@ -176,6 +180,26 @@ func (s *StackEbrc) DeleteVolume(ref string) error {
 	return err
 }
 
+// CreateVolumeSnapshot ...
+func (s *StackEbrc) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// GetVolumeSnapshot ...
+func (s *StackEbrc) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("GetVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// ListVolumeSnapshots ...
+func (s *StackEbrc) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteVolumeSnapshot ...
+func (s *StackEbrc) DeleteVolumeSnapshot(id string) fail.Error {
+	return fail.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
 func hash(s string) string {
 	h := fnv.New32a()
 	_, _ = h.Write([]byte(s))
@@ -19,6 +19,7 @@ package gcp
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
@@ -96,6 +97,16 @@ func (s *Stack) GetImage(id string) (*abstract.Image, fail.Error) {
 	return nil, fail.Errorf(fmt.Sprintf("image with id [%s] not found", id), nil)
 }
 
+// CreateImageFromHost ...
+func (s *Stack) CreateImageFromHost(hostID string, name string) (*abstract.Image, fail.Error) {
+	return nil, fail.NotImplementedError("CreateImageFromHost() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteImage ...
+func (s *Stack) DeleteImage(id string) fail.Error {
+	return fail.NotImplementedError("DeleteImage() not implemented yet") // FIXME: Technical debt
+}
+
 // -------------TEMPLATES------------------------------------------------------------------------------------------------
 
 // ListTemplates overload OpenStackGcp ListTemplate method to filter wind and flex instance and add GPU configuration
@@ -280,6 +291,10 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 		s.GcpConfig.Zone = az
 		logrus.Debugf("Selected Availability Zone: '%s'", az)
 	}
+	zone, err := s.hostAvailabilityZone(request)
+	if err != nil {
+		return nil, userData, err
+	}
 
 	// Sets provider parameters to create host
 	userDataPhase1, err := userData.Generate("phase1")
@@ -331,8 +346,8 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 		func() error {
 			server, err := buildGcpMachine(
 				s.ComputeService, s.GcpConfig.ProjectID, request.ResourceName, rim.URL, s.GcpConfig.Region,
-				s.GcpConfig.Zone, s.GcpConfig.NetworkName, defaultNetwork.Name, string(userDataPhase1), isGateway,
-				template,
+				zone, s.GcpConfig.NetworkName, defaultNetwork.Name, string(userDataPhase1), isGateway,
+				template, request.Labels,
 			)
 			if err != nil {
 				if server != nil {
@@ -498,7 +513,7 @@ func publicAccess(isPublic bool) []*compute.AccessConfig {
 }
 
 // buildGcpMachine ...
-func buildGcpMachine(service *compute.Service, projectID string, instanceName string, imageID string, region string, zone string, network string, subnetwork string, userdata string, isPublic bool, template *abstract.HostTemplate) (*abstract.Host, fail.Error) {
+func buildGcpMachine(service *compute.Service, projectID string, instanceName string, imageID string, region string, zone string, network string, subnetwork string, userdata string, isPublic bool, template *abstract.HostTemplate, labels map[string]string) (*abstract.Host, fail.Error) {
 	prefix := "https://www.googleapis.com/compute/v1/projects/" + projectID
 
 	imageURL := imageID
@@ -518,6 +533,7 @@ func buildGcpMachine(service *compute.Service, projectID string, instanceName st
 		Tags: &compute.Tags{
 			Items: []string{tag},
 		},
+		Labels: toLabels(labels),
 		Disks: []*compute.AttachedDisk{
 			{
 				AutoDelete: true,
@@ -619,7 +635,11 @@ func (s *Stack) InspectHost(hostParam interface{}) (host *abstract.Host, xerr fa
 		return nil, abstract.ResourceNotFoundError("host", hostRef)
 	}
 
-	gcpHost, err := s.ComputeService.Instances.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, hostRef).Do()
+	zone, xerr := s.getInstanceZone(hostRef)
+	if xerr != nil {
+		return nil, xerr
+	}
+	gcpHost, err := s.ComputeService.Instances.Get(s.GcpConfig.ProjectID, zone, hostRef).Do()
 	if err != nil {
 		return nil, err
 	}
@@ -785,12 +805,11 @@ func (s *Stack) GetHostByName(name string) (*abstract.Host, fail.Error) {
 func (s *Stack) DeleteHost(id string) (err error) {
 	service := s.ComputeService
 	projectID := s.GcpConfig.ProjectID
-	zone := s.GcpConfig.Zone
 	instanceName := id
 
-	_, err = service.Instances.Get(projectID, zone, instanceName).Do()
-	if err != nil {
-		return err
+	zone, xerr := s.getInstanceZone(instanceName)
+	if xerr != nil {
+		return xerr
 	}
 
 	op, err := service.Instances.Delete(projectID, zone, instanceName).Do()
@@ -839,22 +858,28 @@ func (s *Stack) ListHosts() ([]*abstract.Host, fail.Error) {
 
 	var hostList []*abstract.Host
 
-	token := ""
-	for paginate := true; paginate; {
-		resp, err := compuService.Instances.List(s.GcpConfig.ProjectID, s.GcpConfig.Zone).PageToken(token).Do()
-		if err != nil {
-			return hostList, fail.Errorf(fmt.Sprintf("cannot list hosts: %v", err), err)
-		}
-		for _, instance := range resp.Items {
-			nhost := abstract.NewHost()
-			nhost.ID = strconv.FormatUint(instance.Id, 10)
-			nhost.Name = instance.Name
-			nhost.LastState, _ = stateConvert(instance.Status)
+	zones, err := s.listZones()
+	if err != nil {
+		return hostList, err
+	}
+	for _, zone := range zones {
+		token := ""
+		for paginate := true; paginate; {
+			resp, err := compuService.Instances.List(s.GcpConfig.ProjectID, zone).PageToken(token).Do()
+			if err != nil {
+				return hostList, fail.Errorf(fmt.Sprintf("cannot list hosts: %v", err), err)
+			}
+			for _, instance := range resp.Items {
+				nhost := abstract.NewHost()
+				nhost.ID = strconv.FormatUint(instance.Id, 10)
+				nhost.Name = instance.Name
+				nhost.LastState, _ = stateConvert(instance.Status)
 
-			hostList = append(hostList, nhost)
+				hostList = append(hostList, nhost)
+			}
+			token := resp.NextPageToken
+			paginate = token != ""
 		}
-		token := resp.NextPageToken
-		paginate = token != ""
 	}
 
 	return hostList, nil
@@ -864,7 +889,12 @@ func (s *Stack) ListHosts() ([]*abstract.Host, fail.Error) {
 func (s *Stack) StopHost(id string) error {
 	service := s.ComputeService
 
-	op, err := service.Instances.Stop(s.GcpConfig.ProjectID, s.GcpConfig.Zone, id).Do()
+	zone, xerr := s.getInstanceZone(id)
+	if xerr != nil {
+		return xerr
+	}
+
+	op, err := service.Instances.Stop(s.GcpConfig.ProjectID, zone, id).Do()
 	if err != nil {
 		return err
 	}
@@ -884,7 +914,12 @@ func (s *Stack) StopHost(id string) error {
 func (s *Stack) StartHost(id string) error {
 	service := s.ComputeService
 
-	op, err := service.Instances.Start(s.GcpConfig.ProjectID, s.GcpConfig.Zone, id).Do()
+	zone, xerr := s.getInstanceZone(id)
+	if xerr != nil {
+		return xerr
+	}
+
+	op, err := service.Instances.Start(s.GcpConfig.ProjectID, zone, id).Do()
 	if err != nil {
 		return err
 	}
@@ -904,7 +939,12 @@ func (s *Stack) StartHost(id string) error {
 func (s *Stack) RebootHost(id string) error {
 	service := s.ComputeService
 
-	op, err := service.Instances.Stop(s.GcpConfig.ProjectID, s.GcpConfig.Zone, id).Do()
+	zone, xerr := s.getInstanceZone(id)
+	if xerr != nil {
+		return xerr
+	}
+
+	op, err := service.Instances.Stop(s.GcpConfig.ProjectID, zone, id).Do()
 	if err != nil {
 		return err
 	}
@@ -921,7 +961,7 @@ func (s *Stack) RebootHost(id string) error {
 		return err
 	}
 
-	op, err = service.Instances.Start(s.GcpConfig.ProjectID, s.GcpConfig.Zone, id).Do()
+	op, err = service.Instances.Start(s.GcpConfig.ProjectID, zone, id).Do()
 	if err != nil {
 		return err
 	}
@@ -957,8 +997,12 @@ func (s *Stack) ListAvailabilityZones() (map[string]bool, fail.Error) {
 	if err != nil {
 		return zones, err
 	}
-	for _, region := range resp.Items {
-		zones[region.Name] = region.Status == "UP"
+	for _, zone := range resp.Items {
+		// Subnetworks are regional, only the zones of the region are usable
+		if s.GcpConfig.Region != "" && !strings.HasSuffix(zone.Region, "/"+s.GcpConfig.Region) {
+			continue
+		}
+		zones[zone.Name] = zone.Status == "UP"
 	}
 
 	return zones, nil
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/googleapi"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// hostAvailabilityZone returns the zone where the host described by request has to be created
+// GCP has no server group; the placement policy is ignored, spreading hosts over zones is the way to go
+func (s *Stack) hostAvailabilityZone(request abstract.HostRequest) (string, fail.Error) {
+	if request.ServerGroup != "" && request.PlacementPolicy != placementpolicy.NONE {
+		logrus.Warnf(
+			"placement policy '%s' of server group '%s' not supported by GCP, ignored",
+			request.PlacementPolicy.String(), request.ServerGroup,
+		)
+	}
+	if request.AvailabilityZone == "" || request.AvailabilityZone == s.GcpConfig.Zone {
+		return s.GcpConfig.Zone, nil
+	}
+
+	azList, err := s.ListAvailabilityZones()
+	if err != nil {
+		return "", err
+	}
+	if available, ok := azList[request.AvailabilityZone]; !ok || !available {
+		return "", fail.InvalidRequestError(
+			fmt.Sprintf(
+				"zone '%s' is not available in region '%s'", request.AvailabilityZone, s.GcpConfig.Region,
+			),
+		)
+	}
+	return request.AvailabilityZone, nil
+}
+
+// listZones returns the zones of the region, the default zone first
+func (s *Stack) listZones() ([]string, fail.Error) {
+	azList, err := s.ListAvailabilityZones()
+	if err != nil {
+		return nil, err
+	}
+	zones := []string{s.GcpConfig.Zone}
+	var others []string
+	for zone := range azList {
+		if zone != s.GcpConfig.Zone {
+			others = append(others, zone)
+		}
+	}
+	sort.Strings(others)
+	return append(zones, others...), nil
+}
+
+// getInstanceZone returns the zone where the instance identified by ref (name or id) lives
+func (s *Stack) getInstanceZone(ref string) (string, fail.Error) {
+	_, err := s.ComputeService.Instances.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, ref).Do()
+	if err == nil {
+		return s.GcpConfig.Zone, nil
+	}
+	if gerr, ok := err.(*googleapi.Error); !ok || gerr.Code != 404 {
+		return "", err
+	}
+
+	zones, xerr := s.listZones()
+	if xerr != nil {
+		return "", xerr
+	}
+	for _, zone := range zones[1:] {
+		_, err = s.ComputeService.Instances.Get(s.GcpConfig.ProjectID, zone, ref).Do()
+		if err == nil {
+			return zone, nil
+		}
+		if gerr, ok := err.(*googleapi.Error); !ok || gerr.Code != 404 {
+			return "", err
+		}
+	}
+	return "", abstract.ResourceNotFoundError("host", ref)
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"google.golang.org/api/compute/v1"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// listQuotas returns the quotas of the project and of the region used, indexed by metric
+func (s *Stack) listQuotas() (map[string]*compute.Quota, fail.Error) {
+	project, err := s.ComputeService.Projects.Get(s.GcpConfig.ProjectID).Do()
+	if err != nil {
+		return nil, err
+	}
+	region, err := s.ComputeService.Regions.Get(s.GcpConfig.ProjectID, s.GcpConfig.Region).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := map[string]*compute.Quota{}
+	for _, q := range project.Quotas {
+		if q != nil {
+			quotas[q.Metric] = q
+		}
+	}
+	// Regional quotas take precedence over project-wide ones
+	for _, q := range region.Quotas {
+		if q != nil {
+			quotas[q.Metric] = q
+		}
+	}
+	return quotas, nil
+}
+
+// GetQuotas returns the maximum amount of resources usable in the tenant
+// GCP doesn't limit RAM nor the number of disks, these are considered unlimited
+func (s *Stack) GetQuotas() (*abstract.Quotas, fail.Error) {
+	list, err := s.listQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := abstract.NewQuotas()
+	limit := func(metric string) int {
+		if q, ok := list[metric]; ok {
+			return int(q.Limit)
+		}
+		return abstract.Unlimited
+	}
+	quotas.Cores = limit("CPUS")
+	quotas.Instances = limit("INSTANCES")
+	quotas.VolumeSize = limit("DISKS_TOTAL_GB")
+	quotas.Networks = limit("NETWORKS")
+	quotas.PublicIPs = limit("IN_USE_ADDRESSES")
+	return quotas, nil
+}
+
+// GetUsage returns the amount of resources currently used in the tenant
+func (s *Stack) GetUsage() (*abstract.Usage, fail.Error) {
+	list, err := s.listQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := func(metric string) int {
+		if q, ok := list[metric]; ok {
+			return int(q.Usage)
+		}
+		return 0
+	}
+	return &abstract.Usage{
+		Cores:      usage("CPUS"),
+		Instances:  usage("INSTANCES"),
+		VolumeSize: usage("DISKS_TOTAL_GB"),
+		Networks:   usage("NETWORKS"),
+		PublicIPs:  usage("IN_USE_ADDRESSES"),
+	}, nil
+}
@@ -141,3 +141,27 @@ func getRegionFromSelfLink(link SelfLink) (string, fail.Error) {
 // 	}
 // 	return nil
 // }
+
+// toLabels converts user-defined labels to GCP labels, whose keys and values only accept lowercase letters, digits,
+// '_' and '-' (other characters are replaced by '_')
+func toLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	sanitize := func(in string) string {
+		return strings.Map(
+			func(r rune) rune {
+				if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+					return r
+				}
+				return '_'
+			}, strings.ToLower(in),
+		)
+	}
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		out[sanitize(k)] = sanitize(v)
+	}
+	return out
+}
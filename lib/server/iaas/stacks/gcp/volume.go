@@ -37,6 +37,10 @@ import (
 // - size is the size of the volume in GB
 // - volumeType is the type of volume to create, if volumeType is empty the driver use a default type
 func (s *Stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	if request.SnapshotID != "" {
+		return nil, fail.NotImplementedError("CreateVolume() from snapshot not implemented yet") // FIXME: Technical debt
+	}
+
 	selectedType := fmt.Sprintf("projects/%s/zones/%s/diskTypes/pd-standard", s.GcpConfig.ProjectID, s.GcpConfig.Zone)
 	if request.Speed == volumespeed.SSD {
 		selectedType = fmt.Sprintf("projects/%s/zones/%s/diskTypes/pd-ssd", s.GcpConfig.ProjectID, s.GcpConfig.Zone)
@@ -48,6 +52,7 @@ func (s *Stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume,
 		SizeGb: int64(request.Size),
 		Type:   selectedType,
 		Zone:   s.GcpConfig.Zone,
+		Labels: toLabels(request.Labels),
 	}
 
 	service := s.ComputeService
@@ -183,6 +188,26 @@ func (s *Stack) DeleteVolume(ref string) error {
 	return err
 }
 
+// CreateVolumeSnapshot ...
+func (s *Stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// GetVolumeSnapshot ...
+func (s *Stack) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("GetVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// ListVolumeSnapshots ...
+func (s *Stack) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteVolumeSnapshot ...
+func (s *Stack) DeleteVolumeSnapshot(id string) fail.Error {
+	return fail.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
 // CreateVolumeAttachment attaches a volume to an host
 // - 'name' of the volume attachment
 // - 'volume' to attach
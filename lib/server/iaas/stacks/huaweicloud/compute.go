@@ -47,6 +47,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hostproperty"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hoststate"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
 	converters "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/userdata"
@@ -363,11 +364,20 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 	}
 
 	// Select usable availability zone
-	az, err := s.SelectedAvailabilityZone()
+	az, err := s.HostAvailabilityZone(request)
 	if err != nil {
 		return nil, userData, err
 	}
 
+	// Select the server group enforcing the placement policy, if any
+	var serverGroupID string
+	if request.ServerGroup != "" && request.PlacementPolicy != placementpolicy.NONE {
+		serverGroupID, err = s.GetServerGroupID(request.ServerGroup, request.PlacementPolicy)
+		if err != nil {
+			return nil, userData, err
+		}
+	}
+
 	// Defines boot disk
 	bootdiskOpts := blockDevice{
 		SourceType:          exbfv.SourceImage,
@@ -390,6 +400,7 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 		FlavorRef:        request.TemplateID,
 		UserData:         userDataPhase1,
 		AvailabilityZone: az,
+		Metadata:         request.Labels,
 	}
 	// Defines host "Extension bootfromvolume" options
 	bdOpts := bootdiskCreateOptsExt{
@@ -405,6 +416,9 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 			), err,
 		)
 	}
+	if serverGroupID != "" {
+		b["os:scheduler_hints"] = map[string]interface{}{"group": serverGroupID}
+	}
 
 	// --- Initializes abstract.Host ---
 
@@ -748,6 +748,7 @@ func (s *Stack) CreateGateway(req abstract.GatewayRequest, sizing *abstract.Sizi
 		TemplateID:   req.TemplateID,
 		Networks:     []*abstract.Network{req.Network},
 		PublicIP:     true,
+		Labels:       req.Labels,
 	}
 	if sizing != nil && sizing.MinDiskSize > 0 {
 		hostReq.DiskSize = sizing.MinDiskSize
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package huaweicloud
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// GetUsage returns the amount of resources currently used in the tenant
+// Quotas and usage are those of openstack, except networks which are subnets of the VPC
+func (s *Stack) GetUsage() (*abstract.Usage, fail.Error) {
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	usage, err := s.Stack.GetUsage()
+	if err != nil {
+		return nil, err
+	}
+	networks, err := s.ListNetworks()
+	if err != nil {
+		return nil, err
+	}
+	usage.Networks = len(networks)
+	return usage, nil
+}
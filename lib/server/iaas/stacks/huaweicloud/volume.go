@@ -103,6 +103,7 @@ func (s *Stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume,
 		Name:             request.Name,
 		Size:             request.Size,
 		VolumeType:       s.getVolumeType(request.Speed),
+		Metadata:         request.Labels,
 	}
 	vol, err := volumes.Create(s.Stack.VolumeClient, opts).Extract()
 	if err != nil {
@@ -196,6 +196,16 @@ func (s *Stack) GetImage(id string) (image *abstract.Image, xerr fail.Error) {
 	return nil, fail.Errorf(fmt.Sprintf("image with id=%s not found", id), err)
 }
 
+// CreateImageFromHost ...
+func (s *Stack) CreateImageFromHost(hostID string, name string) (*abstract.Image, fail.Error) {
+	return nil, fail.NotImplementedError("CreateImageFromHost() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteImage ...
+func (s *Stack) DeleteImage(id string) fail.Error {
+	return fail.NotImplementedError("DeleteImage() not implemented yet") // FIXME: Technical debt
+}
+
 // -------------TEMPLATES------------------------------------------------------------------------------------------------
 
 // ListTemplates overload OpenStack ListTemplate method to filter wind and flex instance and add GPU configuration
@@ -1334,3 +1344,13 @@ func (s *Stack) ListAvailabilityZones() (map[string]bool, fail.Error) {
 func (s *Stack) ListRegions() ([]string, fail.Error) {
 	return []string{"local"}, nil
 }
+
+// GetQuotas ...
+func (s *Stack) GetQuotas() (*abstract.Quotas, fail.Error) {
+	return nil, fail.NotImplementedError("GetQuotas() not implemented yet") // FIXME: Technical debt
+}
+
+// GetUsage ...
+func (s *Stack) GetUsage() (*abstract.Usage, fail.Error) {
+	return nil, fail.NotImplementedError("GetUsage() not implemented yet") // FIXME: Technical debt
+}
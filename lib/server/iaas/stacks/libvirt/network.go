@@ -334,6 +334,7 @@ func (s *Stack) CreateGateway(req abstract.GatewayRequest, sizing *abstract.Sizi
 		TemplateID:   templateID,
 		Networks:     []*abstract.Network{network},
 		PublicIP:     true,
+		Labels:       req.Labels,
 	}
 	if sizing != nil && sizing.MinDiskSize > 0 {
 		hostReq.DiskSize = sizing.MinDiskSize
@@ -378,3 +379,53 @@ func (s *Stack) UnbindHostFromVIP(vip *abstract.VirtualIP, hostID string) error
 func (s *Stack) DeleteVIP(vip *abstract.VirtualIP) error {
 	return fail.NotImplementedError("DeleteVIP() not implemented yet") // FIXME: Technical debt
 }
+
+// ListSecurityGroups ...
+func (s *Stack) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("ListSecurityGroups() not implemented yet") // FIXME: Technical debt
+}
+
+// CreateSecurityGroup ...
+func (s *Stack) CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("CreateSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// InspectSecurityGroup ...
+func (s *Stack) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("InspectSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteSecurityGroup ...
+func (s *Stack) DeleteSecurityGroup(id string) fail.Error {
+	return fail.NotImplementedError("DeleteSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// AddRuleToSecurityGroup ...
+func (s *Stack) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("AddRuleToSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (s *Stack) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("DeleteRuleFromSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// BindSecurityGroupToHost ...
+func (s *Stack) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	return fail.NotImplementedError("BindSecurityGroupToHost() not implemented yet") // FIXME: Technical debt
+}
+
+// UnbindSecurityGroupFromHost ...
+func (s *Stack) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	return fail.NotImplementedError("UnbindSecurityGroupFromHost() not implemented yet") // FIXME: Technical debt
+}
+
+// BindSecurityGroupToNetwork ...
+func (s *Stack) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	return fail.NotImplementedError("BindSecurityGroupToNetwork() not implemented yet") // FIXME: Technical debt
+}
+
+// UnbindSecurityGroupFromNetwork ...
+func (s *Stack) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	return fail.NotImplementedError("UnbindSecurityGroupFromNetwork() not implemented yet") // FIXME: Technical debt
+}
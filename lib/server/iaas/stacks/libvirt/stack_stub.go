@@ -56,6 +56,16 @@ func (s *Stack) GetImage(id string) (*abstract.Image, fail.Error) {
 	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// CreateImageFromHost stub
+func (s *Stack) CreateImageFromHost(hostID string, name string) (*abstract.Image, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// DeleteImage stub
+func (s *Stack) DeleteImage(id string) fail.Error {
+	return fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // GetTemplate stub
 func (s *Stack) GetTemplate(id string) (*abstract.HostTemplate, fail.Error) {
 	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
@@ -146,6 +156,56 @@ func (s *Stack) DeleteVIP(vip *abstract.VirtualIP) error {
 	return fail.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// ListSecurityGroups stub
+func (s *Stack) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// CreateSecurityGroup stub
+func (s *Stack) CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// InspectSecurityGroup stub
+func (s *Stack) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// DeleteSecurityGroup stub
+func (s *Stack) DeleteSecurityGroup(id string) fail.Error {
+	return fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// AddRuleToSecurityGroup stub
+func (s *Stack) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// DeleteRuleFromSecurityGroup stub
+func (s *Stack) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// BindSecurityGroupToHost stub
+func (s *Stack) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	return fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// UnbindSecurityGroupFromHost stub
+func (s *Stack) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	return fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// BindSecurityGroupToNetwork stub
+func (s *Stack) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	return fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// UnbindSecurityGroupFromNetwork stub
+func (s *Stack) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	return fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // CreateHost stub
 func (s *Stack) CreateHost(request abstract.HostRequest) (*abstract.Host, *userdata.Content, fail.Error) {
 	return nil, nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
@@ -216,6 +276,26 @@ func (s *Stack) DeleteVolume(id string) error {
 	return fail.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// CreateVolumeSnapshot stub
+func (s *Stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// GetVolumeSnapshot stub
+func (s *Stack) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// ListVolumeSnapshots stub
+func (s *Stack) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// DeleteVolumeSnapshot stub
+func (s *Stack) DeleteVolumeSnapshot(id string) fail.Error {
+	return fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // CreateVolumeAttachment stub
 func (s *Stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	return "", fail.Errorf(fmt.Sprintf(errorStr), nil)
@@ -236,6 +316,16 @@ func (s *Stack) DeleteVolumeAttachment(serverID, id string) error {
 	return fail.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// GetQuotas stub
+func (s *Stack) GetQuotas() (*abstract.Quotas, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// GetUsage stub
+func (s *Stack) GetUsage() (*abstract.Usage, fail.Error) {
+	return nil, fail.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // GetConfigurationOptions stub
 func (s *Stack) GetConfigurationOptions() stacks.ConfigurationOptions {
 	return stacks.ConfigurationOptions{}
@@ -237,6 +237,10 @@ func getAttachmentFromVolumeAndDomain(volume *libvirt.StorageVol, domain *libvir
 func (s *Stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
 	defer debug.NewTracer(nil, fmt.Sprintf("('%s',%d)", request.Name, request.Size), true).GoingIn().OnExitTrace()()
 
+	if request.SnapshotID != "" {
+		return nil, fail.NotImplementedError("CreateVolume() from snapshot not implemented yet") // FIXME: Technical debt
+	}
+
 	// volume speed is ignored
 	storagePool, err := s.getStoragePoolByPath(s.LibvirtConfig.LibvirtStorage)
 	if err != nil {
@@ -367,6 +371,26 @@ func (s *Stack) DeleteVolume(ref string) error {
 	return nil
 }
 
+// CreateVolumeSnapshot ...
+func (s *Stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// GetVolumeSnapshot ...
+func (s *Stack) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("GetVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// ListVolumeSnapshots ...
+func (s *Stack) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteVolumeSnapshot ...
+func (s *Stack) DeleteVolumeSnapshot(id string) fail.Error {
+	return fail.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
 // CreateVolumeAttachment attaches a volume to an host
 // - 'name' of the volume attachment
 // - 'volume' to attach
@@ -32,6 +32,7 @@ import (
 	az "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/schedulerhints"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/startstop"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
@@ -43,6 +44,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hostproperty"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hoststate"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
 	converters "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/userdata"
@@ -172,6 +174,66 @@ func (s *Stack) GetImage(id string) (image *abstract.Image, xerr fail.Error) {
 	return &abstract.Image{ID: img.ID, Name: img.Name, DiskSize: int64(img.MinDiskGigabytes)}, nil
 }
 
+// CreateImageFromHost creates an image named name from the root disk of the host identified by hostID
+func (s *Stack) CreateImageFromHost(hostID, name string) (image *abstract.Image, xerr fail.Error) {
+	tracer := debug.NewTracer(nil, fmt.Sprintf("(%s, '%s')", hostID, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &xerr)()
+
+	imageID, err := servers.CreateImage(s.ComputeClient, hostID, servers.CreateImageOpts{Name: name}).ExtractImageID()
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("error creating image from host: %s", ProviderErrorToString(err)))
+	}
+
+	// Waits for the image to be active before returning
+	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			img, err := images.Get(s.ComputeClient, imageID).Extract()
+			if err != nil {
+				return err
+			}
+			switch img.Status {
+			case images.ImageStatusActive:
+				image = &abstract.Image{ID: img.ID, Name: img.Name, DiskSize: int64(img.MinDiskGigabytes)}
+				return nil
+			case images.ImageStatusKilled, images.ImageStatusDeleted, images.ImageStatusPendingDelete:
+				return retry.AbortedError("", fail.Errorf(fmt.Sprintf("image '%s' is in state '%s'", name, img.Status), nil))
+			default:
+				return fail.NotAvailableError(fmt.Sprintf("image '%s' is not active yet (state '%s')", name, img.Status))
+			}
+		},
+		temporal.GetLongOperationTimeout(),
+	)
+	if retryErr != nil {
+		if aborted, ok := retryErr.(retry.ErrAborted); ok {
+			retryErr = aborted.Cause()
+		}
+		xerr = fail.Wrap(retryErr, "error creating image from host")
+		if derr := images.Delete(s.ComputeClient, imageID).ExtractErr(); derr != nil {
+			xerr = fail.AddConsequence(xerr, derr)
+		}
+		return nil, xerr
+	}
+	return image, nil
+}
+
+// DeleteImage deletes the image identified by id
+func (s *Stack) DeleteImage(id string) (xerr fail.Error) {
+	tracer := debug.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &xerr)()
+
+	err := images.Delete(s.ComputeClient, id).ExtractErr()
+	if err != nil {
+		err = TranslateError(err)
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return abstract.ResourceNotFoundError("image", id)
+		}
+		return fail.Wrap(err, fmt.Sprintf("error deleting image: %s", ProviderErrorToString(err)))
+	}
+	return nil
+}
+
 // GetTemplate returns the Template referenced by id
 func (s *Stack) GetTemplate(id string) (template *abstract.HostTemplate, xerr fail.Error) {
 	tracer := debug.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn()
@@ -706,12 +768,21 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 		return nil, userData, fail.Errorf(msg, err)
 	}
 
-	// Select usable availability zone, the first one in the list
-	azone, err := s.SelectedAvailabilityZone()
+	// Select usable availability zone, the requested one or the first one in the list
+	azone, err := s.HostAvailabilityZone(request)
 	if err != nil {
 		return nil, userData, err
 	}
 
+	// Select the server group enforcing the placement policy, if any
+	var serverGroupID string
+	if request.ServerGroup != "" && request.PlacementPolicy != placementpolicy.NONE {
+		serverGroupID, err = s.GetServerGroupID(request.ServerGroup, request.PlacementPolicy)
+		if err != nil {
+			return nil, userData, err
+		}
+	}
+
 	// Sets provider parameters to create host
 	userDataPhase1, err := userData.Generate("phase1")
 	if err != nil {
@@ -728,6 +799,14 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 		ImageRef:         request.ImageID,
 		UserData:         userDataPhase1,
 		AvailabilityZone: azone,
+		Metadata:         request.Labels,
+	}
+	var createOpts servers.CreateOptsBuilder = srvOpts
+	if serverGroupID != "" {
+		createOpts = schedulerhints.CreateOptsExt{
+			CreateOptsBuilder: srvOpts,
+			SchedulerHints:    schedulerhints.SchedulerHints{Group: serverGroupID},
+		}
 	}
 
 	// --- Initializes abstract.Host ---
@@ -805,7 +884,7 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (host *abstract.Host, u
 				},
 			}
 			server, ierr := bootfromvolume.Create(s.ComputeClient, bootfromvolume.CreateOptsExt{
-				CreateOptsBuilder: srvOpts,
+				CreateOptsBuilder: createOpts,
 				BlockDevice:       bd,
 			}).Extract()
 			if ierr != nil {
@@ -352,6 +352,7 @@ func (s *Stack) CreateGateway(req abstract.GatewayRequest, sizing *abstract.Sizi
 		TemplateID:   req.TemplateID,
 		Networks:     []*abstract.Network{req.Network},
 		PublicIP:     true,
+		Labels:       req.Labels,
 		Password:     password,
 	}
 	if sizing != nil && sizing.MinDiskSize > 0 {
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openstack
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// serverGroupLock prevents concurrent host creations from creating the same server group twice
+// (server group names are not unique in OpenStack)
+var serverGroupLock sync.Mutex
+
+// toServerGroupPolicy converts a placement policy to its OpenStack server group equivalent
+func toServerGroupPolicy(policy placementpolicy.Enum) (string, fail.Error) {
+	switch policy {
+	case placementpolicy.AFFINITY:
+		return "affinity", nil
+	case placementpolicy.ANTIAFFINITY:
+		return "anti-affinity", nil
+	default:
+		return "", fail.InvalidParameterError("policy", fmt.Sprintf("unsupported placement policy '%s'", policy.String()))
+	}
+}
+
+// HostAvailabilityZone returns the availability zone where the host described by request has to be created
+// If the request doesn't ask for a specific zone, returns the selected availability zone
+func (s *Stack) HostAvailabilityZone(request abstract.HostRequest) (string, fail.Error) {
+	if request.AvailabilityZone == "" {
+		return s.SelectedAvailabilityZone()
+	}
+
+	azList, err := s.ListAvailabilityZones()
+	if err != nil {
+		return "", err
+	}
+	if available, ok := azList[request.AvailabilityZone]; !ok || !available {
+		return "", fail.InvalidRequestError(fmt.Sprintf("availability zone '%s' is not available", request.AvailabilityZone))
+	}
+	return request.AvailabilityZone, nil
+}
+
+// GetServerGroupID returns the ID of the server group named 'name', creating it with the wanted policy if it doesn't exist
+func (s *Stack) GetServerGroupID(name string, policy placementpolicy.Enum) (_ string, xerr fail.Error) {
+	if s == nil {
+		return "", fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return "", fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', %s)", name, policy.String()), true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &xerr)()
+
+	osPolicy, xerr := toServerGroupPolicy(policy)
+	if xerr != nil {
+		return "", xerr
+	}
+
+	serverGroupLock.Lock()
+	defer serverGroupLock.Unlock()
+
+	allPages, err := servergroups.List(s.ComputeClient).AllPages()
+	if err != nil {
+		return "", fail.Wrap(err, fmt.Sprintf("failed to list server groups: %s", ProviderErrorToString(err)))
+	}
+	groups, err := servergroups.ExtractServerGroups(allPages)
+	if err != nil {
+		return "", fail.Wrap(err, "failed to list server groups")
+	}
+	for _, group := range groups {
+		if group.Name != name {
+			continue
+		}
+		for _, p := range group.Policies {
+			if p == osPolicy {
+				return group.ID, nil
+			}
+		}
+		return "", fail.DuplicateError(fmt.Sprintf("server group '%s' already exists with policies %v", name, group.Policies))
+	}
+
+	group, err := servergroups.Create(
+		s.ComputeClient, servergroups.CreateOpts{
+			Name:     name,
+			Policies: []string{osPolicy},
+		},
+	).Extract()
+	if err != nil {
+		return "", fail.Wrap(err, fmt.Sprintf("failed to create server group '%s': %s", name, ProviderErrorToString(err)))
+	}
+	logrus.Debugf("Server group '%s' created with policy '%s'", name, osPolicy)
+	return group.ID, nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openstack
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/limits"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// volumeLimits is the content of the 'limits' resource of the Block Storage API
+// (not covered by gophercloud for every API version)
+type volumeLimits struct {
+	Limits struct {
+		Absolute struct {
+			MaxTotalVolumes         int `json:"maxTotalVolumes"`
+			MaxTotalVolumeGigabytes int `json:"maxTotalVolumeGigabytes"`
+			TotalVolumesUsed        int `json:"totalVolumesUsed"`
+			TotalGigabytesUsed      int `json:"totalGigabytesUsed"`
+		} `json:"absolute"`
+	} `json:"limits"`
+}
+
+// getLimits returns the absolute limits of the Compute and Block Storage services
+func (s *Stack) getLimits() (*limits.Absolute, *volumeLimits, fail.Error) {
+	computeLimits, err := limits.Get(s.ComputeClient, limits.GetOpts{}).Extract()
+	if err != nil {
+		return nil, nil, fail.Wrap(err, fmt.Sprintf("failed to get compute limits: %s", ProviderErrorToString(err)))
+	}
+
+	var blockLimits volumeLimits
+	_, err = s.VolumeClient.Get(s.VolumeClient.ServiceURL("limits"), &blockLimits, nil)
+	if err != nil {
+		return nil, nil, fail.Wrap(err, fmt.Sprintf("failed to get volume limits: %s", ProviderErrorToString(err)))
+	}
+	return &computeLimits.Absolute, &blockLimits, nil
+}
+
+// GetQuotas returns the maximum amount of resources usable in the tenant
+// OpenStack uses -1 for unlimited resources, as abstract.Unlimited does
+func (s *Stack) GetQuotas() (*abstract.Quotas, fail.Error) {
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, "", true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	compute, block, err := s.getLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := abstract.NewQuotas()
+	quotas.Cores = compute.MaxTotalCores
+	quotas.Instances = compute.MaxTotalInstances
+	quotas.PublicIPs = compute.MaxTotalFloatingIps
+	if compute.MaxTotalRAMSize != abstract.Unlimited {
+		quotas.RAMSize = float32(compute.MaxTotalRAMSize) / 1024.0
+	}
+	quotas.Volumes = block.Limits.Absolute.MaxTotalVolumes
+	quotas.VolumeSize = block.Limits.Absolute.MaxTotalVolumeGigabytes
+	return quotas, nil
+}
+
+// GetUsage returns the amount of resources currently used in the tenant
+func (s *Stack) GetUsage() (*abstract.Usage, fail.Error) {
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, "", true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	compute, block, err := s.getLimits()
+	if err != nil {
+		return nil, err
+	}
+	networks, err := s.ListNetworks()
+	if err != nil {
+		return nil, err
+	}
+
+	return &abstract.Usage{
+		Cores:      compute.TotalCoresUsed,
+		RAMSize:    float32(compute.TotalRAMUsed) / 1024.0,
+		Instances:  compute.TotalInstancesUsed,
+		PublicIPs:  compute.TotalFloatingIpsUsed,
+		Volumes:    block.Limits.Absolute.TotalVolumesUsed,
+		VolumeSize: block.Limits.Absolute.TotalGigabytesUsed,
+		Networks:   len(networks),
+	}, nil
+}
@@ -19,12 +19,17 @@ package openstack
 import (
 	"fmt"
 
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 
 	secgroups "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	secrules "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/pagination"
 
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/securitygroupruledirection"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
 )
 
@@ -32,7 +37,7 @@ import (
 func (s *Stack) GetSecurityGroup(name string) (*secgroups.SecGroup, fail.Error) {
 	var sgList []secgroups.SecGroup
 	opts := secgroups.ListOpts{
-		Name: s.DefaultSecurityGroupName,
+		Name: name,
 	}
 	err := secgroups.List(s.NetworkClient, opts).EachPage(
 		func(page pagination.Page) (bool, fail.Error) {
@@ -280,3 +285,246 @@ func (s *Stack) InitDefaultSecurityGroup() error {
 	s.SecurityGroup = group
 	return nil
 }
+
+// toAbstractSecurityGroup converts an OpenStack security group to an abstract.SecurityGroup
+func toAbstractSecurityGroup(sg *secgroups.SecGroup) *abstract.SecurityGroup {
+	out := abstract.NewSecurityGroup()
+	out.ID = sg.ID
+	out.Name = sg.Name
+	out.Description = sg.Description
+	for _, r := range sg.Rules {
+		out.Rules = append(out.Rules, toAbstractSecurityGroupRule(r))
+	}
+	return out
+}
+
+// toAbstractSecurityGroupRule converts an OpenStack security group rule to an abstract.SecurityGroupRule
+func toAbstractSecurityGroupRule(r secrules.SecGroupRule) abstract.SecurityGroupRule {
+	rule := abstract.SecurityGroupRule{
+		ID:          r.ID,
+		Description: r.Description,
+		Protocol:    r.Protocol,
+		PortFrom:    r.PortRangeMin,
+		PortTo:      r.PortRangeMax,
+		CIDR:        r.RemoteIPPrefix,
+	}
+	switch secrules.RuleDirection(r.Direction) {
+	case secrules.DirIngress:
+		rule.Direction = securitygroupruledirection.INGRESS
+	case secrules.DirEgress:
+		rule.Direction = securitygroupruledirection.EGRESS
+	}
+	switch secrules.RuleEtherType(r.EtherType) {
+	case secrules.EtherType4:
+		rule.EtherType = ipversion.IPv4
+	case secrules.EtherType6:
+		rule.EtherType = ipversion.IPv6
+	}
+	return rule
+}
+
+// ListSecurityGroups lists the security groups
+func (s *Stack) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	defer debug.NewTracer(nil, "", true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	var list []*abstract.SecurityGroup
+	err := secgroups.List(s.NetworkClient, secgroups.ListOpts{}).EachPage(
+		func(page pagination.Page) (bool, error) {
+			groups, err := secgroups.ExtractGroups(page)
+			if err != nil {
+				return false, err
+			}
+			for _, g := range groups {
+				g := g
+				list = append(list, toAbstractSecurityGroup(&g))
+			}
+			return true, nil
+		},
+	)
+	if err != nil {
+		return nil, fail.Wrap(TranslateError(err), "failed to list security groups")
+	}
+	return list, nil
+}
+
+// CreateSecurityGroup creates a security group
+func (s *Stack) CreateSecurityGroup(req abstract.SecurityGroupRequest) (_ *abstract.SecurityGroup, xerr fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", req.Name), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	opts := secgroups.CreateOpts{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	group, err := secgroups.Create(s.NetworkClient, opts).Extract()
+	if err != nil {
+		return nil, fail.Wrap(TranslateError(err), fmt.Sprintf("failed to create security group '%s'", req.Name))
+	}
+
+	defer func() {
+		if xerr != nil {
+			derr := secgroups.Delete(s.NetworkClient, group.ID).ExtractErr()
+			if derr != nil {
+				xerr = fail.AddConsequence(xerr, derr)
+			}
+		}
+	}()
+
+	for _, r := range req.Rules {
+		if _, xerr = s.AddRuleToSecurityGroup(group.ID, r); xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	return s.InspectSecurityGroup(group.ID)
+}
+
+// InspectSecurityGroup returns the security group identified by id or name
+func (s *Stack) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	group, err := secgroups.Get(s.NetworkClient, ref).Extract()
+	if err == nil {
+		return toAbstractSecurityGroup(group), nil
+	}
+	err = TranslateError(err)
+	if _, ok := err.(fail.ErrNotFound); !ok {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to get security group '%s'", ref))
+	}
+
+	// Not found by ID, tries by name
+	group, err = s.GetSecurityGroup(ref)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to get security group '%s'", ref))
+	}
+	if group == nil {
+		return nil, abstract.ResourceNotFoundError("security group", ref)
+	}
+	return toAbstractSecurityGroup(group), nil
+}
+
+// DeleteSecurityGroup deletes the security group identified by id
+func (s *Stack) DeleteSecurityGroup(id string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	if s.SecurityGroup != nil && s.SecurityGroup.ID == id {
+		return fail.InvalidRequestError("cannot delete the default security group")
+	}
+	err := secgroups.Delete(s.NetworkClient, id).ExtractErr()
+	if err != nil {
+		err = TranslateError(err)
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return err
+		}
+		return fail.Wrap(err, fmt.Sprintf("failed to delete security group '%s'", id))
+	}
+	return nil
+}
+
+// AddRuleToSecurityGroup adds a rule to the security group identified by sgID
+func (s *Stack) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s)", sgID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	opts := secrules.CreateOpts{
+		SecGroupID:     sgID,
+		Protocol:       secrules.RuleProtocol(rule.Protocol),
+		PortRangeMin:   rule.PortFrom,
+		PortRangeMax:   rule.PortTo,
+		RemoteIPPrefix: rule.CIDR,
+	}
+	if opts.PortRangeMax == 0 {
+		opts.PortRangeMax = opts.PortRangeMin
+	}
+	switch rule.Direction {
+	case securitygroupruledirection.INGRESS:
+		opts.Direction = secrules.DirIngress
+	case securitygroupruledirection.EGRESS:
+		opts.Direction = secrules.DirEgress
+	default:
+		return nil, fail.InvalidParameterError("rule.Direction", "must be INGRESS or EGRESS")
+	}
+	switch rule.EtherType {
+	case ipversion.IPv6:
+		opts.EtherType = secrules.EtherType6
+	default:
+		opts.EtherType = secrules.EtherType4
+	}
+
+	_, err := secrules.Create(s.NetworkClient, opts).Extract()
+	if err != nil {
+		return nil, fail.Wrap(TranslateError(err), fmt.Sprintf("failed to add rule to security group '%s'", sgID))
+	}
+	return s.InspectSecurityGroup(sgID)
+}
+
+// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by sgID
+func (s *Stack) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, ruleID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	err := secrules.Delete(s.NetworkClient, ruleID).ExtractErr()
+	if err != nil {
+		return nil, fail.Wrap(TranslateError(err), fmt.Sprintf("failed to delete rule '%s' from security group '%s'", ruleID, sgID))
+	}
+	return s.InspectSecurityGroup(sgID)
+}
+
+// BindSecurityGroupToHost applies the security group identified by sgID to the ports of the host identified by hostID
+func (s *Stack) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, hostID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	return s.updatePortsSecurityGroup(ports.ListOpts{DeviceID: hostID}, sgID, true)
+}
+
+// UnbindSecurityGroupFromHost removes the security group identified by sgID from the ports of the host identified by hostID
+func (s *Stack) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, hostID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	return s.updatePortsSecurityGroup(ports.ListOpts{DeviceID: hostID}, sgID, false)
+}
+
+// BindSecurityGroupToNetwork applies the security group identified by sgID to the ports of the network identified by networkID
+// Only the ports existing at the time of the call are concerned
+func (s *Stack) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, networkID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	return s.updatePortsSecurityGroup(ports.ListOpts{NetworkID: networkID}, sgID, true)
+}
+
+// UnbindSecurityGroupFromNetwork removes the security group identified by sgID from the ports of the network identified by networkID
+func (s *Stack) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s, %s)", sgID, networkID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	return s.updatePortsSecurityGroup(ports.ListOpts{NetworkID: networkID}, sgID, false)
+}
+
+// updatePortsSecurityGroup adds (if bind is true) or removes (if bind is false) the security group sgID
+// to/from the ports selected by options
+func (s *Stack) updatePortsSecurityGroup(options ports.ListOpts, sgID string, bind bool) fail.Error {
+	list, err := s.listPorts(options)
+	if err != nil {
+		return fail.Wrap(TranslateError(err), "failed to list ports")
+	}
+	for _, p := range list {
+		var groups []string
+		found := false
+		for _, g := range p.SecurityGroups {
+			if g == sgID {
+				found = true
+				if !bind {
+					continue
+				}
+			}
+			groups = append(groups, g)
+		}
+		if found == bind {
+			continue
+		}
+		if bind {
+			groups = append(groups, sgID)
+		}
+		_, err = ports.Update(s.NetworkClient, p.ID, ports.UpdateOpts{SecurityGroups: &groups}).Extract()
+		if err != nil {
+			return fail.Wrap(TranslateError(err), fmt.Sprintf("failed to update security groups of port '%s'", p.ID))
+		}
+	}
+	return nil
+}
@@ -27,8 +27,10 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	gc "github.com/gophercloud/gophercloud"
+	snapshotsv1 "github.com/gophercloud/gophercloud/openstack/blockstorage/v1/snapshots"
 	volumesv1 "github.com/gophercloud/gophercloud/openstack/blockstorage/v1/volumes"
 	volumesv2 "github.com/gophercloud/gophercloud/openstack/blockstorage/v2/volumes"
+	snapshotsv3 "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
 	"github.com/gophercloud/gophercloud/pagination"
 
@@ -116,6 +118,8 @@ func (s *Stack) CreateVolume(request abstract.VolumeRequest) (volume *abstract.V
 				Name:             request.Name,
 				Size:             request.Size,
 				VolumeType:       s.getVolumeType(request.Speed),
+				SnapshotID:       request.SnapshotID,
+				Metadata:         request.Labels,
 			},
 		).Extract()
 		if err != nil {
@@ -142,6 +146,8 @@ func (s *Stack) CreateVolume(request abstract.VolumeRequest) (volume *abstract.V
 				Name:             request.Name,
 				Size:             request.Size,
 				VolumeType:       s.getVolumeType(request.Speed),
+				SnapshotID:       request.SnapshotID,
+				Metadata:         request.Labels,
 			},
 		).Extract()
 		if err != nil {
@@ -281,6 +287,173 @@ func (s *Stack) DeleteVolume(id string) (err error) {
 	return nil
 }
 
+// CreateVolumeSnapshot creates a snapshot of a block volume
+// Snapshot API is the same in block storage v2 and v3, so v3 client is used for both
+func (s *Stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("('%s', %s)", request.Name, request.VolumeID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	var (
+		snap *abstract.VolumeSnapshot
+		err  error
+	)
+	switch s.versions["volume"] {
+	case "v1":
+		var sv *snapshotsv1.Snapshot
+		sv, err = snapshotsv1.Create(
+			s.VolumeClient, snapshotsv1.CreateOpts{
+				VolumeID:    request.VolumeID,
+				Name:        request.Name,
+				Description: request.Description,
+				Force:       request.Force,
+			},
+		).Extract()
+		if err == nil {
+			snap = fromSnapshotV1(sv)
+		}
+	case "v2":
+		var sv *snapshotsv3.Snapshot
+		sv, err = snapshotsv3.Create(
+			s.VolumeClient, snapshotsv3.CreateOpts{
+				VolumeID:    request.VolumeID,
+				Name:        request.Name,
+				Description: request.Description,
+				Force:       request.Force,
+			},
+		).Extract()
+		if err == nil {
+			snap = fromSnapshotV3(sv)
+		}
+	default:
+		err = fail.Errorf(fmt.Sprintf("unmanaged service 'volume' version '%s'", s.versions["volume"]), nil)
+	}
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("error creating volume snapshot: %s", ProviderErrorToString(err)))
+	}
+	return snap, nil
+}
+
+// GetVolumeSnapshot returns the volume snapshot identified by id
+func (s *Stack) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	defer debug.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	var (
+		snap *abstract.VolumeSnapshot
+		err  error
+	)
+	switch s.versions["volume"] {
+	case "v1":
+		var sv *snapshotsv1.Snapshot
+		sv, err = snapshotsv1.Get(s.VolumeClient, id).Extract()
+		if err == nil {
+			snap = fromSnapshotV1(sv)
+		}
+	default:
+		var sv *snapshotsv3.Snapshot
+		sv, err = snapshotsv3.Get(s.VolumeClient, id).Extract()
+		if err == nil {
+			snap = fromSnapshotV3(sv)
+		}
+	}
+	if err != nil {
+		err = TranslateError(err)
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("volume snapshot", id)
+		}
+		return nil, fail.Wrap(err, fmt.Sprintf("error getting volume snapshot: %s", ProviderErrorToString(err)))
+	}
+	return snap, nil
+}
+
+// ListVolumeSnapshots returns the list of all volume snapshots known on the current tenant
+func (s *Stack) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	defer debug.NewTracer(nil, "", true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	var (
+		list []abstract.VolumeSnapshot
+		err  error
+	)
+	switch s.versions["volume"] {
+	case "v1":
+		err = snapshotsv1.List(s.VolumeClient, snapshotsv1.ListOpts{}).EachPage(
+			func(page pagination.Page) (bool, error) {
+				snaps, err := snapshotsv1.ExtractSnapshots(page)
+				if err != nil {
+					return false, err
+				}
+				for _, sv := range snaps {
+					sv := sv
+					list = append(list, *fromSnapshotV1(&sv))
+				}
+				return true, nil
+			},
+		)
+	default:
+		err = snapshotsv3.List(s.VolumeClient, snapshotsv3.ListOpts{}).EachPage(
+			func(page pagination.Page) (bool, error) {
+				snaps, err := snapshotsv3.ExtractSnapshots(page)
+				if err != nil {
+					return false, err
+				}
+				for _, sv := range snaps {
+					sv := sv
+					list = append(list, *fromSnapshotV3(&sv))
+				}
+				return true, nil
+			},
+		)
+	}
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("error listing volume snapshots: %s", ProviderErrorToString(err)))
+	}
+	return list, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *Stack) DeleteVolumeSnapshot(id string) fail.Error {
+	defer debug.NewTracer(nil, "("+id+")", true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	var err error
+	switch s.versions["volume"] {
+	case "v1":
+		err = snapshotsv1.Delete(s.VolumeClient, id).ExtractErr()
+	default:
+		err = snapshotsv3.Delete(s.VolumeClient, id).ExtractErr()
+	}
+	if err != nil {
+		err = TranslateError(err)
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return abstract.ResourceNotFoundError("volume snapshot", id)
+		}
+		return fail.Wrap(err, fmt.Sprintf("error deleting volume snapshot: %s", ProviderErrorToString(err)))
+	}
+	return nil
+}
+
+// fromSnapshotV1 converts a block storage v1 snapshot to an abstract.VolumeSnapshot
+func fromSnapshotV1(sv *snapshotsv1.Snapshot) *abstract.VolumeSnapshot {
+	return &abstract.VolumeSnapshot{
+		ID:          sv.ID,
+		Name:        sv.Name,
+		Description: sv.Description,
+		VolumeID:    sv.VolumeID,
+		Size:        sv.Size,
+		State:       toVolumeState(sv.Status),
+	}
+}
+
+// fromSnapshotV3 converts a block storage v2/v3 snapshot to an abstract.VolumeSnapshot
+func fromSnapshotV3(sv *snapshotsv3.Snapshot) *abstract.VolumeSnapshot {
+	return &abstract.VolumeSnapshot{
+		ID:          sv.ID,
+		Name:        sv.Name,
+		Description: sv.Description,
+		VolumeID:    sv.VolumeID,
+		Size:        sv.Size,
+		State:       toVolumeState(sv.Status),
+		CreatedAt:   sv.CreatedAt,
+	}
+}
+
 // CreateVolumeAttachment attaches a volume to an host
 // - 'name' of the volume attachment
 // - 'volume' to attach
@@ -349,6 +349,91 @@ func (s *Stack) GetImage(id string) (_ *abstract.Image, xerr fail.Error) {
 	}, nil
 }
 
+// CreateImageFromHost creates an image named name from the root disk of the host identified by hostID
+func (s *Stack) CreateImageFromHost(hostID, name string) (_ *abstract.Image, xerr fail.Error) {
+	defer func() {
+		if xerr != nil {
+			xerr = fail.Wrap(xerr, fmt.Sprintf("failed to create image '%s' from host '%s'", name, hostID))
+		}
+	}()
+
+	res, _, err := s.client.ImageApi.CreateImage(
+		s.auth, &osc.CreateImageOpts{
+			CreateImageRequest: optional.NewInterface(
+				osc.CreateImageRequest{
+					VmId:      hostID,
+					ImageName: name,
+				},
+			),
+		},
+	)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+	imageID := res.Image.ImageId
+
+	// Waits for the image to be available before returning
+	var image *abstract.Image
+	err = retry.WhileUnsuccessfulDelay5SecondsTimeout(
+		func() error {
+			res, _, err := s.client.ImageApi.ReadImages(
+				s.auth, &osc.ReadImagesOpts{
+					ReadImagesRequest: optional.NewInterface(
+						osc.ReadImagesRequest{
+							Filters: osc.FiltersImage{
+								ImageIds: []string{imageID},
+							},
+						},
+					),
+				},
+			)
+			if err != nil {
+				return normalizeError(err)
+			}
+			if len(res.Images) != 1 {
+				return fail.NotFoundError(fmt.Sprintf("image '%s' not found", imageID))
+			}
+			img := res.Images[0]
+			if img.State != "available" {
+				return fail.NotAvailableError(fmt.Sprintf("image '%s' is not available yet (state '%s')", name, img.State))
+			}
+			image = &abstract.Image{
+				Description: img.Description,
+				ID:          img.ImageId,
+				Name:        img.ImageName,
+				StorageType: img.RootDeviceType,
+				URL:         img.FileLocation,
+			}
+			return nil
+		}, temporal.GetLongOperationTimeout(),
+	)
+	if err != nil {
+		derr := s.DeleteImage(imageID)
+		if derr != nil {
+			err = fail.AddConsequence(err, derr)
+		}
+		return nil, err
+	}
+	return image, nil
+}
+
+// DeleteImage deletes the image identified by id
+func (s *Stack) DeleteImage(id string) fail.Error {
+	_, _, err := s.client.ImageApi.DeleteImage(
+		s.auth, &osc.DeleteImageOpts{
+			DeleteImageRequest: optional.NewInterface(
+				osc.DeleteImageRequest{
+					ImageId: id,
+				},
+			),
+		},
+	)
+	if err != nil {
+		return fail.Wrap(normalizeError(err), fmt.Sprintf("failed to delete image '%s'", id))
+	}
+	return nil
+}
+
 // GetTemplate returns the Template referenced by id
 func (s *Stack) GetTemplate(id string) (*abstract.HostTemplate, fail.Error) {
 	return s.parseTemplateID(id)
@@ -1021,9 +1106,11 @@ func (s *Stack) CreateHost(request abstract.HostRequest) (_ *abstract.Host, _ *u
 		return nil, userData, err
 	}
 	err = s.setResourceTags(
-		vm.VmId, map[string]string{
-			"name": request.ResourceName,
-		},
+		vm.VmId, withLabels(
+			map[string]string{
+				"name": request.ResourceName,
+			}, request.Labels,
+		),
 	)
 	if err != nil {
 		return nil, userData, err
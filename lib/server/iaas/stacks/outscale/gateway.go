@@ -54,6 +54,7 @@ func (s *Stack) CreateGateway(req abstract.GatewayRequest, sizing *abstract.Sizi
 		TemplateID:   req.TemplateID,
 		Networks:     []*abstract.Network{req.Network},
 		PublicIP:     true,
+		Labels:       req.Labels,
 		Password:     password,
 	}
 	if sizing != nil && sizing.MinDiskSize > 0 {
@@ -67,9 +67,11 @@ func (s *Stack) createSubnet(req abstract.NetworkRequest, vpcID string) (_ *osc.
 	}()
 
 	err = s.setResourceTags(
-		resSubnet.Subnet.SubnetId, map[string]string{
-			"name": req.Name,
-		},
+		resSubnet.Subnet.SubnetId, withLabels(
+			map[string]string{
+				"name": req.Name,
+			}, req.Labels,
+		),
 	)
 	if err != nil {
 		return nil, err
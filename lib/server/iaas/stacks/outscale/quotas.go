@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package outscale
+
+import (
+	"github.com/antihax/optional"
+	"github.com/outscale-dev/osc-sdk-go/osc"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// listQuotas returns the quotas of the account, indexed by name
+func (s *Stack) listQuotas() (map[string]osc.Quota, fail.Error) {
+	res, _, err := s.client.QuotaApi.ReadQuotas(
+		s.auth, &osc.ReadQuotasOpts{
+			ReadQuotasRequest: optional.NewInterface(osc.ReadQuotasRequest{}),
+		},
+	)
+	if err != nil {
+		return nil, fail.Wrap(normalizeError(err), "failed to read quotas")
+	}
+
+	quotas := map[string]osc.Quota{}
+	for _, qt := range res.QuotaTypes {
+		for _, q := range qt.Quotas {
+			quotas[q.Name] = q
+		}
+	}
+	return quotas, nil
+}
+
+// GetQuotas returns the maximum amount of resources usable in the tenant
+func (s *Stack) GetQuotas() (*abstract.Quotas, fail.Error) {
+	list, err := s.listQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	limit := func(name string) int {
+		if q, ok := list[name]; ok {
+			return int(q.MaxValue)
+		}
+		return abstract.Unlimited
+	}
+	quotas := abstract.NewQuotas()
+	quotas.Cores = limit("core_limit")
+	if ram := limit("ram_limit"); ram != abstract.Unlimited {
+		quotas.RAMSize = float32(ram)
+	}
+	quotas.Instances = limit("vm_limit")
+	quotas.Volumes = limit("volume_limit")
+	quotas.VolumeSize = limit("storage_limit")
+	quotas.Networks = limit("vpc_limit")
+	quotas.PublicIPs = limit("public_ip_limit")
+	return quotas, nil
+}
+
+// GetUsage returns the amount of resources currently used in the tenant
+func (s *Stack) GetUsage() (*abstract.Usage, fail.Error) {
+	list, err := s.listQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	used := func(name string) int {
+		if q, ok := list[name]; ok {
+			return int(q.UsedValue)
+		}
+		return 0
+	}
+	return &abstract.Usage{
+		Cores:      used("core_limit"),
+		RAMSize:    float32(used("ram_limit")),
+		Instances:  used("vm_limit"),
+		Volumes:    used("volume_limit"),
+		VolumeSize: used("storage_limit"),
+		Networks:   used("vpc_limit"),
+		PublicIPs:  used("public_ip_limit"),
+	}, nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package outscale
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// ListSecurityGroups ...
+func (s *Stack) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("ListSecurityGroups() not implemented yet") // FIXME: Technical debt
+}
+
+// CreateSecurityGroup ...
+func (s *Stack) CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("CreateSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// InspectSecurityGroup ...
+func (s *Stack) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("InspectSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteSecurityGroup ...
+func (s *Stack) DeleteSecurityGroup(id string) fail.Error {
+	return fail.NotImplementedError("DeleteSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// AddRuleToSecurityGroup ...
+func (s *Stack) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("AddRuleToSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (s *Stack) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	return nil, fail.NotImplementedError("DeleteRuleFromSecurityGroup() not implemented yet") // FIXME: Technical debt
+}
+
+// BindSecurityGroupToHost ...
+func (s *Stack) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	return fail.NotImplementedError("BindSecurityGroupToHost() not implemented yet") // FIXME: Technical debt
+}
+
+// UnbindSecurityGroupFromHost ...
+func (s *Stack) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	return fail.NotImplementedError("UnbindSecurityGroupFromHost() not implemented yet") // FIXME: Technical debt
+}
+
+// BindSecurityGroupToNetwork ...
+func (s *Stack) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	return fail.NotImplementedError("BindSecurityGroupToNetwork() not implemented yet") // FIXME: Technical debt
+}
+
+// UnbindSecurityGroupFromNetwork ...
+func (s *Stack) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	return fail.NotImplementedError("UnbindSecurityGroupFromNetwork() not implemented yet") // FIXME: Technical debt
+}
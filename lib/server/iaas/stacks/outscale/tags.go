@@ -80,3 +80,13 @@ func unwrapTags(tags []osc.ResourceTag) map[string]string {
 	}
 	return m
 }
+
+// withLabels returns tags completed with the user-defined labels
+func withLabels(tags map[string]string, labels map[string]string) map[string]string {
+	for k, v := range labels {
+		if _, ok := tags[k]; !ok {
+			tags[k] = v
+		}
+	}
+	return tags
+}
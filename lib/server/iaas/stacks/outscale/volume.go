@@ -18,6 +18,10 @@ import (
 
 // CreateVolume creates a block volume
 func (s *Stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume, xerr fail.Error) {
+	if request.SnapshotID != "" {
+		return nil, fail.NotImplementedError("CreateVolume() from snapshot not implemented yet") // FIXME: Technical debt
+	}
+
 	v, _ := s.GetVolumeByName(request.Name)
 	if v != nil {
 		return nil, abstract.ResourceDuplicateError("volume", request.Name)
@@ -62,9 +66,11 @@ func (s *Stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume
 	}()
 
 	err = s.setResourceTags(
-		res.Volume.VolumeId, map[string]string{
-			"name": request.Name,
-		},
+		res.Volume.VolumeId, withLabels(
+			map[string]string{
+				"name": request.Name,
+			}, request.Labels,
+		),
 	)
 	if err != nil {
 		return nil, err
@@ -246,6 +252,26 @@ func (s *Stack) DeleteVolume(id string) error {
 	return normalizeError(err)
 }
 
+// CreateVolumeSnapshot ...
+func (s *Stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// GetVolumeSnapshot ...
+func (s *Stack) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("GetVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// ListVolumeSnapshots ...
+func (s *Stack) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteVolumeSnapshot ...
+func (s *Stack) DeleteVolumeSnapshot(id string) fail.Error {
+	return fail.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
 func freeDevice(usedDevices []string, device string) bool {
 	for _, usedDevice := range usedDevices {
 		if device == usedDevice {
@@ -0,0 +1,699 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// defaultImages contains the images available in a fresh simulator
+var defaultImages = []abstract.Image{
+	{ID: "img-ubuntu-1804", Name: "Ubuntu 18.04", URL: "simulator://images/img-ubuntu-1804", DiskSize: 10},
+	{ID: "img-ubuntu-2004", Name: "Ubuntu 20.04", URL: "simulator://images/img-ubuntu-2004", DiskSize: 10},
+	{ID: "img-centos-73", Name: "CentOS 7.3", URL: "simulator://images/img-centos-73", DiskSize: 10},
+	{ID: "img-debian-10", Name: "Debian 10", URL: "simulator://images/img-debian-10", DiskSize: 10},
+}
+
+// defaultTemplates contains the host templates available in a fresh simulator
+var defaultTemplates = []abstract.HostTemplate{
+	{ID: "tpl-tiny", Name: "sim.tiny", Cores: 1, RAMSize: 1, DiskSize: 10},
+	{ID: "tpl-small", Name: "sim.small", Cores: 1, RAMSize: 2, DiskSize: 20},
+	{ID: "tpl-medium", Name: "sim.medium", Cores: 2, RAMSize: 4, DiskSize: 40},
+	{ID: "tpl-large", Name: "sim.large", Cores: 4, RAMSize: 8, DiskSize: 80},
+	{ID: "tpl-xlarge", Name: "sim.xlarge", Cores: 8, RAMSize: 16, DiskSize: 160},
+	{ID: "tpl-2xlarge", Name: "sim.2xlarge", Cores: 16, RAMSize: 64, DiskSize: 320},
+	{ID: "tpl-gpu", Name: "sim.gpu", Cores: 8, RAMSize: 32, DiskSize: 160, GPUNumber: 1, GPUType: "simulated"},
+}
+
+// server is the in-memory representation of a host
+type server struct {
+	id                      string
+	name                    string
+	state                   hoststate.Enum
+	templateID              string
+	imageID                 string
+	networkIDs              []string
+	privateIPs              map[string]string
+	publicIP                string
+	isGateway               bool
+	defaultGatewayID        string
+	defaultGatewayPrivateIP string
+	created                 time.Time
+	updated                 time.Time
+}
+
+// ListImages lists available OS images
+func (s *Stack) ListImages() ([]abstract.Image, fail.Error) {
+	if err := s.simulate("ListImages"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var list []abstract.Image
+	for _, img := range s.images {
+		list = append(list, *img)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// GetImage returns the Image referenced by id
+func (s *Stack) GetImage(id string) (*abstract.Image, fail.Error) {
+	if err := s.simulate("GetImage"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	img, ok := s.images[id]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("image", id)
+	}
+	clone := *img
+	return &clone, nil
+}
+
+// CreateImageFromHost creates an image from the current content of the host
+func (s *Stack) CreateImageFromHost(hostID string, name string) (*abstract.Image, fail.Error) {
+	if err := s.simulate("CreateImageFromHost"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	srv, ok := s.hosts[hostID]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("host", hostID)
+	}
+	for _, img := range s.images {
+		if img.Name == name {
+			return nil, abstract.ResourceDuplicateError("image", name)
+		}
+	}
+
+	img := &abstract.Image{
+		ID:          s.newID("img"),
+		Name:        name,
+		Description: fmt.Sprintf("captured from host '%s'", srv.name),
+	}
+	img.URL = "simulator://images/" + img.ID
+	if tpl, ok := s.templates[srv.templateID]; ok {
+		img.DiskSize = int64(tpl.DiskSize)
+	}
+	s.images[img.ID] = img
+
+	clone := *img
+	return &clone, nil
+}
+
+// DeleteImage deletes the image identified by id
+func (s *Stack) DeleteImage(id string) fail.Error {
+	if err := s.simulate("DeleteImage"); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.images[id]; !ok {
+		return abstract.ResourceNotFoundError("image", id)
+	}
+	delete(s.images, id)
+	return nil
+}
+
+// ListTemplates lists available host templates
+func (s *Stack) ListTemplates() ([]abstract.HostTemplate, fail.Error) {
+	if err := s.simulate("ListTemplates"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var list []abstract.HostTemplate
+	for _, tpl := range s.templates {
+		list = append(list, *tpl)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// GetTemplate returns the template referenced by id
+func (s *Stack) GetTemplate(id string) (*abstract.HostTemplate, fail.Error) {
+	if err := s.simulate("GetTemplate"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tpl, ok := s.templates[id]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("template", id)
+	}
+	clone := *tpl
+	return &clone, nil
+}
+
+// CreateKeyPair creates and import a key pair
+func (s *Stack) CreateKeyPair(name string) (*abstract.KeyPair, fail.Error) {
+	if err := s.simulate("CreateKeyPair"); err != nil {
+		return nil, err
+	}
+
+	privKey, pubKey, err := crypt.GenerateRSAKeyPair(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.keypairs[name]; ok {
+		return nil, abstract.ResourceDuplicateError("keypair", name)
+	}
+	kp := &abstract.KeyPair{
+		ID:         name,
+		Name:       name,
+		PrivateKey: privKey,
+		PublicKey:  pubKey,
+	}
+	s.keypairs[name] = kp
+
+	clone := *kp
+	return &clone, nil
+}
+
+// GetKeyPair returns the key pair identified by id
+func (s *Stack) GetKeyPair(id string) (*abstract.KeyPair, fail.Error) {
+	if err := s.simulate("GetKeyPair"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	kp, ok := s.keypairs[id]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("keypair", id)
+	}
+	clone := *kp
+	return &clone, nil
+}
+
+// ListKeyPairs lists available key pairs
+func (s *Stack) ListKeyPairs() ([]abstract.KeyPair, fail.Error) {
+	if err := s.simulate("ListKeyPairs"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var list []abstract.KeyPair
+	for _, kp := range s.keypairs {
+		list = append(list, *kp)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteKeyPair deletes the key pair identified by id
+func (s *Stack) DeleteKeyPair(id string) fail.Error {
+	if err := s.simulate("DeleteKeyPair"); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.keypairs[id]; !ok {
+		return abstract.ResourceNotFoundError("keypair", id)
+	}
+	delete(s.keypairs, id)
+	return nil
+}
+
+// CreateHost creates an host satisfying request
+func (s *Stack) CreateHost(request abstract.HostRequest) (*abstract.Host, *userdata.Content, fail.Error) {
+	if err := s.simulate("CreateHost"); err != nil {
+		return nil, nil, err
+	}
+	return s.createHost(request)
+}
+
+// createHost creates the host without simulating latency and failure, used by CreateHost and CreateGateway
+func (s *Stack) createHost(request abstract.HostRequest) (host *abstract.Host, userData *userdata.Content, xerr fail.Error) {
+	userData = userdata.NewContent()
+
+	resourceName := request.ResourceName
+	if resourceName == "" {
+		return nil, userData, fail.InvalidParameterError("request.ResourceName", "cannot be empty string")
+	}
+	if len(request.Networks) == 0 {
+		return nil, userData, fail.InvalidRequestError(
+			fmt.Sprintf("the host %s must be on at least one network (even if public)", resourceName),
+		)
+	}
+
+	// The Default Network is the first of the provided list, by convention
+	defaultNetwork := request.Networks[0]
+	defaultGateway := request.DefaultGateway
+	isGateway := defaultGateway == nil && defaultNetwork.Name != abstract.SingleHostNetworkName
+	if defaultGateway == nil && !request.PublicIP {
+		return nil, userData, fail.InvalidRequestError(
+			fmt.Sprintf("the host %s must have a gateway or be public", resourceName),
+		)
+	}
+
+	// If no password is provided, create one
+	if request.Password == "" {
+		password, err := utils.GeneratePassword(16)
+		if err != nil {
+			return nil, userData, fail.Errorf(fmt.Sprintf("failed to generate password: %s", err.Error()), err)
+		}
+		request.Password = password
+	}
+
+	// If no key pair is supplied, create one
+	if request.KeyPair == nil {
+		kp, err := abstract.NewKeyPair(resourceName)
+		if err != nil {
+			return nil, userData, fail.Errorf(fmt.Sprintf("failed to create host key pair: %s", err.Error()), err)
+		}
+		request.KeyPair = kp
+	}
+
+	// Constructs userdata content, even if never executed, to behave like real stacks
+	err := userData.Prepare(*s.Config, request, defaultNetwork.CIDR, "")
+	if err != nil {
+		return nil, userData, fail.Errorf(fmt.Sprintf("failed to prepare user data content: %+v", err), err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.templates[request.TemplateID]; !ok {
+		return nil, userData, abstract.ResourceNotFoundError("template", request.TemplateID)
+	}
+	if _, ok := s.images[request.ImageID]; !ok {
+		return nil, userData, abstract.ResourceNotFoundError("image", request.ImageID)
+	}
+	for _, srv := range s.hosts {
+		if srv.name == resourceName {
+			return nil, userData, abstract.ResourceDuplicateError("host", resourceName)
+		}
+	}
+
+	now := time.Now()
+	srv := &server{
+		id:         s.newID("host"),
+		name:       resourceName,
+		state:      hoststate.STARTED,
+		templateID: request.TemplateID,
+		imageID:    request.ImageID,
+		privateIPs: map[string]string{},
+		isGateway:  isGateway,
+		created:    now,
+		updated:    now,
+	}
+	for _, n := range request.Networks {
+		simNet, ok := s.networks[n.ID]
+		if !ok {
+			return nil, userData, abstract.ResourceNotFoundError("network", n.ID)
+		}
+		ip, err := simNet.ips.allocate()
+		if err != nil {
+			return nil, userData, err
+		}
+		srv.networkIDs = append(srv.networkIDs, n.ID)
+		srv.privateIPs[n.ID] = ip
+	}
+	if request.PublicIP {
+		ip, err := s.publicIPs.allocate()
+		if err != nil {
+			return nil, userData, err
+		}
+		srv.publicIP = ip
+	}
+	if defaultGateway != nil {
+		srv.defaultGatewayID = defaultGateway.ID
+		if gw, ok := s.hosts[defaultGateway.ID]; ok {
+			srv.defaultGatewayPrivateIP = gw.privateIPs[defaultNetwork.ID]
+		}
+	}
+	s.hosts[srv.id] = srv
+
+	host = abstract.NewHost()
+	host.PrivateKey = request.KeyPair.PrivateKey
+	host.Password = request.Password
+	err = s.complementHost(host, srv)
+	if err != nil {
+		delete(s.hosts, srv.id)
+		return nil, userData, fail.Wrap(err, "failed to build host")
+	}
+
+	logrus.Debugf("simulator: host '%s' created with id '%s'", srv.name, srv.id)
+	return host, userData, nil
+}
+
+// complementHost fills the host with the content of the server
+// Must be called with s.lock held
+func (s *Stack) complementHost(host *abstract.Host, srv *server) error {
+	host.ID = srv.id
+	host.Name = srv.name
+	host.LastState = srv.state
+
+	err := host.Properties.LockForWrite(hostproperty.DescriptionV1).ThenUse(
+		func(clonable data.Clonable) error {
+			hostDescriptionV1 := clonable.(*propsv1.HostDescription)
+			if hostDescriptionV1.Created.IsZero() {
+				hostDescriptionV1.Created = srv.created
+			}
+			hostDescriptionV1.Updated = srv.updated
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = host.Properties.LockForWrite(hostproperty.SizingV1).ThenUse(
+		func(clonable data.Clonable) error {
+			hostSizingV1 := clonable.(*propsv1.HostSizing)
+			if tpl, ok := s.templates[srv.templateID]; ok {
+				hostSizingV1.Template = tpl.ID
+				hostSizingV1.AllocatedSize = properties.ModelHostTemplateToPropertyHostSize(tpl)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(
+		func(clonable data.Clonable) error {
+			hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+			hostNetworkV1.IsGateway = srv.isGateway
+			hostNetworkV1.DefaultGatewayID = srv.defaultGatewayID
+			hostNetworkV1.DefaultGatewayPrivateIP = srv.defaultGatewayPrivateIP
+			hostNetworkV1.PublicIPv4 = srv.publicIP
+			if len(srv.networkIDs) > 0 {
+				hostNetworkV1.DefaultNetworkID = srv.networkIDs[0]
+			}
+			hostNetworkV1.NetworksByID = map[string]string{}
+			hostNetworkV1.NetworksByName = map[string]string{}
+			hostNetworkV1.IPv4Addresses = map[string]string{}
+			for _, id := range srv.networkIDs {
+				name := ""
+				if n, ok := s.networks[id]; ok {
+					name = n.name
+					hostNetworkV1.NetworksByName[name] = id
+				}
+				hostNetworkV1.NetworksByID[id] = name
+				hostNetworkV1.IPv4Addresses[id] = srv.privateIPs[id]
+			}
+			return nil
+		},
+	)
+}
+
+// findServer returns the server corresponding to the host parameter (either an ID, or an *abstract.Host)
+// Must be called with s.lock held
+func (s *Stack) findServer(hostParam interface{}) (*server, *abstract.Host, fail.Error) {
+	var host *abstract.Host
+	switch hostParam := hostParam.(type) {
+	case string:
+		if hostParam == "" {
+			return nil, nil, fail.InvalidParameterError("hostParam", "cannot be an empty string")
+		}
+		host = abstract.NewHost()
+		host.ID = hostParam
+	case *abstract.Host:
+		if hostParam == nil {
+			return nil, nil, fail.InvalidParameterError("hostParam", "cannot be nil")
+		}
+		host = hostParam
+	default:
+		return nil, nil, fail.InvalidParameterError("hostParam", "must be a string or a *abstract.Host")
+	}
+
+	if srv, ok := s.hosts[host.ID]; ok {
+		return srv, host, nil
+	}
+	if host.ID == "" {
+		for _, srv := range s.hosts {
+			if srv.name == host.Name {
+				return srv, host, nil
+			}
+		}
+	}
+	ref := host.ID
+	if ref == "" {
+		ref = host.Name
+	}
+	return nil, nil, abstract.ResourceNotFoundError("host", ref)
+}
+
+// InspectHost gathers host information from provider
+func (s *Stack) InspectHost(hostParam interface{}) (*abstract.Host, fail.Error) {
+	if err := s.simulate("InspectHost"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	srv, host, err := s.findServer(hostParam)
+	if err != nil {
+		return nil, err
+	}
+	if inErr := s.complementHost(host, srv); inErr != nil {
+		return nil, fail.Wrap(inErr, "failed to build host")
+	}
+	return host, nil
+}
+
+// GetHostByName returns the host using the name passed as parameter
+func (s *Stack) GetHostByName(name string) (*abstract.Host, fail.Error) {
+	if err := s.simulate("GetHostByName"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, srv := range s.hosts {
+		if srv.name == name {
+			host := abstract.NewHost()
+			if err := s.complementHost(host, srv); err != nil {
+				return nil, fail.Wrap(err, "failed to build host")
+			}
+			return host, nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("host", name)
+}
+
+// GetHostState returns the current state of the host
+func (s *Stack) GetHostState(hostParam interface{}) (hoststate.Enum, fail.Error) {
+	if err := s.simulate("GetHostState"); err != nil {
+		return hoststate.ERROR, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	srv, _, err := s.findServer(hostParam)
+	if err != nil {
+		return hoststate.ERROR, err
+	}
+	return srv.state, nil
+}
+
+// ListHosts lists all hosts
+func (s *Stack) ListHosts() ([]*abstract.Host, fail.Error) {
+	if err := s.simulate("ListHosts"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var list []*abstract.Host
+	for _, srv := range s.hosts {
+		host := abstract.NewHost()
+		if err := s.complementHost(host, srv); err != nil {
+			return nil, fail.Wrap(err, "failed to build host")
+		}
+		list = append(list, host)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteHost deletes the host identified by id
+func (s *Stack) DeleteHost(id string) fail.Error {
+	if err := s.simulate("DeleteHost"); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.deleteHost(id)
+}
+
+// deleteHost removes the host and detaches everything bound to it
+// Must be called with s.lock held
+func (s *Stack) deleteHost(id string) fail.Error {
+	srv, ok := s.hosts[id]
+	if !ok {
+		return abstract.ResourceNotFoundError("host", id)
+	}
+
+	// Volumes attached are released, as a cloud provider would do
+	for attID, att := range s.attachments {
+		if att.ServerID == srv.id {
+			if vol, ok := s.volumes[att.VolumeID]; ok {
+				vol.State = volumestate.AVAILABLE
+			}
+			delete(s.attachments, attID)
+		}
+	}
+	for _, vip := range s.vips {
+		vip.Hosts = removeString(vip.Hosts, srv.id)
+	}
+	for _, sg := range s.securityGroups {
+		delete(sg.hosts, srv.id)
+	}
+	for _, n := range s.networks {
+		if n.gatewayID == srv.id {
+			n.gatewayID = ""
+		}
+	}
+	delete(s.hosts, id)
+	return nil
+}
+
+// setHostState changes the state of the host identified by id
+func (s *Stack) setHostState(operation string, id string, state hoststate.Enum) fail.Error {
+	if err := s.simulate(operation); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	srv, ok := s.hosts[id]
+	if !ok {
+		return abstract.ResourceNotFoundError("host", id)
+	}
+	srv.state = state
+	srv.updated = time.Now()
+	return nil
+}
+
+// StopHost stops the host identified by id
+func (s *Stack) StopHost(id string) fail.Error {
+	return s.setHostState("StopHost", id, hoststate.STOPPED)
+}
+
+// StartHost starts the host identified by id
+func (s *Stack) StartHost(id string) fail.Error {
+	return s.setHostState("StartHost", id, hoststate.STARTED)
+}
+
+// RebootHost reboots the host identified by id
+func (s *Stack) RebootHost(id string) fail.Error {
+	return s.setHostState("RebootHost", id, hoststate.STARTED)
+}
+
+// ResizeHost changes the template used by the host, choosing the smallest one satisfying request
+func (s *Stack) ResizeHost(id string, request abstract.SizingRequirements) (*abstract.Host, fail.Error) {
+	if err := s.simulate("ResizeHost"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	srv, ok := s.hosts[id]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("host", id)
+	}
+
+	var selected *abstract.HostTemplate
+	for _, tpl := range s.templates {
+		if tpl.Cores < request.MinCores || tpl.RAMSize < request.MinRAMSize || tpl.DiskSize < request.MinDiskSize || tpl.GPUNumber < request.MinGPU {
+			continue
+		}
+		if request.MaxCores > 0 && tpl.Cores > request.MaxCores {
+			continue
+		}
+		if request.MaxRAMSize > 0 && tpl.RAMSize > request.MaxRAMSize {
+			continue
+		}
+		if selected == nil || tpl.Cores < selected.Cores || (tpl.Cores == selected.Cores && tpl.RAMSize < selected.RAMSize) {
+			selected = tpl
+		}
+	}
+	if selected == nil {
+		return nil, fail.InvalidRequestError("no template satisfies the sizing requirements")
+	}
+	srv.templateID = selected.ID
+	srv.updated = time.Now()
+
+	host := abstract.NewHost()
+	if err := s.complementHost(host, srv); err != nil {
+		return nil, fail.Wrap(err, "failed to build host")
+	}
+	return host, nil
+}
+
+// removeString returns list without the occurrences of value
+func removeString(list []string, value string) []string {
+	var result []string
+	for _, v := range list {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
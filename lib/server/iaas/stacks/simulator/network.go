@@ -0,0 +1,365 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulator
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/ipversion"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// network is the in-memory representation of a network
+type network struct {
+	id        string
+	name      string
+	cidr      string
+	ipVersion ipversion.Enum
+	domain    string
+	gatewayID string
+	ips       *ipAllocator
+}
+
+// toAbstractNetwork converts the in-memory network to an *abstract.Network
+func (n *network) toAbstractNetwork() *abstract.Network {
+	an := abstract.NewNetwork()
+	an.ID = n.id
+	an.Name = n.name
+	an.CIDR = n.cidr
+	an.IPVersion = n.ipVersion
+	an.Domain = n.domain
+	an.GatewayID = n.gatewayID
+	return an
+}
+
+// CreateNetwork creates a network
+func (s *Stack) CreateNetwork(req abstract.NetworkRequest) (*abstract.Network, fail.Error) {
+	if err := s.simulate("CreateNetwork"); err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	if req.IPVersion == ipversion.IPv6 {
+		return nil, fail.InvalidRequestError("IPv6 networks are not simulated")
+	}
+	_, ipnet, err := net.ParseCIDR(req.CIDR)
+	if err != nil {
+		return nil, fail.InvalidRequestError(fmt.Sprintf("'%s' is not a valid CIDR: %v", req.CIDR, err))
+	}
+	ips, xerr := newIPAllocator(ipnet.String())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, n := range s.networks {
+		if n.name == req.Name {
+			return nil, abstract.ResourceDuplicateError("network", req.Name)
+		}
+	}
+
+	n := &network{
+		id:        s.newID("net"),
+		name:      req.Name,
+		cidr:      ipnet.String(),
+		ipVersion: ipversion.IPv4,
+		domain:    req.Domain,
+		ips:       ips,
+	}
+	s.networks[n.id] = n
+	return n.toAbstractNetwork(), nil
+}
+
+// GetNetwork returns the network identified by id
+func (s *Stack) GetNetwork(id string) (*abstract.Network, fail.Error) {
+	if err := s.simulate("GetNetwork"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if n, ok := s.networks[id]; ok {
+		return n.toAbstractNetwork(), nil
+	}
+	// Like most of the providers, accepts also the name of the network
+	for _, n := range s.networks {
+		if n.name == id {
+			return n.toAbstractNetwork(), nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("network", id)
+}
+
+// GetNetworkByName returns the network identified by name
+func (s *Stack) GetNetworkByName(name string) (*abstract.Network, fail.Error) {
+	if err := s.simulate("GetNetworkByName"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, n := range s.networks {
+		if n.name == name {
+			return n.toAbstractNetwork(), nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("network", name)
+}
+
+// ListNetworks lists all networks
+func (s *Stack) ListNetworks() ([]*abstract.Network, fail.Error) {
+	if err := s.simulate("ListNetworks"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var list []*abstract.Network
+	for _, n := range s.networks {
+		list = append(list, n.toAbstractNetwork())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteNetwork deletes the network identified by id
+func (s *Stack) DeleteNetwork(id string) fail.Error {
+	if err := s.simulate("DeleteNetwork"); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.networks[id]; !ok {
+		return abstract.ResourceNotFoundError("network", id)
+	}
+	// As a real provider, refuses to delete a network still in use
+	for _, srv := range s.hosts {
+		if _, ok := srv.privateIPs[id]; ok {
+			return fail.InvalidRequestError(fmt.Sprintf("network '%s' is still used by host '%s'", id, srv.name))
+		}
+	}
+	for _, vip := range s.vips {
+		if vip.NetworkID == id {
+			return fail.InvalidRequestError(fmt.Sprintf("network '%s' is still used by VIP '%s'", id, vip.Name))
+		}
+	}
+	delete(s.networks, id)
+	return nil
+}
+
+// CreateGateway creates a public Gateway for a private network
+func (s *Stack) CreateGateway(req abstract.GatewayRequest, sizing *abstract.SizingRequirements) (*abstract.Host, *userdata.Content, fail.Error) {
+	if err := s.simulate("CreateGateway"); err != nil {
+		return nil, nil, err
+	}
+
+	if req.Network == nil {
+		return nil, nil, fail.InvalidParameterError("req.Network", "cannot be nil")
+	}
+	gwname := strings.Split(req.Name, ".")[0] // req.Name may contain a FQDN...
+	if gwname == "" {
+		gwname = "gw-" + req.Network.Name
+	}
+
+	password, err := utils.GeneratePassword(16)
+	if err != nil {
+		return nil, nil, fail.Errorf(fmt.Sprintf("failed to generate password: %s", err.Error()), err)
+	}
+	hostReq := abstract.HostRequest{
+		ImageID:      req.ImageID,
+		KeyPair:      req.KeyPair,
+		HostName:     req.Name,
+		ResourceName: gwname,
+		TemplateID:   req.TemplateID,
+		Networks:     []*abstract.Network{req.Network},
+		PublicIP:     true,
+		Labels:       req.Labels,
+		Password:     password,
+	}
+	if sizing != nil && sizing.MinDiskSize > 0 {
+		hostReq.DiskSize = sizing.MinDiskSize
+	}
+	host, userData, xerr := s.createHost(hostReq)
+	if xerr != nil {
+		return nil, userData, fail.Wrap(xerr, "error creating gateway")
+	}
+
+	s.lock.Lock()
+	if n, ok := s.networks[req.Network.ID]; ok && n.gatewayID == "" {
+		n.gatewayID = host.ID
+	}
+	s.lock.Unlock()
+
+	err = host.Properties.LockForWrite(hostproperty.SizingV1).ThenUse(
+		func(clonable data.Clonable) error {
+			hostSizingV1 := clonable.(*propsv1.HostSizing)
+			hostSizingV1.Template = req.TemplateID
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, userData, fail.Wrap(err, "error creating gateway")
+	}
+	return host, userData, nil
+}
+
+// DeleteGateway deletes the gateway identified by id
+func (s *Stack) DeleteGateway(id string) fail.Error {
+	if err := s.simulate("DeleteGateway"); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.deleteHost(id)
+}
+
+// CreateVIP creates a private virtual IP
+func (s *Stack) CreateVIP(networkID string, name string) (*abstract.VirtualIP, fail.Error) {
+	if err := s.simulate("CreateVIP"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	n, ok := s.networks[networkID]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("network", networkID)
+	}
+	ip, err := n.ips.allocate()
+	if err != nil {
+		return nil, err
+	}
+	vip := abstract.NewVirtualIP()
+	vip.ID = s.newID("vip")
+	vip.Name = name
+	vip.NetworkID = networkID
+	vip.PrivateIP = ip
+	s.vips[vip.ID] = vip
+	return vip.Clone().(*abstract.VirtualIP), nil
+}
+
+// AddPublicIPToVIP adds a public IP to the virtual IP
+func (s *Stack) AddPublicIPToVIP(vip *abstract.VirtualIP) fail.Error {
+	if err := s.simulate("AddPublicIPToVIP"); err != nil {
+		return err
+	}
+	if vip == nil {
+		return fail.InvalidParameterError("vip", "cannot be nil")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stored, ok := s.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("VIP", vip.ID)
+	}
+	if stored.PublicIP == "" {
+		ip, err := s.publicIPs.allocate()
+		if err != nil {
+			return err
+		}
+		stored.PublicIP = ip
+		stored.PublicIPID = "pip-" + stored.ID
+	}
+	vip.PublicIP = stored.PublicIP
+	vip.PublicIPID = stored.PublicIPID
+	return nil
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the virtual IP
+func (s *Stack) BindHostToVIP(vip *abstract.VirtualIP, hostID string) fail.Error {
+	if err := s.simulate("BindHostToVIP"); err != nil {
+		return err
+	}
+	if vip == nil {
+		return fail.InvalidParameterError("vip", "cannot be nil")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stored, ok := s.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("VIP", vip.ID)
+	}
+	if _, ok := s.hosts[hostID]; !ok {
+		return abstract.ResourceNotFoundError("host", hostID)
+	}
+	stored.Hosts = append(removeString(stored.Hosts, hostID), hostID)
+	return nil
+}
+
+// UnbindHostFromVIP removes the host passed as parameter from the allowed targets of the virtual IP
+func (s *Stack) UnbindHostFromVIP(vip *abstract.VirtualIP, hostID string) fail.Error {
+	if err := s.simulate("UnbindHostFromVIP"); err != nil {
+		return err
+	}
+	if vip == nil {
+		return fail.InvalidParameterError("vip", "cannot be nil")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stored, ok := s.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("VIP", vip.ID)
+	}
+	stored.Hosts = removeString(stored.Hosts, hostID)
+	return nil
+}
+
+// DeleteVIP deletes the virtual IP
+func (s *Stack) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
+	if err := s.simulate("DeleteVIP"); err != nil {
+		return err
+	}
+	if vip == nil {
+		return fail.InvalidParameterError("vip", "cannot be nil")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.vips[vip.ID]; !ok {
+		return abstract.ResourceNotFoundError("VIP", vip.ID)
+	}
+	delete(s.vips, vip.ID)
+	return nil
+}
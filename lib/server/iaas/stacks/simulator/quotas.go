@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulator
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// GetQuotas returns the quotas set in the tenant configuration
+func (s *Stack) GetQuotas() (*abstract.Quotas, fail.Error) {
+	if err := s.simulate("GetQuotas"); err != nil {
+		return nil, err
+	}
+
+	quotas := abstract.NewQuotas()
+	for name, value := range s.SimConfig.Quotas {
+		switch name {
+		case "Cores":
+			quotas.Cores = value
+		case "RAMSize":
+			quotas.RAMSize = float32(value)
+		case "Instances":
+			quotas.Instances = value
+		case "Volumes":
+			quotas.Volumes = value
+		case "VolumeSize":
+			quotas.VolumeSize = value
+		case "Networks":
+			quotas.Networks = value
+		case "PublicIPs":
+			quotas.PublicIPs = value
+		}
+	}
+	return quotas, nil
+}
+
+// GetUsage returns the amount of resources held in memory
+func (s *Stack) GetUsage() (*abstract.Usage, fail.Error) {
+	if err := s.simulate("GetUsage"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	usage := &abstract.Usage{
+		Instances: len(s.hosts),
+		Volumes:   len(s.volumes),
+		Networks:  len(s.networks),
+	}
+	for _, srv := range s.hosts {
+		if tpl, ok := s.templates[srv.templateID]; ok {
+			usage.Cores += tpl.Cores
+			usage.RAMSize += tpl.RAMSize
+		}
+		if srv.publicIP != "" {
+			usage.PublicIPs++
+		}
+	}
+	for _, vip := range s.vips {
+		if vip.PublicIP != "" {
+			usage.PublicIPs++
+		}
+	}
+	for _, v := range s.volumes {
+		usage.VolumeSize += v.Size
+	}
+	return usage, nil
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/securitygroupruledirection"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// securityGroup is the in-memory representation of a security group, with its bindings
+type securityGroup struct {
+	group    *abstract.SecurityGroup
+	hosts    map[string]bool
+	networks map[string]bool
+}
+
+// findSecurityGroup returns the security group identified by ID or name
+// Must be called with s.lock held
+func (s *Stack) findSecurityGroup(ref string) (*securityGroup, fail.Error) {
+	if sg, ok := s.securityGroups[ref]; ok {
+		return sg, nil
+	}
+	for _, sg := range s.securityGroups {
+		if sg.group.Name == ref {
+			return sg, nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("security group", ref)
+}
+
+// ListSecurityGroups lists the security groups
+func (s *Stack) ListSecurityGroups() ([]*abstract.SecurityGroup, fail.Error) {
+	if err := s.simulate("ListSecurityGroups"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var list []*abstract.SecurityGroup
+	for _, sg := range s.securityGroups {
+		list = append(list, sg.group.Clone().(*abstract.SecurityGroup))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// CreateSecurityGroup creates a security group with the rules of the request
+func (s *Stack) CreateSecurityGroup(req abstract.SecurityGroupRequest) (*abstract.SecurityGroup, fail.Error) {
+	if err := s.simulate("CreateSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, sg := range s.securityGroups {
+		if sg.group.Name == req.Name {
+			return nil, abstract.ResourceDuplicateError("security group", req.Name)
+		}
+	}
+
+	group := abstract.NewSecurityGroup()
+	group.ID = s.newID("sg")
+	group.Name = req.Name
+	group.Description = req.Description
+	for _, rule := range req.Rules {
+		if err := s.appendRule(group, rule); err != nil {
+			return nil, err
+		}
+	}
+	s.securityGroups[group.ID] = &securityGroup{
+		group:    group,
+		hosts:    map[string]bool{},
+		networks: map[string]bool{},
+	}
+	return group.Clone().(*abstract.SecurityGroup), nil
+}
+
+// appendRule validates the rule and adds it to the group, with a new ID
+// Must be called with s.lock held
+func (s *Stack) appendRule(group *abstract.SecurityGroup, rule abstract.SecurityGroupRule) fail.Error {
+	switch rule.Direction {
+	case securitygroupruledirection.INGRESS, securitygroupruledirection.EGRESS:
+	default:
+		return fail.InvalidParameterError("rule.Direction", "must be INGRESS or EGRESS")
+	}
+	if rule.PortTo == 0 {
+		rule.PortTo = rule.PortFrom
+	}
+	rule.ID = s.newID("sgr")
+	group.Rules = append(group.Rules, rule)
+	return nil
+}
+
+// InspectSecurityGroup returns the security group identified by id or name
+func (s *Stack) InspectSecurityGroup(ref string) (*abstract.SecurityGroup, fail.Error) {
+	if err := s.simulate("InspectSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sg, err := s.findSecurityGroup(ref)
+	if err != nil {
+		return nil, err
+	}
+	return sg.group.Clone().(*abstract.SecurityGroup), nil
+}
+
+// DeleteSecurityGroup deletes the security group identified by id
+func (s *Stack) DeleteSecurityGroup(id string) fail.Error {
+	if err := s.simulate("DeleteSecurityGroup"); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sg, ok := s.securityGroups[id]
+	if !ok {
+		return abstract.ResourceNotFoundError("security group", id)
+	}
+	if len(sg.hosts) > 0 || len(sg.networks) > 0 {
+		return fail.InvalidRequestError(fmt.Sprintf("security group '%s' is still in use", sg.group.Name))
+	}
+	delete(s.securityGroups, id)
+	return nil
+}
+
+// AddRuleToSecurityGroup adds a rule to the security group identified by sgID
+func (s *Stack) AddRuleToSecurityGroup(sgID string, rule abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	if err := s.simulate("AddRuleToSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sg, ok := s.securityGroups[sgID]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("security group", sgID)
+	}
+	if err := s.appendRule(sg.group, rule); err != nil {
+		return nil, err
+	}
+	return sg.group.Clone().(*abstract.SecurityGroup), nil
+}
+
+// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by sgID
+func (s *Stack) DeleteRuleFromSecurityGroup(sgID string, ruleID string) (*abstract.SecurityGroup, fail.Error) {
+	if err := s.simulate("DeleteRuleFromSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sg, ok := s.securityGroups[sgID]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("security group", sgID)
+	}
+	for i, rule := range sg.group.Rules {
+		if rule.ID == ruleID {
+			sg.group.Rules = append(sg.group.Rules[:i], sg.group.Rules[i+1:]...)
+			return sg.group.Clone().(*abstract.SecurityGroup), nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("security group rule", ruleID)
+}
+
+// bindSecurityGroup records or removes the binding between a security group and a resource
+func (s *Stack) bindSecurityGroup(operation string, sgID string, kind string, resourceID string, bind bool) fail.Error {
+	if err := s.simulate(operation); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sg, ok := s.securityGroups[sgID]
+	if !ok {
+		return abstract.ResourceNotFoundError("security group", sgID)
+	}
+	bindings := sg.hosts
+	if kind == "network" {
+		bindings = sg.networks
+		if _, ok := s.networks[resourceID]; !ok && bind {
+			return abstract.ResourceNotFoundError(kind, resourceID)
+		}
+	} else if _, ok := s.hosts[resourceID]; !ok && bind {
+		return abstract.ResourceNotFoundError(kind, resourceID)
+	}
+	if bind {
+		bindings[resourceID] = true
+	} else {
+		delete(bindings, resourceID)
+	}
+	return nil
+}
+
+// BindSecurityGroupToHost applies the security group identified by sgID to the host identified by hostID
+func (s *Stack) BindSecurityGroupToHost(sgID string, hostID string) fail.Error {
+	return s.bindSecurityGroup("BindSecurityGroupToHost", sgID, "host", hostID, true)
+}
+
+// UnbindSecurityGroupFromHost removes the security group identified by sgID from the host identified by hostID
+func (s *Stack) UnbindSecurityGroupFromHost(sgID string, hostID string) fail.Error {
+	return s.bindSecurityGroup("UnbindSecurityGroupFromHost", sgID, "host", hostID, false)
+}
+
+// BindSecurityGroupToNetwork applies the security group identified by sgID to the network identified by networkID
+func (s *Stack) BindSecurityGroupToNetwork(sgID string, networkID string) fail.Error {
+	return s.bindSecurityGroup("BindSecurityGroupToNetwork", sgID, "network", networkID, true)
+}
+
+// UnbindSecurityGroupFromNetwork removes the security group identified by sgID from the network identified by networkID
+func (s *Stack) UnbindSecurityGroupFromNetwork(sgID string, networkID string) fail.Error {
+	return s.bindSecurityGroup("UnbindSecurityGroupFromNetwork", sgID, "network", networkID, false)
+}
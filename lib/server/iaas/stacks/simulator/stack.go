@@ -0,0 +1,190 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulator
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// publicCIDR is the network used to allocate simulated public IP addresses (TEST-NET-3, RFC 5737)
+const publicCIDR = "203.0.113.0/24"
+
+// Stack is an in-memory implementation of the stack API, keeping every resource in memory
+// It is meant to run SafeScale without cloud credentials (tests, development on a laptop, ...)
+type Stack struct {
+	Config      *stacks.ConfigurationOptions
+	AuthOptions *stacks.AuthenticationOptions
+	SimConfig   *stacks.SimulatorConfiguration
+
+	lock   sync.Mutex
+	random *rand.Rand
+	failOn map[string]bool
+	serial uint64
+
+	images         map[string]*abstract.Image
+	templates      map[string]*abstract.HostTemplate
+	keypairs       map[string]*abstract.KeyPair
+	networks       map[string]*network
+	hosts          map[string]*server
+	vips           map[string]*abstract.VirtualIP
+	securityGroups map[string]*securityGroup
+	volumes        map[string]*abstract.Volume
+	snapshots      map[string]*abstract.VolumeSnapshot
+	attachments    map[string]*abstract.VolumeAttachment
+	publicIPs      *ipAllocator
+}
+
+// New creates and initializes a simulator stack
+func New(auth stacks.AuthenticationOptions, simCfg stacks.SimulatorConfiguration, cfg stacks.ConfigurationOptions) (*Stack, fail.Error) {
+	if simCfg.FailureRate < 0 || simCfg.FailureRate > 1 {
+		return nil, fail.InvalidParameterError("simCfg.FailureRate", "must be between 0 and 1")
+	}
+	if simCfg.Latency < 0 {
+		return nil, fail.InvalidParameterError("simCfg.Latency", "cannot be negative")
+	}
+
+	seed := simCfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	publicIPs, err := newIPAllocator(publicCIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Stack{
+		Config:         &cfg,
+		AuthOptions:    &auth,
+		SimConfig:      &simCfg,
+		random:         rand.New(rand.NewSource(seed)), // nolint
+		failOn:         map[string]bool{},
+		images:         map[string]*abstract.Image{},
+		templates:      map[string]*abstract.HostTemplate{},
+		keypairs:       map[string]*abstract.KeyPair{},
+		networks:       map[string]*network{},
+		hosts:          map[string]*server{},
+		vips:           map[string]*abstract.VirtualIP{},
+		securityGroups: map[string]*securityGroup{},
+		volumes:        map[string]*abstract.Volume{},
+		snapshots:      map[string]*abstract.VolumeSnapshot{},
+		attachments:    map[string]*abstract.VolumeAttachment{},
+		publicIPs:      publicIPs,
+	}
+	for _, op := range simCfg.FailOn {
+		s.failOn[op] = true
+	}
+	for _, i := range defaultImages {
+		img := i
+		s.images[img.ID] = &img
+	}
+	for _, t := range defaultTemplates {
+		tpl := t
+		s.templates[tpl.ID] = &tpl
+	}
+	return s, nil
+}
+
+// GetConfigurationOptions ...
+func (s *Stack) GetConfigurationOptions() stacks.ConfigurationOptions {
+	return *s.Config
+}
+
+// GetAuthenticationOptions ...
+func (s *Stack) GetAuthenticationOptions() stacks.AuthenticationOptions {
+	return *s.AuthOptions
+}
+
+// ListRegions returns the region configured in the tenant, the only one simulated
+func (s *Stack) ListRegions() ([]string, fail.Error) {
+	if err := s.simulate("ListRegions"); err != nil {
+		return nil, err
+	}
+	return []string{s.AuthOptions.Region}, nil
+}
+
+// ListAvailabilityZones returns the availability zone configured in the tenant, the only one simulated
+func (s *Stack) ListAvailabilityZones() (map[string]bool, fail.Error) {
+	if err := s.simulate("ListAvailabilityZones"); err != nil {
+		return nil, err
+	}
+	return map[string]bool{s.AuthOptions.AvailabilityZone: true}, nil
+}
+
+// simulate applies the configured latency, then decides if the operation has to fail
+func (s *Stack) simulate(operation string) fail.Error {
+	if s.SimConfig.Latency > 0 {
+		time.Sleep(s.SimConfig.Latency)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.failOn[operation] || (s.SimConfig.FailureRate > 0 && s.random.Float64() < s.SimConfig.FailureRate) {
+		logrus.Debugf("simulator: injecting failure in '%s'", operation)
+		return fail.NotAvailableError(fmt.Sprintf("simulated failure of '%s'", operation))
+	}
+	return nil
+}
+
+// newID returns a new unique identifier for a resource of the kind passed as parameter
+// Must be called with s.lock held
+func (s *Stack) newID(kind string) string {
+	s.serial++
+	return fmt.Sprintf("%s-%08x", kind, s.serial)
+}
+
+// ipAllocator distributes sequentially the IP addresses of a CIDR
+type ipAllocator struct {
+	network *net.IPNet
+	next    uint32
+}
+
+// newIPAllocator creates an allocator for the IPv4 addresses of cidr
+func newIPAllocator(cidr string) (*ipAllocator, fail.Error) {
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fail.InvalidParameterError("cidr", fmt.Sprintf("'%s' is not a valid CIDR: %v", cidr, err))
+	}
+	if ipnet.IP.To4() == nil {
+		return nil, fail.InvalidParameterError("cidr", "only IPv4 networks are simulated")
+	}
+	// .0 is the network address, .1 is reserved as provider router
+	return &ipAllocator{network: ipnet, next: 2}, nil
+}
+
+// allocate returns the next free IP address of the network
+func (a *ipAllocator) allocate() (string, fail.Error) {
+	ones, bits := a.network.Mask.Size()
+	size := uint32(1) << uint(bits-ones)
+	if a.next >= size-1 {
+		return "", fail.NotAvailableError(fmt.Sprintf("no more IP address available in '%s'", a.network.String()))
+	}
+	base := a.network.IP.To4()
+	value := (uint32(base[0])<<24 | uint32(base[1])<<16 | uint32(base[2])<<8 | uint32(base[3])) + a.next
+	a.next++
+	return net.IPv4(byte(value>>24), byte(value>>16), byte(value>>8), byte(value)).String(), nil
+}
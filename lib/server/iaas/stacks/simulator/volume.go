@@ -0,0 +1,317 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// copyVolume returns a copy of the volume, with its own properties
+func copyVolume(v *abstract.Volume) *abstract.Volume {
+	clone := abstract.NewVolume()
+	clone.ID = v.ID
+	clone.Name = v.Name
+	clone.Size = v.Size
+	clone.Speed = v.Speed
+	clone.State = v.State
+	return clone
+}
+
+// CreateVolume creates a block volume
+func (s *Stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	if err := s.simulate("CreateVolume"); err != nil {
+		return nil, err
+	}
+
+	if request.Name == "" {
+		return nil, fail.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if request.Size <= 0 && request.SnapshotID == "" {
+		return nil, fail.InvalidParameterError("request.Size", "must be greater than 0")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, v := range s.volumes {
+		if v.Name == request.Name {
+			return nil, abstract.ResourceDuplicateError("volume", request.Name)
+		}
+	}
+
+	size := request.Size
+	if request.SnapshotID != "" {
+		snapshot, ok := s.snapshots[request.SnapshotID]
+		if !ok {
+			return nil, abstract.ResourceNotFoundError("volume snapshot", request.SnapshotID)
+		}
+		if size < snapshot.Size {
+			size = snapshot.Size
+		}
+	}
+
+	volume := abstract.NewVolume()
+	volume.ID = s.newID("vol")
+	volume.Name = request.Name
+	volume.Size = size
+	volume.Speed = request.Speed
+	if volume.Speed == 0 {
+		volume.Speed = volumespeed.HDD
+	}
+	volume.State = volumestate.AVAILABLE
+	s.volumes[volume.ID] = volume
+	return copyVolume(volume), nil
+}
+
+// GetVolume returns the volume identified by id
+func (s *Stack) GetVolume(id string) (*abstract.Volume, fail.Error) {
+	if err := s.simulate("GetVolume"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	volume, ok := s.volumes[id]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("volume", id)
+	}
+	return copyVolume(volume), nil
+}
+
+// ListVolumes lists available volumes
+func (s *Stack) ListVolumes() ([]abstract.Volume, fail.Error) {
+	if err := s.simulate("ListVolumes"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var list []abstract.Volume
+	for _, v := range s.volumes {
+		list = append(list, *copyVolume(v))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteVolume deletes the volume identified by id
+func (s *Stack) DeleteVolume(id string) fail.Error {
+	if err := s.simulate("DeleteVolume"); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	volume, ok := s.volumes[id]
+	if !ok {
+		return abstract.ResourceNotFoundError("volume", id)
+	}
+	if _, ok := s.attachments[id]; ok {
+		return fail.InvalidRequestError(fmt.Sprintf("volume '%s' is still attached", volume.Name))
+	}
+	delete(s.volumes, id)
+	return nil
+}
+
+// CreateVolumeSnapshot creates a snapshot of a block volume
+func (s *Stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	if err := s.simulate("CreateVolumeSnapshot"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	volume, ok := s.volumes[request.VolumeID]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("volume", request.VolumeID)
+	}
+	if _, ok := s.attachments[volume.ID]; ok && !request.Force {
+		return nil, fail.InvalidRequestError(fmt.Sprintf("volume '%s' is attached, snapshot has to be forced", volume.Name))
+	}
+
+	snapshot := abstract.NewVolumeSnapshot()
+	snapshot.ID = s.newID("snap")
+	snapshot.Name = request.Name
+	snapshot.Description = request.Description
+	snapshot.VolumeID = volume.ID
+	snapshot.Size = volume.Size
+	snapshot.State = volumestate.AVAILABLE
+	snapshot.CreatedAt = time.Now()
+	s.snapshots[snapshot.ID] = snapshot
+
+	clone := *snapshot
+	return &clone, nil
+}
+
+// GetVolumeSnapshot returns the volume snapshot identified by id
+func (s *Stack) GetVolumeSnapshot(id string) (*abstract.VolumeSnapshot, fail.Error) {
+	if err := s.simulate("GetVolumeSnapshot"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshot, ok := s.snapshots[id]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("volume snapshot", id)
+	}
+	clone := *snapshot
+	return &clone, nil
+}
+
+// ListVolumeSnapshots lists available volume snapshots
+func (s *Stack) ListVolumeSnapshots() ([]abstract.VolumeSnapshot, fail.Error) {
+	if err := s.simulate("ListVolumeSnapshots"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var list []abstract.VolumeSnapshot
+	for _, snapshot := range s.snapshots {
+		list = append(list, *snapshot)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *Stack) DeleteVolumeSnapshot(id string) fail.Error {
+	if err := s.simulate("DeleteVolumeSnapshot"); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.snapshots[id]; !ok {
+		return abstract.ResourceNotFoundError("volume snapshot", id)
+	}
+	delete(s.snapshots, id)
+	return nil
+}
+
+// CreateVolumeAttachment attaches a volume to a host
+// The ID of the attachment is the ID of the volume, a volume being attachable to only one host
+func (s *Stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
+	if err := s.simulate("CreateVolumeAttachment"); err != nil {
+		return "", err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	volume, ok := s.volumes[request.VolumeID]
+	if !ok {
+		return "", abstract.ResourceNotFoundError("volume", request.VolumeID)
+	}
+	if _, ok := s.hosts[request.HostID]; !ok {
+		return "", abstract.ResourceNotFoundError("host", request.HostID)
+	}
+	if att, ok := s.attachments[volume.ID]; ok {
+		return "", fail.InvalidRequestError(fmt.Sprintf("volume '%s' is already attached to host '%s'", volume.Name, att.ServerID))
+	}
+
+	count := 0
+	for _, att := range s.attachments {
+		if att.ServerID == request.HostID {
+			count++
+		}
+	}
+	if count >= 25 {
+		return "", fail.NotAvailableError(fmt.Sprintf("no more device available on host '%s'", request.HostID))
+	}
+
+	s.attachments[volume.ID] = &abstract.VolumeAttachment{
+		ID:       volume.ID,
+		Name:     request.Name,
+		VolumeID: volume.ID,
+		ServerID: request.HostID,
+		Device:   fmt.Sprintf("/dev/vd%c", 'b'+count),
+	}
+	volume.State = volumestate.USED
+	return volume.ID, nil
+}
+
+// GetVolumeAttachment returns the volume attachment identified by id
+func (s *Stack) GetVolumeAttachment(serverID, id string) (*abstract.VolumeAttachment, fail.Error) {
+	if err := s.simulate("GetVolumeAttachment"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	att, ok := s.attachments[id]
+	if !ok || att.ServerID != serverID {
+		return nil, abstract.ResourceNotFoundError("volume attachment", id)
+	}
+	clone := *att
+	return &clone, nil
+}
+
+// ListVolumeAttachments lists available volume attachments of a host
+func (s *Stack) ListVolumeAttachments(serverID string) ([]abstract.VolumeAttachment, fail.Error) {
+	if err := s.simulate("ListVolumeAttachments"); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var list []abstract.VolumeAttachment
+	for _, att := range s.attachments {
+		if att.ServerID == serverID {
+			list = append(list, *att)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Device < list[j].Device })
+	return list, nil
+}
+
+// DeleteVolumeAttachment detaches the volume identified by id from the host identified by serverID
+func (s *Stack) DeleteVolumeAttachment(serverID, id string) fail.Error {
+	if err := s.simulate("DeleteVolumeAttachment"); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	att, ok := s.attachments[id]
+	if !ok || att.ServerID != serverID {
+		return abstract.ResourceNotFoundError("volume attachment", id)
+	}
+	if volume, ok := s.volumes[att.VolumeID]; ok {
+		volume.State = volumestate.AVAILABLE
+	}
+	delete(s.attachments, id)
+	return nil
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stacks
+
+import (
+	"time"
+)
+
+// SimulatorConfiguration stores the behavior of the in-memory simulator stack
+type SimulatorConfiguration struct {
+	// Latency is the delay applied to each call to the stack
+	Latency time.Duration
+	// FailureRate is the probability (between 0 and 1) for a call to fail with an injected error
+	FailureRate float64
+	// FailOn lists the operations (by method name) that always fail
+	FailOn []string
+	// Seed initializes the random generator used for failure injection (0 means seeded with current time)
+	Seed int64
+	// Quotas limits the resources reported as usable, indexed by name (Cores, RAMSize, Instances, Volumes,
+	// VolumeSize, Networks, PublicIPs); a missing name means unlimited
+	Quotas map[string]int
+}
@@ -48,6 +48,7 @@ import (
 
 	libvirt "github.com/CS-SI/SafeScale/lib/server/iaas/stacks/libvirt"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/openstack"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/simulator"
 
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/aws"            // Imported to initialize tenant ovh
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/cloudferro"     // Imported to initialize tenant ovh
@@ -56,6 +57,7 @@ import (
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/local"          // Imported to initialize tenant local
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/opentelekom"    // Imported to initialize tenant opentelekoms
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/ovh"            // Imported to initialize tenant ovh
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/simulator"      // Imported to initialize tenant simulator
 )
 
 // ServiceTester helper class to test clients
@@ -73,6 +75,7 @@ func (tester *ServiceTester) VerifyStacks(t *testing.T) {
 	stack = &gcp.Stack{}         // nolint
 	stack = &aws.Stack{}         // nolint
 	stack = &outscale.Stack{}    // nolint
+	stack = &simulator.Stack{}   // nolint
 	_ = stack
 }
 
@@ -90,6 +90,12 @@ type Feature struct {
 	task  concurrency.Task
 }
 
+// ClusterFeatureDescription describes a feature suitable for clusters, as listed by ListFeatures("cluster")
+type ClusterFeatureDescription struct {
+	FeatureName    string   `json:"feature"`
+	ClusterFlavors []string `json:"available-cluster-flavors"`
+}
+
 // ListFeatures lists all features suitable for hosts or clusters
 func ListFeatures(suitableFor string) ([]interface{}, error) {
 	features := allEmbeddedMap
@@ -135,10 +141,7 @@ func ListFeatures(suitableFor string) ([]interface{}, error) {
 			if feature.Specs().IsSet(yamlKey) {
 				values := strings.Split(strings.ToLower(feature.Specs().GetString(yamlKey)), ",")
 				if values[0] == "all" || values[0] == "dcos" || values[0] == "k8s" || values[0] == "boh" || values[0] == "swarm" || values[0] == "ohpc" {
-					cfg := struct {
-						FeatureName    string   `json:"feature"`
-						ClusterFlavors []string `json:"available-cluster-flavors"`
-					}{feature.displayName, []string{}}
+					cfg := ClusterFeatureDescription{feature.displayName, []string{}}
 
 					cfg.ClusterFlavors = append(cfg.ClusterFlavors, values...)
 
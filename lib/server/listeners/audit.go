@@ -0,0 +1,114 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// safescale audit list [--since 24h] [--resource host:foo]
+
+// AuditListener audit service server grpc
+type AuditListener struct{}
+
+// List returns the records of the audit log of the current tenant
+func (s *AuditListener) List(ctx context.Context, in *pb.AuditListRequest) (arl *pb.AuditRecordList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", in.GetSince(), in.GetResource()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Audit List"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	since, err := parseAuditSince(in.GetSince())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	tenant := getCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't list audit log: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list audit log: no tenant set")
+	}
+
+	records, err := metadata.ListAuditRecords(tenant.Service, since, in.GetResource())
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot list audit log"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return srvutils.ToPBAuditRecordList(records), nil
+}
+
+// parseAuditSince converts 'since', either a duration before now or a date in RFC3339 format, to a date;
+// an empty string means since the beginning of the audit log
+func parseAuditSince(since string) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fail.InvalidParameterError("since", fmt.Sprintf("'%s' is neither a duration nor a date in RFC3339 format", since))
+}
+
+// PurgeAuditLogPeriodically removes at start, then every day, the records of the audit logs of all the tenants older
+// than retention
+// It never returns and is meant to be run as a goroutine by the daemon.
+func PurgeAuditLogPeriodically(retention time.Duration) {
+	if retention <= 0 {
+		retention = abstract.DefaultAuditRetention
+	}
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	// purged at start too, a daemon restarted more often than daily would never rotate its audit log otherwise
+	for {
+		forEachTenant(
+			func(tenant *Tenant) {
+				count, err := metadata.PurgeAuditRecords(tenant.Service, retention)
+				if err != nil {
+					log.Warnf("failed to purge audit log of tenant '%s': %v", tenant.name, err)
+					return
+				}
+				log.Infof("%d records removed from audit log of tenant '%s'", count, tenant.name)
+			},
+		)
+		<-ticker.C
+	}
+}
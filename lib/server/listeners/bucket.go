@@ -19,6 +19,8 @@ package listeners
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 
@@ -29,6 +31,7 @@ import (
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
@@ -42,6 +45,9 @@ var BucketHandler = handlers.NewBucketHandler
 // safescale bucket delete c1
 // safescale bucket list
 // safescale bucket inspect C1
+// safescale bucket sync tenant1:c1 tenant2:c2
+// safescale bucket lifecycle set c1 --rule prefix=logs/,expire-after=30
+// safescale bucket url c1 o1 --ttl 1h
 
 // BucketListener is the bucket service grpc server
 type BucketListener struct{}
@@ -95,7 +101,11 @@ func (s *BucketListener) Create(ctx context.Context, in *pb.Bucket) (empty *goog
 	}
 
 	handler := BucketHandler(tenant.Service)
-	err = handler.Create(ctx, bucketName)
+	encryption := abstract.BucketEncryption{
+		Enabled:    in.GetEncrypted() || in.GetPassphrase() != "",
+		Passphrase: in.GetPassphrase(),
+	}
+	err = handler.Create(ctx, bucketName, encryption)
 	if err != nil {
 		tbr := fail.Wrap(err, "cannot create bucket"+adaptedUserMessage(err))
 		return nil, status.Errorf(codes.Internal, tbr.Message())
@@ -254,3 +264,183 @@ func (s *BucketListener) Unmount(ctx context.Context, in *pb.BucketMountingPoint
 	}
 	return &googleprotobuf.Empty{}, nil
 }
+
+// Sync replicates the objects of a bucket in another one, possibly of another tenant
+func (s *BucketListener) Sync(ctx context.Context, in *pb.BucketSyncRequest) (report *pb.BucketSyncReport, err error) {
+	source, destination := in.GetSource(), in.GetDestination()
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", source, destination), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Bucket Sync : "+source+" to "+destination); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	} else {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to register the process"+adaptedUserMessage(err))
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Cannot synchronize buckets: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot synchronize buckets: no tenant set")
+	}
+
+	handler := BucketHandler(tenant.Service)
+	options := abstract.BucketSyncOptions{
+		Delete:      in.GetDelete(),
+		Concurrency: int(in.GetConcurrency()),
+	}
+	resp, err := handler.Sync(ctx, source, destination, options)
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot synchronize buckets"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return srvutils.ToPBBucketSyncReport(resp), nil
+}
+
+// SetLifecycle replaces the lifecycle rules of a bucket
+func (s *BucketListener) SetLifecycle(ctx context.Context, in *pb.BucketLifecycle) (empty *googleprotobuf.Empty, err error) {
+	bucketName := in.GetBucket()
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", bucketName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Bucket SetLifecycle : "+bucketName); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	} else {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to register the process"+adaptedUserMessage(err))
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Cannot set lifecycle of bucket: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set lifecycle of bucket: no tenant set")
+	}
+
+	handler := BucketHandler(tenant.Service)
+	err = handler.SetLifecycle(ctx, bucketName, srvutils.FromPBBucketLifecycleRules(in.GetRules()))
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot set lifecycle of bucket"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return &googleprotobuf.Empty{}, nil
+}
+
+// GetLifecycle returns the lifecycle rules of a bucket
+func (s *BucketListener) GetLifecycle(ctx context.Context, in *pb.Bucket) (bl *pb.BucketLifecycle, err error) {
+	bucketName := in.GetName()
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", bucketName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Bucket GetLifecycle : "+bucketName); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	} else {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to register the process"+adaptedUserMessage(err))
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Cannot get lifecycle of bucket: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot get lifecycle of bucket: no tenant set")
+	}
+
+	handler := BucketHandler(tenant.Service)
+	resp, err := handler.GetLifecycle(ctx, bucketName)
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot get lifecycle of bucket"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return srvutils.ToPBBucketLifecycle(resp), nil
+}
+
+// ClearLifecycle removes the lifecycle rules of a bucket
+func (s *BucketListener) ClearLifecycle(ctx context.Context, in *pb.Bucket) (empty *googleprotobuf.Empty, err error) {
+	bucketName := in.GetName()
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", bucketName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Bucket ClearLifecycle : "+bucketName); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	} else {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to register the process"+adaptedUserMessage(err))
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Cannot clear lifecycle of bucket: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot clear lifecycle of bucket: no tenant set")
+	}
+
+	handler := BucketHandler(tenant.Service)
+	err = handler.ClearLifecycle(ctx, bucketName)
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot clear lifecycle of bucket"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return &googleprotobuf.Empty{}, nil
+}
+
+// PresignObject returns an URL giving access to an object without credentials during a limited time
+func (s *BucketListener) PresignObject(ctx context.Context, in *pb.BucketObjectURLRequest) (bou *pb.BucketObjectURL, err error) {
+	bucketName, objectName := in.GetBucket(), in.GetObject()
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", bucketName, objectName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Bucket PresignObject : "+bucketName+"/"+objectName); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	} else {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to register the process"+adaptedUserMessage(err))
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Cannot presign object: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot presign object: no tenant set")
+	}
+
+	ttl := time.Duration(in.GetTtl()) * time.Second
+	handler := BucketHandler(tenant.Service)
+	url, err := handler.Presign(ctx, bucketName, objectName, in.GetUpload(), ttl)
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot presign object"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	bou = &pb.BucketObjectURL{
+		Url:     url,
+		Method:  http.MethodGet,
+		Expires: time.Now().Add(ttl).Format(time.RFC3339),
+	}
+	if in.GetUpload() {
+		bou.Method = http.MethodPut
+	}
+	return bou, nil
+}
+
+// EnforceBucketLifecycles applies every interval the lifecycle rules of the buckets of all the tenants, the rules
+// being kept in the metadata of each tenant whatever the current one
+// It never returns and is meant to be run as a goroutine by the daemon.
+func EnforceBucketLifecycles(interval time.Duration) {
+	if interval <= 0 {
+		interval = abstract.DefaultBucketLifecycleInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		forEachTenant(
+			func(tenant *Tenant) {
+				err := BucketHandler(tenant.Service).EnforceLifecycles(context.Background())
+				if err != nil {
+					logrus.Warnf("failed to enforce lifecycle rules of buckets of tenant '%s': %v", tenant.name, err)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,1239 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/api"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/complexity"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/install"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// ClusterHandler ...
+var ClusterHandler = handlers.NewClusterHandler
+
+// ClusterListener cluster service server grpc
+type ClusterListener struct{}
+
+// Create creates a new cluster
+func (s *ClusterListener) Create(ctx context.Context, in *pb.ClusterDefinition) (c *pb.Cluster, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("in", "cannot be nil").Message(),
+		)
+	}
+	name := in.GetName()
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Create Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't create cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot create cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	instance, err := handler.Create(ctx, toClusterRequest(in, tenant.name))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	c, err = toPBCluster(concurrency.RootTask(), instance)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Cluster '%s' created", name)
+	return c, nil
+}
+
+// Estimate returns the cost of the infrastructure of the cluster that would be created with the definition
+func (s *ClusterListener) Estimate(ctx context.Context, in *pb.ClusterDefinition) (ce *pb.CostEstimate, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("in", "cannot be nil").Message(),
+		)
+	}
+	name := in.GetName()
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Estimate Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't estimate cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot estimate cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	estimate, err := handler.Estimate(ctx, toClusterRequest(in, tenant.name))
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, status.Errorf(codes.NotFound, getUserMessage(err))
+		}
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return srvutils.ToPBCostEstimate(estimate), nil
+}
+
+// List lists the clusters managed by SafeScale
+func (s *ClusterListener) List(ctx context.Context, in *googleprotobuf.Empty) (cl *pb.ClusterList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "List Clusters"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't list clusters: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list clusters: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	list, err := handler.List(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	var clusters []*pb.Cluster
+	for _, instance := range list {
+		c, err := toPBCluster(concurrency.RootTask(), instance)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		clusters = append(clusters, c)
+	}
+	return &pb.ClusterList{Clusters: clusters}, nil
+}
+
+// Inspect returns the description of a cluster
+func (s *ClusterListener) Inspect(ctx context.Context, in *pb.Reference) (c *pb.Cluster, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Inspect Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't inspect cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	instance, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		switch err.(type) {
+		case fail.ErrNotFound:
+			return nil, status.Errorf(codes.NotFound, getUserMessage(err))
+		default:
+			return nil, status.Errorf(codes.Internal, getUserMessage(err))
+		}
+	}
+
+	c, err = toPBCluster(concurrency.RootTask(), instance)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return c, nil
+}
+
+// State returns the current state of a cluster
+func (s *ClusterListener) State(ctx context.Context, in *pb.Reference) (cs *pb.ClusterState, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "State of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't get cluster state: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot get cluster state: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	state, err := handler.State(ctx, ref)
+	if err != nil {
+		switch err.(type) {
+		case fail.ErrNotFound:
+			return nil, status.Errorf(codes.NotFound, getUserMessage(err))
+		default:
+			return nil, status.Errorf(codes.Internal, getUserMessage(err))
+		}
+	}
+	return &pb.ClusterState{Name: ref, State: int32(state), StateLabel: state.String()}, nil
+}
+
+// Start starts a cluster
+func (s *ClusterListener) Start(ctx context.Context, in *pb.Reference) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Start Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't start cluster: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot start cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	err = handler.Start(ctx, ref)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Cluster '%s' successfully started", ref)
+	return empty, nil
+}
+
+// Stop stops a cluster
+func (s *ClusterListener) Stop(ctx context.Context, in *pb.Reference) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Stop Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't stop cluster: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot stop cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	err = handler.Stop(ctx, ref)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Cluster '%s' stopped", ref)
+	return empty, nil
+}
+
+// Delete deletes a cluster
+func (s *ClusterListener) Delete(ctx context.Context, in *pb.ClusterDeleteRequest) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	name := in.GetName()
+	if name == "" {
+		return empty, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("name", "cannot be empty string").Message(),
+		)
+	}
+	force := in.GetForce()
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', %v)", name, force), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Delete Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't delete cluster: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	err = handler.Delete(ctx, name, force)
+	if err != nil {
+		switch err.(type) {
+		case fail.ErrNotFound:
+			return empty, status.Errorf(codes.NotFound, getUserMessage(err))
+		default:
+			return empty, status.Errorf(codes.Internal, getUserMessage(err))
+		}
+	}
+
+	log.Infof("Cluster '%s' deleted", name)
+	return empty, nil
+}
+
+// Expand adds nodes to a cluster
+func (s *ClusterListener) Expand(ctx context.Context, in *pb.ClusterResizeRequest) (nl *pb.ClusterNodeList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("name", "cannot be empty string").Message(),
+		)
+	}
+	count := int(in.GetCount())
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', %d)", name, count), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Expand Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't expand cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot expand cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	nodes, err := handler.Expand(ctx, name, count, in.GetNodeDefinition())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Cluster '%s' expanded with %d node(s)", name, len(nodes))
+	return toPBClusterNodeList(nodes), nil
+}
+
+// Shrink removes the last added nodes from a cluster
+func (s *ClusterListener) Shrink(ctx context.Context, in *pb.ClusterResizeRequest) (nl *pb.ClusterNodeList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("name", "cannot be empty string").Message(),
+		)
+	}
+	count := int(in.GetCount())
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', %d)", name, count), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Shrink Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't shrink cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot shrink cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	nodes, err := handler.Shrink(ctx, name, count)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Cluster '%s' shrunk by %d node(s)", name, len(nodes))
+	return toPBClusterNodeList(nodes), nil
+}
+
+// InspectAutoscaling returns the autoscaling policy of a cluster
+func (s *ClusterListener) InspectAutoscaling(ctx context.Context, in *pb.Reference) (ca *pb.ClusterAutoscaling, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Inspect autoscaling of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't inspect cluster autoscaling: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect cluster autoscaling: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	policy, err := handler.InspectAutoscaling(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return toPBClusterAutoscaling(ref, policy), nil
+}
+
+// SetAutoscaling replaces the autoscaling policy of a cluster
+func (s *ClusterListener) SetAutoscaling(ctx context.Context, in *pb.ClusterAutoscaling) (ca *pb.ClusterAutoscaling, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("name", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	policy, err := fromPBClusterAutoscaling(in)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, getUserMessage(err))
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Set autoscaling of Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't set cluster autoscaling: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set cluster autoscaling: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	policy, err = handler.SetAutoscaling(ctx, name, policy)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Autoscaling of cluster '%s' set (enabled: %v)", name, policy.Enabled)
+	return toPBClusterAutoscaling(name, policy), nil
+}
+
+// AutoscaleClustersPeriodically applies every interval the autoscaling policies of the clusters of the current tenant
+// It never returns and is meant to be run as a goroutine by the daemon.
+func AutoscaleClustersPeriodically(interval time.Duration) {
+	if interval <= 0 {
+		interval = control.DefaultAutoscaleInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		tenant := GetCurrentTenant()
+		if tenant == nil {
+			continue
+		}
+		err := ClusterHandler(tenant.Service).Autoscale(context.Background())
+		if err != nil {
+			log.Warnf("failed to autoscale clusters of tenant '%s': %v", tenant.name, err)
+		}
+	}
+}
+
+// CheckHealth probes the hosts of a cluster and returns them with their state
+func (s *ClusterListener) CheckHealth(ctx context.Context, in *pb.Reference) (ch *pb.ClusterHealth, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Check health of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't check cluster health: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot check cluster health: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	nodes, err := handler.CheckHealth(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return toPBClusterHealth(ref, nodes), nil
+}
+
+// InspectHealthPolicy returns the health policy of a cluster
+func (s *ClusterListener) InspectHealthPolicy(ctx context.Context, in *pb.Reference) (hp *pb.ClusterHealthPolicy, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Inspect health policy of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't inspect cluster health policy: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect cluster health policy: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	policy, err := handler.InspectHealth(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return toPBClusterHealthPolicy(ref, policy), nil
+}
+
+// SetHealthPolicy replaces the health policy of a cluster
+func (s *ClusterListener) SetHealthPolicy(ctx context.Context, in *pb.ClusterHealthPolicy) (hp *pb.ClusterHealthPolicy, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("name", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	policy, err := fromPBClusterHealthPolicy(in)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, getUserMessage(err))
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Set health policy of Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't set cluster health policy: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set cluster health policy: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	policy, err = handler.SetHealth(ctx, name, policy)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Health policy of cluster '%s' set (auto repair: %v)", name, policy.AutoRepair)
+	return toPBClusterHealthPolicy(name, policy), nil
+}
+
+// CheckClustersHealthPeriodically probes every interval the hosts of the clusters of the current tenant, and repairs
+// them according to their health policy
+// It never returns and is meant to be run as a goroutine by the daemon.
+func CheckClustersHealthPeriodically(interval time.Duration) {
+	if interval <= 0 {
+		interval = control.DefaultHealthCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		tenant := GetCurrentTenant()
+		if tenant == nil {
+			continue
+		}
+		err := ClusterHandler(tenant.Service).CheckAllHealth(context.Background())
+		if err != nil {
+			log.Warnf("failed to check health of clusters of tenant '%s': %v", tenant.name, err)
+		}
+	}
+}
+
+// ListMasters lists the masters of a cluster
+func (s *ClusterListener) ListMasters(ctx context.Context, in *pb.Reference) (nl *pb.ClusterNodeList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "List masters of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't list cluster masters: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list cluster masters: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	instance, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return toPBClusterNodeList(instance.ListMasters(concurrency.RootTask())), nil
+}
+
+// ListNodes lists the nodes of a cluster
+func (s *ClusterListener) ListNodes(ctx context.Context, in *pb.Reference) (nl *pb.ClusterNodeList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "List nodes of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't list cluster nodes: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list cluster nodes: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	instance, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return toPBClusterNodeList(instance.ListNodes(concurrency.RootTask())), nil
+}
+
+// FindAvailableMaster returns a master of the cluster ready to execute orders
+func (s *ClusterListener) FindAvailableMaster(ctx context.Context, in *pb.Reference) (n *pb.ClusterNode, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("ref", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Find available master of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't find available cluster master: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot find available cluster master: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	node, err := handler.FindAvailableMaster(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return toPBClusterNode(node), nil
+}
+
+// ListFeatures lists the features suitable for clusters
+func (s *ClusterListener) ListFeatures(ctx context.Context, in *googleprotobuf.Empty) (fl *pb.ClusterFeatureList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "List cluster features"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't list cluster features: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list cluster features: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	features, err := handler.ListFeatures(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	fl = &pb.ClusterFeatureList{}
+	for _, v := range features {
+		fl.Features = append(fl.Features, &pb.ClusterFeature{Name: v.FeatureName, Flavors: v.ClusterFlavors})
+	}
+	return fl, nil
+}
+
+// AddFeature installs a feature on a cluster
+func (s *ClusterListener) AddFeature(ctx context.Context, in *pb.ClusterFeatureRequest) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	clusterName := in.GetCluster()
+	featureName := in.GetFeature()
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", clusterName, featureName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Add feature "+featureName+" on Cluster "+clusterName); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't add cluster feature: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot add cluster feature: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	values, settings := fromPBClusterFeatureRequest(in)
+	err = handler.AddFeature(ctx, clusterName, featureName, values, settings)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Feature '%s' added on cluster '%s'", featureName, clusterName)
+	return empty, nil
+}
+
+// CheckFeature checks if a feature is installed on a cluster; returns codes.NotFound if not
+func (s *ClusterListener) CheckFeature(ctx context.Context, in *pb.ClusterFeatureRequest) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	clusterName := in.GetCluster()
+	featureName := in.GetFeature()
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", clusterName, featureName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Check feature "+featureName+" on Cluster "+clusterName); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't check cluster feature: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot check cluster feature: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	values, settings := fromPBClusterFeatureRequest(in)
+	err = handler.CheckFeature(ctx, clusterName, featureName, values, settings)
+	if err != nil {
+		switch err.(type) {
+		case fail.ErrNotFound:
+			return empty, status.Errorf(codes.NotFound, getUserMessage(err))
+		default:
+			return empty, status.Errorf(codes.Internal, getUserMessage(err))
+		}
+	}
+	return empty, nil
+}
+
+// DeleteFeature removes a feature from a cluster
+func (s *ClusterListener) DeleteFeature(ctx context.Context, in *pb.ClusterFeatureRequest) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	clusterName := in.GetCluster()
+	featureName := in.GetFeature()
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", clusterName, featureName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Delete feature "+featureName+" from Cluster "+clusterName); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't delete cluster feature: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete cluster feature: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	values, settings := fromPBClusterFeatureRequest(in)
+	err = handler.DeleteFeature(ctx, clusterName, featureName, values, settings)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Feature '%s' deleted from cluster '%s'", featureName, clusterName)
+	return empty, nil
+}
+
+// fromPBClusterFeatureRequest extracts feature variables and settings from a pb.ClusterFeatureRequest
+func fromPBClusterFeatureRequest(in *pb.ClusterFeatureRequest) (install.Variables, install.Settings) {
+	values := install.Variables{}
+	for k, v := range in.GetParams() {
+		values[k] = v
+	}
+	settings := install.Settings{SkipProxy: in.GetSkipProxy()}
+	return values, settings
+}
+
+// toPBClusterNode converts a cluster node to a pb.ClusterNode
+func toPBClusterNode(in *clusterpropsv2.Node) *pb.ClusterNode {
+	if in == nil {
+		return nil
+	}
+	out := &pb.ClusterNode{
+		Id:          in.ID,
+		Name:        in.Name,
+		PublicIp:    in.PublicIP,
+		PrivateIp:   in.PrivateIP,
+		State:       int32(in.State),
+		StateLabel:  in.State.String(),
+		StateReason: in.StateReason,
+	}
+	if !in.StateDate.IsZero() {
+		out.StateDate = in.StateDate.Format(time.RFC3339)
+	}
+	if !in.StateSince.IsZero() {
+		out.StateSince = in.StateSince.Format(time.RFC3339)
+	}
+	return out
+}
+
+// toPBClusterNodeList converts a slice of cluster nodes to a pb.ClusterNodeList
+func toPBClusterNodeList(in []*clusterpropsv2.Node) *pb.ClusterNodeList {
+	out := &pb.ClusterNodeList{}
+	for _, v := range in {
+		out.Nodes = append(out.Nodes, toPBClusterNode(v))
+	}
+	return out
+}
+
+// toPBHostSizingFromDefinition converts the legacy abstract.HostDefinition used in cluster defaults to a pb.HostSizing
+func toPBHostSizingFromDefinition(in abstract.HostDefinition) *pb.HostSizing {
+	return &pb.HostSizing{
+		MinCpuCount: int32(in.Cores),
+		MinRamSize:  in.RAMSize,
+		MinDiskSize: int32(in.DiskSize),
+		GpuCount:    int32(in.GPUNumber),
+		MinCpuFreq:  in.CPUFreq,
+	}
+}
+
+// toPBCluster converts a cluster instance to a pb.Cluster
+func toPBCluster(task concurrency.Task, instance api.Cluster) (*pb.Cluster, error) {
+	if instance == nil {
+		return nil, fail.InvalidParameterError("instance", "cannot be nil")
+	}
+
+	identity := instance.GetIdentity(task)
+	out := &pb.Cluster{
+		Name:            identity.Name,
+		Flavor:          int32(identity.Flavor),
+		FlavorLabel:     identity.Flavor.String(),
+		Complexity:      int32(identity.Complexity),
+		ComplexityLabel: identity.Complexity.String(),
+		AdminLogin:      "cladm",
+		AdminPassword:   identity.AdminPassword,
+	}
+
+	properties := instance.GetProperties(task)
+	err := properties.LockForRead(property.CompositeV1).ThenUse(
+		func(clonable data.Clonable) error {
+			tenants := clonable.(*clusterpropsv1.Composite).Tenants
+			if len(tenants) > 0 {
+				out.Tenant = tenants[0]
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	netCfg, err := instance.GetNetworkConfig(task)
+	if err != nil {
+		return nil, err
+	}
+	out.Network = &pb.ClusterNetwork{
+		NetworkId:          netCfg.NetworkID,
+		Cidr:               netCfg.CIDR,
+		Domain:             netCfg.Domain,
+		GatewayId:          netCfg.GatewayID,
+		GatewayIp:          netCfg.GatewayIP,
+		SecondaryGatewayId: netCfg.SecondaryGatewayID,
+		SecondaryGatewayIp: netCfg.SecondaryGatewayIP,
+		DefaultRouteIp:     netCfg.DefaultRouteIP,
+		PrimaryPublicIp:    netCfg.PrimaryPublicIP,
+		SecondaryPublicIp:  netCfg.SecondaryPublicIP,
+		EndpointIp:         netCfg.EndpointIP,
+	}
+
+	if !properties.Lookup(property.DefaultsV2) {
+		err = properties.LockForRead(property.DefaultsV1).ThenUse(
+			func(clonable data.Clonable) error {
+				defaultsV1 := clonable.(*clusterpropsv1.Defaults)
+				out.Defaults = &pb.ClusterDefaults{
+					Image:         defaultsV1.Image,
+					GatewaySizing: toPBHostSizingFromDefinition(defaultsV1.GatewaySizing),
+					MasterSizing:  toPBHostSizingFromDefinition(defaultsV1.MasterSizing),
+					NodeSizing:    toPBHostSizingFromDefinition(defaultsV1.NodeSizing),
+				}
+				return nil
+			},
+		)
+	} else {
+		err = properties.LockForRead(property.DefaultsV2).ThenUse(
+			func(clonable data.Clonable) error {
+				defaultsV2 := clonable.(*clusterpropsv2.Defaults)
+				out.Defaults = &pb.ClusterDefaults{
+					Image:         defaultsV2.Image,
+					GatewaySizing: srvutils.ToPBHostSizing(defaultsV2.GatewaySizing),
+					MasterSizing:  srvutils.ToPBHostSizing(defaultsV2.MasterSizing),
+					NodeSizing:    srvutils.ToPBHostSizing(defaultsV2.NodeSizing),
+				}
+				return nil
+			},
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.LockForRead(property.NodesV2).ThenUse(
+		func(clonable data.Clonable) error {
+			nodesV2 := clonable.(*clusterpropsv2.Nodes)
+			out.Gateways = toPBClusterNodeList(nodesV2.Gateways).Nodes
+			out.Masters = toPBClusterNodeList(nodesV2.Masters).Nodes
+			out.Nodes = toPBClusterNodeList(nodesV2.PrivateNodes).Nodes
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.LockForRead(property.FeaturesV1).ThenUse(
+		func(clonable data.Clonable) error {
+			featuresV1 := clonable.(*clusterpropsv1.Features)
+			out.InstalledFeatures = map[string]string{}
+			for k, v := range featuresV1.Installed {
+				out.InstalledFeatures[k] = v
+			}
+			for k := range featuresV1.Disabled {
+				out.DisabledFeatures = append(out.DisabledFeatures, k)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.LockForRead(property.StateV1).ThenUse(
+		func(clonable data.Clonable) error {
+			state := clonable.(*clusterpropsv1.State).State
+			out.State = int32(state)
+			out.StateLabel = state.String()
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if properties.Lookup(property.AutoscalingV1) {
+		err = properties.LockForRead(property.AutoscalingV1).ThenUse(
+			func(clonable data.Clonable) error {
+				out.Autoscaling = toPBClusterAutoscaling(identity.Name, clonable.(*clusterpropsv1.Autoscaling))
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if properties.Lookup(property.HealthV1) {
+		err = properties.LockForRead(property.HealthV1).ThenUse(
+			func(clonable data.Clonable) error {
+				out.Health = toPBClusterHealthPolicy(identity.Name, clonable.(*clusterpropsv1.Health))
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// toPBClusterAutoscaling converts an autoscaling policy to its protobuf message
+func toPBClusterAutoscaling(name string, in *clusterpropsv1.Autoscaling) *pb.ClusterAutoscaling {
+	out := &pb.ClusterAutoscaling{
+		Name:               name,
+		Enabled:            in.Enabled,
+		MinNodes:           int32(in.MinNodes),
+		MaxNodes:           int32(in.MaxNodes),
+		ScaleUpThreshold:   float32(in.ScaleUpThreshold),
+		ScaleDownThreshold: float32(in.ScaleDownThreshold),
+		ScaleUpCooldown:    in.ScaleUpCooldown.String(),
+		ScaleDownCooldown:  in.ScaleDownCooldown.String(),
+	}
+	if !in.LastScaleUp.IsZero() {
+		out.LastScaleUp = in.LastScaleUp.Format(time.RFC3339)
+	}
+	if !in.LastScaleDown.IsZero() {
+		out.LastScaleDown = in.LastScaleDown.Format(time.RFC3339)
+	}
+	return out
+}
+
+// fromPBClusterAutoscaling converts a protobuf message to an autoscaling policy; unset cooldowns keep their default values
+func fromPBClusterAutoscaling(in *pb.ClusterAutoscaling) (*clusterpropsv1.Autoscaling, error) {
+	out := &clusterpropsv1.Autoscaling{
+		Enabled:            in.GetEnabled(),
+		MinNodes:           int(in.GetMinNodes()),
+		MaxNodes:           int(in.GetMaxNodes()),
+		ScaleUpThreshold:   float64(in.GetScaleUpThreshold()),
+		ScaleDownThreshold: float64(in.GetScaleDownThreshold()),
+	}
+	var err error
+	if in.GetScaleUpCooldown() != "" {
+		out.ScaleUpCooldown, err = time.ParseDuration(in.GetScaleUpCooldown())
+		if err != nil {
+			return nil, fail.SyntaxError(fmt.Sprintf("invalid scale up cooldown '%s'", in.GetScaleUpCooldown()))
+		}
+	}
+	if in.GetScaleDownCooldown() != "" {
+		out.ScaleDownCooldown, err = time.ParseDuration(in.GetScaleDownCooldown())
+		if err != nil {
+			return nil, fail.SyntaxError(fmt.Sprintf("invalid scale down cooldown '%s'", in.GetScaleDownCooldown()))
+		}
+	}
+	return out, nil
+}
+
+// toPBClusterHealthPolicy converts a health policy to its protobuf message
+func toPBClusterHealthPolicy(name string, in *clusterpropsv1.Health) *pb.ClusterHealthPolicy {
+	out := &pb.ClusterHealthPolicy{
+		Name:        name,
+		AutoRepair:  in.AutoRepair,
+		RepairDelay: in.RepairDelay.String(),
+	}
+	if !in.LastCheck.IsZero() {
+		out.LastCheck = in.LastCheck.Format(time.RFC3339)
+	}
+	if !in.LastRepair.IsZero() {
+		out.LastRepair = in.LastRepair.Format(time.RFC3339)
+	}
+	return out
+}
+
+// fromPBClusterHealthPolicy converts a protobuf message to a health policy; an unset repair delay keeps its default value
+func fromPBClusterHealthPolicy(in *pb.ClusterHealthPolicy) (*clusterpropsv1.Health, error) {
+	out := &clusterpropsv1.Health{AutoRepair: in.GetAutoRepair()}
+	if in.GetRepairDelay() != "" {
+		var err error
+		out.RepairDelay, err = time.ParseDuration(in.GetRepairDelay())
+		if err != nil {
+			return nil, fail.SyntaxError(fmt.Sprintf("invalid repair delay '%s'", in.GetRepairDelay()))
+		}
+	}
+	return out, nil
+}
+
+// toPBClusterHealth converts the nodes of a cluster with their state to a pb.ClusterHealth
+func toPBClusterHealth(name string, in *clusterpropsv2.Nodes) *pb.ClusterHealth {
+	return &pb.ClusterHealth{
+		Name:     name,
+		Gateways: toPBClusterNodeList(in.Gateways).Nodes,
+		Masters:  toPBClusterNodeList(in.Masters).Nodes,
+		Nodes:    toPBClusterNodeList(in.PrivateNodes).Nodes,
+	}
+}
+
+// toClusterRequest converts a cluster definition to the request of creation of the cluster in tenant
+func toClusterRequest(in *pb.ClusterDefinition, tenant string) control.Request {
+	disabled := map[string]struct{}{}
+	for _, v := range in.GetDisabledFeatures() {
+		disabled[v] = struct{}{}
+	}
+	return control.Request{
+		Name:                    in.GetName(),
+		Complexity:              complexity.Enum(in.GetComplexity()),
+		Flavor:                  flavor.Enum(in.GetFlavor()),
+		CIDR:                    in.GetCidr(),
+		Domain:                  in.GetDomain(),
+		Tenant:                  tenant,
+		KeepOnFailure:           in.GetKeepOnFailure(),
+		Force:                   in.GetForce(),
+		GatewaysDef:             in.GetGateways(),
+		MastersDef:              in.GetMasters(),
+		NodesDef:                in.GetNodes(),
+		DisabledDefaultFeatures: disabled,
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// DataHandler ...
+var DataHandler = handlers.NewDataHandler
+
+// safescale data push ./file.tgz --name=file.tgz --bucket=b1 --bucket=b2
+// safescale data get file.tgz ./file.tgz
+// safescale data list
+// safescale data delete file.tgz
+
+// DataListener is the data service grpc server
+type DataListener struct{}
+
+// Push stores a local file in buckets
+func (s *DataListener) Push(ctx context.Context, in *pb.File) (empty *googleprotobuf.Empty, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	name := in.GetName()
+	tracer := debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', %v)", in.GetLocalPath(), name, in.GetBuckets()), true,
+	).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Data Push : "+name); err != nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error(),
+		)
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Cannot push data: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot push data: no tenant set")
+	}
+
+	redundancy := abstract.DataRedundancy{
+		DataShards:   int(in.GetDataShards()),
+		ParityShards: int(in.GetParityShards()),
+		Replicas:     int(in.GetReplicas()),
+	}
+	handler := DataHandler(tenant.Service)
+	_, err = handler.Push(ctx, in.GetLocalPath(), name, in.GetBuckets(), redundancy)
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot push data"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return &googleprotobuf.Empty{}, nil
+}
+
+// Get restores a stored file in a local file
+func (s *DataListener) Get(ctx context.Context, in *pb.File) (empty *googleprotobuf.Empty, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	name := in.GetName()
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", in.GetLocalPath(), name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Data Get : "+name); err != nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error(),
+		)
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Cannot get data: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot get data: no tenant set")
+	}
+
+	handler := DataHandler(tenant.Service)
+	_, err = handler.Get(ctx, in.GetLocalPath(), name)
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot get data"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return &googleprotobuf.Empty{}, nil
+}
+
+// Delete removes a stored file
+func (s *DataListener) Delete(ctx context.Context, in *pb.File) (empty *googleprotobuf.Empty, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	name := in.GetName()
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Data Delete : "+name); err != nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error(),
+		)
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Cannot delete data: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete data: no tenant set")
+	}
+
+	handler := DataHandler(tenant.Service)
+	err = handler.Delete(ctx, name)
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot delete data"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return &googleprotobuf.Empty{}, nil
+}
+
+// List returns the stored files
+func (s *DataListener) List(ctx context.Context, in *googleprotobuf.Empty) (fl *pb.FileList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidInstanceError().Message())
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Data List"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Cannot list data: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list data: no tenant set")
+	}
+
+	handler := DataHandler(tenant.Service)
+	list, err := handler.List(ctx)
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot list data"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return srvutils.ToPBFileList(list), nil
+}
@@ -32,6 +32,7 @@ import (
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/placementpolicy"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
@@ -206,7 +207,7 @@ func (s *HostListener) List(ctx context.Context, in *pb.HostListRequest) (hl *pb
 	}
 
 	handler := HostHandler(tenant.Service)
-	hosts, err := handler.List(ctx, all)
+	hosts, err := handler.List(ctx, all, in.GetLabels())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
 	}
@@ -252,23 +253,9 @@ func (s *HostListener) Create(ctx context.Context, in *pb.HostDefinition) (h *pb
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot create host: no tenant set")
 	}
 
-	var sizing *abstract.SizingRequirements
-	if in.Sizing == nil {
-		sizing = &abstract.SizingRequirements{
-			MinCores:    int(in.GetCpuCount()),
-			MaxCores:    int(in.GetCpuCount()),
-			MinRAMSize:  in.GetRam(),
-			MaxRAMSize:  in.GetRam(),
-			MinDiskSize: int(in.GetDisk()),
-			MinGPU:      int(in.GetGpuCount()),
-			MinFreq:     in.GetCpuFreq(),
-		}
-	} else {
-		s, err := srvutils.FromPBHostSizing(in.Sizing)
-		if err != nil {
-			return nil, err
-		}
-		sizing = &s
+	sizing, err := sizingFromPBHostDefinition(in)
+	if err != nil {
+		return nil, err
 	}
 
 	handler := HostHandler(tenant.Service)
@@ -282,6 +269,12 @@ func (s *HostListener) Create(ctx context.Context, in *pb.HostDefinition) (h *pb
 		in.Force,
 		in.Domain,
 		in.KeepOnFailure,
+		in.GetLabels(),
+		abstract.HostPlacement{
+			AvailabilityZone: in.GetZone(),
+			ServerGroup:      in.GetServerGroup(),
+			PlacementPolicy:  placementpolicy.Enum(in.GetPlacement()),
+		},
 	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
@@ -494,3 +487,118 @@ func (s *HostListener) SSH(ctx context.Context, in *pb.Reference) (sc *pb.SshCon
 	}
 	return srvutils.ToPBSshConfig(sshConfig)
 }
+
+// CaptureImage creates an image from the root disk of an host
+func (s *HostListener) CaptureImage(ctx context.Context, in *pb.HostImageRequest) (img *pb.Image, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetHost())
+	if ref == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, "cannot capture image of host: neither name nor id given as reference",
+		)
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("name", "cannot be empty string").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Capture image of Host "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't capture image of host: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot capture image of host: no tenant set")
+	}
+
+	handler := HostHandler(tenant.Service)
+	image, err := handler.CaptureImage(ctx, ref, name)
+	if err != nil {
+		switch err.(type) {
+		case fail.ErrNotFound:
+			return nil, status.Errorf(codes.NotFound, getUserMessage(err))
+		case fail.ErrDuplicate:
+			return nil, status.Errorf(codes.AlreadyExists, getUserMessage(err))
+		default:
+			return nil, status.Errorf(codes.Internal, getUserMessage(err))
+		}
+	}
+
+	log.Infof("Image '%s' captured from host '%s'", name, ref)
+	return srvutils.ToPBImage(image)
+}
+
+// Estimate returns the cost of the host that would be created with the definition
+func (s *HostListener) Estimate(ctx context.Context, in *pb.HostDefinition) (ce *pb.CostEstimate, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fail.InvalidParameterError("in", "cannot be nil").Message(),
+		)
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Estimate Host "+in.GetName()); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't estimate host: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot estimate host: no tenant set")
+	}
+
+	sizing, err := sizingFromPBHostDefinition(in)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := HostHandler(tenant.Service)
+	estimate, err := handler.Estimate(ctx, *sizing, in.GetForce())
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, status.Errorf(codes.NotFound, getUserMessage(err))
+		}
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return srvutils.ToPBCostEstimate(estimate), nil
+}
+
+// sizingFromPBHostDefinition returns the sizing requirements of a host definition, honoring deprecated fields
+func sizingFromPBHostDefinition(in *pb.HostDefinition) (*abstract.SizingRequirements, error) {
+	if in.Sizing == nil {
+		return &abstract.SizingRequirements{
+			MinCores:    int(in.GetCpuCount()),
+			MaxCores:    int(in.GetCpuCount()),
+			MinRAMSize:  in.GetRam(),
+			MaxRAMSize:  in.GetRam(),
+			MinDiskSize: int(in.GetDisk()),
+			MinGPU:      int(in.GetGpuCount()),
+			MinFreq:     in.GetCpuFreq(),
+		}, nil
+	}
+	sizing, err := srvutils.FromPBHostSizing(in.Sizing)
+	if err != nil {
+		return nil, err
+	}
+	return &sizing, nil
+}
@@ -18,9 +18,11 @@ package listeners
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -35,6 +37,7 @@ import (
 var ImageHandler = handlers.NewImageHandler
 
 // safescale image list --all=false
+// safescale image delete <image>
 
 // ImageListener image service server grpc
 type ImageListener struct{}
@@ -79,3 +82,44 @@ func (s *ImageListener) List(ctx context.Context, in *pb.ImageListRequest) (il *
 	rv := &pb.ImageList{Images: pbImages}
 	return rv, nil
 }
+
+// Delete deletes an image
+func (s *ImageListener) Delete(ctx context.Context, in *pb.Reference) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(
+			codes.FailedPrecondition, "cannot delete image: neither name nor id given as reference",
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Delete Image "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		logrus.Info("Can't delete image: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete image: no tenant set")
+	}
+
+	handler := ImageHandler(tenant.Service)
+	err = handler.Delete(ctx, ref)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return empty, status.Errorf(codes.NotFound, getUserMessage(err))
+		}
+		return empty, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	logrus.Infof("Image '%s' deleted", ref)
+	return empty, nil
+}
@@ -111,6 +111,7 @@ func (s *NetworkListener) Create(ctx context.Context, in *pb.NetworkDefinition)
 		in.FailOver,
 		in.Domain,
 		in.KeepOnFailure,
+		in.GetLabels(),
 	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
@@ -150,7 +151,7 @@ func (s *NetworkListener) List(ctx context.Context, in *pb.NetworkListRequest) (
 	}
 
 	handler := NetworkHandler(tenant.Service)
-	networks, err := handler.List(ctx, in.GetAll())
+	networks, err := handler.List(ctx, in.GetAll(), in.GetLabels())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
 	}
@@ -0,0 +1,465 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// SecurityGroupHandler ...
+var SecurityGroupHandler = handlers.NewSecurityGroupHandler
+
+// safescale security-group create sg1 --description="..."
+// safescale security-group list
+// safescale security-group inspect sg1
+// safescale security-group delete sg1
+// safescale security-group rule add sg1 --direction=ingress --protocol=tcp --port-from=22
+// safescale security-group rule delete sg1 <rule id>
+// safescale security-group bind host sg1 host1
+// safescale security-group unbind network sg1 net1
+
+// SecurityGroupListener security group service server grpc
+type SecurityGroupListener struct{}
+
+// securityGroupErrorToStatus converts an error returned by the handler to a grpc status
+func securityGroupErrorToStatus(err error) error {
+	switch err.(type) {
+	case fail.ErrNotFound:
+		return status.Errorf(codes.NotFound, getUserMessage(err))
+	case fail.ErrDuplicate:
+		return status.Errorf(codes.AlreadyExists, getUserMessage(err))
+	case fail.ErrInvalidParameter:
+		return status.Errorf(codes.InvalidArgument, getUserMessage(err))
+	default:
+		return status.Errorf(codes.Internal, getUserMessage(err))
+	}
+}
+
+// List lists the security groups
+func (s *SecurityGroupListener) List(ctx context.Context, in *googleprotobuf.Empty) (_ *pb.SecurityGroupList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "List security groups"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't list security groups: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list security groups: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	list, err := handler.List(ctx)
+	if err != nil {
+		return nil, securityGroupErrorToStatus(err)
+	}
+
+	out := &pb.SecurityGroupList{}
+	for _, sg := range list {
+		pbsg, err := srvutils.ToPBSecurityGroup(sg)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		out.SecurityGroups = append(out.SecurityGroups, pbsg)
+	}
+	return out, nil
+}
+
+// Create creates a security group
+func (s *SecurityGroupListener) Create(ctx context.Context, in *pb.SecurityGroupDefinition) (_ *pb.SecurityGroup, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	name := in.GetName()
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Create security group "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't create security group: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot create security group: no tenant set")
+	}
+
+	req := abstract.SecurityGroupRequest{
+		Name:        name,
+		Description: in.GetDescription(),
+	}
+	for _, r := range in.GetRules() {
+		rule, err := srvutils.FromPBSecurityGroupRule(r)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, getUserMessage(err))
+		}
+		req.Rules = append(req.Rules, rule)
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	sg, err := handler.Create(ctx, req)
+	if err != nil {
+		return nil, securityGroupErrorToStatus(err)
+	}
+
+	log.Infof("Security group '%s' successfully created.", name)
+	return srvutils.ToPBSecurityGroup(sg)
+}
+
+// Inspect returns the description of a security group
+func (s *SecurityGroupListener) Inspect(ctx context.Context, in *pb.Reference) (_ *pb.SecurityGroup, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect security group: neither name nor id given as reference")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Inspect security group "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't inspect security group: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect security group: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	sg, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, securityGroupErrorToStatus(err)
+	}
+	return srvutils.ToPBSecurityGroup(sg)
+}
+
+// Delete deletes a security group
+func (s *SecurityGroupListener) Delete(ctx context.Context, in *pb.Reference) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete security group: neither name nor id given as reference")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Delete security group "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't delete security group: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete security group: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	err = handler.Delete(ctx, ref)
+	if err != nil {
+		return empty, securityGroupErrorToStatus(err)
+	}
+
+	log.Infof("Security group '%s' successfully deleted.", ref)
+	return empty, nil
+}
+
+// AddRule adds a rule to a security group
+func (s *SecurityGroupListener) AddRule(ctx context.Context, in *pb.SecurityGroupRuleRequest) (_ *pb.SecurityGroup, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetGroup())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot add rule to security group: neither name nor id given as reference")
+	}
+	rule, err := srvutils.FromPBSecurityGroupRule(in.GetRule())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, getUserMessage(err))
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Add rule to security group "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't add rule to security group: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot add rule to security group: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	sg, err := handler.AddRule(ctx, ref, rule)
+	if err != nil {
+		return nil, securityGroupErrorToStatus(err)
+	}
+	return srvutils.ToPBSecurityGroup(sg)
+}
+
+// DeleteRule deletes a rule from a security group
+func (s *SecurityGroupListener) DeleteRule(ctx context.Context, in *pb.SecurityGroupRuleDeleteRequest) (_ *pb.SecurityGroup, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetGroup())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete rule from security group: neither name nor id given as reference")
+	}
+	ruleID := in.GetRuleId()
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, ruleID), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Delete rule from security group "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't delete rule from security group: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete rule from security group: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	sg, err := handler.DeleteRule(ctx, ref, ruleID)
+	if err != nil {
+		return nil, securityGroupErrorToStatus(err)
+	}
+	return srvutils.ToPBSecurityGroup(sg)
+}
+
+// BindToHost applies a security group to a host
+func (s *SecurityGroupListener) BindToHost(ctx context.Context, in *pb.SecurityGroupBindRequest) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetGroup())
+	if ref == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot bind security group to host: neither name nor id given as reference of security group")
+	}
+	hostRef := srvutils.GetReference(in.GetResource())
+	if hostRef == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot bind security group to host: neither name nor id given as reference of host")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, hostRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, fmt.Sprintf("Bind security group %s to host %s", ref, hostRef)); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't bind security group to host: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot bind security group to host: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	err = handler.BindToHost(ctx, ref, hostRef)
+	if err != nil {
+		return empty, securityGroupErrorToStatus(err)
+	}
+	return empty, nil
+}
+
+// UnbindFromHost removes a security group from a host
+func (s *SecurityGroupListener) UnbindFromHost(ctx context.Context, in *pb.SecurityGroupBindRequest) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetGroup())
+	if ref == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot unbind security group from host: neither name nor id given as reference of security group")
+	}
+	hostRef := srvutils.GetReference(in.GetResource())
+	if hostRef == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot unbind security group from host: neither name nor id given as reference of host")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, hostRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, fmt.Sprintf("Unbind security group %s from host %s", ref, hostRef)); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't unbind security group from host: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot unbind security group from host: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	err = handler.UnbindFromHost(ctx, ref, hostRef)
+	if err != nil {
+		return empty, securityGroupErrorToStatus(err)
+	}
+	return empty, nil
+}
+
+// BindToNetwork applies a security group to a network
+func (s *SecurityGroupListener) BindToNetwork(ctx context.Context, in *pb.SecurityGroupBindRequest) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetGroup())
+	if ref == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot bind security group to network: neither name nor id given as reference of security group")
+	}
+	networkRef := srvutils.GetReference(in.GetResource())
+	if networkRef == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot bind security group to network: neither name nor id given as reference of network")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, networkRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, fmt.Sprintf("Bind security group %s to network %s", ref, networkRef)); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't bind security group to network: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot bind security group to network: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	err = handler.BindToNetwork(ctx, ref, networkRef)
+	if err != nil {
+		return empty, securityGroupErrorToStatus(err)
+	}
+	return empty, nil
+}
+
+// UnbindFromNetwork removes a security group from a network
+func (s *SecurityGroupListener) UnbindFromNetwork(ctx context.Context, in *pb.SecurityGroupBindRequest) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetGroup())
+	if ref == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot unbind security group from network: neither name nor id given as reference of security group")
+	}
+	networkRef := srvutils.GetReference(in.GetResource())
+	if networkRef == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot unbind security group from network: neither name nor id given as reference of network")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, networkRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, fmt.Sprintf("Unbind security group %s from network %s", ref, networkRef)); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't unbind security group from network: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot unbind security group from network: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	err = handler.UnbindFromNetwork(ctx, ref, networkRef)
+	if err != nil {
+		return empty, securityGroupErrorToStatus(err)
+	}
+	return empty, nil
+}
@@ -19,6 +19,8 @@ package listeners
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 
@@ -28,7 +30,10 @@ import (
 	"google.golang.org/grpc/status"
 
 	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
@@ -43,6 +48,9 @@ var (
 	currentTenant *Tenant
 )
 
+// TenantHandler ...
+var TenantHandler = handlers.NewTenantHandler
+
 // GetCurrentTenant contains the current tenant
 var GetCurrentTenant = getCurrentTenant
 
@@ -66,6 +74,24 @@ func getCurrentTenant() *Tenant {
 	return currentTenant
 }
 
+// forEachTenant calls action on each tenant of the configuration, current or not; a tenant whose service cannot be
+// built (missing credentials, unreachable provider, ...) is skipped
+func forEachTenant(action func(*Tenant)) {
+	tenants, err := iaas.GetTenantNames()
+	if err != nil {
+		log.Warnf("failed to list tenants: %v", err)
+		return
+	}
+	for name := range tenants {
+		service, err := iaas.UseService(name)
+		if err != nil {
+			log.Debugf("tenant '%s' skipped: %v", name, err)
+			continue
+		}
+		action(&Tenant{name: name, Service: service})
+	}
+}
+
 // TenantListener server is used to implement SafeScale.safescale.
 type TenantListener struct{}
 
@@ -128,6 +154,39 @@ func (s *TenantListener) Get(ctx context.Context, in *googleprotobuf.Empty) (tn
 	return &pb.TenantName{Name: currentTenant.name}, nil
 }
 
+// Quotas returns the quotas and the current usage of resources of the current tenant
+func (s *TenantListener) Quotas(ctx context.Context, in *googleprotobuf.Empty) (tq *pb.TenantQuotas, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant Quotas"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := getCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't get tenant quotas: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot get tenant quotas: no tenant set")
+	}
+
+	quotas, err := tenant.Service.GetQuotas()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	usage, err := tenant.Service.GetUsage()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return srvutils.ToPBTenantQuotas(tenant.name, quotas, usage), nil
+}
+
 // Set the the tenant to use for each command
 func (s *TenantListener) Set(ctx context.Context, in *pb.TenantName) (empty *googleprotobuf.Empty, err error) {
 	empty = &googleprotobuf.Empty{}
@@ -150,10 +209,7 @@ func (s *TenantListener) Set(ctx context.Context, in *pb.TenantName) (empty *goo
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	if currentTenant != nil && currentTenant.name == in.GetName() {
-		return empty, nil
-	}
-
+	// the service is asked again even for the current tenant, to use a service rebuilt if the configuration changed
 	service, err := iaas.UseService(in.GetName())
 	if err != nil {
 		return empty, fmt.Errorf("unable to set tenant '%s': %s", name, getUserMessage(err))
@@ -162,3 +218,231 @@ func (s *TenantListener) Set(ctx context.Context, in *pb.TenantName) (empty *goo
 	log.Infof("Current tenant is now '%s'", name)
 	return empty, nil
 }
+
+// MigrateMetadata upgrades the properties of the metadata of the current tenant to their latest version
+func (s *TenantListener) MigrateMetadata(ctx context.Context, in *pb.MetadataMigrationRequest) (mmr *pb.MetadataMigrationReport, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("(%v)", in.GetDryRun()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant MigrateMetadata"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := getCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't migrate metadata: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot migrate metadata: no tenant set")
+	}
+
+	report, err := metadata.Migrate(tenant.Service, in.GetDryRun())
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot migrate metadata"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return srvutils.ToPBMetadataMigrationReport(report), nil
+}
+
+// ExportMetadata writes an archive of the decrypted metadata of the current tenant in a file local to the daemon
+func (s *TenantListener) ExportMetadata(ctx context.Context, in *pb.MetadataArchiveRequest) (mar *pb.MetadataArchiveReport, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil || in.GetPath() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in.Path", "cannot be empty string").Message())
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", in.GetPath()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant ExportMetadata "+in.GetPath()); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := getCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't export metadata: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot export metadata: no tenant set")
+	}
+
+	file, err := os.OpenFile(in.GetPath(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fail.Wrap(err, "cannot export metadata").Message())
+	}
+	report, err := metadata.Export(tenant.Service, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(in.GetPath())
+		tbr := fail.Wrap(err, "cannot export metadata"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	report.Path = in.GetPath()
+	return srvutils.ToPBMetadataArchiveReport(report), nil
+}
+
+// ImportMetadata writes in the metadata of the current tenant the content of an archive local to the daemon,
+// encrypted with the key of the tenant
+func (s *TenantListener) ImportMetadata(ctx context.Context, in *pb.MetadataArchiveRequest) (mar *pb.MetadataArchiveReport, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil || in.GetPath() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in.Path", "cannot be empty string").Message())
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", in.GetPath()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant ImportMetadata "+in.GetPath()); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := getCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't import metadata: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot import metadata: no tenant set")
+	}
+
+	file, err := os.Open(in.GetPath())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fail.Wrap(err, "cannot import metadata").Message())
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	report, err := metadata.Import(tenant.Service, file)
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot import metadata"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	report.Path = in.GetPath()
+	return srvutils.ToPBMetadataArchiveReport(report), nil
+}
+
+// BackupMetadata writes an archive of the metadata of the current tenant in a bucket
+func (s *TenantListener) BackupMetadata(ctx context.Context, in *pb.MetadataArchiveRequest) (mar *pb.MetadataArchiveReport, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil || in.GetBucket() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in.Bucket", "cannot be empty string").Message())
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", in.GetBucket()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant BackupMetadata "+in.GetBucket()); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := getCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't backup metadata: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot backup metadata: no tenant set")
+	}
+
+	report, err := metadata.Backup(tenant.Service, in.GetBucket())
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot backup metadata"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return srvutils.ToPBMetadataArchiveReport(report), nil
+}
+
+// RekeyMetadata re-encrypts the metadata of the current tenant with a new key
+func (s *TenantListener) RekeyMetadata(ctx context.Context, in *pb.MetadataRekeyRequest) (mrr *pb.MetadataRekeyReport, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil || in.GetNewKey() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in.NewKey", "cannot be empty string").Message())
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant RekeyMetadata"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := getCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't rekey metadata: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot rekey metadata: no tenant set")
+	}
+
+	report, err := metadata.Rekey(tenant.Service, in.GetNewKey())
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot rekey metadata"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return srvutils.ToPBMetadataRekeyReport(report), nil
+}
+
+// Fsck cross-checks the metadata of the current tenant against the resources of its provider, and repairs them if asked
+func (s *TenantListener) Fsck(ctx context.Context, in *pb.FsckRequest) (fr *pb.FsckReport, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("(%v)", in.GetRepair()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant Fsck"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := getCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't check metadata: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot check metadata: no tenant set")
+	}
+
+	report, err := TenantHandler(tenant.Service).Fsck(ctx, in.GetRepair())
+	if err != nil {
+		tbr := fail.Wrap(err, "cannot check metadata"+adaptedUserMessage(err))
+		return nil, status.Errorf(codes.Internal, tbr.Message())
+	}
+	return srvutils.ToPBFsckReport(report), nil
+}
+
+// BackupMetadataPeriodically writes every interval an archive of the metadata of the current tenant in the bucket
+// It never returns and is meant to be run as a goroutine by the daemon.
+func BackupMetadataPeriodically(bucketName string, interval time.Duration) {
+	if interval <= 0 {
+		interval = abstract.DefaultMetadataBackupInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		tenant := GetCurrentTenant()
+		if tenant == nil {
+			continue
+		}
+		report, err := metadata.Backup(tenant.Service, bucketName)
+		if err != nil {
+			log.Warnf("failed to backup metadata of tenant '%s': %v", tenant.name, err)
+			continue
+		}
+		log.Infof("metadata of tenant '%s' saved in '%s:%s'", tenant.name, report.Bucket, report.Path)
+	}
+}
@@ -77,7 +77,7 @@ func (s *VolumeListener) List(ctx context.Context, in *pb.VolumeListRequest) (_
 	}
 
 	handler := VolumeHandler(tenant.Service)
-	volumes, err := handler.List(ctx, in.GetAll())
+	volumes, err := handler.List(ctx, in.GetAll(), in.GetLabels())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
 	}
@@ -130,7 +130,7 @@ func (s *VolumeListener) Create(ctx context.Context, in *pb.VolumeDefinition) (_
 	}
 
 	handler := VolumeHandler(tenant.Service)
-	vol, err := handler.Create(ctx, name, int(size), volumespeed.Enum(speed))
+	vol, err := handler.Create(ctx, name, int(size), volumespeed.Enum(speed), in.GetLabels())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
 	}
@@ -386,3 +386,199 @@ func (s *VolumeListener) Inspect(ctx context.Context, in *pb.Reference) (_ *pb.V
 
 	return srvutils.ToPBVolumeInfo(volume, mounts)
 }
+
+// CreateSnapshot creates a snapshot of a volume
+func (s *VolumeListener) CreateSnapshot(ctx context.Context, in *pb.VolumeSnapshotDefinition) (_ *pb.VolumeSnapshot, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	volumeRef := srvutils.GetReference(in.GetVolume())
+	if volumeRef == "" {
+		return nil, status.Errorf(
+			codes.InvalidArgument, "cannot create volume snapshot: neither name nor id given as reference of volume",
+		)
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot create volume snapshot: name cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s', '%s')", volumeRef, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume Snapshot Create "+name); err != nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error(),
+		)
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot create volume snapshot: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	snapshot, err := handler.CreateSnapshot(ctx, volumeRef, name, in.GetDescription(), in.GetForce())
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, status.Errorf(codes.NotFound, getUserMessage(err))
+		}
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Snapshot '%s' of volume '%s' created", name, volumeRef)
+	return srvutils.ToPBVolumeSnapshot(snapshot)
+}
+
+// ListSnapshots lists the volume snapshots, restricted to the ones of a volume if a reference is given
+func (s *VolumeListener) ListSnapshots(ctx context.Context, in *pb.Reference) (_ *pb.VolumeSnapshotList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	volumeRef := srvutils.GetReference(in)
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", volumeRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume Snapshots List"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list volume snapshots: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	snapshots, err := handler.ListSnapshots(ctx, volumeRef)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, status.Errorf(codes.NotFound, getUserMessage(err))
+		}
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	var pbsnapshots []*pb.VolumeSnapshot
+	for _, snapshot := range snapshots {
+		pbs, err := srvutils.ToPBVolumeSnapshot(&snapshot)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		pbsnapshots = append(pbsnapshots, pbs)
+	}
+	return &pb.VolumeSnapshotList{Snapshots: pbsnapshots}, nil
+}
+
+// DeleteSnapshot deletes a volume snapshot
+func (s *VolumeListener) DeleteSnapshot(ctx context.Context, in *pb.Reference) (_ *googleprotobuf.Empty, err error) {
+	empty := &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(
+			codes.InvalidArgument, "cannot delete volume snapshot: neither name nor id given as reference",
+		)
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume Snapshot delete "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete volume snapshot: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	err = handler.DeleteSnapshot(ctx, ref)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return empty, status.Errorf(codes.NotFound, getUserMessage(err))
+		}
+		return empty, status.Errorf(
+			codes.Internal, fmt.Sprintf("cannot delete volume snapshot '%s': %s", ref, getUserMessage(err)),
+		)
+	}
+	log.Infof("Volume snapshot '%s' successfully deleted.", ref)
+	return empty, nil
+}
+
+// RestoreSnapshot creates a new volume from a volume snapshot
+func (s *VolumeListener) RestoreSnapshot(ctx context.Context, in *pb.VolumeSnapshotRestoreRequest) (_ *pb.Volume, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fail.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	snapshotRef := srvutils.GetReference(in.GetSnapshot())
+	if snapshotRef == "" {
+		return nil, status.Errorf(
+			codes.InvalidArgument, "cannot restore volume snapshot: neither name nor id given as reference of snapshot",
+		)
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot restore volume snapshot: volume name cannot be empty string")
+	}
+	speed := in.GetSpeed()
+
+	tracer := debug.NewTracer(
+		nil, fmt.Sprintf("('%s', '%s', %s)", snapshotRef, name, speed.String()), true,
+	).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume Snapshot Restore "+snapshotRef); err != nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error(),
+		)
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot restore volume snapshot: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	vol, err := handler.RestoreSnapshot(ctx, snapshotRef, name, volumespeed.Enum(speed))
+	if err != nil {
+		switch err.(type) {
+		case fail.ErrNotFound:
+			return nil, status.Errorf(codes.NotFound, getUserMessage(err))
+		case fail.ErrDuplicate:
+			return nil, status.Errorf(codes.AlreadyExists, getUserMessage(err))
+		default:
+			return nil, status.Errorf(codes.Internal, getUserMessage(err))
+		}
+	}
+
+	log.Infof("Volume '%s' restored from snapshot '%s'", name, snapshotRef)
+	return srvutils.ToPBVolume(vol)
+}
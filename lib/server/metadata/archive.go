@@ -0,0 +1,314 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+)
+
+const (
+	// MetadataBackupPrefix is the prefix of the names of the archives written by Backup in the backup bucket
+	MetadataBackupPrefix = "safescale-metadata/"
+
+	// metadataArchiveManifest is the name of the first entry of an archive, describing its content
+	metadataArchiveManifest = "MANIFEST.json"
+	// metadataArchiveVersion is the version of the layout of the archives
+	metadataArchiveVersion = 1
+)
+
+// archivedFolders contains the metadata folders exported in archives
+var archivedFolders = struct {
+	sync.Mutex
+	folders map[string]struct{}
+}{
+	folders: map[string]struct{}{
+		hostsFolderName:           {},
+		networksFolderName:        {},
+		volumesFolderName:         {},
+		volumeSnapshotsFolderName: {},
+		shareFolderName:           {},
+		dataFolderName:            {},
+		bucketLifecycleFolderName: {},
+		bucketSyncFolderName:      {},
+		auditFolderName:           {},
+		"buckets":                 {}, // encryption settings of buckets, maintained by iaas.Service
+	},
+}
+
+// metadataArchiveManifestContent is the content of the manifest of an archive
+type metadataArchiveManifestContent struct {
+	Version  int       `json:"version"`
+	Provider string    `json:"provider"`
+	Date     time.Time `json:"date"`
+	Folders  []string  `json:"folders"`
+}
+
+// RegisterArchivedFolder declares a metadata folder to be exported by Export
+func RegisterArchivedFolder(folder string) {
+	archivedFolders.Lock()
+	defer archivedFolders.Unlock()
+
+	archivedFolders.folders[strings.Trim(folder, "/")] = struct{}{}
+}
+
+// getArchivedFolders returns the sorted list of the folders exported by Export
+func getArchivedFolders() []string {
+	archivedFolders.Lock()
+	defer archivedFolders.Unlock()
+
+	var folders []string
+	for k := range archivedFolders.folders {
+		folders = append(folders, k)
+	}
+	sort.Strings(folders)
+	return folders
+}
+
+// Export writes in w a tar.gz archive of the decrypted metadata of the tenant
+// The archive is not encrypted; it has to be stored as safely as the keys of the tenant.
+func Export(svc iaas.Service, w io.Writer) (report *abstract.MetadataArchiveReport, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if w == nil {
+		return nil, fail.InvalidParameterError("w", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	report = &abstract.MetadataArchiveReport{
+		Date:    time.Now().UTC(),
+		Folders: getArchivedFolders(),
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	writeEntry := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0600,
+			Size:     int64(len(data)),
+			ModTime:  report.Date,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	manifest, err := json.Marshal(
+		metadataArchiveManifestContent{
+			Version:  metadataArchiveVersion,
+			Provider: svc.GetName(),
+			Date:     report.Date,
+			Folders:  report.Folders,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = writeEntry(metadataArchiveManifest, manifest)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to write manifest of archive")
+	}
+
+	for _, folder := range report.Folders {
+		f, err := metadata.NewFolder(svc, folder)
+		if err != nil {
+			return nil, err
+		}
+		err = f.Walk(
+			".", func(name string, data []byte) error {
+				if err := writeEntry(name, data); err != nil {
+					return err
+				}
+				report.Objects++
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, fail.Wrap(err, fmt.Sprintf("failed to export metadata folder '%s'", folder))
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return nil, err
+	}
+	if err = gw.Close(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Import reads a tar.gz archive produced by Export and writes its content in the metadata of the tenant, encrypted
+// with the key of the tenant; existing objects with the same names are overwritten
+func Import(svc iaas.Service, r io.Reader) (report *abstract.MetadataArchiveReport, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if r == nil {
+		return nil, fail.InvalidParameterError("r", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fail.InvalidRequestError(fmt.Sprintf("not a metadata archive: %v", err))
+	}
+	defer func() {
+		_ = gr.Close()
+	}()
+	tr := tar.NewReader(gr)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != metadataArchiveManifest {
+		return nil, fail.InvalidRequestError(fmt.Sprintf("not a metadata archive: missing '%s'", metadataArchiveManifest))
+	}
+	manifest := metadataArchiveManifestContent{}
+	err = json.NewDecoder(tr).Decode(&manifest)
+	if err != nil {
+		return nil, fail.InvalidRequestError(fmt.Sprintf("not a metadata archive: invalid '%s': %v", metadataArchiveManifest, err))
+	}
+	if manifest.Version > metadataArchiveVersion {
+		return nil, fail.InvalidRequestError(
+			fmt.Sprintf("metadata archive version %d is not supported (max %d)", manifest.Version, metadataArchiveVersion),
+		)
+	}
+
+	report = &abstract.MetadataArchiveReport{
+		Date:    manifest.Date,
+		Folders: manifest.Folders,
+	}
+	known := map[string]struct{}{}
+	for _, v := range getArchivedFolders() {
+		known[v] = struct{}{}
+	}
+	folders := map[string]*metadata.Folder{}
+	for {
+		hdr, err = tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, fail.Wrap(err, "failed to read metadata archive")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(hdr.Name)
+		parts := strings.SplitN(name, "/", 2)
+		if len(parts) != 2 || strings.HasPrefix(name, "/") || strings.HasPrefix(name, "..") {
+			return report, fail.InvalidRequestError(fmt.Sprintf("invalid entry '%s' in metadata archive", hdr.Name))
+		}
+		if _, ok := known[parts[0]]; !ok {
+			return report, fail.InvalidRequestError(fmt.Sprintf("unknown metadata folder '%s' in archive", parts[0]))
+		}
+		f, ok := folders[parts[0]]
+		if !ok {
+			f, err = metadata.NewFolder(svc, parts[0])
+			if err != nil {
+				return report, err
+			}
+			folders[parts[0]] = f
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return report, fail.Wrap(err, fmt.Sprintf("failed to read '%s' from metadata archive", name))
+		}
+		dir, file := path.Split(parts[1])
+		err = f.Write(strings.Trim(dir, "/"), file, data)
+		if err != nil {
+			return report, fail.Wrap(err, fmt.Sprintf("failed to import '%s'", name))
+		}
+		report.Objects++
+	}
+	return report, nil
+}
+
+// Backup writes an archive of the metadata of the tenant in the bucket 'bucketName', which has to exist and cannot be
+// the metadata bucket; the name of the archive starts with MetadataBackupPrefix, allowing lifecycle rules of the bucket
+// to remove old backups
+func Backup(svc iaas.Service, bucketName string) (report *abstract.MetadataArchiveReport, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if bucketName == "" {
+		return nil, fail.InvalidParameterError("bucketName", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, fmt.Sprintf("('%s')", bucketName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	if mb := svc.GetMetadataBucket(); mb != nil {
+		if name, _ := mb.GetName(); name == bucketName {
+			return nil, fail.InvalidRequestError("cannot backup metadata in the metadata bucket")
+		}
+	}
+	found, err := svc.FindBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fail.NotFoundError(fmt.Sprintf("failed to find backup bucket '%s'", bucketName))
+	}
+
+	var buffer bytes.Buffer
+	report, err = Export(svc, &buffer)
+	if err != nil {
+		return nil, err
+	}
+	report.Bucket = bucketName
+	report.Path = MetadataBackupPrefix + report.Date.Format("20060102-150405") + ".tar.gz"
+	_, err = svc.WriteObject(bucketName, report.Path, &buffer, int64(buffer.Len()), nil)
+	if err != nil {
+		return nil, fail.Wrap(err, fmt.Sprintf("failed to write backup of metadata in bucket '%s'", bucketName))
+	}
+	return report, nil
+}
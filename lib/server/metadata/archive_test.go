@@ -0,0 +1,175 @@
+package metadata
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/metadatastore"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+)
+
+// archiveTestService implements the part of iaas.Service used by Export, Import and Backup, on a local object storage
+type archiveTestService struct {
+	iaas.Service
+	location objectstorage.Location
+	bucket   objectstorage.Bucket
+	store    metadatastore.Store
+	keyring  *crypt.Keyring
+}
+
+func newArchiveTestService(t *testing.T, passphrase string) *archiveTestService {
+	folder, err := ioutil.TempDir("", "safescale-metadata")
+	require.Nil(t, err)
+	l, err := objectstorage.NewLocation(objectstorage.Config{Type: "local", Endpoint: folder})
+	require.Nil(t, err)
+	b, err := l.CreateBucket("metadata")
+	require.Nil(t, err)
+	store, err := metadatastore.NewBucketStore(b)
+	require.Nil(t, err)
+	key, err := crypt.NewEncryptionKey([]byte(passphrase))
+	require.Nil(t, err)
+	return &archiveTestService{location: l, bucket: b, store: store, keyring: crypt.NewKeyring(key)}
+}
+
+func (s *archiveTestService) GetName() string {
+	return "local"
+}
+
+func (s *archiveTestService) GetTenantParameters() map[string]interface{} {
+	return map[string]interface{}{"name": "test"}
+}
+
+func (s *archiveTestService) GetMetadataBucket() objectstorage.Bucket {
+	return s.bucket
+}
+
+func (s *archiveTestService) GetMetadataStore() metadatastore.Store {
+	return s.store
+}
+
+func (s *archiveTestService) GetMetadataKeyring() *crypt.Keyring {
+	return s.keyring
+}
+
+func (s *archiveTestService) FindBucket(name string) (bool, error) {
+	return s.location.FindBucket(name)
+}
+
+func (s *archiveTestService) CreateBucket(name string) (objectstorage.Bucket, error) {
+	return s.location.CreateBucket(name)
+}
+
+func (s *archiveTestService) WriteObject(
+	bucketName, objectName string, source io.Reader, size int64, om objectstorage.ObjectMetadata,
+) (objectstorage.Object, error) {
+	return s.location.WriteObject(bucketName, objectName, source, size, om)
+}
+
+func writeTestMetadata(t *testing.T, svc iaas.Service, folder, path, name, content string) {
+	f, err := metadata.NewFolder(svc, folder)
+	require.Nil(t, err)
+	require.Nil(t, f.Write(path, name, []byte(content)))
+}
+
+func readTestMetadata(t *testing.T, svc iaas.Service, folder, path, name string) string {
+	f, err := metadata.NewFolder(svc, folder)
+	require.Nil(t, err)
+	var content string
+	err = f.Read(
+		path, name, func(buf []byte) error {
+			content = string(buf)
+			return nil
+		},
+	)
+	require.Nil(t, err)
+	return content
+}
+
+func TestExportImport(t *testing.T) {
+	source := newArchiveTestService(t, "source key")
+	writeTestMetadata(t, source, hostsFolderName, ByIDFolderName, "1", `{"id":"1","name":"gw-net"}`)
+	writeTestMetadata(t, source, networksFolderName, ByNameFolderName, "net", `{"id":"2","name":"net"}`)
+
+	var archive bytes.Buffer
+	report, err := Export(source, &archive)
+	require.Nil(t, err)
+	assert.Equal(t, 2, report.Objects)
+	assert.Contains(t, report.Folders, hostsFolderName)
+
+	// the target tenant has another key: objects are re-encrypted on import
+	target := newArchiveTestService(t, "target key")
+	report, err = Import(target, bytes.NewReader(archive.Bytes()))
+	require.Nil(t, err)
+	assert.Equal(t, 2, report.Objects)
+	assert.Equal(t, `{"id":"1","name":"gw-net"}`, readTestMetadata(t, target, hostsFolderName, ByIDFolderName, "1"))
+	assert.Equal(t, `{"id":"2","name":"net"}`, readTestMetadata(t, target, networksFolderName, ByNameFolderName, "net"))
+
+	var raw bytes.Buffer
+	_, err = target.bucket.ReadObject(hostsFolderName+"/"+ByIDFolderName+"/1", &raw, 0, 0)
+	require.Nil(t, err)
+	_, err = source.keyring.Open(raw.Bytes())
+	assert.NotNil(t, err)
+}
+
+func TestImport_InvalidArchive(t *testing.T) {
+	svc := newArchiveTestService(t, "key")
+
+	_, err := Import(svc, bytes.NewReader([]byte("not an archive")))
+	assert.IsType(t, fail.ErrInvalidRequest{}, err)
+
+	buildArchive := func(entries ...string) *bytes.Buffer {
+		var buffer bytes.Buffer
+		gw := gzip.NewWriter(&buffer)
+		tw := tar.NewWriter(gw)
+		for _, e := range entries {
+			content := []byte(`{"version":1}`)
+			require.Nil(t, tw.WriteHeader(&tar.Header{Name: e, Typeflag: tar.TypeReg, Mode: 0600, Size: int64(len(content))}))
+			_, err := tw.Write(content)
+			require.Nil(t, err)
+		}
+		require.Nil(t, tw.Close())
+		require.Nil(t, gw.Close())
+		return &buffer
+	}
+
+	_, err = Import(svc, buildArchive("hosts/byID/1"))
+	assert.IsType(t, fail.ErrInvalidRequest{}, err)
+	_, err = Import(svc, buildArchive(metadataArchiveManifest, "../hosts/byID/1"))
+	assert.IsType(t, fail.ErrInvalidRequest{}, err)
+	_, err = Import(svc, buildArchive(metadataArchiveManifest, "unknown/1"))
+	assert.IsType(t, fail.ErrInvalidRequest{}, err)
+}
+
+func TestBackup(t *testing.T) {
+	svc := newArchiveTestService(t, "key")
+	writeTestMetadata(t, svc, volumesFolderName, ByIDFolderName, "1", `{"id":"1"}`)
+
+	_, err := Backup(svc, "backups")
+	assert.IsType(t, fail.ErrNotFound{}, err)
+	_, err = Backup(svc, "metadata")
+	assert.IsType(t, fail.ErrInvalidRequest{}, err)
+
+	_, err = svc.CreateBucket("backups")
+	require.Nil(t, err)
+	report, err := Backup(svc, "backups")
+	require.Nil(t, err)
+	assert.Equal(t, "backups", report.Bucket)
+	assert.Equal(t, 1, report.Objects)
+
+	var archive bytes.Buffer
+	require.Nil(t, svc.location.ReadObject("backups", report.Path, &archive, 0, 0))
+	report, err = Import(svc, &archive)
+	require.Nil(t, err)
+	assert.Equal(t, 1, report.Objects)
+}
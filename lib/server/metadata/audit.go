@@ -0,0 +1,188 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+)
+
+const (
+	// auditFolderName is the folder containing the audit log, one subfolder per day
+	auditFolderName = "audit"
+	// auditDayLayout is the layout of the names of the folders of the days
+	auditDayLayout = "20060102"
+)
+
+// Audit starts the record of an operation on 'target' ('<resource>:<name or id>'); the returned function completes
+// the record with the outcome of the operation and writes it in the audit log. Intended to be deferred:
+//
+//	defer metadata.Audit(ctx, svc, "host.delete", "host:"+ref, nil)(&err)
+//
+// A failure to write the record is logged, and doesn't change the outcome of the operation.
+func Audit(ctx context.Context, svc iaas.Service, operation, target string, parameters map[string]string) func(*error) {
+	record := abstract.AuditRecord{
+		Date:       time.Now().UTC(),
+		Actor:      srvutils.GetActor(ctx),
+		Operation:  operation,
+		Target:     target,
+		Parameters: parameters,
+	}
+	return func(err *error) {
+		record.Duration = time.Since(record.Date)
+		record.Outcome = abstract.AuditSuccess
+		if err != nil && *err != nil {
+			record.Outcome = abstract.AuditFailure
+			record.Error = (*err).Error()
+		}
+		if xerr := WriteAuditRecord(svc, &record); xerr != nil {
+			logrus.Warnf("failed to record operation '%s' on '%s' in audit log: %v", operation, target, xerr)
+		}
+	}
+}
+
+// WriteAuditRecord appends a record to the audit log of the tenant; each record is a new object, never modified
+func WriteAuditRecord(svc iaas.Service, record *abstract.AuditRecord) error {
+	if svc == nil {
+		return fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if record == nil {
+		return fail.InvalidParameterError("record", "cannot be nil")
+	}
+
+	if record.Tenant == "" {
+		record.Tenant, _ = svc.GetTenantParameters()["name"].(string)
+	}
+	content, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	folder, err := metadata.NewFolder(svc, auditFolderName)
+	if err != nil {
+		return err
+	}
+	name := record.Date.UTC().Format("20060102T150405.000000000Z") + "-" + id.String()
+	return folder.Write(record.Date.UTC().Format(auditDayLayout), name, content)
+}
+
+// ListAuditRecords returns the records of the audit log of the tenant since 'since', concerning 'resource' if not
+// empty ('<resource>' or '<resource>:<name or id>'), sorted by date
+func ListAuditRecords(svc iaas.Service, since time.Time, resource string) ([]*abstract.AuditRecord, error) {
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+
+	folder, err := metadata.NewFolder(svc, auditFolderName)
+	if err != nil {
+		return nil, err
+	}
+	firstDay := since.UTC().Format(auditDayLayout)
+	var list []*abstract.AuditRecord
+	err = forEachAuditRecord(
+		folder, func(day, name string) error {
+			if day < firstDay {
+				return nil
+			}
+			return folder.Read(
+				day, name, func(buf []byte) error {
+					record := &abstract.AuditRecord{}
+					err := json.Unmarshal(buf, record)
+					if err != nil {
+						return err
+					}
+					if !record.Date.Before(since) && record.MatchResource(resource) {
+						list = append(list, record)
+					}
+					return nil
+				},
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Date.Before(list[j].Date) })
+	return list, nil
+}
+
+// PurgeAuditRecords removes the records of the audit log of the tenant older than 'retention', a day at a time;
+// returns the number of records removed
+func PurgeAuditRecords(svc iaas.Service, retention time.Duration) (int, error) {
+	if svc == nil {
+		return 0, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if retention <= 0 {
+		return 0, fail.InvalidParameterError("retention", "must be positive")
+	}
+
+	folder, err := metadata.NewFolder(svc, auditFolderName)
+	if err != nil {
+		return 0, err
+	}
+	// the day containing the limit is kept whole
+	limit := time.Now().UTC().Add(-retention).Format(auditDayLayout)
+	count := 0
+	err = forEachAuditRecord(
+		folder, func(day, name string) error {
+			if day >= limit {
+				return nil
+			}
+			err := folder.Delete(day, name)
+			if err == nil {
+				count++
+			}
+			return err
+		},
+	)
+	return count, err
+}
+
+// forEachAuditRecord calls callback with the day and the name of each record of the audit log
+func forEachAuditRecord(folder *metadata.Folder, callback func(day, name string) error) error {
+	list, err := folder.GetStore().List(auditFolderName)
+	if err != nil {
+		return fail.Wrap(err, "failed to list audit log")
+	}
+	for _, key := range list {
+		parts := strings.Split(strings.TrimPrefix(key, auditFolderName+"/"), "/")
+		if len(parts) != 2 || parts[1] == "" {
+			continue
+		}
+		err = callback(parts[0], parts[1])
+		if err != nil {
+			return fail.Wrap(err, fmt.Sprintf("failed to process audit record '%s'", key))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+)
+
+func TestAudit(t *testing.T) {
+	svc := newArchiveTestService(t, "key")
+
+	func() {
+		var err error
+		defer Audit(context.Background(), svc, "host.create", "host:foo", map[string]string{"network": "net"})(&err)
+	}()
+	func() {
+		err := errors.New("quota exceeded")
+		defer Audit(context.Background(), svc, "host.create", "host:bar", nil)(&err)
+	}()
+	func() {
+		var err error
+		defer Audit(context.Background(), svc, "network.delete", "network:net", nil)(&err)
+	}()
+
+	records, err := ListAuditRecords(svc, time.Now().Add(-time.Hour), "")
+	require.Nil(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "test", records[0].Tenant)
+	assert.Equal(t, abstract.AuditSuccess, records[0].Outcome)
+	assert.Equal(t, "net", records[0].Parameters["network"])
+	assert.NotEmpty(t, records[0].Actor)
+	assert.Equal(t, abstract.AuditFailure, records[1].Outcome)
+	assert.Equal(t, "quota exceeded", records[1].Error)
+
+	records, err = ListAuditRecords(svc, time.Time{}, "host")
+	require.Nil(t, err)
+	assert.Len(t, records, 2)
+	records, err = ListAuditRecords(svc, time.Time{}, "host:foo")
+	require.Nil(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "host:foo", records[0].Target)
+	records, err = ListAuditRecords(svc, time.Now().Add(time.Hour), "")
+	require.Nil(t, err)
+	assert.Empty(t, records)
+}
+
+func TestPurgeAuditRecords(t *testing.T) {
+	svc := newArchiveTestService(t, "key")
+	old := &abstract.AuditRecord{Date: time.Now().Add(-100 * 24 * time.Hour), Operation: "host.delete", Target: "host:old"}
+	require.Nil(t, WriteAuditRecord(svc, old))
+	recent := &abstract.AuditRecord{Date: time.Now(), Operation: "host.delete", Target: "host:recent"}
+	require.Nil(t, WriteAuditRecord(svc, recent))
+
+	_, err := PurgeAuditRecords(svc, 0)
+	assert.NotNil(t, err)
+
+	count, err := PurgeAuditRecords(svc, abstract.DefaultAuditRetention)
+	require.Nil(t, err)
+	assert.Equal(t, 1, count)
+	records, err := ListAuditRecords(svc, time.Time{}, "")
+	require.Nil(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "host:recent", records[0].Target)
+}
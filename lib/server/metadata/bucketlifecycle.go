@@ -0,0 +1,226 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+const (
+	// bucketLifecycleFolderName is the technical name of the container used to store the lifecycle rules of the buckets
+	bucketLifecycleFolderName = "lifecycles"
+)
+
+// BucketLifecycle links Object Storage folder and lifecycle rules of buckets
+type BucketLifecycle struct {
+	item *metadata.Item
+	name *string
+}
+
+// NewBucketLifecycle creates an instance of metadata.BucketLifecycle
+func NewBucketLifecycle(svc iaas.Service) (_ *BucketLifecycle, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	aLifecycle, err := metadata.NewItem(svc, bucketLifecycleFolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &BucketLifecycle{
+		item: aLifecycle,
+	}, nil
+}
+
+// Carry links a BucketLifecycle instance to the Metadata instance
+func (mbl *BucketLifecycle) Carry(bl *abstract.BucketLifecycle) (_ *BucketLifecycle, err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbl == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if mbl.item == nil {
+		return nil, fail.InvalidInstanceContentError("mbl.item", "cannot be nil")
+	}
+	if bl == nil {
+		return nil, fail.InvalidParameterError("bl", "cannot be nil")
+	}
+	mbl.item.Carry(bl)
+	mbl.name = &bl.Bucket
+	return mbl, nil
+}
+
+// Get returns the BucketLifecycle instance linked to metadata
+func (mbl *BucketLifecycle) Get() (_ *abstract.BucketLifecycle, err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbl == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if mbl.item == nil {
+		return nil, fail.InvalidInstanceContentError("mbl.item", "cannot be nil")
+	}
+	if bl, ok := mbl.item.Get().(*abstract.BucketLifecycle); ok {
+		return bl, nil
+	}
+	return nil, fail.InconsistentError("invalid content in bucket lifecycle metadata")
+}
+
+// Write updates the metadata corresponding to the lifecycle rules in the Object Storage
+func (mbl *BucketLifecycle) Write() (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbl == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mbl.item == nil {
+		return fail.InvalidInstanceContentError("mbl.item", "cannot be nil")
+	}
+	return mbl.item.Write(*mbl.name)
+}
+
+// ReadByName reads the lifecycle rules of a bucket identified by name
+func (mbl *BucketLifecycle) ReadByName(name string) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbl == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mbl.item == nil {
+		return fail.InvalidInstanceContentError("mbl.item", "cannot be nil")
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "('"+name+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	bl := &abstract.BucketLifecycle{}
+	err = mbl.item.Read(
+		name, func(buf []byte) (serialize.Serializable, error) {
+			err := bl.Deserialize(buf)
+			if err != nil {
+				return nil, err
+			}
+			return bl, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	mbl.name = &bl.Bucket
+	return nil
+}
+
+// Delete deletes the metadata corresponding to the lifecycle rules
+func (mbl *BucketLifecycle) Delete() (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbl == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mbl.item == nil {
+		return fail.InvalidInstanceContentError("mbl.item", "cannot be nil")
+	}
+
+	err = mbl.item.Delete(*mbl.name)
+	if err != nil {
+		return err
+	}
+	mbl.name = nil
+	return nil
+}
+
+// Browse walks through lifecycles folder and executes a callback for each entry
+func (mbl *BucketLifecycle) Browse(callback func(*abstract.BucketLifecycle) error) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbl == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mbl.item == nil {
+		return fail.InvalidInstanceContentError("mbl.item", "cannot be nil")
+	}
+
+	return mbl.item.Browse(
+		func(buf []byte) error {
+			bl := &abstract.BucketLifecycle{}
+			err := bl.Deserialize(buf)
+			if err != nil {
+				return err
+			}
+			return callback(bl)
+		},
+	)
+}
+
+// SaveBucketLifecycle saves the lifecycle rules of a bucket in Object Storage
+func SaveBucketLifecycle(svc iaas.Service, bl *abstract.BucketLifecycle) (mbl *BucketLifecycle, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if bl == nil {
+		return nil, fail.InvalidParameterError("bl", "cannot be nil")
+	}
+
+	mbl, err = NewBucketLifecycle(svc)
+	if err != nil {
+		return nil, err
+	}
+	_, err = mbl.Carry(bl)
+	if err != nil {
+		return nil, err
+	}
+	err = mbl.Write()
+	if err != nil {
+		return nil, err
+	}
+	return mbl, nil
+}
+
+// LoadBucketLifecycle gets the lifecycle rules of a bucket from Object Storage
+func LoadBucketLifecycle(svc iaas.Service, name string) (mbl *BucketLifecycle, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	mbl, err = NewBucketLifecycle(svc)
+	if err != nil {
+		return nil, err
+	}
+	err = mbl.ReadByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return mbl, nil
+}
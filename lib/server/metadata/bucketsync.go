@@ -0,0 +1,186 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+const (
+	// bucketSyncFolderName is the technical name of the container used to store the states of bucket synchronizations
+	bucketSyncFolderName = "bucketsyncs"
+)
+
+// BucketSync links Object Storage folder and states of bucket synchronizations
+type BucketSync struct {
+	item *metadata.Item
+	name *string
+}
+
+// NewBucketSync creates an instance of metadata.BucketSync
+func NewBucketSync(svc iaas.Service) (_ *BucketSync, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	aSync, err := metadata.NewItem(svc, bucketSyncFolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &BucketSync{
+		item: aSync,
+	}, nil
+}
+
+// Carry links a BucketSyncState instance to the Metadata instance
+func (mbs *BucketSync) Carry(bss *abstract.BucketSyncState) (_ *BucketSync, err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbs == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if mbs.item == nil {
+		return nil, fail.InvalidInstanceContentError("mbs.item", "cannot be nil")
+	}
+	if bss == nil {
+		return nil, fail.InvalidParameterError("bss", "cannot be nil")
+	}
+	mbs.item.Carry(bss)
+	name := bss.GetName()
+	mbs.name = &name
+	return mbs, nil
+}
+
+// Get returns the BucketSyncState instance linked to metadata
+func (mbs *BucketSync) Get() (_ *abstract.BucketSyncState, err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbs == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if mbs.item == nil {
+		return nil, fail.InvalidInstanceContentError("mbs.item", "cannot be nil")
+	}
+	if bss, ok := mbs.item.Get().(*abstract.BucketSyncState); ok {
+		return bss, nil
+	}
+	return nil, fail.InconsistentError("invalid content in bucket synchronization metadata")
+}
+
+// Write updates the metadata corresponding to the synchronization in the Object Storage
+func (mbs *BucketSync) Write() (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbs == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mbs.item == nil {
+		return fail.InvalidInstanceContentError("mbs.item", "cannot be nil")
+	}
+	return mbs.item.Write(*mbs.name)
+}
+
+// ReadByName reads the metadata of a synchronization identified by name
+func (mbs *BucketSync) ReadByName(name string) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mbs == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mbs.item == nil {
+		return fail.InvalidInstanceContentError("mbs.item", "cannot be nil")
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "('"+name+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	bss := &abstract.BucketSyncState{}
+	err = mbs.item.Read(
+		name, func(buf []byte) (serialize.Serializable, error) {
+			err := bss.Deserialize(buf)
+			if err != nil {
+				return nil, err
+			}
+			return bss, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	name = bss.GetName()
+	mbs.name = &name
+	return nil
+}
+
+// SaveBucketSync saves the state of a synchronization in Object Storage
+func SaveBucketSync(svc iaas.Service, bss *abstract.BucketSyncState) (mbs *BucketSync, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if bss == nil {
+		return nil, fail.InvalidParameterError("bss", "cannot be nil")
+	}
+
+	mbs, err = NewBucketSync(svc)
+	if err != nil {
+		return nil, err
+	}
+	_, err = mbs.Carry(bss)
+	if err != nil {
+		return nil, err
+	}
+	err = mbs.Write()
+	if err != nil {
+		return nil, err
+	}
+	return mbs, nil
+}
+
+// LoadBucketSync gets the state of a synchronization from Object Storage
+func LoadBucketSync(svc iaas.Service, name string) (mbs *BucketSync, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	mbs, err = NewBucketSync(svc)
+	if err != nil {
+		return nil, err
+	}
+	err = mbs.ReadByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return mbs, nil
+}
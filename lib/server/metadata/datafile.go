@@ -0,0 +1,226 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+const (
+	// dataFolderName is the technical name of the container used to store the manifests of the data service
+	dataFolderName = "data"
+)
+
+// DataFile links Object Storage folder and manifests of files stored by the data service
+type DataFile struct {
+	item *metadata.Item
+	name *string
+}
+
+// NewDataFile creates an instance of metadata.DataFile
+func NewDataFile(svc iaas.Service) (_ *DataFile, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	aFile, err := metadata.NewItem(svc, dataFolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &DataFile{
+		item: aFile,
+	}, nil
+}
+
+// Carry links a DataFile instance to the Metadata instance
+func (mdf *DataFile) Carry(df *abstract.DataFile) (_ *DataFile, err error) {
+	defer fail.OnPanic(&err)()
+
+	if mdf == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if mdf.item == nil {
+		return nil, fail.InvalidInstanceContentError("mdf.item", "cannot be nil")
+	}
+	if df == nil {
+		return nil, fail.InvalidParameterError("df", "cannot be nil")
+	}
+	mdf.item.Carry(df)
+	mdf.name = &df.Name
+	return mdf, nil
+}
+
+// Get returns the DataFile instance linked to metadata
+func (mdf *DataFile) Get() (_ *abstract.DataFile, err error) {
+	defer fail.OnPanic(&err)()
+
+	if mdf == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if mdf.item == nil {
+		return nil, fail.InvalidInstanceContentError("mdf.item", "cannot be nil")
+	}
+	if df, ok := mdf.item.Get().(*abstract.DataFile); ok {
+		return df, nil
+	}
+	return nil, fail.InconsistentError("invalid content in data file metadata")
+}
+
+// Write updates the metadata corresponding to the file in the Object Storage
+func (mdf *DataFile) Write() (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mdf == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mdf.item == nil {
+		return fail.InvalidInstanceContentError("mdf.item", "cannot be nil")
+	}
+	return mdf.item.Write(*mdf.name)
+}
+
+// ReadByName reads the metadata of a file identified by name
+func (mdf *DataFile) ReadByName(name string) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mdf == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mdf.item == nil {
+		return fail.InvalidInstanceContentError("mdf.item", "cannot be nil")
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "('"+name+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	df := &abstract.DataFile{}
+	err = mdf.item.Read(
+		name, func(buf []byte) (serialize.Serializable, error) {
+			err := df.Deserialize(buf)
+			if err != nil {
+				return nil, err
+			}
+			return df, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	mdf.name = &df.Name
+	return nil
+}
+
+// Delete deletes the metadata corresponding to the file
+func (mdf *DataFile) Delete() (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mdf == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mdf.item == nil {
+		return fail.InvalidInstanceContentError("mdf.item", "cannot be nil")
+	}
+
+	err = mdf.item.Delete(*mdf.name)
+	if err != nil {
+		return err
+	}
+	mdf.name = nil
+	return nil
+}
+
+// Browse walks through data folder and executes a callback for each entry
+func (mdf *DataFile) Browse(callback func(*abstract.DataFile) error) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if mdf == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mdf.item == nil {
+		return fail.InvalidInstanceContentError("mdf.item", "cannot be nil")
+	}
+
+	return mdf.item.Browse(
+		func(buf []byte) error {
+			df := &abstract.DataFile{}
+			err := df.Deserialize(buf)
+			if err != nil {
+				return err
+			}
+			return callback(df)
+		},
+	)
+}
+
+// SaveDataFile saves the manifest of a file in Object Storage
+func SaveDataFile(svc iaas.Service, df *abstract.DataFile) (mdf *DataFile, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if df == nil {
+		return nil, fail.InvalidParameterError("df", "cannot be nil")
+	}
+
+	mdf, err = NewDataFile(svc)
+	if err != nil {
+		return nil, err
+	}
+	_, err = mdf.Carry(df)
+	if err != nil {
+		return nil, err
+	}
+	err = mdf.Write()
+	if err != nil {
+		return nil, err
+	}
+	return mdf, nil
+}
+
+// LoadDataFile gets the manifest of a file from Object Storage
+func LoadDataFile(svc iaas.Service, name string) (mdf *DataFile, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	mdf, err = NewDataFile(svc)
+	if err != nil {
+		return nil, err
+	}
+	err = mdf.ReadByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return mdf, nil
+}
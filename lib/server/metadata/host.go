@@ -448,8 +448,18 @@ func LoadHost(svc iaas.Service, ref string) (mh *Host, err error) {
 }
 
 // Acquire waits until the write lock is available, then locks the metadata
-func (mh *Host) Acquire() {
-	mh.item.Acquire()
+// The lock is shared with the other processes using the same metadata; fails with fail.ErrNotAvailable on contention.
+func (mh *Host) Acquire() error {
+	if mh == nil {
+		return fail.InvalidInstanceError()
+	}
+	if mh.item == nil {
+		return fail.InvalidInstanceContentError("mh.item", "cannot be nil")
+	}
+	if mh.id == nil {
+		return fail.InvalidInstanceContentError("mh.id", "cannot be nil")
+	}
+	return mh.item.Acquire(*mh.id)
 }
 
 // Release unlocks the metadata
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// propertiesFolders associates the metadata folders whose objects have properties to the module of these properties
+var propertiesFolders = struct {
+	sync.Mutex
+	modules map[string]string
+}{
+	modules: map[string]string{
+		hostsFolderName:    "abstract.host",
+		networksFolderName: "abstract.network",
+		volumesFolderName:  "abstract.volume",
+	},
+}
+
+// RegisterPropertiesFolder declares a metadata folder whose objects have properties of module, to be migrated by Migrate
+func RegisterPropertiesFolder(folder, module string) {
+	propertiesFolders.Lock()
+	defer propertiesFolders.Unlock()
+
+	propertiesFolders.modules[folder] = module
+}
+
+// Migrate applies to all the objects of metadata the migrations of properties registered in
+// serialize.PropertyMigrationRegistry, and saves them; with dryRun, nothing is saved
+// Objects are also migrated when read, the migration in bulk allows to remove later the code reading old properties.
+func Migrate(svc iaas.Service, dryRun bool) (report *abstract.MetadataMigrationReport, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	propertiesFolders.Lock()
+	modules := map[string]string{}
+	var folders []string
+	for k, v := range propertiesFolders.modules {
+		modules[k] = v
+		folders = append(folders, k)
+	}
+	propertiesFolders.Unlock()
+	sort.Strings(folders)
+
+	report = &abstract.MetadataMigrationReport{DryRun: dryRun}
+	for _, folder := range folders {
+		module := modules[folder]
+		if len(serialize.PropertyMigrationRegistry.Migrations(module)) == 0 {
+			continue
+		}
+		f, err := metadata.NewFolder(svc, folder)
+		if err != nil {
+			return report, err
+		}
+		err = f.Rewrite(
+			".", func(path string, buf []byte) ([]byte, error) {
+				report.Scanned++
+				migrated, applied, err := serialize.MigrateJSON(module, buf)
+				if err != nil || len(applied) == 0 {
+					return nil, err
+				}
+				mo := abstract.MigratedMetadata{Path: path}
+				for _, m := range applied {
+					mo.Migrations = append(mo.Migrations, m.String())
+				}
+				report.Migrated = append(report.Migrated, mo)
+				if dryRun {
+					return nil, nil
+				}
+				return migrated, nil
+			},
+		)
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
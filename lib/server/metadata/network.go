@@ -497,8 +497,18 @@ func (m *Network) ListHosts() (list []*abstract.Host, err error) {
 }
 
 // Acquire waits until the write lock is available, then locks the metadata
-func (m *Network) Acquire() {
-	m.item.Acquire()
+// The lock is shared with the other processes using the same metadata; fails with fail.ErrNotAvailable on contention.
+func (m *Network) Acquire() error {
+	if m == nil {
+		return fail.InvalidInstanceError()
+	}
+	if m.item == nil {
+		return fail.InvalidInstanceContentError("m.item", "cannot be nil")
+	}
+	if m.id == nil {
+		return fail.InvalidInstanceContentError("m.id", "cannot be nil")
+	}
+	return m.item.Acquire(*m.id)
 }
 
 // Release unlocks the metadata
@@ -562,6 +572,54 @@ func RemoveNetwork(svc iaas.Service, net *abstract.Network) (err error) {
 	return aNetm.Delete()
 }
 
+// UpdateNetwork applies 'updatefn' to the latest metadata of the network identified by 'id' and saves them, holding
+// the lock on these metadata to not overwrite concurrent updates made by other processes
+func UpdateNetwork(svc iaas.Service, id string, updatefn func(*abstract.Network) error) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+	if updatefn == nil {
+		return fail.InvalidParameterError("updatefn", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, "(<iaas.Service>, '"+id+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mn, err := NewNetwork(svc)
+	if err != nil {
+		return err
+	}
+	err = mn.ReadByID(id)
+	if err != nil {
+		return err
+	}
+	err = mn.Acquire()
+	if err != nil {
+		return err
+	}
+	defer mn.Release()
+
+	err = mn.Reload()
+	if err != nil {
+		return err
+	}
+	network, err := mn.Get()
+	if err != nil {
+		return err
+	}
+	err = updatefn(network)
+	if err != nil {
+		return err
+	}
+	return mn.Write()
+}
+
 // LoadNetwork gets the Network definition from Object Storage
 // logic: Read by ID; if error is ErrNotFound then read by name; if error is ErrNotFound return this error
 //        In case of any other error, abort the retry to propagate the error
@@ -797,10 +855,14 @@ func (mg *Gateway) Delete() (err error) {
 	defer tracer.OnExitTrace()()
 	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
 
-	mg.network.Acquire()
+	err = mg.network.Acquire()
+	if err != nil {
+		return err
+	}
 
 	mgm, err := mg.network.Get()
 	if err != nil {
+		mg.network.Release()
 		return err
 	}
 
@@ -810,14 +872,20 @@ func (mg *Gateway) Delete() (err error) {
 	if err != nil {
 		return err
 	}
-	mg.host.Acquire()
+	err = mg.host.Acquire()
+	if err != nil {
+		return err
+	}
 	defer mg.host.Release()
 	return mg.host.Delete()
 }
 
 // Acquire waits until the write lock is available, then locks the metadata
-func (mg *Gateway) Acquire() {
-	mg.host.Acquire()
+func (mg *Gateway) Acquire() error {
+	if mg == nil {
+		return fail.InvalidInstanceError()
+	}
+	return mg.host.Acquire()
 }
 
 // Release unlocks the metadata
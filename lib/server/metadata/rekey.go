@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Rekey re-encrypts in place every object of the metadata of the tenant with the key derived from 'passphrase'.
+// The new key becomes the key encrypting the metadata written by the daemon, the former keys being still used to read
+// the objects not yet re-encrypted. Each object records the ID of its key (its generation): objects already encrypted
+// with the new key are skipped, so Rekey can be run again with the same passphrase to resume an interrupted rotation.
+func Rekey(svc iaas.Service, passphrase string) (report *abstract.MetadataRekeyReport, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if passphrase == "" {
+		return nil, fail.InvalidParameterError("passphrase", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	keyring := svc.GetMetadataKeyring()
+	if keyring == nil {
+		return nil, fail.InvalidRequestError(
+			"the metadata of the tenant are not encrypted; set 'CryptKey' in section 'metadata' of the tenant to encrypt them",
+		)
+	}
+	key, err := crypt.NewEncryptionKey([]byte(passphrase))
+	if err != nil {
+		return nil, err
+	}
+	keyring.Rotate(key)
+
+	report = &abstract.MetadataRekeyReport{KeyID: keyring.CurrentID()}
+	store := svc.GetMetadataStore()
+	list, err := store.List("")
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to list metadata")
+	}
+	for _, name := range list {
+		rekeyed := false
+		err = store.Update(
+			name, func(data []byte) ([]byte, error) {
+				rekeyed = false
+				// empty content is a folder of some object storages
+				if len(data) == 0 || crypt.Generation(data) == report.KeyID {
+					return nil, nil
+				}
+				content, err := keyring.Open(data)
+				if err != nil {
+					return nil, err
+				}
+				rekeyed = true
+				return keyring.Seal(content)
+			},
+		)
+		if err != nil {
+			return nil, fail.Wrap(
+				err, fmt.Sprintf(
+					"failed to re-encrypt metadata '%s' after %d objects; run the rotation again to resume", name, report.Rekeyed,
+				),
+			)
+		}
+		report.Scanned++
+		if rekeyed {
+			report.Rekeyed++
+		} else {
+			report.Skipped++
+		}
+	}
+	return report, nil
+}
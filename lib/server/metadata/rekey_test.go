@@ -0,0 +1,70 @@
+package metadata
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func TestRekey(t *testing.T) {
+	svc := newArchiveTestService(t, "old key")
+	writeTestMetadata(t, svc, hostsFolderName, ByIDFolderName, "1", `{"id":"1","name":"host"}`)
+	writeTestMetadata(t, svc, networksFolderName, ByIDFolderName, "2", `{"id":"2","name":"net"}`)
+	// object written before the key generation was recorded
+	oldKey, err := crypt.NewEncryptionKey([]byte("old key"))
+	require.Nil(t, err)
+	legacy, err := crypt.Encrypt([]byte(`{"id":"3","name":"vol"}`), oldKey)
+	require.Nil(t, err)
+	_, err = svc.bucket.WriteObject(volumesFolderName+"/"+ByIDFolderName+"/3", bytes.NewReader(legacy), int64(len(legacy)), nil)
+	require.Nil(t, err)
+
+	_, err = Rekey(svc, "")
+	assert.IsType(t, fail.ErrInvalidParameter{}, err)
+
+	report, err := Rekey(svc, "new key")
+	require.Nil(t, err)
+	newKey, err := crypt.NewEncryptionKey([]byte("new key"))
+	require.Nil(t, err)
+	assert.Equal(t, crypt.KeyID(newKey), report.KeyID)
+	assert.Equal(t, 3, report.Scanned)
+	assert.Equal(t, 3, report.Rekeyed)
+
+	var raw bytes.Buffer
+	_, err = svc.bucket.ReadObject(volumesFolderName+"/"+ByIDFolderName+"/3", &raw, 0, 0)
+	require.Nil(t, err)
+	assert.Equal(t, report.KeyID, crypt.Generation(raw.Bytes()))
+	assert.Equal(t, `{"id":"3","name":"vol"}`, readTestMetadata(t, svc, volumesFolderName, ByIDFolderName, "3"))
+
+	// the new key alone reads the re-encrypted metadata
+	svc.keyring = crypt.NewKeyring(newKey)
+	assert.Equal(t, `{"id":"1","name":"host"}`, readTestMetadata(t, svc, hostsFolderName, ByIDFolderName, "1"))
+
+	// running the rotation again is harmless
+	report, err = Rekey(svc, "new key")
+	require.Nil(t, err)
+	assert.Equal(t, 0, report.Rekeyed)
+	assert.Equal(t, 3, report.Skipped)
+}
+
+func TestRekey_Resume(t *testing.T) {
+	svc := newArchiveTestService(t, "old key")
+	writeTestMetadata(t, svc, hostsFolderName, ByIDFolderName, "1", `{"id":"1","name":"host"}`)
+
+	// an interrupted rotation leaves objects of both generations, which are all read
+	newKey, err := crypt.NewEncryptionKey([]byte("new key"))
+	require.Nil(t, err)
+	svc.keyring.Rotate(newKey)
+	writeTestMetadata(t, svc, networksFolderName, ByIDFolderName, "2", `{"id":"2","name":"net"}`)
+	assert.Equal(t, `{"id":"1","name":"host"}`, readTestMetadata(t, svc, hostsFolderName, ByIDFolderName, "1"))
+	assert.Equal(t, `{"id":"2","name":"net"}`, readTestMetadata(t, svc, networksFolderName, ByIDFolderName, "2"))
+
+	report, err := Rekey(svc, "new key")
+	require.Nil(t, err)
+	assert.Equal(t, 1, report.Rekeyed)
+	assert.Equal(t, 1, report.Skipped)
+}
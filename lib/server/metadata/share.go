@@ -319,6 +319,28 @@ func (ms *Share) Browse(callback func(string, string) error) (err error) {
 	)
 }
 
+// BrowseAll walks through shares folder and executes a callback for each entry, with all the fields of the entry
+func (ms *Share) BrowseAll(callback func(hostID, hostName, shareID, shareName string) error) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return fail.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+	return ms.item.BrowseInto(
+		ByNameFolderName, func(buf []byte) error {
+			si := shareItem{}
+			err := (&si).Deserialize(buf)
+			if err != nil {
+				return err
+			}
+			return callback(si.HostID, si.HostName, si.ShareID, si.ShareName)
+		},
+	)
+}
+
 // // AddClient adds a client to the Nas definition in Object Storage
 // func (m *Nas) AddClient(nas *abstract.Nas) error {
 // 	return NewNas(m.item.GetService()).Carry(nas).item.WriteInto(*m.id, nas.ID)
@@ -370,16 +392,20 @@ func (ms *Share) Browse(callback func(string, string) error) (err error) {
 // }
 
 // Acquire waits until the write lock is available, then locks the metadata.
+// The lock is shared with the other processes using the same metadata; fails with fail.ErrNotAvailable on contention.
 //
 // May panic (see fail.OnPanic() usage to intercept and translate it to an error)
-func (ms *Share) Acquire() {
+func (ms *Share) Acquire() error {
 	if ms == nil {
 		panic("invalid instance")
 	}
 	if ms.item == nil {
 		panic("invalid instance content: ms.item cannot be nil")
 	}
-	ms.item.Acquire()
+	if ms.id == nil {
+		return fail.InvalidInstanceContentError("ms.id", "cannot be nil")
+	}
+	return ms.item.Acquire(*ms.id)
 }
 
 // Release unlocks the metadata
@@ -0,0 +1,442 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/graymeta/stow"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// volumeSnapshotsFolderName is the technical name of the container used to store volume snapshot info
+	volumeSnapshotsFolderName = "snapshots"
+)
+
+// VolumeSnapshot links Object Storage folder and VolumeSnapshots
+type VolumeSnapshot struct {
+	item *metadata.Item
+	name *string
+	id   *string
+}
+
+// NewVolumeSnapshot creates an instance of metadata.VolumeSnapshot
+func NewVolumeSnapshot(svc iaas.Service) (_ *VolumeSnapshot, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	aSnap, err := metadata.NewItem(svc, volumeSnapshotsFolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &VolumeSnapshot{
+		item: aSnap,
+		name: nil,
+		id:   nil,
+	}, nil
+}
+
+// Carry links a VolumeSnapshot instance to the Metadata instance
+func (ms *VolumeSnapshot) Carry(snapshot *abstract.VolumeSnapshot) (_ *VolumeSnapshot, err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return nil, fail.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+	if snapshot == nil {
+		return nil, fail.InvalidParameterError("snapshot", "cannot be nil!")
+	}
+	ms.item.Carry(snapshot)
+	ms.name = &snapshot.Name
+	ms.id = &snapshot.ID
+	return ms, nil
+}
+
+// Get returns the VolumeSnapshot instance linked to metadata
+func (ms *VolumeSnapshot) Get() (_ *abstract.VolumeSnapshot, err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return nil, fail.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+	if snapshot, ok := ms.item.Get().(*abstract.VolumeSnapshot); ok {
+		return snapshot, nil
+	}
+	return nil, fail.InconsistentError("invalid content in snapshot metadata")
+}
+
+// Write updates the metadata corresponding to the snapshot in the Object Storage
+func (ms *VolumeSnapshot) Write() (err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return fail.InvalidInstanceContentError("ms.item", "cannot be nil!")
+	}
+
+	err = ms.item.WriteInto(ByIDFolderName, *ms.id)
+	if err != nil {
+		return err
+	}
+	return ms.item.WriteInto(ByNameFolderName, *ms.name)
+}
+
+// Reload reloads the content of the Object Storage, overriding what is in the metadata instance
+func (ms *VolumeSnapshot) Reload() (err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return fail.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+	err = ms.ReadByID(*ms.id)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return fail.NotFoundError(fmt.Sprintf("metadata of snapshot '%s' vanished", *ms.name))
+		}
+		return err
+	}
+	return nil
+}
+
+// ReadByReference tries to read with 'ref' as id, then if not found as name
+func (ms *VolumeSnapshot) ReadByReference(ref string) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return fail.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+	if ref == "" {
+		return fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "('"+ref+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogErrorWithLevel(tracer.TraceMessage(""), &err, logrus.TraceLevel)()
+
+	var errors []error
+	err1 := ms.mayReadByID(ref) // First read by ID ...
+	if err1 != nil {
+		errors = append(errors, err1)
+	}
+
+	err2 := ms.mayReadByName(ref) // ... then read by name only if by id failed (no need to read twice if the 2 exist)
+	if err2 != nil {
+		errors = append(errors, err2)
+	}
+
+	if len(errors) == 2 {
+		if err1 == stow.ErrNotFound && err2 == stow.ErrNotFound { // FIXME: Remove stow dependency
+			return fail.NotFoundErrorWithCause(fmt.Sprintf("reference %s not found", ref), fail.ErrListError(errors))
+		}
+
+		if _, ok := err1.(fail.ErrNotFound); ok {
+			if _, ok := err2.(fail.ErrNotFound); ok {
+				return fail.NotFoundErrorWithCause(
+					fmt.Sprintf("reference %s not found", ref), fail.ErrListError(errors),
+				)
+			}
+		}
+
+		return fail.ErrListError(errors)
+	}
+
+	return nil
+}
+
+// mayReadByID reads the metadata of a snapshot identified by ID from Object Storage
+// Doesn't log error or validate parameters by design; caller does that
+func (ms *VolumeSnapshot) mayReadByID(id string) error {
+	snapshot := abstract.NewVolumeSnapshot()
+	err := ms.item.ReadFrom(
+		ByIDFolderName, id, func(buf []byte) (serialize.Serializable, error) {
+			err := snapshot.Deserialize(buf)
+			if err != nil {
+				return nil, err
+			}
+			return snapshot, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = ms.Carry(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// mayReadByName reads the metadata of a snapshot identified by name
+// Doesn't log error or validate parameters by design; caller does that
+func (ms *VolumeSnapshot) mayReadByName(name string) error {
+	snapshot := abstract.NewVolumeSnapshot()
+	err := ms.item.ReadFrom(
+		ByNameFolderName, name, func(buf []byte) (serialize.Serializable, error) {
+			err := snapshot.Deserialize(buf)
+			if err != nil {
+				return nil, err
+			}
+			return snapshot, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = ms.Carry(snapshot)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReadByID reads the metadata of a snapshot identified by ID from Object Storage
+func (ms *VolumeSnapshot) ReadByID(id string) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return fail.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "("+id+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return ms.mayReadByID(id)
+}
+
+// ReadByName reads the metadata of a snapshot identified by name
+func (ms *VolumeSnapshot) ReadByName(name string) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return fail.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "('"+name+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return ms.mayReadByName(name)
+}
+
+// Delete delete the metadata corresponding to the snapshot
+func (ms *VolumeSnapshot) Delete() (err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return fail.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	err = ms.item.DeleteFrom(ByIDFolderName, *ms.id)
+	if err != nil {
+		return err
+	}
+	err = ms.item.DeleteFrom(ByNameFolderName, *ms.name)
+	if err != nil {
+		return err
+	}
+	ms.item.Reset()
+	ms.name = nil
+	ms.id = nil
+	return nil
+}
+
+// Browse walks through snapshot folder and executes a callback for each entries
+func (ms *VolumeSnapshot) Browse(callback func(*abstract.VolumeSnapshot) error) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if ms == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return fail.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return ms.item.BrowseInto(
+		ByIDFolderName, func(buf []byte) error {
+			snapshot := abstract.NewVolumeSnapshot()
+			err := snapshot.Deserialize(buf)
+			if err != nil {
+				return err
+			}
+			return callback(snapshot)
+		},
+	)
+}
+
+// SaveVolumeSnapshot saves the VolumeSnapshot definition in Object Storage
+func SaveVolumeSnapshot(svc iaas.Service, snapshot *abstract.VolumeSnapshot) (ms *VolumeSnapshot, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if snapshot == nil {
+		return nil, fail.InvalidParameterError("snapshot", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(nil, "("+snapshot.Name+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ms, err = NewVolumeSnapshot(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	vo, err := ms.Carry(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	err = vo.Write()
+	if err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
+
+// RemoveVolumeSnapshot removes the VolumeSnapshot definition from Object Storage
+func RemoveVolumeSnapshot(svc iaas.Service, snapshotID string) (err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if snapshotID == "" {
+		return fail.InvalidParameterError("snapshotID", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "("+snapshotID+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	m, err := LoadVolumeSnapshot(svc, snapshotID)
+	if err != nil {
+		return err
+	}
+	return m.Delete()
+}
+
+// LoadVolumeSnapshot gets the VolumeSnapshot definition from Object Storage
+// logic: Read by ID; if error is ErrNotFound then read by name; if error is ErrNotFound return this error
+//        In case of any other error, abort the retry to propagate the error
+//        If retry times out, return errNotFound
+func LoadVolumeSnapshot(svc iaas.Service, ref string) (ms *VolumeSnapshot, err error) {
+	defer fail.OnPanic(&err)()
+
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil")
+	}
+	if ref == "" {
+		return nil, fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, "("+ref+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer fail.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ms, err = NewVolumeSnapshot(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	retryErr := retry.WhileUnsuccessfulDelay1Second(
+		func() error {
+			innerErr := ms.ReadByReference(ref)
+			if innerErr != nil {
+				if _, ok := innerErr.(fail.ErrNotFound); ok {
+					return retry.AbortedError("no metadata found", innerErr)
+				}
+
+				if innerErr == stow.ErrNotFound { // FIXME: Remove stow dependency
+					return retry.AbortedError("no metadata found", innerErr)
+				}
+
+				return innerErr
+			}
+			return nil
+		},
+		2*temporal.GetDefaultDelay(),
+	)
+	if retryErr != nil {
+		switch err := retryErr.(type) {
+		case retry.ErrAborted:
+			return nil, err.Cause()
+		case fail.ErrTimeout:
+			return nil, err
+		default:
+			return nil, fail.Cause(err)
+		}
+	}
+
+	return ms, nil
+}
@@ -30,4 +30,5 @@ import (
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/opentelekom"    // Imported to initialise tenants
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/outscale"       // Imported to initialise tenants
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/ovh"            // Imported to initialise tenants
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/simulator"      // Imported to initialise tenants
 )
@@ -18,13 +18,20 @@ package utils
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"os/user"
 	"sync"
 	"time"
 
 	uuid "github.com/satori/go.uuid"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 )
 
+// actorKey is the key of the gRPC metadata containing the user of the client and its host
+const actorKey = "actor"
+
 var (
 	clientRPCUUID       uuid.UUID
 	uuidSet             bool
@@ -40,10 +47,20 @@ func GetContext(storeUUID bool) (context.Context, error) {
 	if err != nil {
 		return nil, err
 	}
-	clientContext = metadata.AppendToOutgoingContext(clientContext, "UUID", aUUID)
+	clientContext = metadata.AppendToOutgoingContext(clientContext, "UUID", aUUID, actorKey, currentActor())
 	return clientContext, nil
 }
 
+// currentActor returns '<user>@<hostname>' for the current process
+func currentActor() string {
+	hostname, _ := os.Hostname()
+	username := "unknown"
+	if curUser, err := user.Current(); err == nil {
+		username = curUser.Username
+	}
+	return username + "@" + hostname
+}
+
 // GetTimeoutContext return a context for grpc commands
 func GetTimeoutContext(timeout time.Duration) (context.Context, context.CancelFunc, error) {
 	// Contact the server and print out its response.
@@ -78,3 +95,31 @@ func generateUUID(store bool) (string, error) {
 	}
 	return newUUID.String(), nil
 }
+
+// --------------------- SERVER ---------------------------------
+
+// GetActor returns who requested the operation running in ctx: the user and host declared by the client, followed by
+// the address of the client; operations not requested by a client (periodic tasks of the daemon) are done by
+// 'safescaled:<user>@<hostname>'
+func GetActor(ctx context.Context) string {
+	if ctx == nil {
+		return "safescaled:" + currentActor()
+	}
+	actor := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(actorKey); len(values) > 0 {
+			actor = values[0]
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		if actor == "" {
+			return "safescaled:" + currentActor()
+		}
+		return actor
+	}
+	if actor == "" {
+		actor = "unknown"
+	}
+	return fmt.Sprintf("%s (%s)", actor, p.Addr.String())
+}
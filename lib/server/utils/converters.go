@@ -17,13 +17,17 @@
 package utils
 
 import (
-	"math"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/networkproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/securitygroupruledirection"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/abstract/enums/volumeproperty"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/abstract/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/system"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
@@ -81,13 +85,28 @@ func ToPBVolume(in *abstract.Volume) (*pb.Volume, error) {
 		return nil, fail.InvalidParameterError("in", "cannot be nil")
 	}
 	return &pb.Volume{
-		Id:    in.ID,
-		Name:  in.Name,
-		Size:  int32(in.Size),
-		Speed: pb.VolumeSpeed(in.Speed),
+		Id:     in.ID,
+		Name:   in.Name,
+		Size:   int32(in.Size),
+		Speed:  pb.VolumeSpeed(in.Speed),
+		Labels: volumeLabels(in),
 	}, nil
 }
 
+// volumeLabels returns the user-defined labels of a volume, if any
+func volumeLabels(in *abstract.Volume) (labels map[string]string) {
+	if in.Properties == nil {
+		return nil
+	}
+	_ = in.Properties.LockForRead(volumeproperty.LabelsV1).ThenUse(
+		func(clonable data.Clonable) error {
+			labels = clonable.(*propsv1.VolumeLabels).Labels
+			return nil
+		},
+	)
+	return labels
+}
+
 // ToPBVolumeAttachment converts an api.Volume to a *Volume
 func ToPBVolumeAttachment(in *abstract.VolumeAttachment) (*pb.VolumeAttachment, error) {
 	if in == nil {
@@ -101,6 +120,51 @@ func ToPBVolumeAttachment(in *abstract.VolumeAttachment) (*pb.VolumeAttachment,
 	}, nil
 }
 
+// ToPBTenantQuotas converts quotas and usage of a tenant to a *pb.TenantQuotas
+func ToPBTenantQuotas(name string, quotas *abstract.Quotas, usage *abstract.Usage) *pb.TenantQuotas {
+	return &pb.TenantQuotas{
+		Name: name,
+		Quotas: &pb.TenantResources{
+			Cores:      int32(quotas.Cores),
+			RamSize:    quotas.RAMSize,
+			Instances:  int32(quotas.Instances),
+			Volumes:    int32(quotas.Volumes),
+			VolumeSize: int32(quotas.VolumeSize),
+			Networks:   int32(quotas.Networks),
+			PublicIps:  int32(quotas.PublicIPs),
+		},
+		Usage: &pb.TenantResources{
+			Cores:      int32(usage.Cores),
+			RamSize:    usage.RAMSize,
+			Instances:  int32(usage.Instances),
+			Volumes:    int32(usage.Volumes),
+			VolumeSize: int32(usage.VolumeSize),
+			Networks:   int32(usage.Networks),
+			PublicIps:  int32(usage.PublicIPs),
+		},
+	}
+}
+
+// ToPBCostEstimate converts an abstract.CostEstimate to a *pb.CostEstimate
+func ToPBCostEstimate(in *abstract.CostEstimate) *pb.CostEstimate {
+	out := &pb.CostEstimate{
+		HourlyCost:  in.HourlyCost(),
+		MonthlyCost: in.MonthlyCost(),
+	}
+	for _, i := range in.Items {
+		out.Items = append(
+			out.Items, &pb.CostItem{
+				Role:         i.Role,
+				Count:        int32(i.Count),
+				TemplateId:   i.TemplateID,
+				TemplateName: i.TemplateName,
+				PricePerHour: i.PricePerHour,
+			},
+		)
+	}
+	return out
+}
+
 // ToPBVolumeInfo converts an api.Volume to a *VolumeInfo
 func ToPBVolumeInfo(volume *abstract.Volume, mounts map[string]*propsv1.HostLocalMount) (*pb.VolumeInfo, error) {
 	if volume == nil {
@@ -108,10 +172,11 @@ func ToPBVolumeInfo(volume *abstract.Volume, mounts map[string]*propsv1.HostLoca
 	}
 
 	pbvi := &pb.VolumeInfo{
-		Id:    volume.ID,
-		Name:  volume.Name,
-		Size:  int32(volume.Size),
-		Speed: pb.VolumeSpeed(volume.Speed),
+		Id:     volume.ID,
+		Name:   volume.Name,
+		Size:   int32(volume.Size),
+		Speed:  pb.VolumeSpeed(volume.Speed),
+		Labels: volumeLabels(volume),
 	}
 	if len(mounts) > 0 {
 		for k, mount := range mounts {
@@ -126,6 +191,25 @@ func ToPBVolumeInfo(volume *abstract.Volume, mounts map[string]*propsv1.HostLoca
 	return pbvi, nil
 }
 
+// ToPBVolumeSnapshot converts an abstract.VolumeSnapshot to a *VolumeSnapshot
+func ToPBVolumeSnapshot(in *abstract.VolumeSnapshot) (*pb.VolumeSnapshot, error) {
+	if in == nil {
+		return nil, fail.InvalidParameterError("in", "cannot be nil")
+	}
+	out := &pb.VolumeSnapshot{
+		Id:          in.ID,
+		Name:        in.Name,
+		Description: in.Description,
+		VolumeId:    in.VolumeID,
+		Size:        int32(in.Size),
+		State:       in.State.String(),
+	}
+	if !in.CreatedAt.IsZero() {
+		out.CreatedAt = in.CreatedAt.Format(time.RFC3339)
+	}
+	return out, nil
+}
+
 // ToPBBucketList convert a list of string into a *ContainerLsit
 func ToPBBucketList(in []string) (*pb.BucketList, error) {
 	var buckets []*pb.Bucket
@@ -143,12 +227,157 @@ func ToPBBucketMountPoint(in *abstract.Bucket) (*pb.BucketMountingPoint, error)
 		return nil, fail.InvalidParameterError("in", "cannot be nil")
 	}
 	return &pb.BucketMountingPoint{
-		Bucket: in.Name,
-		Path:   in.MountPoint,
-		Host:   &pb.Reference{Name: in.Host},
+		Bucket:    in.Name,
+		Path:      in.MountPoint,
+		Host:      &pb.Reference{Name: in.Host},
+		Encrypted: in.Encrypted,
+		KeyId:     in.KeyID,
 	}, nil
 }
 
+// ToPBBucketSyncReport converts the report of a bucket synchronization to protocolbuffer format
+func ToPBBucketSyncReport(in *abstract.BucketSyncReport) *pb.BucketSyncReport {
+	return &pb.BucketSyncReport{
+		Source:      in.Source,
+		Destination: in.Destination,
+		Copied:      int32(in.Copied),
+		Skipped:     int32(in.Skipped),
+		Deleted:     int32(in.Deleted),
+		Bytes:       in.Bytes,
+	}
+}
+
+// ToPBBucketLifecycle converts the lifecycle rules of a bucket to protocolbuffer format
+func ToPBBucketLifecycle(in *abstract.BucketLifecycle) *pb.BucketLifecycle {
+	out := &pb.BucketLifecycle{Bucket: in.Bucket}
+	if !in.LastEnforcement.IsZero() {
+		out.LastEnforcement = in.LastEnforcement.Format(time.RFC3339)
+	}
+	for _, r := range in.Rules {
+		out.Rules = append(
+			out.Rules, &pb.BucketLifecycleRule{
+				Prefix:          r.Prefix,
+				ExpireAfterDays: int32(r.ExpireAfterDays),
+				KeepLast:        int32(r.KeepLast),
+			},
+		)
+	}
+	return out
+}
+
+// FromPBBucketLifecycleRules converts the lifecycle rules of a bucket from protocolbuffer format
+func FromPBBucketLifecycleRules(in []*pb.BucketLifecycleRule) []abstract.BucketLifecycleRule {
+	var out []abstract.BucketLifecycleRule
+	for _, r := range in {
+		out = append(
+			out, abstract.BucketLifecycleRule{
+				Prefix:          r.GetPrefix(),
+				ExpireAfterDays: int(r.GetExpireAfterDays()),
+				KeepLast:        int(r.GetKeepLast()),
+			},
+		)
+	}
+	return out
+}
+
+// ToPBMetadataMigrationReport converts the report of a migration of metadata to protocolbuffer format
+func ToPBMetadataMigrationReport(in *abstract.MetadataMigrationReport) *pb.MetadataMigrationReport {
+	out := &pb.MetadataMigrationReport{
+		DryRun:  in.DryRun,
+		Scanned: int32(in.Scanned),
+	}
+	for _, mo := range in.Migrated {
+		out.Migrated = append(out.Migrated, &pb.MigratedMetadata{Path: mo.Path, Migrations: mo.Migrations})
+	}
+	return out
+}
+
+// ToPBMetadataArchiveReport converts the report of an export, an import or a backup of metadata to protocolbuffer format
+func ToPBMetadataArchiveReport(in *abstract.MetadataArchiveReport) *pb.MetadataArchiveReport {
+	return &pb.MetadataArchiveReport{
+		Path:    in.Path,
+		Bucket:  in.Bucket,
+		Date:    in.Date.Format(time.RFC3339),
+		Folders: in.Folders,
+		Objects: int32(in.Objects),
+	}
+}
+
+// ToPBMetadataRekeyReport converts the report of a re-encryption of metadata to protocolbuffer format
+func ToPBMetadataRekeyReport(in *abstract.MetadataRekeyReport) *pb.MetadataRekeyReport {
+	return &pb.MetadataRekeyReport{
+		KeyId:   in.KeyID,
+		Scanned: int32(in.Scanned),
+		Rekeyed: int32(in.Rekeyed),
+		Skipped: int32(in.Skipped),
+	}
+}
+
+// ToPBFsckReport converts the report of a consistency check of metadata to protocolbuffer format
+func ToPBFsckReport(in *abstract.FsckReport) *pb.FsckReport {
+	out := &pb.FsckReport{Repair: in.Repair}
+	for _, i := range in.Issues {
+		out.Issues = append(
+			out.Issues, &pb.FsckIssue{
+				Kind:     i.Kind,
+				Resource: i.Resource,
+				Id:       i.ID,
+				Name:     i.Name,
+				Message:  i.Message,
+				Repaired: i.Repaired,
+			},
+		)
+	}
+	return out
+}
+
+// ToPBFile converts the manifest of a file stored by the data service to protocolbuffer format
+func ToPBFile(in *abstract.DataFile) *pb.File {
+	var buckets []string
+	for _, b := range in.Buckets {
+		buckets = append(buckets, b.String())
+	}
+	return &pb.File{
+		Name:         in.Name,
+		Date:         in.Date.Format(time.RFC3339),
+		Size:         in.Size,
+		Buckets:      buckets,
+		DataShards:   int32(in.Redundancy.DataShards),
+		ParityShards: int32(in.Redundancy.ParityShards),
+		Replicas:     int32(in.Redundancy.Replicas),
+	}
+}
+
+// ToPBFileList converts a list of manifests of files stored by the data service to protocolbuffer format
+func ToPBFileList(in []*abstract.DataFile) *pb.FileList {
+	var files []*pb.File
+	for _, df := range in {
+		files = append(files, ToPBFile(df))
+	}
+	return &pb.FileList{Files: files}
+}
+
+// ToPBAuditRecordList converts records of the audit log to protocolbuffer format
+func ToPBAuditRecordList(in []*abstract.AuditRecord) *pb.AuditRecordList {
+	var records []*pb.AuditRecord
+	for _, r := range in {
+		records = append(
+			records, &pb.AuditRecord{
+				Date:       r.Date.Format(time.RFC3339),
+				Actor:      r.Actor,
+				Tenant:     r.Tenant,
+				Operation:  r.Operation,
+				Target:     r.Target,
+				Parameters: r.Parameters,
+				Outcome:    r.Outcome,
+				Error:      r.Error,
+				Duration:   r.Duration.Milliseconds(),
+			},
+		)
+	}
+	return &pb.AuditRecordList{Records: records}
+}
+
 // ToPBShare convert a share from model to protocolbuffer format
 func ToPBShare(hostName string, share *propsv1.HostShare) (*pb.ShareDefinition, error) {
 	if share == nil {
@@ -212,6 +441,7 @@ func ToPBHost(in *abstract.Host) (*pb.Host, error) {
 		hostNetworkV1 *propsv1.HostNetwork
 		hostSizingV1  *propsv1.HostSizing
 		hostVolumesV1 *propsv1.HostVolumes
+		hostLabelsV1  *propsv1.HostLabels
 		volumes       []string
 	)
 
@@ -231,7 +461,12 @@ func ToPBHost(in *abstract.Host) (*pb.Host, error) {
 							for k := range hostVolumesV1.VolumesByName {
 								volumes = append(volumes, k)
 							}
-							return nil
+							return in.Properties.LockForRead(hostproperty.LabelsV1).ThenUse(
+								func(clonable data.Clonable) error {
+									hostLabelsV1 = clonable.(*propsv1.HostLabels)
+									return nil
+								},
+							)
 						},
 					)
 				},
@@ -254,6 +489,7 @@ func ToPBHost(in *abstract.Host) (*pb.Host, error) {
 		Ram:                 hostSizingV1.AllocatedSize.RAMSize,
 		State:               pb.HostState(in.LastState),
 		AttachedVolumeNames: volumes,
+		Labels:              hostLabelsV1.Labels,
 	}, nil
 }
 
@@ -364,6 +600,18 @@ func ToPBNetwork(in *abstract.Network) (*pb.Network, error) {
 	if in.VIP != nil {
 		pbVIP = ToPBVirtualIP(*in.VIP)
 	}
+	var labels map[string]string
+	if in.Properties != nil {
+		err := in.Properties.LockForRead(networkproperty.LabelsV1).ThenUse(
+			func(clonable data.Clonable) error {
+				labels = clonable.(*propsv1.NetworkLabels).Labels
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &pb.Network{
 		Id:                 in.ID,
 		Name:               in.Name,
@@ -372,27 +620,10 @@ func ToPBNetwork(in *abstract.Network) (*pb.Network, error) {
 		SecondaryGatewayId: in.SecondaryGatewayID,
 		VirtualIp:          pbVIP,
 		Failover:           in.SecondaryGatewayID != "",
+		Labels:             labels,
 	}, nil
 }
 
-// ToPBFileList convert a list of file names from api to protocolbuffer FileList format
-func ToPBFileList(fileNames []string, uploadDates []string, fileSizes []int64, fileBuckets [][]string) *pb.FileList {
-	var files []*pb.File
-	nbFiles := int(
-		math.Min(
-			math.Min(
-				math.Min(float64(len(fileNames)), float64(len(uploadDates))), float64(len(fileSizes)),
-			), float64(len(fileBuckets)),
-		),
-	)
-	for i := 0; i < nbFiles; i++ {
-		files = append(
-			files, &pb.File{Name: fileNames[i], Date: uploadDates[i], Size: fileSizes[i], Buckets: fileBuckets[i]},
-		)
-	}
-	return &pb.FileList{Files: files}
-}
-
 // ToPBHostSizing converts a protobuf HostSizing message to abstract.SizingRequirements
 func ToPBHostSizing(src abstract.SizingRequirements) *pb.HostSizing {
 	return &pb.HostSizing{
@@ -435,6 +666,67 @@ func ToPBVirtualIP(src abstract.VirtualIP) *pb.VirtualIp {
 	return dest
 }
 
+// ToPBSecurityGroupRule converts an abstract.SecurityGroupRule to a pb.SecurityGroupRule
+func ToPBSecurityGroupRule(src abstract.SecurityGroupRule) *pb.SecurityGroupRule {
+	dest := &pb.SecurityGroupRule{
+		Id:          src.ID,
+		Description: src.Description,
+		EtherType:   int32(src.EtherType),
+		Protocol:    src.Protocol,
+		PortFrom:    int32(src.PortFrom),
+		PortTo:      int32(src.PortTo),
+		Cidr:        src.CIDR,
+	}
+	switch src.Direction {
+	case securitygroupruledirection.INGRESS:
+		dest.Direction = "ingress"
+	case securitygroupruledirection.EGRESS:
+		dest.Direction = "egress"
+	}
+	return dest
+}
+
+// FromPBSecurityGroupRule converts a pb.SecurityGroupRule to an abstract.SecurityGroupRule
+func FromPBSecurityGroupRule(src *pb.SecurityGroupRule) (abstract.SecurityGroupRule, error) {
+	if src == nil {
+		return abstract.SecurityGroupRule{}, fail.InvalidParameterError("src", "cannot be nil")
+	}
+	direction, err := securitygroupruledirection.Parse(src.Direction)
+	if err != nil {
+		return abstract.SecurityGroupRule{}, fail.InvalidParameterError("src.Direction", err.Error())
+	}
+	dest := abstract.SecurityGroupRule{
+		ID:          src.Id,
+		Description: src.Description,
+		Direction:   direction,
+		EtherType:   ipversion.IPv4,
+		Protocol:    src.Protocol,
+		PortFrom:    int(src.PortFrom),
+		PortTo:      int(src.PortTo),
+		CIDR:        src.Cidr,
+	}
+	if src.EtherType == int32(ipversion.IPv6) {
+		dest.EtherType = ipversion.IPv6
+	}
+	return dest, nil
+}
+
+// ToPBSecurityGroup converts an abstract.SecurityGroup to a pb.SecurityGroup
+func ToPBSecurityGroup(in *abstract.SecurityGroup) (*pb.SecurityGroup, error) {
+	if in == nil {
+		return nil, fail.InvalidParameterError("in", "cannot be nil")
+	}
+	dest := &pb.SecurityGroup{
+		Id:          in.ID,
+		Name:        in.Name,
+		Description: in.Description,
+	}
+	for _, r := range in.Rules {
+		dest.Rules = append(dest.Rules, ToPBSecurityGroupRule(r))
+	}
+	return dest, nil
+}
+
 // ClonePBHostSizing ...
 func ClonePBHostSizing(in *pb.HostSizing) *pb.HostSizing {
 	if in == nil {
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// keyringMagic starts the header of the data sealed by a Keyring, followed by the ID of the key (16 hexadecimal digits)
+const keyringMagic = "SSKEY1:"
+
+// keyringHeaderLen is the length of the header of the data sealed by a Keyring
+const keyringHeaderLen = len(keyringMagic) + 16
+
+// KeyID returns an identifier of the key, which does not reveal the key
+func KeyID(key *Key) string {
+	sum := sha256.Sum256(key[:])
+	return hex.EncodeToString(sum[:8])
+}
+
+// Keyring keeps the key encrypting data and the previous keys, still accepted to decrypt data during a key rotation
+// The data sealed by a Keyring start with a header containing the ID of the key (its generation); data without
+// header (encrypted before the introduction of the header) are decrypted with each key, starting with the current one.
+type Keyring struct {
+	lock    sync.RWMutex
+	current *Key
+	keys    map[string]*Key
+	order   []string // IDs of the keys, the current one first
+}
+
+// NewKeyring creates a Keyring encrypting with 'current' and accepting also 'previous' to decrypt
+func NewKeyring(current *Key, previous ...*Key) *Keyring {
+	k := &Keyring{keys: map[string]*Key{}}
+	for i := len(previous) - 1; i >= 0; i-- {
+		if previous[i] != nil {
+			k.add(previous[i])
+		}
+	}
+	k.add(current)
+	return k
+}
+
+// add makes 'key' the current key, keeping the former one to decrypt
+func (k *Keyring) add(key *Key) {
+	id := KeyID(key)
+	order := []string{id}
+	for _, v := range k.order {
+		if v != id {
+			order = append(order, v)
+		}
+	}
+	k.order = order
+	k.keys[id] = key
+	k.current = key
+}
+
+// Rotate makes 'key' the current key; the former keys are still accepted to decrypt
+func (k *Keyring) Rotate(key *Key) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	k.add(key)
+}
+
+// Current returns the key encrypting data
+func (k *Keyring) Current() *Key {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	return k.current
+}
+
+// CurrentID returns the ID of the key encrypting data
+func (k *Keyring) CurrentID() string {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	return k.order[0]
+}
+
+// Seal encrypts data with the current key, prefixed with the header containing the ID of the key
+func (k *Keyring) Seal(plaintext []byte) ([]byte, error) {
+	k.lock.RLock()
+	current, id := k.current, k.order[0]
+	k.lock.RUnlock()
+
+	ciphertext, err := Encrypt(plaintext, current)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(keyringMagic+id), ciphertext...), nil
+}
+
+// Open decrypts data sealed by Seal, with the key identified by the header, or data without header, with the first
+// key that succeeds
+func (k *Keyring) Open(data []byte) ([]byte, error) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	if id := Generation(data); id != "" {
+		key, ok := k.keys[id]
+		if !ok {
+			return nil, fmt.Errorf("data encrypted with unknown key '%s'", id)
+		}
+		return Decrypt(data[keyringHeaderLen:], key)
+	}
+
+	var err error
+	for _, id := range k.order {
+		var plaintext []byte
+		plaintext, err = Decrypt(data, k.keys[id])
+		if err == nil {
+			return plaintext, nil
+		}
+	}
+	return nil, err
+}
+
+// Generation returns the ID of the key which sealed data, or an empty string if data has no header
+func Generation(data []byte) string {
+	if len(data) < keyringHeaderLen || !bytes.HasPrefix(data, []byte(keyringMagic)) {
+		return ""
+	}
+	id := string(data[len(keyringMagic):keyringHeaderLen])
+	if _, err := hex.DecodeString(id); err != nil {
+		return ""
+	}
+	return id
+}
@@ -17,12 +17,12 @@
 package metadata
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/metadatastore"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
 	"github.com/CS-SI/SafeScale/lib/utils/crypt"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -31,10 +31,10 @@ import (
 // Folder describes a metadata folder
 type Folder struct {
 	// path contains the base path where to read/write record in Object Storage
-	path     string
-	service  iaas.Service
-	crypt    bool
-	cryptKey *crypt.Key
+	path    string
+	service iaas.Service
+	crypt   bool
+	keyring *crypt.Keyring
 }
 
 // FolderDecoderCallback is the prototype of the function that will decode data read from Metadata
@@ -45,15 +45,12 @@ func NewFolder(svc iaas.Service, path string) (*Folder, error) {
 	if svc == nil {
 		return nil, fail.InvalidParameterError("svc", "cannot be nil!")
 	}
-	cryptKey := svc.GetMetadataKey()
-	crypto := cryptKey != nil && len(cryptKey) > 0
+	keyring := svc.GetMetadataKeyring()
 	f := &Folder{
 		path:    strings.Trim(path, "/"),
 		service: svc,
-		crypt:   crypto,
-	}
-	if crypto {
-		f.cryptKey = cryptKey
+		crypt:   keyring != nil,
+		keyring: keyring,
 	}
 	return f, nil
 }
@@ -63,11 +60,16 @@ func (f *Folder) GetService() iaas.Service {
 	return f.service
 }
 
-// GetBucket returns the bucket used by the folder to store Object Storage
+// GetBucket returns the bucket used by the folder to store Object Storage (nil if metadata are not stored in a bucket)
 func (f *Folder) GetBucket() objectstorage.Bucket {
 	return f.service.GetMetadataBucket()
 }
 
+// GetStore returns the storage of the metadata used by the folder
+func (f *Folder) GetStore() metadatastore.Store {
+	return f.service.GetMetadataStore()
+}
+
 // GetPath returns the base path of the folder
 func (f *Folder) GetPath() string {
 	return f.path
@@ -89,30 +91,20 @@ func (f *Folder) absolutePath(path ...string) string {
 
 // Search tells if the object named 'name' is inside the ObjectStorage folder
 func (f *Folder) Search(path string, name string) error {
-	absPath := strings.Trim(f.absolutePath(path), "/")
-	list, err := f.service.GetMetadataBucket().List(absPath, objectstorage.NoPrefix)
+	fullPath := strings.Trim(f.absolutePath(path, name), "/")
+	found, err := f.GetStore().Exists(fullPath)
 	if err != nil {
 		return err
 	}
-	if absPath != "" {
-		absPath += "/"
-	}
-	fullPath := absPath + name
-	for _, item := range list {
-		if item == fullPath {
-			return nil
-		}
+	if !found {
+		return fail.NotFoundError(fmt.Sprintf("failed to find '%s'", fullPath))
 	}
-	return fail.NotFoundError(fmt.Sprintf("failed to find '%s'", fullPath))
+	return nil
 }
 
 // Delete removes metadata passed as parameter
 func (f *Folder) Delete(path string, name string) error {
-	err := f.service.GetMetadataBucket().DeleteObject(f.absolutePath(path, name))
-	if err != nil {
-		return fmt.Errorf("failed to remove metadata in Object Storage: %s", err.Error())
-	}
-	return nil
+	return f.GetStore().Delete(f.absolutePath(path, name))
 }
 
 // Read loads the content of the object stored in metadata bucket
@@ -121,26 +113,12 @@ func (f *Folder) Delete(path string, name string) error {
 // returns true, nil if the object has been found
 // The callback function has to know how to decode it and where to store the result
 func (f *Folder) Read(path string, name string, callback FolderDecoderCallback) error {
-	err := f.Search(path, name)
+	data, err := f.GetStore().Read(f.absolutePath(path, name))
 	if err != nil {
-		if _, ok := err.(fail.ErrNotFound); ok {
-			return err
-		}
-
 		return err
 	}
-
-	var buffer bytes.Buffer
-	_, err = f.service.GetMetadataBucket().ReadObject(f.absolutePath(path, name), &buffer, 0, 0)
-	if err != nil {
-		if _, ok := err.(fail.ErrNotFound); ok {
-			return fail.NotFoundError(fmt.Sprintf("failed to read '%s/%s' in Metadata Storage: %v", path, name, err))
-		}
-		return err
-	}
-	data := buffer.Bytes()
 	if f.crypt {
-		data, err = crypt.Decrypt(data, f.cryptKey)
+		data, err = f.keyring.Open(data)
 		if err != nil {
 			if _, ok := err.(fail.ErrNotFound); ok {
 				return fail.NotFoundError(fmt.Sprintf("failed to decrypt metadata '%s/%s': %v", path, name, err))
@@ -166,7 +144,7 @@ func (f *Folder) Write(path string, name string, content []byte) error {
 	)
 
 	if f.crypt {
-		data, err = crypt.Encrypt(content, f.cryptKey)
+		data, err = f.keyring.Seal(content)
 		if err != nil {
 			return err
 		}
@@ -174,14 +152,38 @@ func (f *Folder) Write(path string, name string, content []byte) error {
 		data = content
 	}
 
-	source := bytes.NewBuffer(data)
-	_, err = f.service.GetMetadataBucket().WriteObject(f.absolutePath(path, name), source, int64(source.Len()), nil)
-	return err
+	return f.GetStore().Write(f.absolutePath(path, name), data)
+}
+
+// Update replaces the content in Object Storage by the content returned by 'callback', called with the current
+// content (nil if there is none); nothing is written if callback returns nil content.
+// The update is transactional if the backend of the metadata allows it (etcd, bbolt).
+func (f *Folder) Update(path string, name string, callback func([]byte) ([]byte, error)) error {
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil!")
+	}
+
+	return f.GetStore().Update(
+		f.absolutePath(path, name), func(data []byte) ([]byte, error) {
+			var err error
+			if f.crypt && data != nil {
+				data, err = f.keyring.Open(data)
+				if err != nil {
+					return nil, err
+				}
+			}
+			content, err := callback(data)
+			if err != nil || content == nil || !f.crypt {
+				return content, err
+			}
+			return f.keyring.Seal(content)
+		},
+	)
 }
 
 // Browse browses the content of a specific path in Metadata and executes 'cb' on each entry
 func (f *Folder) Browse(path string, callback FolderDecoderCallback) error {
-	list, err := f.service.GetMetadataBucket().List(f.absolutePath(path), objectstorage.NoPrefix)
+	list, err := f.GetStore().List(f.absolutePath(path))
 	if err != nil {
 		return fail.Wrap(err, "Error browsing metadata: listing objects")
 	}
@@ -192,16 +194,14 @@ func (f *Folder) Browse(path string, callback FolderDecoderCallback) error {
 	}
 
 	for _, i := range list {
-		var buffer bytes.Buffer
-		_, err = f.service.GetMetadataBucket().ReadObject(i, &buffer, 0, 0)
+		data, err := f.GetStore().Read(i)
 		if err != nil {
 			return fail.Wrap(err, "Error browsing metadata: reading from buffer")
 		}
-		data := buffer.Bytes()
 		if f.crypt {
 			dal := len(data)
 
-			data, err = crypt.Decrypt(data, f.cryptKey)
+			data, err = f.keyring.Open(data)
 			if err != nil {
 				if dal > 0 {
 					return fail.ForbiddenError(fmt.Sprintf("problem decrypting data with the key provided in (tenants.metadata.CryptKey): %s", err))
@@ -234,3 +234,62 @@ func (f *Folder) Browse(path string, callback FolderDecoderCallback) error {
 	}
 	return nil
 }
+
+// Walk browses the content of a specific path in Metadata and executes 'callback' on each entry, with the full name
+// of the entry and its decrypted content
+func (f *Folder) Walk(path string, callback func(string, []byte) error) error {
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil!")
+	}
+
+	list, err := f.GetStore().List(f.absolutePath(path))
+	if err != nil {
+		return fail.Wrap(err, "Error walking metadata: listing objects")
+	}
+
+	for _, i := range list {
+		if i == f.absolutePath(path) {
+			continue
+		}
+		data, err := f.GetStore().Read(i)
+		if err != nil {
+			return fail.Wrap(err, "Error walking metadata: reading from buffer")
+		}
+		if f.crypt {
+			data, err = f.keyring.Open(data)
+			if err != nil {
+				return fail.Wrap(err, fmt.Sprintf("Error walking metadata: decrypting '%s'", i))
+			}
+		}
+
+		err = callback(i, data)
+		if err != nil {
+			return fail.Wrap(err, fmt.Sprintf("Error walking metadata: running callback on '%s'", i))
+		}
+	}
+	return nil
+}
+
+// Rewrite browses the content of a specific path in Metadata and replaces each entry by the content returned by
+// 'callback', called with the full name of the entry; the entry is left untouched if callback returns nil content
+func (f *Folder) Rewrite(path string, callback func(string, []byte) ([]byte, error)) error {
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil!")
+	}
+
+	return f.Walk(
+		path, func(name string, data []byte) error {
+			content, err := callback(name, data)
+			if err != nil || content == nil {
+				return err
+			}
+			if f.crypt {
+				content, err = f.keyring.Seal(content)
+				if err != nil {
+					return err
+				}
+			}
+			return f.GetStore().Write(name, content)
+		},
+	)
+}
@@ -19,10 +19,13 @@ package metadata
 import (
 	"sync"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
 // Item is an entry in the ObjectStorage
@@ -31,6 +34,7 @@ type Item struct {
 	folder  *Folder
 	written bool
 	lock    *sync.Mutex
+	lease   *Lease
 }
 
 // ItemDecoderCallback ...
@@ -193,12 +197,36 @@ func (i *Item) Browse(callback func([]byte) error) error {
 	return i.BrowseInto(".", callback)
 }
 
-// Acquire waits until the lock is available, then locks the metadata
-func (i *Item) Acquire() {
+// Acquire waits until the lock on the metadata object 'name' is available, then locks it
+// The lock is a Lease shared with the other processes using the same metadata bucket; fails with fail.ErrNotAvailable
+// if the lock is held by someone else for too long.
+func (i *Item) Acquire(name string) error {
+	if i == nil {
+		return fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
 	i.lock.Lock()
+	lease, err := AcquireLease(
+		i.GetService(), i.GetPath(), name, temporal.GetMetadataLeaseDuration(), temporal.GetMetadataLeaseTimeout(),
+	)
+	if err != nil {
+		i.lock.Unlock()
+		return err
+	}
+	i.lease = lease
+	return nil
 }
 
 // Release unlocks the metadata
 func (i *Item) Release() {
+	if i.lease != nil {
+		if err := i.lease.Release(); err != nil {
+			logrus.Warnf("failed to release lease on metadata '%s': %v", i.lease.String(), err)
+		}
+		i.lease = nil
+	}
 	i.lock.Unlock()
 }
@@ -0,0 +1,252 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/metadatastore"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// leasesFolderName is the folder of the metadata bucket containing the leases
+	leasesFolderName = "leases"
+)
+
+var (
+	// leaseSettleDelay is the time let to concurrent acquisitions of a lease before reading it back
+	leaseSettleDelay = 100 * time.Millisecond
+	// leasePollInterval is the delay between two attempts to acquire a lease held by someone else
+	leasePollInterval = temporal.SmallDelay
+
+	// leaseOwner identifies the current process in the leases it holds
+	leaseOwner = func() string {
+		hostname, _ := os.Hostname()
+		return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+	}()
+)
+
+// leaseRecord is the content of a lease stored in the metadata bucket
+type leaseRecord struct {
+	Owner  string    `json:"owner"`
+	Token  string    `json:"token"` // identifies the acquisition, as an owner may hold several leases
+	Expiry time.Time `json:"expiry"`
+}
+
+// Lease is a lock on a metadata object shared by all the processes using the same metadata bucket (several
+// safescaled, safescale commands, ...). It is stored in the folder 'leases' of the bucket, renewed in background
+// while held, and expires if its owner disappears without releasing it.
+// The lease is taken with Folder.Update, which is transactional with the etcd and bbolt backends. Object storages
+// don't offer conditional writes: with the bucket backend, the lease is written then read back to detect a concurrent
+// acquisition, which narrows the window of a race between acquisitions without closing it.
+type Lease struct {
+	folder   *Folder
+	path     string
+	name     string
+	duration time.Duration
+
+	lock   sync.Mutex
+	record leaseRecord
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// AcquireLease acquires the lease on the metadata object 'name' in folder 'path', valid for 'duration' and renewed
+// until released; if the lease is held by someone else, waits at most 'timeout' before failing with fail.ErrNotAvailable
+func AcquireLease(svc iaas.Service, path, name string, duration, timeout time.Duration) (*Lease, error) {
+	if svc == nil {
+		return nil, fail.InvalidParameterError("svc", "cannot be nil!")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if duration <= 0 {
+		duration = temporal.GetMetadataLeaseDuration()
+	}
+
+	folder, err := NewFolder(svc, leasesFolderName)
+	if err != nil {
+		return nil, err
+	}
+	token, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	l := &Lease{
+		folder:   folder,
+		path:     strings.Trim(path, "/"),
+		name:     name,
+		duration: duration,
+		record:   leaseRecord{Owner: leaseOwner, Token: token.String()},
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var (
+			current *leaseRecord
+			written bool
+		)
+		err = l.folder.Update(
+			l.path, l.name, func(buf []byte) ([]byte, error) {
+				current, written = nil, false
+				if buf != nil {
+					current = &leaseRecord{}
+					if err := json.Unmarshal(buf, current); err != nil {
+						return nil, err
+					}
+					if time.Now().Before(current.Expiry) {
+						return nil, nil
+					}
+				}
+				l.record.Expiry = time.Now().Add(duration)
+				written = true
+				return json.Marshal(l.record)
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if written {
+			if l.folder.GetStore().GetBackend() == metadatastore.BucketBackend {
+				time.Sleep(leaseSettleDelay)
+			}
+			current, err = l.read()
+			if err != nil {
+				return nil, err
+			}
+			if current != nil && current.Token == l.record.Token {
+				l.stop = make(chan struct{})
+				l.done = make(chan struct{})
+				go l.renew()
+				return l, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			holder := "someone else"
+			if current != nil {
+				holder = fmt.Sprintf("'%s' until %s", current.Owner, current.Expiry.Format(time.RFC3339))
+			}
+			return nil, fail.NotAvailableError(fmt.Sprintf("metadata '%s' is locked by %s", l.String(), holder))
+		}
+		time.Sleep(leasePollInterval)
+	}
+}
+
+// String returns the name of the metadata object locked by the lease
+func (l *Lease) String() string {
+	if l.path == "" {
+		return l.name
+	}
+	return l.path + "/" + l.name
+}
+
+// Release stops the renewal of the lease and removes it, if it's still held
+func (l *Lease) Release() error {
+	if l == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	if l.stop != nil {
+		close(l.stop)
+		<-l.done
+		l.stop = nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	current, err := l.read()
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Token != l.record.Token {
+		return nil
+	}
+	return l.folder.Delete(l.path, l.name)
+}
+
+// renew extends the lease every third of its duration, until released or taken by someone else
+func (l *Lease) renew() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.duration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			lost := false
+			l.lock.Lock()
+			err := l.folder.Update(
+				l.path, l.name, func(buf []byte) ([]byte, error) {
+					current := &leaseRecord{}
+					if buf != nil {
+						if err := json.Unmarshal(buf, current); err != nil {
+							return nil, err
+						}
+					}
+					lost = current.Token != l.record.Token
+					if lost {
+						return nil, nil
+					}
+					l.record.Expiry = time.Now().Add(l.duration)
+					return json.Marshal(l.record)
+				},
+			)
+			l.lock.Unlock()
+			if err != nil {
+				logrus.Warnf("failed to renew lease on metadata '%s': %v", l.String(), err)
+				continue
+			}
+			if lost {
+				logrus.Warnf("lease on metadata '%s' has been lost", l.String())
+				return
+			}
+		}
+	}
+}
+
+// read returns the lease currently stored, or nil if there is none
+func (l *Lease) read() (*leaseRecord, error) {
+	var record *leaseRecord
+	err := l.folder.Read(
+		l.path, l.name, func(buf []byte) error {
+			record = &leaseRecord{}
+			return json.Unmarshal(buf, record)
+		},
+	)
+	if err != nil {
+		if _, ok := err.(fail.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
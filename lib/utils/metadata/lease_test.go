@@ -0,0 +1,73 @@
+package metadata
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/simulator"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func newLeaseTestService(t *testing.T) iaas.Service {
+	folder, err := ioutil.TempDir("", "safescale-lease")
+	require.Nil(t, err)
+	svc, err := iaas.NewServiceFromTenant(
+		map[string]interface{}{
+			"name":     "lease",
+			"client":   "simulator",
+			"identity": map[string]interface{}{},
+			"compute":  map[string]interface{}{"Region": "local"},
+			"objectstorage": map[string]interface{}{
+				"Type":     "local",
+				"Endpoint": folder,
+			},
+		},
+	)
+	require.Nil(t, err)
+	return svc
+}
+
+func TestLease(t *testing.T) {
+	leasePollInterval = 10 * time.Millisecond
+	svc := newLeaseTestService(t)
+
+	first, err := AcquireLease(svc, "hosts", "1", 300*time.Millisecond, 0)
+	require.Nil(t, err)
+	_, err = AcquireLease(svc, "hosts", "1", 300*time.Millisecond, 0)
+	assert.IsType(t, fail.ErrNotAvailable{}, err)
+	other, err := AcquireLease(svc, "hosts", "2", 300*time.Millisecond, 0)
+	require.Nil(t, err)
+	require.Nil(t, other.Release())
+
+	// the lease is renewed while held
+	time.Sleep(600 * time.Millisecond)
+	_, err = AcquireLease(svc, "hosts", "1", 300*time.Millisecond, 100*time.Millisecond)
+	assert.IsType(t, fail.ErrNotAvailable{}, err)
+
+	require.Nil(t, first.Release())
+	second, err := AcquireLease(svc, "hosts", "1", 300*time.Millisecond, 0)
+	require.Nil(t, err)
+	require.Nil(t, second.Release())
+}
+
+func TestLease_Expired(t *testing.T) {
+	svc := newLeaseTestService(t)
+
+	// lease left by an owner which disappeared
+	f, err := NewFolder(svc, leasesFolderName)
+	require.Nil(t, err)
+	buf, err := json.Marshal(leaseRecord{Owner: "gone:1", Token: "token", Expiry: time.Now().Add(-time.Second)})
+	require.Nil(t, err)
+	require.Nil(t, f.Write("networks", "1", buf))
+
+	lease, err := AcquireLease(svc, "networks", "1", time.Second, 0)
+	require.Nil(t, err)
+	assert.Equal(t, "networks/1", lease.String())
+	require.Nil(t, lease.Release())
+}
@@ -235,10 +235,23 @@ func (x *JSONProperties) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON implement json.Unmarshaller
+// The migrations of properties registered for the module are applied, the upgraded properties are saved
+// with the next write of the object.
 // Note: DO NOT LOCK property here, deadlock risk
 func (x *JSONProperties) UnmarshalJSON(b []byte) (xerr error) {
 	defer fail.OnPanic(&xerr)()
 
+	err := x.decode(b)
+	if err != nil {
+		return err
+	}
+	_, err = x.migrate()
+	return err
+}
+
+// decode restores properties from JSON data
+// Note: DO NOT LOCK property here, deadlock risk
+func (x *JSONProperties) decode(b []byte) error {
 	// Decode JSON data
 	unjsoned := map[string]string{}
 	err := FromJSON(b, &unjsoned)
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serialize
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// PropertyMigration describes the upgrade of a property to a newer version of it
+// The property 'From' is kept, so the object stays readable by releases not knowing the property 'To'.
+type PropertyMigration struct {
+	// From is the key of the property to upgrade
+	From string
+	// To is the key of the property replacing it
+	To string
+	// Convert fills 'to', a zeroed value of the property 'To', from 'from', the content of the property 'From'
+	Convert func(from, to data.Clonable) error
+}
+
+// String returns the text describing the migration
+func (pm PropertyMigration) String() string {
+	return pm.From + " > " + pm.To
+}
+
+// propertyMigrationRegistry contains the migrations of properties, by module, in order of registration
+type propertyMigrationRegistry struct {
+	lock       sync.RWMutex
+	migrations map[string][]PropertyMigration
+}
+
+// Register declares a migration of a property of a module
+func (r *propertyMigrationRegistry) Register(module string, migration PropertyMigration) {
+	if module == "" {
+		panic("module is empty!")
+	}
+	if migration.From == "" || migration.To == "" || migration.From == migration.To {
+		panic(fmt.Sprintf("invalid migration '%s' of module '%s'!", migration, module))
+	}
+	if migration.Convert == nil {
+		panic(fmt.Sprintf("missing conversion function of migration '%s' of module '%s'!", migration, module))
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.migrations[module] = append(r.migrations[module], migration)
+}
+
+// Migrations returns the migrations registered for a module
+func (r *propertyMigrationRegistry) Migrations(module string) []PropertyMigration {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return append([]PropertyMigration{}, r.migrations[module]...)
+}
+
+// PropertyMigrationRegistry contains the migrations of properties applied when properties are read
+var PropertyMigrationRegistry = &propertyMigrationRegistry{migrations: map[string][]PropertyMigration{}}
+
+// migrate applies the registered migrations whose source property is present and target property is absent,
+// and returns the migrations applied
+// A migration producing the source of another one (v1 > v2 > v3) is applied first, whatever the order of registration.
+// Note: DO NOT LOCK property here, called from UnmarshalJSON
+func (x *JSONProperties) migrate() ([]PropertyMigration, error) {
+	var applied []PropertyMigration
+	migrations := PropertyMigrationRegistry.Migrations(x.module)
+	for {
+		progress := false
+		for _, m := range migrations {
+			from, ok := x.Properties[m.From]
+			if !ok {
+				continue
+			}
+			if _, ok = x.Properties[m.To]; ok {
+				continue
+			}
+			to := PropertyTypeRegistry.ZeroValue(x.module, m.To)
+			err := m.Convert(from.Data.Clone(), to)
+			if err != nil {
+				return applied, fail.Wrap(err, fmt.Sprintf("failed to migrate property '%s' of module '%s'", m, x.module))
+			}
+			x.Properties[m.To] = &jsonProperty{
+				Data:   to,
+				module: x.module,
+				key:    m.To,
+			}
+			applied = append(applied, m)
+			progress = true
+		}
+		if !progress {
+			return applied, nil
+		}
+	}
+}
+
+// MigrateJSON applies the registered migrations to the properties of module found in the field 'properties'
+// of a JSON object, and returns the JSON object updated and the migrations applied
+// If no migration applies, buf is returned unchanged.
+func MigrateJSON(module string, buf []byte) (_ []byte, _ []PropertyMigration, xerr error) {
+	defer fail.OnPanic(&xerr)()
+
+	var object map[string]json.RawMessage
+	err := FromJSON(buf, &object)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, ok := object["properties"]
+	if !ok {
+		return buf, nil, nil
+	}
+
+	properties := NewJSONProperties(module)
+	err = properties.decode(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	applied, err := properties.migrate()
+	if err != nil || len(applied) == 0 {
+		return buf, nil, err
+	}
+
+	object["properties"], err = properties.MarshalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	buf, err = ToJSON(object)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buf, applied, nil
+}
@@ -0,0 +1,88 @@
+package serialize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+)
+
+type sizeV1 struct {
+	Cores int `json:"cores"`
+}
+
+func (s *sizeV1) Clone() data.Clonable {
+	return (&sizeV1{}).Replace(s)
+}
+
+func (s *sizeV1) Replace(p data.Clonable) data.Clonable {
+	*s = *p.(*sizeV1)
+	return s
+}
+
+type sizeV2 struct {
+	MinCores int `json:"min_cores"`
+	MaxCores int `json:"max_cores"`
+}
+
+func (s *sizeV2) Clone() data.Clonable {
+	return (&sizeV2{}).Replace(s)
+}
+
+func (s *sizeV2) Replace(p data.Clonable) data.Clonable {
+	*s = *p.(*sizeV2)
+	return s
+}
+
+func init() {
+	PropertyTypeRegistry.Register("test.migration", "1", &sizeV1{})
+	PropertyTypeRegistry.Register("test.migration", "2", &sizeV2{})
+	PropertyMigrationRegistry.Register(
+		"test.migration", PropertyMigration{
+			From: "1",
+			To:   "2",
+			Convert: func(from, to data.Clonable) error {
+				cores := from.(*sizeV1).Cores
+				to.(*sizeV2).MinCores, to.(*sizeV2).MaxCores = cores, cores
+				return nil
+			},
+		},
+	)
+}
+
+func TestMigrateJSON(t *testing.T) {
+	buf := []byte(`{"name":"host","properties":{"1":"{\"cores\":4}"}}`)
+	migrated, applied, err := MigrateJSON("test.migration", buf)
+	require.Nil(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, "1 > 2", applied[0].String())
+
+	// The object is migrated again when read, but the migration is not applied twice
+	_, applied, err = MigrateJSON("test.migration", migrated)
+	require.Nil(t, err)
+	assert.Empty(t, applied)
+
+	properties := NewJSONProperties("test.migration")
+	object := struct {
+		Name       string          `json:"name"`
+		Properties *JSONProperties `json:"properties"`
+	}{Properties: properties}
+	require.Nil(t, FromJSON(migrated, &object))
+	assert.Equal(t, "host", object.Name)
+	err = properties.LockForRead("2").ThenUse(
+		func(clonable data.Clonable) error {
+			assert.Equal(t, sizeV2{MinCores: 4, MaxCores: 4}, *clonable.(*sizeV2))
+			return nil
+		},
+	)
+	assert.Nil(t, err)
+}
+
+func TestJSONProperties_MigrateOnRead(t *testing.T) {
+	properties := NewJSONProperties("test.migration")
+	require.Nil(t, properties.UnmarshalJSON([]byte(`{"1":"{\"cores\":2}"}`)))
+	assert.True(t, properties.Lookup("1"))
+	assert.True(t, properties.Lookup("2"))
+}
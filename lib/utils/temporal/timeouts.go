@@ -53,6 +53,12 @@ const (
 
 	// BigDelay is a big delay
 	BigDelay = 30 * time.Second
+
+	// MetadataLeaseDuration is the default validity of a lease on metadata, renewed while held
+	MetadataLeaseDuration = 30 * time.Second
+
+	// MetadataLeaseTimeout is the default time to wait for a lease on metadata held by someone else
+	MetadataLeaseTimeout = 1 * time.Minute
 )
 
 // GetTimeoutFromEnv reads a environment variable 'string', interprets the variable as a time.Duration if possible and returns the time to the caller
@@ -126,3 +132,13 @@ func GetExecutionTimeout() time.Duration {
 func GetLongOperationTimeout() time.Duration {
 	return GetTimeoutFromEnv("SAFESCALE_HOST_LONG_OPERATION_TIMEOUT", LongHostOperationTimeout)
 }
+
+// GetMetadataLeaseDuration ...
+func GetMetadataLeaseDuration() time.Duration {
+	return GetTimeoutFromEnv("SAFESCALE_METADATA_LEASE_DURATION", MetadataLeaseDuration)
+}
+
+// GetMetadataLeaseTimeout ...
+func GetMetadataLeaseTimeout() time.Duration {
+	return GetTimeoutFromEnv("SAFESCALE_METADATA_LEASE_TIMEOUT", MetadataLeaseTimeout)
+}